@@ -0,0 +1,58 @@
+// Package adapters provides ready-made Collection implementations wrapping the mongo driver collections and
+// the driver wrappers built on them, so no custom wrapper struct is needed to call Find. The collections of the v2
+// driver are wrapped by mongov2.WrapCollection, as the mongov2 module depends on it.
+package adapters
+
+import (
+	"context"
+
+	mcpmongo "github.com/qlik-oss/mongocursorpagination/mongo"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// CollectionCloner is implemented by driver wrappers exposing their underlying mongo collection,
+	// e.g. qmgo's *qmgo.Collection
+	CollectionCloner interface {
+		CloneCollection() (*mongo.Collection, error)
+	}
+
+	mongoCollection struct {
+		collection *mongo.Collection
+	}
+)
+
+// WrapMongoCollection returns the Collection to use in mongo.FindParams for the specified mongo collection. It is
+// also a mongo.AggregateCollection, and a mongo.DocsExaminedEstimator so that scan budgets enforce their
+// MaxDocsExamined.
+func WrapMongoCollection(c *mongo.Collection) mcpmongo.Collection {
+	return &mongoCollection{collection: c}
+}
+
+// WrapQmgoCollection returns the Collection to use in mongo.FindParams for the specified qmgo collection, or any
+// other driver wrapper exposing its underlying mongo collection
+func WrapQmgoCollection(c CollectionCloner) (mcpmongo.Collection, error) {
+	collection, err := c.CloneCollection()
+	if err != nil {
+		return nil, err
+	}
+	return WrapMongoCollection(collection), nil
+}
+
+func (c *mongoCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	return c.collection.CountDocuments(ctx, filter, opts...)
+}
+
+func (c *mongoCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (mcpmongo.MongoCursor, error) {
+	return c.collection.Find(ctx, filter, opts...)
+}
+
+func (c *mongoCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (mcpmongo.MongoCursor, error) {
+	return c.collection.Aggregate(ctx, pipeline, opts...)
+}
+
+// EstimateDocsExamined implements mongo.DocsExaminedEstimator with mongo.ExplainDocsExamined
+func (c *mongoCollection) EstimateDocsExamined(ctx context.Context, filter interface{}, opts *options.FindOptions) (int64, error) {
+	return mcpmongo.ExplainDocsExamined(ctx, c.collection, filter, opts)
+}
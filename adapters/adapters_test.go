@@ -0,0 +1,61 @@
+package adapters
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mcpmongo "github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type fakeCloner struct {
+	collection *mongo.Collection
+	err        error
+}
+
+func (c *fakeCloner) CloneCollection() (*mongo.Collection, error) {
+	return c.collection, c.err
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}
+
+func TestWrapMongoCollection(t *testing.T) {
+	client, err := mongo.Connect(context.Background(), options.Client().ApplyURI("mongodb://localhost:1"))
+	require.NoError(t, err)
+	collection := client.Database("test_db").Collection("items")
+
+	c := WrapMongoCollection(collection)
+	require.Equal(t, &mongoCollection{collection: collection}, c)
+
+	_, err = c.Find(canceledContext(), bson.M{})
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = c.CountDocuments(canceledContext(), bson.M{})
+	require.ErrorIs(t, err, context.Canceled)
+
+	// The aggregations and scan budgets are supported too
+	require.Implements(t, (*mcpmongo.AggregateCollection)(nil), c)
+	_, err = c.(mcpmongo.AggregateCollection).Aggregate(canceledContext(), bson.A{})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Implements(t, (*mcpmongo.DocsExaminedEstimator)(nil), c)
+	_, err = c.(mcpmongo.DocsExaminedEstimator).EstimateDocsExamined(canceledContext(), bson.M{}, options.Find())
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestWrapQmgoCollection(t *testing.T) {
+	collection := &mongo.Collection{}
+	c, err := WrapQmgoCollection(&fakeCloner{collection: collection})
+	require.NoError(t, err)
+	require.Equal(t, &mongoCollection{collection: collection}, c)
+
+	c, err = WrapQmgoCollection(&fakeCloner{err: errors.New("error")})
+	require.Nil(t, c)
+	require.EqualError(t, err, "error")
+}
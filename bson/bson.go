@@ -3,52 +3,252 @@ package bson
 import (
 	"errors"
 	"fmt"
+
+	driverbson "go.mongodb.org/mongo-driver/bson"
+)
+
+type (
+	// element is a key value pair of a query document
+	element struct {
+		key   string
+		value interface{}
+	}
+
+	// queryBuilder builds the documents of the cursor queries
+	queryBuilder interface {
+		// doc returns the document holding the elements
+		doc(elements ...element) interface{}
+		// docs returns the array of the documents built by doc
+		docs(docs ...interface{}) interface{}
+	}
+
+	// mapBuilder builds the documents as maps, whose key order is nondeterministic
+	mapBuilder struct{}
+
+	// orderedBuilder builds the documents as bson.D of the mongo driver, whose key order is stable
+	orderedBuilder struct{}
 )
 
-// GenerateCursorQuery generates and returns a cursor range query
+func (mapBuilder) doc(elements ...element) interface{} {
+	m := make(map[string]interface{}, len(elements))
+	for _, e := range elements {
+		m[e.key] = e.value
+	}
+	return m
+}
+
+func (mapBuilder) docs(docs ...interface{}) interface{} {
+	ms := make([]map[string]interface{}, 0, len(docs))
+	for _, d := range docs {
+		ms = append(ms, d.(map[string]interface{}))
+	}
+	return ms
+}
+
+func (orderedBuilder) doc(elements ...element) interface{} {
+	d := make(driverbson.D, 0, len(elements))
+	for _, e := range elements {
+		d = append(d, driverbson.E{Key: e.key, Value: e.value})
+	}
+	return d
+}
+
+func (orderedBuilder) docs(docs ...interface{}) interface{} {
+	ds := make([]driverbson.D, 0, len(docs))
+	for _, d := range docs {
+		ds = append(ds, d.(driverbson.D))
+	}
+	return ds
+}
+
+// GenerateCursorQuery generates and returns a cursor range query. The last paginated field, usually the _id, must
+// be unique as it breaks the ties of the other ones.
 func GenerateCursorQuery(paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}) (map[string]interface{}, error) {
-	var query map[string]interface{}
+	return asMap(generateCursorQuery(mapBuilder{}, paginatedFields, comparisonOps, cursorFieldValues, false))
+}
+
+// GenerateInclusiveCursorQuery generates and returns a cursor range query that also matches the document the
+// cursor points at, by comparing the last paginated field with $gte or $lte
+func GenerateInclusiveCursorQuery(paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}) (map[string]interface{}, error) {
+	return asMap(generateCursorQuery(mapBuilder{}, paginatedFields, comparisonOps, cursorFieldValues, true))
+}
+
+// GenerateOrderedCursorQuery generates and returns the query of GenerateCursorQuery, or of
+// GenerateInclusiveCursorQuery when inclusive is true, as a bson.D of the go.mongodb.org/mongo-driver module. Its
+// documents have a stable key order, so the query has the same shape for every page, e.g. for query shape based
+// plan caching or to compare it in tests.
+func GenerateOrderedCursorQuery(paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}, inclusive bool) (driverbson.D, error) {
+	return asD(generateCursorQuery(orderedBuilder{}, paginatedFields, comparisonOps, cursorFieldValues, inclusive))
+}
+
+// GenerateOrFreeCursorQuery generates and returns a cursor range query without $or, for servers rejecting or
+// poorly planning $or predicates such as Azure Cosmos DB's API for MongoDB. Each paginated field is bounded with
+// $gte or $lte and the documents tied with the cursor on it are excluded with $nor. The query matches the same
+// documents as GenerateCursorQuery, or GenerateInclusiveCursorQuery when inclusive is true.
+func GenerateOrFreeCursorQuery(paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}, inclusive bool) (map[string]interface{}, error) {
+	return asMap(generateOrFreeCursorQuery(mapBuilder{}, paginatedFields, comparisonOps, cursorFieldValues, inclusive))
+}
+
+// GenerateOrderedOrFreeCursorQuery generates and returns the query of GenerateOrFreeCursorQuery as a bson.D with a
+// stable key order, see GenerateOrderedCursorQuery
+func GenerateOrderedOrFreeCursorQuery(paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}, inclusive bool) (driverbson.D, error) {
+	return asD(generateOrFreeCursorQuery(orderedBuilder{}, paginatedFields, comparisonOps, cursorFieldValues, inclusive))
+}
+
+// GenerateRowValueCursorQuery generates and returns a cursor range query comparing the array of the paginated
+// fields' values to the array of the cursor values in a $expr, which mongo compares element by element like a row
+// value. The comparison operators must all be the same as the fields are compared in a single direction. The
+// cursor's document is matched too when inclusive is true.
+func GenerateRowValueCursorQuery(paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}, inclusive bool) (map[string]interface{}, error) {
+	return asMap(generateRowValueCursorQuery(mapBuilder{}, paginatedFields, comparisonOps, cursorFieldValues, inclusive))
+}
 
+// GenerateOrderedRowValueCursorQuery generates and returns the query of GenerateRowValueCursorQuery as a bson.D,
+// see GenerateOrderedCursorQuery
+func GenerateOrderedRowValueCursorQuery(paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}, inclusive bool) (driverbson.D, error) {
+	return asD(generateRowValueCursorQuery(orderedBuilder{}, paginatedFields, comparisonOps, cursorFieldValues, inclusive))
+}
+
+func asMap(query interface{}, err error) (map[string]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	return query.(map[string]interface{}), nil
+}
+
+func asD(query interface{}, err error) (driverbson.D, error) {
+	if err != nil {
+		return nil, err
+	}
+	return query.(driverbson.D), nil
+}
+
+func validateCursorQueryArgs(paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}) error {
 	if len(paginatedFields) != len(cursorFieldValues) {
-		return nil, errors.New("wrong number of cursor field values specified")
+		return errors.New("wrong number of cursor field values specified")
 	}
 
 	if len(comparisonOps) != len(cursorFieldValues) {
-		return nil, errors.New("wrong number of comparison operators specified")
+		return errors.New("wrong number of comparison operators specified")
 	}
 
 	for i := range comparisonOps {
 		if comparisonOps[i] != "$lt" && comparisonOps[i] != "$gt" {
-			return nil, errors.New("invalid comparison operator specified: only $lt and $gt are allowed")
+			return errors.New("invalid comparison operator specified: only $lt and $gt are allowed")
+		}
+	}
+	return nil
+}
+
+func generateCursorQuery(b queryBuilder, paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}, inclusive bool) (interface{}, error) {
+	var query interface{}
+
+	err := validateCursorQueryArgs(paginatedFields, comparisonOps, cursorFieldValues)
+	if err != nil {
+		return nil, err
+	}
+
+	// The last paginated field, usually the _id, is the unique tiebreaker of the other ones. Its comparison
+	// decides whether the document the cursor points at is matched
+	tiebreaker := paginatedFields[len(paginatedFields)-1]
+	idOp := func(op string) string {
+		if inclusive {
+			return fmt.Sprintf("%se", op)
 		}
+		return op
 	}
 
 	if len(paginatedFields) > 1 {
 		if len(paginatedFields) == 2 {
 			rangeOp := fmt.Sprintf("%se", comparisonOps[0])
-			query = map[string]interface{}{"$or": []map[string]interface{}{
-				{paginatedFields[0]: map[string]interface{}{comparisonOps[0]: cursorFieldValues[0]}},
-				{"$and": []map[string]interface{}{
-					{paginatedFields[0]: map[string]interface{}{rangeOp: cursorFieldValues[0]}},
-					{"_id": map[string]interface{}{comparisonOps[0]: cursorFieldValues[1]}},
-				}},
-			}}
+			query = b.doc(element{"$or", b.docs(
+				b.doc(element{paginatedFields[0], b.doc(element{comparisonOps[0], cursorFieldValues[0]})}),
+				b.doc(element{"$and", b.docs(
+					b.doc(element{paginatedFields[0], b.doc(element{rangeOp, cursorFieldValues[0]})}),
+					b.doc(element{tiebreaker, b.doc(element{idOp(comparisonOps[0]), cursorFieldValues[1]})}),
+				)}),
+			)})
 		} else {
-			conditions := make([]map[string]interface{}, len(paginatedFields)-1)
+			conditions := make([]interface{}, len(paginatedFields)-1)
 			for i := 0; i < len(paginatedFields)-1; i++ {
 				rangeOp := fmt.Sprintf("%se", comparisonOps[i])
-				conditions[i] = map[string]interface{}{"$or": []map[string]interface{}{
-					{paginatedFields[i]: map[string]interface{}{comparisonOps[i]: cursorFieldValues[i]}},
-					{"$and": []map[string]interface{}{
-						{paginatedFields[i]: map[string]interface{}{rangeOp: cursorFieldValues[i]}},
-						{"_id": map[string]interface{}{comparisonOps[i]: cursorFieldValues[len(cursorFieldValues)-1]}},
-					}},
-				}}
+				conditions[i] = b.doc(element{"$or", b.docs(
+					b.doc(element{paginatedFields[i], b.doc(element{comparisonOps[i], cursorFieldValues[i]})}),
+					b.doc(element{"$and", b.docs(
+						b.doc(element{paginatedFields[i], b.doc(element{rangeOp, cursorFieldValues[i]})}),
+						b.doc(element{tiebreaker, b.doc(element{idOp(comparisonOps[i]), cursorFieldValues[len(cursorFieldValues)-1]})}),
+					)}),
+				)})
 			}
-			query = map[string]interface{}{"$and": conditions}
+			query = b.doc(element{"$and", b.docs(conditions...)})
 		}
 	} else {
-		query = map[string]interface{}{"_id": map[string]interface{}{comparisonOps[0]: cursorFieldValues[0]}}
+		query = b.doc(element{tiebreaker, b.doc(element{idOp(comparisonOps[0]), cursorFieldValues[0]})})
 	}
 	return query, nil
 }
+
+func generateOrFreeCursorQuery(b queryBuilder, paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}, inclusive bool) (interface{}, error) {
+	err := validateCursorQueryArgs(paginatedFields, comparisonOps, cursorFieldValues)
+	if err != nil {
+		return nil, err
+	}
+
+	// The last paginated field is the unique tiebreaker, see generateCursorQuery
+	tiebreaker := paginatedFields[len(paginatedFields)-1]
+	idValue := cursorFieldValues[len(cursorFieldValues)-1]
+	if len(paginatedFields) == 1 {
+		op := comparisonOps[0]
+		if inclusive {
+			op = fmt.Sprintf("%se", op)
+		}
+		return b.doc(element{tiebreaker, b.doc(element{op, idValue})}), nil
+	}
+
+	conditions := make([]interface{}, 0, 2*(len(paginatedFields)-1))
+	for i := 0; i < len(paginatedFields)-1; i++ {
+		rangeOp := fmt.Sprintf("%se", comparisonOps[i])
+		// The ties on the field preceding the cursor's tiebreaker, and the cursor's document unless inclusive
+		var tieOp string
+		switch {
+		case comparisonOps[i] == "$gt" && inclusive:
+			tieOp = "$lt"
+		case comparisonOps[i] == "$gt":
+			tieOp = "$lte"
+		case inclusive:
+			tieOp = "$gt"
+		default:
+			tieOp = "$gte"
+		}
+		conditions = append(conditions,
+			b.doc(element{paginatedFields[i], b.doc(element{rangeOp, cursorFieldValues[i]})}),
+			b.doc(element{"$nor", b.docs(b.doc(
+				element{paginatedFields[i], cursorFieldValues[i]},
+				element{tiebreaker, b.doc(element{tieOp, idValue})},
+			))}),
+		)
+	}
+	return b.doc(element{"$and", b.docs(conditions...)}), nil
+}
+
+func generateRowValueCursorQuery(b queryBuilder, paginatedFields []string, comparisonOps []string, cursorFieldValues []interface{}, inclusive bool) (interface{}, error) {
+	err := validateCursorQueryArgs(paginatedFields, comparisonOps, cursorFieldValues)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldPaths := make([]interface{}, 0, len(paginatedFields))
+	for i := range paginatedFields {
+		if comparisonOps[i] != comparisonOps[0] {
+			return nil, errors.New("invalid comparison operators specified: row value comparisons require a single direction")
+		}
+		fieldPaths = append(fieldPaths, "$"+paginatedFields[i])
+	}
+
+	op := comparisonOps[0]
+	if inclusive {
+		op = fmt.Sprintf("%se", op)
+	}
+	values := append([]interface{}{}, cursorFieldValues...)
+	return b.doc(element{"$expr", b.doc(element{op, []interface{}{fieldPaths, values}})}), nil
+}
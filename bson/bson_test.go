@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	driverbson "go.mongodb.org/mongo-driver/bson"
 )
 
 func TestGenerateCursorQuery(t *testing.T) {
@@ -89,3 +90,279 @@ func TestGenerateCursorQuery(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateInclusiveCursorQuery(t *testing.T) {
+	var cases = []struct {
+		name              string
+		paginatedFields   []string
+		comparisonOps     []string
+		cursorFieldValues []interface{}
+		expectedQuery     map[string]interface{}
+		expectedErr       error
+	}{
+		{
+			"error when an invalid comparison operator is specified",
+			[]string{"_id"},
+			[]string{"$gte"},
+			[]interface{}{"abc"},
+			nil,
+			errors.New("invalid comparison operator specified: only $lt and $gt are allowed"),
+		},
+		{
+			"return inclusive cursor query when there is no paginated field",
+			[]string{"_id"},
+			[]string{"$lt"},
+			[]interface{}{"123"},
+			map[string]interface{}{"_id": map[string]interface{}{"$lte": "123"}},
+			nil,
+		},
+		{
+			"return inclusive cursor query when sorting on single field",
+			[]string{"name", "_id"},
+			[]string{"$gt", "$gt"},
+			[]interface{}{"test item", "123"},
+			map[string]interface{}{"$or": []map[string]interface{}{
+				{"name": map[string]interface{}{"$gt": "test item"}},
+				{"$and": []map[string]interface{}{
+					{"name": map[string]interface{}{"$gte": "test item"}},
+					{"_id": map[string]interface{}{"$gte": "123"}}},
+				},
+			}},
+			nil,
+		},
+		{
+			"return inclusive cursor query when sorting on multiple fields",
+			[]string{"name", "createdAt", "_id"},
+			[]string{"$lt", "$gt", "$lt"},
+			[]interface{}{"test item", "2024", "123"},
+			map[string]interface{}{"$and": []map[string]interface{}{
+				{"$or": []map[string]interface{}{
+					{"name": map[string]interface{}{"$lt": "test item"}},
+					{"$and": []map[string]interface{}{
+						{"name": map[string]interface{}{"$lte": "test item"}},
+						{"_id": map[string]interface{}{"$lte": "123"}}}}}},
+				{"$or": []map[string]interface{}{
+					{"createdAt": map[string]interface{}{"$gt": "2024"}},
+					{"$and": []map[string]interface{}{
+						{"createdAt": map[string]interface{}{"$gte": "2024"}},
+						{"_id": map[string]interface{}{"$gte": "123"}}}}}}}},
+			nil,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := GenerateInclusiveCursorQuery(tc.paginatedFields, tc.comparisonOps, tc.cursorFieldValues)
+			require.Equal(t, tc.expectedQuery, query)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestGenerateOrFreeCursorQuery(t *testing.T) {
+	var cases = []struct {
+		name              string
+		paginatedFields   []string
+		comparisonOps     []string
+		cursorFieldValues []interface{}
+		inclusive         bool
+		expectedQuery     map[string]interface{}
+		expectedErr       error
+	}{
+		{
+			"error when wrong number of cursor field values specified",
+			[]string{"name", "_id"},
+			[]string{"$gt", "$gt"},
+			[]interface{}{"test item"},
+			false,
+			nil,
+			errors.New("wrong number of cursor field values specified"),
+		},
+		{
+			"return cursor query when there is no paginated field",
+			[]string{"_id"},
+			[]string{"$lt"},
+			[]interface{}{"123"},
+			true,
+			map[string]interface{}{"_id": map[string]interface{}{"$lte": "123"}},
+			nil,
+		},
+		{
+			"return cursor query excluding ties when sorting on single field",
+			[]string{"name", "_id"},
+			[]string{"$gt", "$gt"},
+			[]interface{}{"test item", "123"},
+			false,
+			map[string]interface{}{"$and": []map[string]interface{}{
+				{"name": map[string]interface{}{"$gte": "test item"}},
+				{"$nor": []map[string]interface{}{{
+					"name": "test item",
+					"_id":  map[string]interface{}{"$lte": "123"},
+				}}},
+			}},
+			nil,
+		},
+		{
+			"return inclusive cursor query when sorting on multiple fields",
+			[]string{"name", "createdAt", "_id"},
+			[]string{"$lt", "$gt", "$lt"},
+			[]interface{}{"test item", "2024", "123"},
+			true,
+			map[string]interface{}{"$and": []map[string]interface{}{
+				{"name": map[string]interface{}{"$lte": "test item"}},
+				{"$nor": []map[string]interface{}{{
+					"name": "test item",
+					"_id":  map[string]interface{}{"$gt": "123"},
+				}}},
+				{"createdAt": map[string]interface{}{"$gte": "2024"}},
+				{"$nor": []map[string]interface{}{{
+					"createdAt": "2024",
+					"_id":       map[string]interface{}{"$lt": "123"},
+				}}},
+			}},
+			nil,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := GenerateOrFreeCursorQuery(tc.paginatedFields, tc.comparisonOps, tc.cursorFieldValues, tc.inclusive)
+			require.Equal(t, tc.expectedQuery, query)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestGenerateRowValueCursorQuery(t *testing.T) {
+	var cases = []struct {
+		name              string
+		paginatedFields   []string
+		comparisonOps     []string
+		cursorFieldValues []interface{}
+		inclusive         bool
+		expectedQuery     map[string]interface{}
+		expectedErr       error
+	}{
+		{
+			"error when the comparison operators differ",
+			[]string{"name", "_id"},
+			[]string{"$gt", "$lt"},
+			[]interface{}{"test item", "123"},
+			false,
+			nil,
+			errors.New("invalid comparison operators specified: row value comparisons require a single direction"),
+		},
+		{
+			"return cursor query comparing the fields as a row",
+			[]string{"name", "createdAt", "_id"},
+			[]string{"$lt", "$lt", "$lt"},
+			[]interface{}{"test item", "2024", "123"},
+			false,
+			map[string]interface{}{"$expr": map[string]interface{}{"$lt": []interface{}{
+				[]interface{}{"$name", "$createdAt", "$_id"},
+				[]interface{}{"test item", "2024", "123"},
+			}}},
+			nil,
+		},
+		{
+			"return inclusive cursor query comparing the fields as a row",
+			[]string{"name", "_id"},
+			[]string{"$gt", "$gt"},
+			[]interface{}{"test item", "123"},
+			true,
+			map[string]interface{}{"$expr": map[string]interface{}{"$gte": []interface{}{
+				[]interface{}{"$name", "$_id"},
+				[]interface{}{"test item", "123"},
+			}}},
+			nil,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			query, err := GenerateRowValueCursorQuery(tc.paginatedFields, tc.comparisonOps, tc.cursorFieldValues, tc.inclusive)
+			require.Equal(t, tc.expectedQuery, query)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestGenerateOrderedCursorQuery(t *testing.T) {
+	query, err := GenerateOrderedCursorQuery([]string{"name", "_id"}, []string{"$gt", "$gt"}, []interface{}{"abc", 1}, false)
+	require.NoError(t, err)
+	require.Equal(t, driverbson.D{{Key: "$or", Value: []driverbson.D{
+		{{Key: "name", Value: driverbson.D{{Key: "$gt", Value: "abc"}}}},
+		{{Key: "$and", Value: []driverbson.D{
+			{{Key: "name", Value: driverbson.D{{Key: "$gte", Value: "abc"}}}},
+			{{Key: "_id", Value: driverbson.D{{Key: "$gt", Value: 1}}}},
+		}}},
+	}}}, query)
+
+	_, err = GenerateOrderedCursorQuery([]string{"_id"}, []string{"$blabla"}, []interface{}{1}, false)
+	require.EqualError(t, err, "invalid comparison operator specified: only $lt and $gt are allowed")
+}
+
+func TestGenerateOrderedOrFreeCursorQuery(t *testing.T) {
+	query, err := GenerateOrderedOrFreeCursorQuery([]string{"name", "_id"}, []string{"$lt", "$lt"}, []interface{}{"abc", 1}, true)
+	require.NoError(t, err)
+	require.Equal(t, driverbson.D{{Key: "$and", Value: []driverbson.D{
+		{{Key: "name", Value: driverbson.D{{Key: "$lte", Value: "abc"}}}},
+		{{Key: "$nor", Value: []driverbson.D{{
+			{Key: "name", Value: "abc"},
+			{Key: "_id", Value: driverbson.D{{Key: "$gt", Value: 1}}},
+		}}}},
+	}}}, query)
+
+	// The query has the same bytes every time
+	data, err := driverbson.Marshal(query)
+	require.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		query, err = GenerateOrderedOrFreeCursorQuery([]string{"name", "_id"}, []string{"$lt", "$lt"}, []interface{}{"abc", 1}, true)
+		require.NoError(t, err)
+		again, err := driverbson.Marshal(query)
+		require.NoError(t, err)
+		require.Equal(t, data, again)
+	}
+}
+
+func TestGenerateOrderedQueriesMatchMapQueries(t *testing.T) {
+	fields := []string{"name", "createdAt", "_id"}
+	ops := []string{"$gt", "$gt", "$gt"}
+	values := []interface{}{"abc", int64(2), int32(3)}
+
+	for _, tc := range []struct {
+		name    string
+		ordered func() (driverbson.D, error)
+		mapped  func() (map[string]interface{}, error)
+	}{
+		{
+			name:    "nested $or",
+			ordered: func() (driverbson.D, error) { return GenerateOrderedCursorQuery(fields, ops, values, true) },
+			mapped:  func() (map[string]interface{}, error) { return GenerateInclusiveCursorQuery(fields, ops, values) },
+		},
+		{
+			name:    "$or free",
+			ordered: func() (driverbson.D, error) { return GenerateOrderedOrFreeCursorQuery(fields, ops, values, false) },
+			mapped:  func() (map[string]interface{}, error) { return GenerateOrFreeCursorQuery(fields, ops, values, false) },
+		},
+		{
+			name:    "row value",
+			ordered: func() (driverbson.D, error) { return GenerateOrderedRowValueCursorQuery(fields, ops, values, false) },
+			mapped:  func() (map[string]interface{}, error) { return GenerateRowValueCursorQuery(fields, ops, values, false) },
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ordered, err := tc.ordered()
+			require.NoError(t, err)
+			mapped, err := tc.mapped()
+			require.NoError(t, err)
+			require.Equal(t, toM(t, mapped), toM(t, ordered))
+		})
+	}
+}
+
+// toM round trips the query through bson to compare queries regardless of their document types
+func toM(t *testing.T, query interface{}) driverbson.M {
+	data, err := driverbson.Marshal(query)
+	require.NoError(t, err)
+	var m driverbson.M
+	require.NoError(t, driverbson.Unmarshal(data, &m))
+	return m
+}
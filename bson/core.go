@@ -0,0 +1,428 @@
+package bson
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+const (
+	// DefaultMaxCursorSize is the default maximum size of a decoded cursor in bytes, far beyond the size of the
+	// cursors of a few paginated fields
+	DefaultMaxCursorSize = 4096
+	// DefaultMaxCursorDepth is the default maximum nesting depth of the documents and arrays of a decoded cursor
+	DefaultMaxCursorDepth = 8
+)
+
+// EnsurePaginatedFields returns the paginated fields of a query, ending with the tiebreaker breaking the ties of
+// the other ones, along with their sort orders. paginatedFields takes precedence over paginatedField, the
+// tiebreaker being sorted in ascending order when appended to it. The sort orders default to sortAscending.
+func EnsurePaginatedFields(paginatedField string, paginatedFields []string, sortOrders []int, sortAscending bool, tiebreaker string) ([]string, []int) {
+	if paginatedField == "" {
+		paginatedField = tiebreaker
+	}
+	if len(paginatedFields) == 0 {
+		if paginatedField == tiebreaker {
+			paginatedFields = []string{tiebreaker}
+		} else {
+			paginatedFields = []string{paginatedField, tiebreaker}
+		}
+	} else if paginatedFields[len(paginatedFields)-1] != tiebreaker {
+		paginatedFields = append(paginatedFields, tiebreaker)
+		sortOrders = append(sortOrders, 1)
+	}
+	if len(sortOrders) == 0 {
+		order := -1
+		if sortAscending {
+			order = 1
+		}
+		sortOrders = make([]int, 0, len(paginatedFields))
+		for i := 0; i < len(paginatedFields); i++ {
+			sortOrders = append(sortOrders, order)
+		}
+	}
+	return paginatedFields, sortOrders
+}
+
+// ComparisonOps returns the comparison operators of the cursor query of a page, one per sort order, and sets the
+// sort orders in place to the ones of the page's query, which are reversed for a previous page
+func ComparisonOps(sortOrders []int, previous bool) []string {
+	comparisonOps := make([]string, 0, len(sortOrders))
+	for i := range sortOrders {
+		// Figure out the sort direction and comparison operator that will be used in the augmented query
+		sortAsc := (sortOrders[i] == -1 && previous) || (sortOrders[i] == 1 && !previous)
+		if sortAsc {
+			comparisonOps = append(comparisonOps, "$gt")
+			sortOrders[i] = 1
+		} else {
+			comparisonOps = append(comparisonOps, "$lt")
+			sortOrders[i] = -1
+		}
+	}
+	return comparisonOps
+}
+
+// CheckCursorLength returns an error if a decoded cursor doesn't hold one element per paginated field
+func CheckCursorLength(length int, numPaginatedFields int) error {
+	if length == numPaginatedFields {
+		return nil
+	}
+	if numPaginatedFields == 1 {
+		return errors.New("expecting a cursor with a single element")
+	}
+	return fmt.Errorf("expecting a cursor with %d elements", numPaginatedFields)
+}
+
+// CheckCursorSize returns an error if the base64 URL encoded cursor decodes to more than maxSize bytes, before it
+// is decoded so that a hostile cursor doesn't allocate beyond it
+func CheckCursorSize(cursor string, maxSize int) error {
+	if base64.RawURLEncoding.DecodedLen(len(cursor)) > maxSize {
+		return fmt.Errorf("cursor is larger than %d bytes", maxSize)
+	}
+	return nil
+}
+
+// CheckDocumentLength returns an error if data doesn't start with the length of a BSON document it holds, which
+// the drivers' validation of a document may panic on
+func CheckDocumentLength(data []byte) error {
+	if len(data) < 5 {
+		return errors.New("cursor document is truncated")
+	}
+	length := int64(binary.LittleEndian.Uint32(data))
+	if length < 5 {
+		return fmt.Errorf("cursor document length %d is invalid", length)
+	}
+	if length > int64(len(data)) {
+		return fmt.Errorf("cursor document length %d exceeds its %d bytes", length, len(data))
+	}
+	return nil
+}
+
+// EncodeCursor encodes the BSON document of a cursor into a url safe string, the default wire format of the cursors
+func EncodeCursor(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// LookupField returns the BSON type and the raw value of the top level field of the BSON document record, found
+// being false if the record has no such field. Only the elements preceding the field are checked.
+func LookupField(record []byte, field string) (t byte, value []byte, found bool, err error) {
+	err = CheckDocumentLength(record)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	elements := record[4 : binary.LittleEndian.Uint32(record)-1]
+	for len(elements) > 0 {
+		t = elements[0]
+		end := bytes.IndexByte(elements[1:], 0)
+		if end < 0 {
+			return 0, nil, false, errors.New("document element key isn't terminated")
+		}
+		key := elements[1 : end+1]
+		value = elements[end+2:]
+		size, err := valueSize(t, value)
+		if err != nil {
+			return 0, nil, false, fmt.Errorf("document element %s is invalid: %s", key, err)
+		}
+		if string(key) == field {
+			return t, value[:size], true, nil
+		}
+		elements = value[size:]
+	}
+	return 0, nil, false, nil
+}
+
+// CursorDocument returns the BSON document of the cursor of the BSON document record, holding the raw values of its
+// paginated fields in order, so that their BSON type (e.g. decimal128, timestamp or binary subtype) and the key order
+// of embedded documents are preserved. The missing and null fields are left out.
+func CursorDocument(record []byte, paginatedFields []string) ([]byte, error) {
+	doc := make([]byte, 4, 64)
+	for _, field := range paginatedFields {
+		t, value, found, err := LookupField(record, field)
+		if err != nil {
+			return nil, err
+		}
+		if !found || t == 0x0A {
+			continue
+		}
+		doc = append(doc, t)
+		doc = append(doc, field...)
+		doc = append(doc, 0)
+		doc = append(doc, value...)
+	}
+	doc = append(doc, 0)
+	binary.LittleEndian.PutUint32(doc, uint32(len(doc)))
+	return doc, nil
+}
+
+// DecodeCursor decodes the base64 URL encoded cursor into the BSON document it holds, rejecting the cursors decoding
+// to more than maxSize bytes and the documents CheckCursorDocument rejects before any driver unmarshals them. Every
+// cursor or token read from a client is decoded by it.
+func DecodeCursor(cursor string, maxSize int, maxDepth int) ([]byte, error) {
+	err := CheckCursorSize(cursor, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	err = CheckCursorDocument(data, maxDepth)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// CheckCursorDocument returns an error if data isn't exactly a well formed BSON document, nests documents or arrays
+// deeper than maxDepth or holds values of types a cursor never holds, e.g. JavaScript code
+func CheckCursorDocument(data []byte, maxDepth int) error {
+	err := CheckDocumentLength(data)
+	if err != nil {
+		return err
+	}
+	if int(binary.LittleEndian.Uint32(data)) != len(data) {
+		return fmt.Errorf("cursor document length %d doesn't match its %d bytes", binary.LittleEndian.Uint32(data), len(data))
+	}
+	return checkDocument(data, maxDepth)
+}
+
+// forbiddenTypes are the names of the deprecated or executable BSON types a cursor never holds, by type
+var forbiddenTypes = map[byte]string{
+	0x06: "undefined",
+	0x0C: "dbPointer",
+	0x0D: "javascript",
+	0x0E: "symbol",
+	0x0F: "code with scope",
+}
+
+// checkDocument checks the elements of the document or array doc, whose length was checked, and the depth of their
+// documents
+func checkDocument(doc []byte, depth int) error {
+	if depth <= 0 {
+		return errors.New("cursor is nested too deeply")
+	}
+	if doc[len(doc)-1] != 0 {
+		return errors.New("cursor document isn't terminated")
+	}
+	elements := doc[4 : len(doc)-1]
+	for len(elements) > 0 {
+		t := elements[0]
+		end := bytes.IndexByte(elements[1:], 0)
+		if end < 0 {
+			return errors.New("cursor element key isn't terminated")
+		}
+		key := string(elements[1 : end+1])
+		value := elements[end+2:]
+		if name, ok := forbiddenTypes[t]; ok {
+			return fmt.Errorf("unexpected %s element %s in cursor", name, key)
+		}
+		size, err := valueSize(t, value)
+		if err != nil {
+			return fmt.Errorf("cursor element %s is invalid: %s", key, err)
+		}
+		if t == 0x03 || t == 0x04 {
+			err = checkDocument(value[:size], depth-1)
+			if err != nil {
+				return err
+			}
+		}
+		elements = value[size:]
+	}
+	return nil
+}
+
+// valueSize returns the size of the value of the BSON type t starting value
+func valueSize(t byte, value []byte) (int, error) {
+	size := 0
+	switch t {
+	case 0x06, 0x0A, 0x7F, 0xFF: // undefined, null, max key and min key
+	case 0x08: // boolean
+		size = 1
+		if len(value) >= size && value[0] > 1 {
+			return 0, fmt.Errorf("invalid boolean %d", value[0])
+		}
+	case 0x10: // int32
+		size = 4
+	case 0x01, 0x09, 0x11, 0x12: // double, date, timestamp and int64
+		size = 8
+	case 0x07: // ObjectID
+		size = 12
+	case 0x13: // decimal128
+		size = 16
+	case 0x02, 0x0D, 0x0E: // string, JavaScript code and symbol
+		return stringSize(value)
+	case 0x0C: // DBPointer, its namespace and ObjectID
+		size, err := stringSize(value)
+		if err != nil {
+			return 0, err
+		}
+		if size+12 > len(value) {
+			return 0, errors.New("value is truncated")
+		}
+		return size + 12, nil
+	case 0x0F: // JavaScript code with scope
+		length, err := valueLength(value)
+		if err != nil {
+			return 0, err
+		}
+		if length < 14 || length > len(value) {
+			return 0, fmt.Errorf("invalid code with scope length %d", length)
+		}
+		return length, nil
+	case 0x03, 0x04: // document and array
+		length, err := valueLength(value)
+		if err != nil {
+			return 0, err
+		}
+		if length < 5 || length > len(value) {
+			return 0, fmt.Errorf("invalid document length %d", length)
+		}
+		return length, nil
+	case 0x05: // binary
+		length, err := valueLength(value)
+		if err != nil {
+			return 0, err
+		}
+		size = 5 + length
+		if length < 0 {
+			return 0, fmt.Errorf("invalid binary length %d", length)
+		}
+	case 0x0B: // regular expression, its pattern and options
+		pattern := bytes.IndexByte(value, 0)
+		if pattern < 0 {
+			return 0, errors.New("invalid regular expression")
+		}
+		options := bytes.IndexByte(value[pattern+1:], 0)
+		if options < 0 {
+			return 0, errors.New("invalid regular expression")
+		}
+		return pattern + options + 2, nil
+	default:
+		return 0, fmt.Errorf("unknown type 0x%02x", t)
+	}
+	if size > len(value) {
+		return 0, errors.New("value is truncated")
+	}
+	return size, nil
+}
+
+// stringSize returns the size of the string starting value, prefixed by its length and terminated by a null byte
+func stringSize(value []byte) (int, error) {
+	length, err := valueLength(value)
+	if err != nil {
+		return 0, err
+	}
+	size := 4 + length
+	if length < 1 || size > len(value) || value[size-1] != 0 {
+		return 0, errors.New("invalid string")
+	}
+	return size, nil
+}
+
+// valueLength returns the int32 length prefixing the value
+func valueLength(value []byte) (int, error) {
+	if len(value) < 4 {
+		return 0, errors.New("value is truncated")
+	}
+	return int(int32(binary.LittleEndian.Uint32(value))), nil
+}
+
+// ReverseResults reverses the results slice in place, restoring the sort order of a previous page queried in the
+// reverse order
+func ReverseResults(results reflect.Value) {
+	swap := reflect.Swapper(results.Interface())
+	for left, right := 0, results.Len()-1; left < right; left, right = left+1, right-1 {
+		swap(left, right)
+	}
+}
+
+// MergeQueries returns the filter matching the documents matched by all the queries. Rather than wrapping them in
+// an $and, which defeats some query planner optimizations, the queries are merged into a single document when none
+// of their keys conflict, and empty queries are dropped.
+func MergeQueries[M ~map[string]interface{}](queries []M) M {
+	nonEmpty := make([]M, 0, len(queries))
+	keys := 0
+	for _, query := range queries {
+		if len(query) > 0 {
+			nonEmpty = append(nonEmpty, query)
+			keys += len(query)
+		}
+	}
+	switch len(nonEmpty) {
+	case 0:
+		return M{}
+	case 1:
+		return nonEmpty[0]
+	}
+
+	merged := make(M, keys)
+	for _, query := range nonEmpty {
+		for key, value := range query {
+			if _, conflicting := merged[key]; conflicting {
+				return M{"$and": nonEmpty}
+			}
+			merged[key] = value
+		}
+	}
+	return merged
+}
+
+// ParseBSONTag returns the field name of a bson struct tag and whether it has the inline option, as the struct
+// codec of the mongo drivers parses them, without allocating as it is called for every field of the results' struct
+func ParseBSONTag(tag string) (fieldName string, inline bool) {
+	name, options, _ := strings.Cut(tag, ",")
+	for options != "" && !inline {
+		var option string
+		option, options, _ = strings.Cut(options, ",")
+		inline = strings.EqualFold(strings.TrimSpace(option), "inline")
+	}
+	return strings.TrimSpace(name), inline
+}
+
+// HasPaginatedField returns whether the struct type elem has a field whose bson key matches the paginated field,
+// looking into the inlined structs and struct pointers at any depth. As with the mongo drivers, the key of an
+// untagged field is its lowercased name and a field shadows the ones of the same key inlined deeper, while the
+// fields of the same key inlined at the same depth are ambiguous and none of them is found.
+func HasPaginatedField(elem reflect.Type, paginatedField string) bool {
+	level := []reflect.Type{elem}
+	visited := map[reflect.Type]bool{elem: true}
+	for len(level) > 0 {
+		var inlined []reflect.Type
+		matches := 0
+		for _, t := range level {
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				if !field.IsExported() {
+					continue
+				}
+				fieldName, inline := ParseBSONTag(field.Tag.Get("bson"))
+				if fieldName == "-" {
+					continue
+				}
+				if inline {
+					inlineType := field.Type
+					if inlineType.Kind() == reflect.Ptr {
+						inlineType = inlineType.Elem()
+					}
+					if inlineType.Kind() == reflect.Struct && !visited[inlineType] {
+						visited[inlineType] = true
+						inlined = append(inlined, inlineType)
+					}
+					continue
+				}
+				if fieldName == paginatedField || fieldName == "" && strings.ToLower(field.Name) == paginatedField {
+					matches++
+				}
+			}
+		}
+		if matches > 0 {
+			return matches == 1
+		}
+		level = inlined
+	}
+	return false
+}
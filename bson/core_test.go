@@ -0,0 +1,202 @@
+package bson
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsurePaginatedFields(t *testing.T) {
+	var cases = []struct {
+		name               string
+		paginatedField     string
+		paginatedFields    []string
+		sortOrders         []int
+		sortAscending      bool
+		expectedFields     []string
+		expectedSortOrders []int
+	}{
+		{"defaults to the tiebreaker", "", nil, nil, false, []string{"_id"}, []int{-1}},
+		{"appends the tiebreaker to the paginated field", "name", nil, nil, true, []string{"name", "_id"}, []int{1, 1}},
+		{"appends the tiebreaker sorted ascending to the paginated fields", "", []string{"name"}, []int{-1}, false, []string{"name", "_id"}, []int{-1, 1}},
+		{"keeps the paginated fields ending with the tiebreaker", "", []string{"name", "_id"}, []int{1, -1}, true, []string{"name", "_id"}, []int{1, -1}},
+		{"prefers the paginated fields", "count", []string{"name"}, []int{-1}, false, []string{"name", "_id"}, []int{-1, 1}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields, sortOrders := EnsurePaginatedFields(tc.paginatedField, tc.paginatedFields, tc.sortOrders, tc.sortAscending, "_id")
+			require.Equal(t, tc.expectedFields, fields)
+			require.Equal(t, tc.expectedSortOrders, sortOrders)
+		})
+	}
+}
+
+func TestComparisonOps(t *testing.T) {
+	sortOrders := []int{1, -1}
+	require.Equal(t, []string{"$gt", "$lt"}, ComparisonOps(sortOrders, false))
+	require.Equal(t, []int{1, -1}, sortOrders)
+
+	// A previous page is queried in the reverse order
+	require.Equal(t, []string{"$lt", "$gt"}, ComparisonOps(sortOrders, true))
+	require.Equal(t, []int{-1, 1}, sortOrders)
+}
+
+func TestCheckCursorLength(t *testing.T) {
+	require.NoError(t, CheckCursorLength(2, 2))
+	require.EqualError(t, CheckCursorLength(2, 1), "expecting a cursor with a single element")
+	require.EqualError(t, CheckCursorLength(1, 2), "expecting a cursor with 2 elements")
+}
+
+func TestCheckCursorSize(t *testing.T) {
+	require.NoError(t, CheckCursorSize("AAAA", 3))
+	require.EqualError(t, CheckCursorSize("AAAAAA", 3), "cursor is larger than 3 bytes")
+}
+
+func TestCheckDocumentLength(t *testing.T) {
+	require.NoError(t, CheckDocumentLength([]byte{5, 0, 0, 0, 0}))
+	require.EqualError(t, CheckDocumentLength([]byte{0, 0, 0}), "cursor document is truncated")
+	require.EqualError(t, CheckDocumentLength([]byte{0, 0, 0, 0}), "cursor document is truncated")
+	require.EqualError(t, CheckDocumentLength([]byte{0, 0, 0, 0, 0}), "cursor document length 0 is invalid")
+	require.EqualError(t, CheckDocumentLength([]byte{6, 0, 0, 0, 0}), "cursor document length 6 exceeds its 5 bytes")
+}
+
+func TestReverseResults(t *testing.T) {
+	results := []string{"a", "b", "c"}
+	ReverseResults(reflect.ValueOf(results))
+	require.Equal(t, []string{"c", "b", "a"}, results)
+
+	empty := []string{}
+	ReverseResults(reflect.ValueOf(empty))
+	require.Empty(t, empty)
+}
+
+func TestEncodeCursor(t *testing.T) {
+	require.Equal(t, "BQAAAAA", EncodeCursor([]byte{5, 0, 0, 0, 0}))
+}
+
+func TestLookupField(t *testing.T) {
+	// {"name": "a", "n": null, "_id": int32(1)}
+	record := []byte{0x1D, 0, 0, 0, 0x02, 'n', 'a', 'm', 'e', 0, 2, 0, 0, 0, 'a', 0, 0x0A, 'n', 0, 0x10, '_', 'i', 'd', 0, 1, 0, 0, 0, 0}
+
+	bsonType, value, found, err := LookupField(record, "_id")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, byte(0x10), bsonType)
+	require.Equal(t, []byte{1, 0, 0, 0}, value)
+
+	bsonType, value, found, err = LookupField(record, "n")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, byte(0x0A), bsonType)
+	require.Empty(t, value)
+
+	_, _, found, err = LookupField(record, "missing")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	_, _, _, err = LookupField(record[:3], "_id")
+	require.EqualError(t, err, "cursor document is truncated")
+}
+
+func TestCursorDocument(t *testing.T) {
+	// {"name": "a", "n": null, "_id": int32(1)}
+	record := []byte{0x1D, 0, 0, 0, 0x02, 'n', 'a', 'm', 'e', 0, 2, 0, 0, 0, 'a', 0, 0x0A, 'n', 0, 0x10, '_', 'i', 'd', 0, 1, 0, 0, 0, 0}
+
+	// The missing and null fields are left out, the others are kept in the order of the paginated fields
+	doc, err := CursorDocument(record, []string{"missing", "_id", "n", "name"})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1A, 0, 0, 0, 0x10, '_', 'i', 'd', 0, 1, 0, 0, 0, 0x02, 'n', 'a', 'm', 'e', 0, 2, 0, 0, 0, 'a', 0, 0}, doc)
+	require.NoError(t, CheckCursorDocument(doc, DefaultMaxCursorDepth))
+
+	_, err = CursorDocument([]byte{0, 0}, []string{"_id"})
+	require.EqualError(t, err, "cursor document is truncated")
+}
+
+func TestDecodeCursor(t *testing.T) {
+	// {"name": "a", "n": int32(1)}
+	valid := []byte{0x18, 0, 0, 0, 0x02, 'n', 'a', 'm', 'e', 0, 2, 0, 0, 0, 'a', 0, 0x10, 'n', 0, 1, 0, 0, 0, 0}
+	cursor := base64.RawURLEncoding.EncodeToString(valid)
+	data, err := DecodeCursor(cursor, DefaultMaxCursorSize, DefaultMaxCursorDepth)
+	require.NoError(t, err)
+	require.Equal(t, valid, data)
+
+	_, err = DecodeCursor(cursor, 8, DefaultMaxCursorDepth)
+	require.EqualError(t, err, "cursor is larger than 8 bytes")
+	_, err = DecodeCursor("!", DefaultMaxCursorSize, DefaultMaxCursorDepth)
+	require.Error(t, err)
+	_, err = DecodeCursor(cursor[:len(cursor)-4], DefaultMaxCursorSize, DefaultMaxCursorDepth)
+	require.Error(t, err)
+}
+
+func TestCheckCursorDocument(t *testing.T) {
+	var cases = []struct {
+		name        string
+		doc         []byte
+		maxDepth    int
+		expectedErr string
+	}{
+		{"accepts the empty document", []byte{5, 0, 0, 0, 0}, 1, ""},
+		{"accepts nested documents up to the depth", []byte{0x0D, 0, 0, 0, 0x03, 'a', 0, 5, 0, 0, 0, 0, 0}, 2, ""},
+		{"rejects trailing bytes", []byte{5, 0, 0, 0, 0, 0}, 1, "cursor document length 5 doesn't match its 6 bytes"},
+		{"rejects an unterminated document", []byte{5, 0, 0, 0, 1}, 1, "cursor document isn't terminated"},
+		{"rejects an unterminated key", []byte{7, 0, 0, 0, 0x0A, 'a', 0}, 1, "cursor element key isn't terminated"},
+		{"rejects documents nested too deeply", []byte{0x0D, 0, 0, 0, 0x03, 'a', 0, 5, 0, 0, 0, 0, 0}, 1, "cursor is nested too deeply"},
+		{"rejects JavaScript code", []byte{0x0E, 0, 0, 0, 0x0D, 'a', 0, 2, 0, 0, 0, 'x', 0, 0}, 1, "unexpected javascript element a in cursor"},
+		{"rejects unknown types", []byte{8, 0, 0, 0, 0x42, 'a', 0, 0}, 1, "cursor element a is invalid: unknown type 0x42"},
+		{"rejects truncated values", []byte{0x0B, 0, 0, 0, 0x12, 'a', 0, 1, 2, 3, 0}, 1, "cursor element a is invalid: value is truncated"},
+		{"rejects negative string lengths", []byte{0x0E, 0, 0, 0, 0x02, 'a', 0, 0xFF, 0xFF, 0xFF, 0xFF, 'x', 0, 0}, 1, "cursor element a is invalid: invalid string"},
+		{"rejects embedded documents overflowing their parent", []byte{0x0D, 0, 0, 0, 0x03, 'a', 0, 9, 0, 0, 0, 0, 0}, 2, "cursor element a is invalid: invalid document length 9"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckCursorDocument(tc.doc, tc.maxDepth)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
+func FuzzDecodeCursor(f *testing.F) {
+	f.Add("GAAAAAJuYW1lAAIAAABhABBuAAEAAAAA")
+	f.Add("DQAAAANhAAUAAAAAAA")
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, cursor string) {
+		data, err := DecodeCursor(cursor, DefaultMaxCursorSize, DefaultMaxCursorDepth)
+		if err != nil {
+			return
+		}
+		// A decoded cursor is exactly the document it holds
+		require.Equal(t, len(data), int(binary.LittleEndian.Uint32(data)))
+	})
+}
+
+func TestMergeQueries(t *testing.T) {
+	type M map[string]interface{}
+	require.Equal(t, M{}, MergeQueries([]M{nil, {}}))
+	require.Equal(t, M{"name": "a", "_id": M{"$gt": 1}}, MergeQueries([]M{{"name": "a"}, nil, {"_id": M{"$gt": 1}}}))
+	require.Equal(t, M{"$and": []M{{"_id": 1}, {"_id": M{"$gt": 1}}}}, MergeQueries([]M{{"_id": 1}, {"_id": M{"$gt": 1}}}))
+}
+
+func TestHasPaginatedField(t *testing.T) {
+	type inlined struct {
+		Name string `bson:"name"`
+	}
+	type item struct {
+		ID       int `bson:"_id"`
+		Untagged string
+		Skipped  string   `bson:"-"`
+		Inline   *inlined `bson:",omitempty,inline"`
+	}
+	elem := reflect.TypeOf(item{})
+	require.True(t, HasPaginatedField(elem, "_id"))
+	require.True(t, HasPaginatedField(elem, "untagged"))
+	require.True(t, HasPaginatedField(elem, "name"))
+	require.False(t, HasPaginatedField(elem, "Skipped"))
+}
@@ -0,0 +1,53 @@
+// Command describecursor prints the elements of mongocursorpagination cursors in the default wire format, e.g. to
+// debug the pagination issues reported by customers. The cursors are read from the arguments, or one per line from
+// the standard input without any, and described as JSON.
+//
+// Usage:
+//
+//	go run github.com/qlik-oss/mongocursorpagination/cmd/describecursor <cursor>...
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+)
+
+func main() {
+	tokens := os.Args[1:]
+	if len(tokens) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if token := strings.TrimSpace(scanner.Text()); token != "" {
+				tokens = append(tokens, token)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	failed := false
+	for _, token := range tokens {
+		info, err := mongo.DescribeCursor(token)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", token, err)
+			failed = true
+			continue
+		}
+		if err := encoder.Encode(info); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
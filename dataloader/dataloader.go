@@ -0,0 +1,148 @@
+// Package dataloader batches the paginated queries of nested GraphQL fields, e.g. the first N comments of each post,
+// into a single aggregation, so that the dataloaden style batch loaders of a request fetch the pages of all the
+// parents at once instead of running a paginated query per parent.
+package dataloader
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/qlik-oss/mongocursorpagination/relay"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// PageKey identifies the page of the children of a parent, the key of the batch loader
+	PageKey struct {
+		// The value of the parent field of the children, e.g. the _id of their post
+		Parent interface{}
+		// The Relay connection arguments of the page
+		Args relay.ConnectionArgs
+	}
+
+	// Params describes the paginated children of the parents
+	Params struct {
+		Collection mongo.AggregateCollection
+		// The field of the children holding the value of their parent, e.g. "postId"
+		ParentField string
+		// The query, limit and sort of the pages, see mongo.FindParams. Each page starts at the cursor of its
+		// connection arguments, and the total count of the children isn't computed.
+		Page mongo.FindParams
+	}
+)
+
+// LoadPages fetches the pages of the keys with a single aggregation of a $facet per key, and returns their Relay
+// connections and errors in the order of the keys, as the fetch function of a dataloaden style batch loader:
+//
+//	loader := NewCommentsLoader(CommentsLoaderConfig{Fetch: func(keys []dataloader.PageKey) ([]relay.Connection[Comment], []error) {
+//		return dataloader.LoadPages[Comment](ctx, params, keys)
+//	}})
+//
+// The pages of all the keys of a batch must fit in a single document of 16MB.
+func LoadPages[T any](ctx context.Context, p Params, keys []PageKey) ([]relay.Connection[T], []error) {
+	connections := make([]relay.Connection[T], len(keys))
+	errs := make([]error, len(keys))
+	pages := make([]mongo.FindParams, len(keys))
+	parents := make([]interface{}, 0, len(keys))
+	facets := bson.M{}
+	for i, key := range keys {
+		page, err := relay.ApplyConnectionArgs(p.Page, key.Args)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		page.Query = mongo.MergeQueries([]bson.M{p.Page.Query, {p.ParentField: key.Parent}})
+		page.CountTotal = false
+		stages, err := mongo.BuildPipeline(ctx, aggregateParams(p, page, key.Parent))
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		pages[i] = page
+		parents = append(parents, key.Parent)
+		facets[strconv.Itoa(i)] = stages
+	}
+	if len(facets) == 0 {
+		return connections, errs
+	}
+
+	pipeline := []bson.M{
+		{"$match": mongo.MergeQueries([]bson.M{p.Page.Query, {p.ParentField: bson.M{"$in": parents}}})},
+		{"$facet": facets},
+	}
+	facetResults, err := aggregateFacets(ctx, p, pipeline)
+	for i := range keys {
+		if errs[i] != nil {
+			continue
+		}
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		connections[i], errs[i] = newConnection[T](facetResults[strconv.Itoa(i)], pages[i])
+	}
+	return connections, errs
+}
+
+// aggregateParams returns the AggregateParams of the page of the children of a parent
+func aggregateParams(p Params, page mongo.FindParams, parent interface{}) mongo.AggregateParams {
+	return mongo.AggregateParams{
+		Collection:        p.Collection,
+		Pipeline:          []bson.M{{"$match": bson.M{p.ParentField: parent}}},
+		Limit:             page.Limit,
+		SortAscending:     page.SortAscending,
+		PaginatedField:    page.PaginatedField,
+		PaginatedFields:   page.PaginatedFields,
+		SortOrders:        page.SortOrders,
+		Collation:         page.Collation,
+		Next:              page.Next,
+		Previous:          page.Previous,
+		Dialect:           page.Dialect,
+		PredicateStrategy: page.PredicateStrategy,
+		ServerVersion:     page.ServerVersion,
+		TiebreakerField:   page.TiebreakerField,
+	}
+}
+
+// aggregateFacets runs the pipeline and returns the documents of each of its facets
+func aggregateFacets(ctx context.Context, p Params, pipeline []bson.M) (map[string][]bson.Raw, error) {
+	opts := options.Aggregate()
+	if p.Page.Collation != nil {
+		opts.SetCollation(p.Page.Collation)
+	}
+	if p.Page.Hint != nil {
+		opts.SetHint(p.Page.Hint)
+	}
+	if p.Page.Timeout > 0 {
+		opts.SetMaxTime(p.Page.Timeout)
+	}
+	cursor, err := p.Collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return nil, err
+	}
+	var facetResults []map[string][]bson.Raw
+	err = cursor.All(ctx, &facetResults)
+	if err != nil || len(facetResults) == 0 {
+		return nil, err
+	}
+	return facetResults[0], nil
+}
+
+// newConnection returns the connection of the documents of a page, which hold an additional document when more
+// follow it in the direction it was queried
+func newConnection[T any](docs []bson.Raw, page mongo.FindParams) (relay.Connection[T], error) {
+	nodes := make([]T, len(docs))
+	for i, doc := range docs {
+		err := bson.Unmarshal(doc, &nodes[i])
+		if err != nil {
+			return relay.Connection[T]{}, err
+		}
+	}
+	cursor, err := mongo.MakeCursors(&nodes, page, len(docs) > int(page.Limit))
+	if err != nil {
+		return relay.Connection[T]{}, err
+	}
+	return relay.NewConnection(nodes, cursor, page)
+}
@@ -0,0 +1,76 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/qlik-oss/mongocursorpagination/relay"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type comment struct {
+	ID     primitive.ObjectID `bson:"_id"`
+	PostID string             `bson:"postId"`
+	Text   string             `bson:"text"`
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func texts(c relay.Connection[comment]) []string {
+	var texts []string
+	for _, edge := range c.Edges {
+		texts = append(texts, edge.Node.Text)
+	}
+	return texts
+}
+
+func TestLoadPages(t *testing.T) {
+	var docs []interface{}
+	for _, c := range []comment{
+		{PostID: "p1", Text: "a"}, {PostID: "p1", Text: "b"}, {PostID: "p1", Text: "c"},
+		{PostID: "p2", Text: "d"}, {PostID: "p3", Text: "e"},
+	} {
+		c.ID = primitive.NewObjectID()
+		docs = append(docs, c)
+	}
+	col, err := mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+	p := Params{Collection: col, ParentField: "postId", Page: mongo.FindParams{Limit: 10, PaginatedField: "text", SortAscending: true}}
+
+	connections, errs := LoadPages[comment](context.Background(), p, []PageKey{
+		{Parent: "p1", Args: relay.ConnectionArgs{First: intPtr(2)}},
+		{Parent: "p2"},
+		{Parent: "p4"},
+		{Parent: "p1", Args: relay.ConnectionArgs{First: intPtr(1), Last: intPtr(1)}},
+	})
+	require.Equal(t, []error{nil, nil, nil, relay.NewErrInvalidConnectionArgs("first and last can't be specified together")}, errs)
+	require.Equal(t, []string{"a", "b"}, texts(connections[0]))
+	require.True(t, connections[0].PageInfo.HasNextPage)
+	require.Equal(t, []string{"d"}, texts(connections[1]))
+	require.False(t, connections[1].PageInfo.HasNextPage)
+	require.Empty(t, connections[2].Edges)
+
+	// The cursors of the edges page through the children of their parent
+	after := connections[0].PageInfo.EndCursor
+	before := connections[0].Edges[1].Cursor
+	connections, errs = LoadPages[comment](context.Background(), p, []PageKey{
+		{Parent: "p1", Args: relay.ConnectionArgs{First: intPtr(2), After: &after}},
+		{Parent: "p1", Args: relay.ConnectionArgs{Last: intPtr(2), Before: &before}},
+	})
+	require.Equal(t, []error{nil, nil}, errs)
+	require.Equal(t, []string{"c"}, texts(connections[0]))
+	require.False(t, connections[0].PageInfo.HasNextPage)
+	require.Equal(t, []string{"a"}, texts(connections[1]))
+	require.False(t, connections[1].PageInfo.HasPreviousPage)
+
+	// The aggregation error is the error of every key
+	col.AggregateErr = errors.New("boom")
+	_, errs = LoadPages[comment](context.Background(), p, []PageKey{{Parent: "p1"}, {Parent: "p2"}})
+	require.Equal(t, []error{col.AggregateErr, col.AggregateErr}, errs)
+}
@@ -0,0 +1,103 @@
+// Package export walks all the pages of a paginated find mongo query to export the matching documents.
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type (
+	// Manifest describes an exported snapshot so it can be verified and resumed.
+	Manifest struct {
+		// The number of exported documents
+		Count int
+		// The boundaries of each exported page, in order
+		Pages []PageManifest
+		// The hex encoded SHA-256 hash of the exported data stream
+		Hash string
+	}
+
+	// PageManifest describes an exported page.
+	PageManifest struct {
+		// The cursor pointing at the first document of the page
+		Start string
+		// The cursor pointing at the last document of the page. Passing it as FindParams.Next resumes the
+		// export after this page
+		End string
+		// The number of documents in the page
+		Count int
+		// The hex encoded SHA-256 hash of the page's documents
+		Hash string
+	}
+)
+
+// Snapshot walks all the pages of the find query described by p, starting at p.Next when set, and writes each
+// document's raw BSON to w in sort order. The returned manifest holds the page boundaries and content hashes of
+// the written data stream.
+func Snapshot(ctx context.Context, p mongo.FindParams, w io.Writer) (Manifest, error) {
+	if p.Previous != "" {
+		return Manifest{}, errors.New("a snapshot can't be taken from a previous cursor")
+	}
+	p.CountTotal = false
+
+	var manifest Manifest
+	streamHash := sha256.New()
+	for {
+		var docs []bson.Raw
+		cursor, err := mongo.Find(ctx, p, &docs)
+		if err != nil {
+			return manifest, err
+		}
+
+		if len(docs) > 0 {
+			var page PageManifest
+			page, err = writePage(p, docs, io.MultiWriter(w, streamHash))
+			if err != nil {
+				return manifest, err
+			}
+			manifest.Pages = append(manifest.Pages, page)
+			manifest.Count += page.Count
+		}
+
+		if !cursor.HasNext || cursor.Next == "" {
+			break
+		}
+		p.Next = cursor.Next
+	}
+	manifest.Hash = hex.EncodeToString(streamHash.Sum(nil))
+
+	return manifest, nil
+}
+
+func writePage(p mongo.FindParams, docs []bson.Raw, w io.Writer) (PageManifest, error) {
+	var err error
+	page := PageManifest{Count: len(docs)}
+
+	page.Start, err = mongo.GenerateCursor(docs[0], p)
+	if err != nil {
+		return PageManifest{}, fmt.Errorf("could not create a page start cursor: %s", err)
+	}
+	page.End, err = mongo.GenerateCursor(docs[len(docs)-1], p)
+	if err != nil {
+		return PageManifest{}, fmt.Errorf("could not create a page end cursor: %s", err)
+	}
+
+	pageHash := sha256.New()
+	w = io.MultiWriter(w, pageHash)
+	for _, doc := range docs {
+		_, err = w.Write(doc)
+		if err != nil {
+			return PageManifest{}, err
+		}
+	}
+	page.Hash = hex.EncodeToString(pageHash.Sum(nil))
+
+	return page, nil
+}
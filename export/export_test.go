@@ -0,0 +1,97 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	mcpmongo "github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	item struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Name string             `bson:"name"`
+	}
+
+	// fakeCollection serves one batch of canned documents per find query
+	fakeCollection struct {
+		batches [][]interface{}
+		filters []interface{}
+		// The skip of each find query
+		skips []*int64
+		// The errors returned by the first find queries
+		errs []error
+		// Called on each find query
+		onFind func()
+	}
+)
+
+func (c *fakeCollection) CountDocuments(context.Context, interface{}, ...*options.CountOptions) (int64, error) {
+	return 0, nil
+}
+
+func (c *fakeCollection) Find(_ context.Context, filter interface{}, opts ...*options.FindOptions) (mcpmongo.MongoCursor, error) {
+	c.filters = append(c.filters, filter)
+	c.skips = append(c.skips, options.MergeFindOptions(opts...).Skip)
+	if c.onFind != nil {
+		c.onFind()
+	}
+	if len(c.errs) > 0 {
+		err := c.errs[0]
+		c.errs = c.errs[1:]
+		return nil, err
+	}
+	batch := c.batches[0]
+	c.batches = c.batches[1:]
+	return mongo.NewCursorFromDocuments(batch, nil, nil)
+}
+
+func TestSnapshot(t *testing.T) {
+	items := []item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	col := &fakeCollection{batches: [][]interface{}{
+		{items[0], items[1], items[2]},
+		{items[2]},
+	}}
+	p := mcpmongo.FindParams{Collection: col, Limit: 2, SortAscending: true}
+
+	var buf bytes.Buffer
+	manifest, err := Snapshot(context.Background(), p, &buf)
+	require.NoError(t, err)
+	require.Len(t, col.filters, 2)
+
+	var expectedStream []byte
+	for _, it := range items {
+		raw, err := bson.Marshal(it)
+		require.NoError(t, err)
+		expectedStream = append(expectedStream, raw...)
+	}
+	require.Equal(t, expectedStream, buf.Bytes())
+
+	streamHash := sha256.Sum256(expectedStream)
+	require.Equal(t, hex.EncodeToString(streamHash[:]), manifest.Hash)
+	require.Equal(t, 3, manifest.Count)
+	require.Len(t, manifest.Pages, 2)
+	require.Equal(t, 2, manifest.Pages[0].Count)
+	require.Equal(t, 1, manifest.Pages[1].Count)
+
+	end, err := mcpmongo.GenerateCursor(items[1], p)
+	require.NoError(t, err)
+	require.Equal(t, end, manifest.Pages[0].End)
+}
+
+func TestSnapshotErrorsOnPreviousCursor(t *testing.T) {
+	_, err := Snapshot(context.Background(), mcpmongo.FindParams{Previous: "abc"}, &bytes.Buffer{})
+	require.EqualError(t, err, "a snapshot can't be taken from a previous cursor")
+}
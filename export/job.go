@@ -0,0 +1,142 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type (
+	// Job describes a resumable export of a whole collection in _id order, e.g. for backfill and migration jobs.
+	Job struct {
+		// The query of the exported documents, its Limit being the number of documents of a page. Its paginated
+		// fields, sort orders, tiebreaker, cursors, anchor, skipped pages, dedup window and DecodeFunc are
+		// ignored, the documents are exported in ascending _id order from the saved checkpoint. Its RetryPolicy
+		// retries the page queries failing with transient errors, defaults to the RetryPolicy of the Defaults or
+		// DefaultRetryPolicy.
+		Params mongo.FindParams
+		// Where the checkpoint, the cursor of the last exported document, is persisted, required
+		Checkpoints mongo.ProgressStore
+		// Handles the documents of each page, in _id order, required. The checkpoint of a page is saved once it
+		// is handled, so a page may be handled again when the job is interrupted in the meantime.
+		Handle func(ctx context.Context, docs []bson.Raw) error
+		// The minimum interval between the page queries, to limit the load of the job on the cluster
+		PageInterval time.Duration
+		// Called with the progress of the run once the checkpoint of each page is saved
+		OnProgress func(ctx context.Context, progress Progress)
+		// The expected number of documents exported by the run, e.g. from an estimated document count, for the
+		// ETA of the progress
+		ExpectedDocuments int
+		// The number of recent pages the throughput and latency of the progress are averaged over, 10 by default
+		ProgressWindow int
+		// The watchdog aborts the run with an ErrPageLatencyDegraded once the average latency of the recent page
+		// queries exceeds it, after saving the checkpoint of the last page. No watchdog by default
+		MaxPageLatency time.Duration
+	}
+)
+
+// DefaultRetryPolicy is the RetryPolicy of the page queries of the jobs whose Params don't set one when the
+// Defaults don't either
+var DefaultRetryPolicy = mongo.RetryPolicy{MaxAttempts: 3, Backoff: time.Second}
+
+// Run exports the documents of the job page by page from its saved checkpoint, saving the checkpoint after each
+// page, and returns the number of documents it exported.
+func Run(ctx context.Context, job Job) (exported int, err error) {
+	if job.Checkpoints == nil {
+		return 0, errors.New("Checkpoints can't be nil")
+	}
+	if job.Handle == nil {
+		return 0, errors.New("Handle can't be nil")
+	}
+	p := job.Params
+	p.PaginatedField = ""
+	p.PaginatedFields = nil
+	p.SortOrders = nil
+	p.TiebreakerField = ""
+	p.SortAscending = true
+	p.Previous = ""
+	p.IncludeAnchor = false
+	p.SkipPages = 0
+	p.DedupWindow = 0
+	p.DecodeFunc = nil
+	p.CountTotal = false
+	if p.RetryPolicy == nil && mongo.CurrentDefaults().RetryPolicy == nil {
+		retryPolicy := DefaultRetryPolicy
+		p.RetryPolicy = &retryPolicy
+	}
+	p.Next, err = job.Checkpoints.LoadProgress(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not load the checkpoint: %w", err)
+	}
+
+	tracker := newProgressTracker(job)
+	last := now()
+	for pages := 0; ; pages++ {
+		if pages > 0 {
+			err = sleep(ctx, job.PageInterval)
+			if err != nil {
+				return exported, err
+			}
+		}
+
+		var docs []bson.Raw
+		var cursor mongo.Cursor
+		start := now()
+		cursor, err = mongo.Find(ctx, p, &docs)
+		if err != nil {
+			return exported, err
+		}
+		latency := now().Sub(start)
+		if len(docs) == 0 {
+			return exported, nil
+		}
+
+		err = job.Handle(ctx, docs)
+		if err != nil {
+			return exported, err
+		}
+		var checkpoint string
+		checkpoint, err = mongo.GenerateCursor(docs[len(docs)-1], p)
+		if err != nil {
+			return exported, fmt.Errorf("could not create a checkpoint: %s", err)
+		}
+		err = job.Checkpoints.SaveProgress(ctx, checkpoint)
+		if err != nil {
+			return exported, fmt.Errorf("could not save the checkpoint: %w", err)
+		}
+		exported += len(docs)
+
+		current := now()
+		progress := tracker.add(len(docs), checkpoint, latency, current.Sub(last))
+		last = current
+		if job.OnProgress != nil {
+			job.OnProgress(ctx, progress)
+		}
+		if job.MaxPageLatency > 0 && progress.PageLatency > job.MaxPageLatency {
+			return exported, &ErrPageLatencyDegraded{PageLatency: progress.PageLatency, Checkpoint: checkpoint}
+		}
+		if !cursor.HasNext {
+			return exported, nil
+		}
+		p.Next = checkpoint
+	}
+}
+
+// sleep waits for the duration unless the context is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,179 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mcpmongo "github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type checkpointStore struct {
+	checkpoint string
+	saved      []string
+}
+
+func (s *checkpointStore) LoadProgress(context.Context) (string, error) {
+	return s.checkpoint, nil
+}
+
+func (s *checkpointStore) SaveProgress(_ context.Context, checkpoint string) error {
+	s.checkpoint = checkpoint
+	s.saved = append(s.saved, checkpoint)
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	items := []item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	col := &fakeCollection{
+		batches: [][]interface{}{{items[0], items[1], items[2]}, {items[2]}},
+		errs:    []error{mongo.CommandError{Labels: []string{"RetryableReadError"}}},
+	}
+	p := mcpmongo.FindParams{Collection: col, Limit: 2, PaginatedField: "name", RetryPolicy: &mcpmongo.RetryPolicy{MaxAttempts: 2}}
+	store := &checkpointStore{}
+
+	var handled []string
+	exported, err := Run(context.Background(), Job{
+		Params:      p,
+		Checkpoints: store,
+		Handle: func(_ context.Context, docs []bson.Raw) error {
+			for _, doc := range docs {
+				handled = append(handled, doc.Lookup("name").StringValue())
+			}
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, exported)
+	require.Equal(t, []string{"a", "b", "c"}, handled)
+	require.Len(t, col.filters, 3)
+
+	// The pages are exported in _id order and resume after the checkpoint of the last one
+	p = mcpmongo.FindParams{Limit: 2}
+	first, err := mcpmongo.GenerateCursor(items[1], p)
+	require.NoError(t, err)
+	last, err := mcpmongo.GenerateCursor(items[2], p)
+	require.NoError(t, err)
+	require.Equal(t, []string{first, last}, store.saved)
+}
+
+func TestRunErrors(t *testing.T) {
+	handle := func(context.Context, []bson.Raw) error { return nil }
+	_, err := Run(context.Background(), Job{Handle: handle})
+	require.EqualError(t, err, "Checkpoints can't be nil")
+	_, err = Run(context.Background(), Job{Checkpoints: &checkpointStore{}})
+	require.EqualError(t, err, "Handle can't be nil")
+
+	// The errors that aren't transient aren't retried
+	col := &fakeCollection{errs: []error{errors.New("unauthorized")}}
+	retryPolicy := &mcpmongo.RetryPolicy{MaxAttempts: 3}
+	_, err = Run(context.Background(), Job{Params: mcpmongo.FindParams{Collection: col, Limit: 2, RetryPolicy: retryPolicy}, Checkpoints: &checkpointStore{}, Handle: handle})
+	require.EqualError(t, err, "unauthorized")
+	require.Len(t, col.filters, 1)
+
+	// A page is attempted MaxAttempts times at most
+	transient := mongo.CommandError{Labels: []string{"RetryableReadError"}}
+	col = &fakeCollection{errs: []error{transient, transient, transient}}
+	_, err = Run(context.Background(), Job{Params: mcpmongo.FindParams{Collection: col, Limit: 2, RetryPolicy: retryPolicy}, Checkpoints: &checkpointStore{}, Handle: handle})
+	require.Equal(t, transient, err)
+	require.Len(t, col.filters, 3)
+}
+
+func TestRunIgnoresPagingParams(t *testing.T) {
+	items := []item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	run := func(p mcpmongo.FindParams) (*fakeCollection, *checkpointStore, []string) {
+		col := &fakeCollection{batches: [][]interface{}{{items[0], items[1], items[2]}, {items[2]}}}
+		p.Collection = col
+		p.Limit = 2
+		store := &checkpointStore{}
+		var handled []string
+		_, err := Run(context.Background(), Job{
+			Params:      p,
+			Checkpoints: store,
+			Handle: func(_ context.Context, docs []bson.Raw) error {
+				for _, doc := range docs {
+					handled = append(handled, doc.Lookup("name").StringValue())
+				}
+				return nil
+			},
+		})
+		require.NoError(t, err)
+		return col, store, handled
+	}
+	expectedCol, expectedStore, expectedHandled := run(mcpmongo.FindParams{})
+
+	for _, tc := range []struct {
+		name string
+		p    mcpmongo.FindParams
+	}{
+		{name: "the anchor", p: mcpmongo.FindParams{IncludeAnchor: true}},
+		{name: "the skipped pages", p: mcpmongo.FindParams{SkipPages: 2}},
+		{name: "the dedup window", p: mcpmongo.FindParams{DedupWindow: 2}},
+		{name: "the DecodeFunc", p: mcpmongo.FindParams{DecodeFunc: func(bson.Raw) (interface{}, error) {
+			return nil, errors.New("decoded")
+		}}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			col, store, handled := run(tc.p)
+			require.Equal(t, expectedCol.filters, col.filters)
+			require.Equal(t, expectedCol.skips, col.skips)
+			require.Equal(t, expectedStore.saved, store.saved)
+			require.Equal(t, expectedHandled, handled)
+		})
+	}
+}
+
+func TestRunProgressAndWatchdog(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now = func() time.Time { return clock }
+	defer func() { now = time.Now }()
+
+	items := []item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	// The page queries take 1s, then 4s
+	latency := time.Second
+	col := &fakeCollection{
+		batches: [][]interface{}{{items[0], items[1]}, {items[1], items[2]}, {items[2]}},
+		onFind: func() {
+			clock = clock.Add(latency)
+			latency *= 4
+		},
+	}
+	store := &checkpointStore{}
+
+	var progress []Progress
+	exported, err := Run(context.Background(), Job{
+		Params:            mcpmongo.FindParams{Collection: col, Limit: 1},
+		Checkpoints:       store,
+		Handle:            func(context.Context, []bson.Raw) error { return nil },
+		OnProgress:        func(_ context.Context, p Progress) { progress = append(progress, p) },
+		ExpectedDocuments: 3,
+		ProgressWindow:    2,
+		MaxPageLatency:    2 * time.Second,
+	})
+	require.Equal(t, 2, exported)
+	require.Equal(t, &ErrPageLatencyDegraded{PageLatency: 2500 * time.Millisecond, Checkpoint: store.checkpoint}, err)
+	require.EqualError(t, err, "page latency degraded to 2.5s, export aborted")
+	require.Len(t, store.saved, 2)
+
+	require.Equal(t, []Progress{
+		{Pages: 1, Documents: 1, Checkpoint: store.saved[0], Throughput: 1, PageLatency: time.Second, ETA: 2 * time.Second},
+		{Pages: 2, Documents: 2, Checkpoint: store.saved[1], Throughput: 0.4, PageLatency: 2500 * time.Millisecond, ETA: 2500 * time.Millisecond},
+	}, progress)
+}
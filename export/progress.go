@@ -0,0 +1,99 @@
+package export
+
+import (
+	"fmt"
+	"time"
+)
+
+// defaultProgressWindow is the number of recent pages the throughput and latency are averaged over by default
+const defaultProgressWindow = 10
+
+// now returns the current time, replaced by the tests
+var now = time.Now
+
+type (
+	// Progress reports the progress of a Run after each exported page.
+	Progress struct {
+		// The number of pages exported by the run
+		Pages int
+		// The number of documents exported by the run
+		Documents int
+		// The checkpoint saved after the last page, passing it as FindParams.Next resumes the export after it
+		Checkpoint string
+		// The number of documents exported per second over the recent pages
+		Throughput float64
+		// The average latency of the page queries over the recent pages, retries included
+		PageLatency time.Duration
+		// The estimated time left to export the ExpectedDocuments of the job at the recent throughput, 0 if
+		// unknown
+		ETA time.Duration
+	}
+
+	// ErrPageLatencyDegraded is returned by Run when its watchdog aborted the job, the average latency of the
+	// recent page queries exceeding the MaxPageLatency of the job. The checkpoint of the last exported page is
+	// saved, running the job again resumes after it.
+	ErrPageLatencyDegraded struct {
+		// The average latency of the recent page queries
+		PageLatency time.Duration
+		// The checkpoint saved after the last exported page
+		Checkpoint string
+	}
+
+	// pageSample is the measure of an exported page
+	pageSample struct {
+		documents int
+		latency   time.Duration
+		elapsed   time.Duration
+	}
+
+	// progressTracker averages the throughput and latency of the recent pages of a run
+	progressTracker struct {
+		window   int
+		expected int
+		samples  []pageSample
+		progress Progress
+	}
+)
+
+func (e *ErrPageLatencyDegraded) Error() string {
+	return fmt.Sprintf("page latency degraded to %s, export aborted", e.PageLatency)
+}
+
+func newProgressTracker(job Job) *progressTracker {
+	window := job.ProgressWindow
+	if window <= 0 {
+		window = defaultProgressWindow
+	}
+	return &progressTracker{window: window, expected: job.ExpectedDocuments}
+}
+
+// add records an exported page, the latency of its query and the time elapsed since the previous page, and
+// returns the updated progress
+func (t *progressTracker) add(documents int, checkpoint string, latency time.Duration, elapsed time.Duration) Progress {
+	t.samples = append(t.samples, pageSample{documents: documents, latency: latency, elapsed: elapsed})
+	if len(t.samples) > t.window {
+		t.samples = t.samples[1:]
+	}
+
+	var recentDocuments int
+	var recentLatency, recentElapsed time.Duration
+	for _, s := range t.samples {
+		recentDocuments += s.documents
+		recentLatency += s.latency
+		recentElapsed += s.elapsed
+	}
+
+	t.progress.Pages++
+	t.progress.Documents += documents
+	t.progress.Checkpoint = checkpoint
+	t.progress.PageLatency = recentLatency / time.Duration(len(t.samples))
+	t.progress.Throughput = 0
+	if recentElapsed > 0 {
+		t.progress.Throughput = float64(recentDocuments) / recentElapsed.Seconds()
+	}
+	t.progress.ETA = 0
+	if remaining := t.expected - t.progress.Documents; remaining > 0 && t.progress.Throughput > 0 {
+		t.progress.ETA = time.Duration(float64(remaining) / t.progress.Throughput * float64(time.Second))
+	}
+	return t.progress
+}
@@ -0,0 +1,23 @@
+package grpcutil
+
+type (
+	// ErrInvalidArgument is returned for invalid page_size or page_token values and should be mapped to the
+	// gRPC INVALID_ARGUMENT status code.
+	ErrInvalidArgument struct {
+		field   string
+		message string
+	}
+)
+
+func NewErrInvalidArgument(field string, message string) error {
+	return &ErrInvalidArgument{field: field, message: message}
+}
+
+// Field returns the name of the request field holding the invalid argument
+func (e *ErrInvalidArgument) Field() string {
+	return e.field
+}
+
+func (e *ErrInvalidArgument) Error() string {
+	return e.field + ": " + e.message
+}
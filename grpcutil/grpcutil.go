@@ -0,0 +1,90 @@
+// Package grpcutil converts between the package cursors and the page_size, page_token and next_page_token
+// fields of Google AIP-158 style gRPC list methods.
+// See https://google.aip.dev/158
+package grpcutil
+
+import (
+	"errors"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+)
+
+const (
+	// PageSizeField is the name of the request field holding the page size
+	PageSizeField = "page_size"
+	// PageTokenField is the name of the request field holding the page token
+	PageTokenField = "page_token"
+)
+
+type (
+	// PageSizeLimits holds the page sizes enforced on a list method.
+	PageSizeLimits struct {
+		// The page size used when page_size is 0. page_size is required when 0
+		Default int32
+		// The maximum page size, larger page sizes are coerced to it. No maximum is enforced when 0
+		Max int32
+	}
+)
+
+// ApplyPageToken sets the limit and next cursor of p from the specified page_size and page_token values. A
+// negative page size, a page size of 0 without a Default or a page token the CursorCodec of the mongo.Defaults
+// can't decode returns an ErrInvalidArgument.
+func ApplyPageToken(p mongo.FindParams, pageSize int32, pageToken string, limits PageSizeLimits) (mongo.FindParams, error) {
+	r, err := ParsePageRequest(pageSize, pageToken, limits)
+	if err != nil {
+		return p, err
+	}
+	p.Limit = r.Limit
+	p.Next = r.Next
+	p.Previous = ""
+	return p, nil
+}
+
+// ParsePageRequest returns the mongo.PageRequest for the specified page_size and page_token values. A negative
+// page size, a page size of 0 without a Default or a page token the CursorCodec of the mongo.Defaults can't decode
+// returns an ErrInvalidArgument.
+func ParsePageRequest(pageSize int32, pageToken string, limits PageSizeLimits) (mongo.PageRequest, error) {
+	if pageSize < 0 {
+		return mongo.PageRequest{}, NewErrInvalidArgument(PageSizeField, "must not be negative")
+	}
+	if pageSize == 0 {
+		pageSize = limits.Default
+	}
+	// A limit of 0 would fail in mongo.Find instead of being reported to the client
+	if pageSize <= 0 {
+		return mongo.PageRequest{}, NewErrInvalidArgument(PageSizeField, "is required")
+	}
+	if limits.Max > 0 && pageSize > limits.Max {
+		pageSize = limits.Max
+	}
+
+	// The page token is checked by the codec of the cursors, which may sign or encrypt them
+	if pageToken != "" {
+		codec := mongo.CurrentDefaults().CursorCodec
+		if codec == nil {
+			codec = mongo.Base64CursorCodec{}
+		}
+		if _, err := codec.Decode(pageToken); err != nil {
+			return mongo.PageRequest{}, NewErrInvalidArgument(PageTokenField, "malformed page token")
+		}
+	}
+
+	return mongo.NewPageRequest(int64(pageSize)).WithToken(pageToken, mongo.Forward), nil
+}
+
+// NextPageToken returns the next_page_token for the specified cursor, which is empty when there are no more
+// pages.
+func NextPageToken(c mongo.Cursor) string {
+	if !c.HasNext {
+		return ""
+	}
+	return c.Next
+}
+
+// IsInvalidArgument returns true if err should be mapped to the gRPC INVALID_ARGUMENT status code, i.e. it
+// is an ErrInvalidArgument or a cursor error returned by mongo.Find.
+func IsInvalidArgument(err error) bool {
+	var invalidArgument *ErrInvalidArgument
+	var cursorErr *mongo.CursorError
+	return errors.As(err, &invalidArgument) || errors.As(err, &cursorErr)
+}
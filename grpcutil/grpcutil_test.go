@@ -0,0 +1,134 @@
+package grpcutil
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestApplyPageToken(t *testing.T) {
+	token, err := mongo.GenerateCursor(struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}{ID: primitive.NewObjectID()}, mongo.FindParams{})
+	require.NoError(t, err)
+	limits := PageSizeLimits{Default: 10, Max: 100}
+
+	var cases = []struct {
+		name               string
+		pageSize           int32
+		pageToken          string
+		expectedFindParams mongo.FindParams
+		expectedErr        error
+	}{
+		{
+			name:               "uses the default page size when page size is 0",
+			pageSize:           0,
+			pageToken:          "",
+			expectedFindParams: mongo.FindParams{Limit: 10},
+			expectedErr:        nil,
+		},
+		{
+			name:               "coerces the page size to the maximum",
+			pageSize:           1000,
+			pageToken:          "",
+			expectedFindParams: mongo.FindParams{Limit: 100},
+			expectedErr:        nil,
+		},
+		{
+			name:               "sets the next cursor from the page token",
+			pageSize:           5,
+			pageToken:          token,
+			expectedFindParams: mongo.FindParams{Limit: 5, Next: token},
+			expectedErr:        nil,
+		},
+		{
+			name:               "errors when page size is negative",
+			pageSize:           -1,
+			pageToken:          "",
+			expectedFindParams: mongo.FindParams{},
+			expectedErr:        NewErrInvalidArgument(PageSizeField, "must not be negative"),
+		},
+		{
+			name:               "errors when page token is not base64",
+			pageSize:           5,
+			pageToken:          "XXXXXaGVsbG8=",
+			expectedFindParams: mongo.FindParams{},
+			expectedErr:        NewErrInvalidArgument(PageTokenField, "malformed page token"),
+		},
+		{
+			name:               "errors when page token is not bson",
+			pageSize:           5,
+			pageToken:          "aGVsbG8",
+			expectedFindParams: mongo.FindParams{},
+			expectedErr:        NewErrInvalidArgument(PageTokenField, "malformed page token"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := ApplyPageToken(mongo.FindParams{}, tc.pageSize, tc.pageToken, limits)
+			require.Equal(t, tc.expectedFindParams, p)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestParsePageRequest(t *testing.T) {
+	r, err := ParsePageRequest(0, "", PageSizeLimits{Default: 10})
+	require.NoError(t, err)
+	require.Equal(t, mongo.NewPageRequest(10), r)
+
+	_, err = ParsePageRequest(0, "", PageSizeLimits{Max: 100})
+	require.Equal(t, NewErrInvalidArgument(PageSizeField, "is required"), err)
+	require.True(t, IsInvalidArgument(err))
+
+	_, err = ParsePageRequest(5, "aGVsbG8", PageSizeLimits{})
+	require.Equal(t, NewErrInvalidArgument(PageTokenField, "malformed page token"), err)
+
+	// The page tokens are decoded by the codec of the cursors
+	previous := mongo.CurrentDefaults()
+	d := previous
+	d.CursorCodec = prefixCodec{}
+	mongo.SetDefaults(d)
+	t.Cleanup(func() { mongo.SetDefaults(previous) })
+	token, err := mongo.GenerateCursor(struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}{ID: primitive.NewObjectID()}, mongo.FindParams{})
+	require.NoError(t, err)
+	r, err = ParsePageRequest(5, token, PageSizeLimits{})
+	require.NoError(t, err)
+	require.Equal(t, token, r.Next)
+	_, err = ParsePageRequest(5, strings.TrimPrefix(token, "v1."), PageSizeLimits{})
+	require.Equal(t, NewErrInvalidArgument(PageTokenField, "malformed page token"), err)
+}
+
+// prefixCodec wraps the default codec to tell its cursors apart, as a signing codec would
+type prefixCodec struct{}
+
+func (prefixCodec) Encode(cursorData bson.D) (string, error) {
+	cursor, err := mongo.Base64CursorCodec{}.Encode(cursorData)
+	return "v1." + cursor, err
+}
+
+func (prefixCodec) Decode(cursor string) (bson.D, error) {
+	if !strings.HasPrefix(cursor, "v1.") {
+		return nil, errors.New("unknown cursor version")
+	}
+	return mongo.Base64CursorCodec{}.Decode(strings.TrimPrefix(cursor, "v1."))
+}
+
+func TestNextPageToken(t *testing.T) {
+	require.Equal(t, "", NextPageToken(mongo.Cursor{Next: "abc", HasNext: false}))
+	require.Equal(t, "abc", NextPageToken(mongo.Cursor{Next: "abc", HasNext: true}))
+}
+
+func TestIsInvalidArgument(t *testing.T) {
+	require.True(t, IsInvalidArgument(NewErrInvalidArgument(PageSizeField, "must not be negative")))
+	require.True(t, IsInvalidArgument(fmt.Errorf("wrapped: %w", &mongo.CursorError{})))
+	require.False(t, IsInvalidArgument(errors.New("error")))
+}
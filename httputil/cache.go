@@ -0,0 +1,86 @@
+package httputil
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+)
+
+type (
+	// CacheParams holds the caching policy of a paginated response.
+	CacheParams struct {
+		// How long the response may be cached, Cache-Control is set to no-cache when zero so that the response is
+		// revalidated with its ETag
+		MaxAge time.Duration
+		// Whether the response may only be cached by the client, e.g. when the page depends on the user
+		Private bool
+		// The latest modification time of the documents of the page, e.g. the greatest updatedAt of the page or of
+		// its boundary documents when sorted on it. Last-Modified isn't written when zero
+		LastModified time.Time
+	}
+)
+
+// PageETag returns a weak ETag computed from the checksum of the JSON encoded results of a page and of its cursor,
+// so that it changes whenever the page or its links do.
+func PageETag(cursor mongo.Cursor, results interface{}) (string, error) {
+	h := sha256.New()
+	err := json.NewEncoder(h).Encode(results)
+	if err != nil {
+		return "", fmt.Errorf("could not compute the page checksum: %s", err)
+	}
+	fmt.Fprintf(h, "%q %q %t %t %d", cursor.Previous, cursor.Next, cursor.HasPrevious, cursor.HasNext, cursor.Count)
+	return fmt.Sprintf(`W/"%s"`, hex.EncodeToString(h.Sum(nil)[:16])), nil
+}
+
+// WriteCacheHeaders sets the Cache-Control, ETag and Last-Modified headers of the response for the page of results
+// and its cursor. It returns true when the request's If-None-Match or, lacking one, If-Modified-Since header shows
+// that the client already holds the page, in which case the caller should respond with
+// http.StatusNotModified without a body.
+func WriteCacheHeaders(w http.ResponseWriter, r *http.Request, cursor mongo.Cursor, results interface{}, cp CacheParams) (bool, error) {
+	etag, err := PageETag(cursor, results)
+	if err != nil {
+		return false, err
+	}
+
+	visibility := "public"
+	if cp.Private {
+		visibility = "private"
+	}
+	cacheControl := visibility + ", no-cache"
+	if cp.MaxAge > 0 {
+		cacheControl = visibility + ", max-age=" + strconv.FormatInt(int64(cp.MaxAge/time.Second), 10)
+	}
+	w.Header().Set("Cache-Control", cacheControl)
+	w.Header().Set("ETag", etag)
+	lastModified := cp.LastModified.UTC().Truncate(time.Second)
+	if !cp.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag), nil
+	}
+	if ifModifiedSince := r.Header.Get("If-Modified-Since"); ifModifiedSince != "" && !cp.LastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		return err == nil && !lastModified.After(since), nil
+	}
+	return false, nil
+}
+
+// etagMatches returns whether the If-None-Match header lists etag, comparing weakly
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package httputil
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageETag(t *testing.T) {
+	cursor := mongo.Cursor{Next: "n1", HasNext: true}
+	etag, err := PageETag(cursor, []string{"a", "b"})
+	require.NoError(t, err)
+	require.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+
+	same, err := PageETag(cursor, []string{"a", "b"})
+	require.NoError(t, err)
+	require.Equal(t, etag, same)
+
+	otherResults, err := PageETag(cursor, []string{"a", "c"})
+	require.NoError(t, err)
+	require.NotEqual(t, etag, otherResults)
+
+	otherCursor, err := PageETag(mongo.Cursor{Next: "n2", HasNext: true}, []string{"a", "b"})
+	require.NoError(t, err)
+	require.NotEqual(t, etag, otherCursor)
+
+	_, err = PageETag(cursor, []interface{}{make(chan int)})
+	require.Error(t, err)
+}
+
+func TestWriteCacheHeaders(t *testing.T) {
+	cursor := mongo.Cursor{Next: "n1", HasNext: true}
+	results := []string{"a", "b"}
+	etag, err := PageETag(cursor, results)
+	require.NoError(t, err)
+	lastModified := time.Date(2024, 3, 1, 10, 30, 0, 500, time.UTC)
+
+	var cases = []struct {
+		name                 string
+		headers              map[string]string
+		cacheParams          CacheParams
+		expectedCacheControl string
+		expectedLastModified string
+		expectedNotModified  bool
+	}{
+		{
+			name:                 "revalidates public responses without max age",
+			cacheParams:          CacheParams{},
+			expectedCacheControl: "public, no-cache",
+		},
+		{
+			name:                 "writes the max age and last modified time of private responses",
+			cacheParams:          CacheParams{MaxAge: time.Minute, Private: true, LastModified: lastModified},
+			expectedCacheControl: "private, max-age=60",
+			expectedLastModified: "Fri, 01 Mar 2024 10:30:00 GMT",
+		},
+		{
+			name:                 "matches the ETag of the page",
+			headers:              map[string]string{"If-None-Match": `"other", ` + etag[2:]},
+			cacheParams:          CacheParams{LastModified: lastModified},
+			expectedCacheControl: "public, no-cache",
+			expectedLastModified: "Fri, 01 Mar 2024 10:30:00 GMT",
+			expectedNotModified:  true,
+		},
+		{
+			name:                 "prefers If-None-Match to If-Modified-Since",
+			headers:              map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Fri, 01 Mar 2024 10:30:00 GMT"},
+			cacheParams:          CacheParams{LastModified: lastModified},
+			expectedCacheControl: "public, no-cache",
+			expectedLastModified: "Fri, 01 Mar 2024 10:30:00 GMT",
+		},
+		{
+			name:                 "compares the last modified time",
+			headers:              map[string]string{"If-Modified-Since": "Fri, 01 Mar 2024 10:30:00 GMT"},
+			cacheParams:          CacheParams{LastModified: lastModified},
+			expectedCacheControl: "public, no-cache",
+			expectedLastModified: "Fri, 01 Mar 2024 10:30:00 GMT",
+			expectedNotModified:  true,
+		},
+		{
+			name:                 "detects pages modified since",
+			headers:              map[string]string{"If-Modified-Since": "Fri, 01 Mar 2024 10:29:59 GMT"},
+			cacheParams:          CacheParams{LastModified: lastModified},
+			expectedCacheControl: "public, no-cache",
+			expectedLastModified: "Fri, 01 Mar 2024 10:30:00 GMT",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/items", nil)
+			for name, value := range tc.headers {
+				r.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			notModified, err := WriteCacheHeaders(w, r, cursor, results, tc.cacheParams)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedNotModified, notModified)
+			require.Equal(t, tc.expectedCacheControl, w.Header().Get("Cache-Control"))
+			require.Equal(t, etag, w.Header().Get("ETag"))
+			require.Equal(t, tc.expectedLastModified, w.Header().Get("Last-Modified"))
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package httputil
+
+type (
+	ErrInvalidPageRequest struct {
+		message string
+	}
+)
+
+func NewErrInvalidPageRequest(message string) error {
+	return &ErrInvalidPageRequest{message: message}
+}
+
+func (e *ErrInvalidPageRequest) Error() string {
+	return e.message
+}
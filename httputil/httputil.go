@@ -0,0 +1,126 @@
+// Package httputil provides the glue needed to expose paginated mongo queries over HTTP: parsing the
+// pagination query parameters of a request, writing RFC 5988 Link headers and the caching headers for a Cursor.
+package httputil
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+)
+
+const (
+	// LimitParam is the name of the query parameter holding the page size
+	LimitParam = "limit"
+	// NextParam is the name of the query parameter holding the next page cursor
+	NextParam = "next"
+	// PreviousParam is the name of the query parameter holding the previous page cursor
+	PreviousParam = "previous"
+	// SortParam is the name of the query parameter holding the sort expression
+	SortParam = "sort"
+
+	defaultLimit = 20
+)
+
+type (
+	// PageRequest holds the pagination parameters parsed from an HTTP request.
+	PageRequest struct {
+		// The number of results to fetch, defaults to 20 when not specified
+		Limit int64
+		// The cursor to start querying the next page
+		Next string
+		// The cursor to start querying the previous page
+		Previous string
+		// The raw sort expression, e.g. "-createdAt,name"
+		Sort string
+	}
+)
+
+// ParsePageRequest parses the limit, next, previous and sort query parameters of the specified request.
+func ParsePageRequest(r *http.Request) (PageRequest, error) {
+	query := r.URL.Query()
+
+	pr := PageRequest{
+		Limit:    defaultLimit,
+		Next:     query.Get(NextParam),
+		Previous: query.Get(PreviousParam),
+		Sort:     strings.TrimSpace(query.Get(SortParam)),
+	}
+
+	if rawLimit := query.Get(LimitParam); rawLimit != "" {
+		limit, err := strconv.ParseInt(rawLimit, 10, 64)
+		if err != nil || limit <= 0 {
+			return PageRequest{}, NewErrInvalidPageRequest(fmt.Sprintf("invalid %s %q: a positive integer is required", LimitParam, rawLimit))
+		}
+		pr.Limit = limit
+	}
+
+	if pr.Next != "" && pr.Previous != "" {
+		return PageRequest{}, NewErrInvalidPageRequest(fmt.Sprintf("%s and %s can't be specified together", NextParam, PreviousParam))
+	}
+
+	return pr, nil
+}
+
+// MongoPageRequest returns the mongo.PageRequest for the limit and cursors of pr. The sort expression isn't
+// interpreted and must be applied by the caller, e.g. with mongo.ParseSort.
+func (pr PageRequest) MongoPageRequest() mongo.PageRequest {
+	return mongo.PageRequest{
+		Limit:    pr.Limit,
+		Next:     pr.Next,
+		Previous: pr.Previous,
+	}
+}
+
+// EncodePageRequest returns the query parameters requesting the page of r, the inverse of ParsePageRequest
+func EncodePageRequest(r mongo.PageRequest) url.Values {
+	query := url.Values{}
+	if r.Limit > 0 {
+		query.Set(LimitParam, strconv.FormatInt(r.Limit, 10))
+	}
+	token, direction := r.Token()
+	if token != "" {
+		if direction == mongo.Backward {
+			query.Set(PreviousParam, token)
+		} else {
+			query.Set(NextParam, token)
+		}
+	}
+	return query
+}
+
+// WriteLinkHeaders adds RFC 5988 Link headers with rel="next" and rel="prev" to the response for the pages
+// available from the specified cursor. The cursor values are set on baseURL, preserving its other query
+// parameters.
+func WriteLinkHeaders(w http.ResponseWriter, baseURL string, cursor mongo.Cursor) error {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid base URL: %s", err)
+	}
+
+	if cursor.HasNext && cursor.Next != "" {
+		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=%q", pageURL(u, NextParam, cursor.Next), "next"))
+	}
+	if cursor.HasPrevious && cursor.Previous != "" {
+		w.Header().Add("Link", fmt.Sprintf("<%s>; rel=%q", pageURL(u, PreviousParam, cursor.Previous), "prev"))
+	}
+	return nil
+}
+
+// pageURL returns u with its cursor query parameters replaced by param=value. The cursor parameters are
+// only removed when param is empty.
+func pageURL(u *url.URL, param string, value string) string {
+	query := u.Query()
+	query.Del(NextParam)
+	query.Del(PreviousParam)
+	if param != "" {
+		query.Set(param, value)
+	}
+
+	link := *u
+	link.RawQuery = query.Encode()
+	return link.String()
+}
@@ -0,0 +1,104 @@
+package httputil
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageRequest(t *testing.T) {
+	var cases = []struct {
+		name                string
+		url                 string
+		expectedPageRequest PageRequest
+		expectedErr         error
+	}{
+		{
+			name:                "uses the default limit when none is specified",
+			url:                 "/items",
+			expectedPageRequest: PageRequest{Limit: 20},
+			expectedErr:         nil,
+		},
+		{
+			name:                "parses all the pagination parameters",
+			url:                 "/items?limit=5&next=abc&sort=-createdAt,name",
+			expectedPageRequest: PageRequest{Limit: 5, Next: "abc", Sort: "-createdAt,name"},
+			expectedErr:         nil,
+		},
+		{
+			name:                "parses the previous cursor",
+			url:                 "/items?previous=abc",
+			expectedPageRequest: PageRequest{Limit: 20, Previous: "abc"},
+			expectedErr:         nil,
+		},
+		{
+			name:                "errors when limit is not a number",
+			url:                 "/items?limit=abc",
+			expectedPageRequest: PageRequest{},
+			expectedErr:         NewErrInvalidPageRequest(`invalid limit "abc": a positive integer is required`),
+		},
+		{
+			name:                "errors when limit is less than 1",
+			url:                 "/items?limit=0",
+			expectedPageRequest: PageRequest{},
+			expectedErr:         NewErrInvalidPageRequest(`invalid limit "0": a positive integer is required`),
+		},
+		{
+			name:                "errors when both next and previous are specified",
+			url:                 "/items?next=abc&previous=def",
+			expectedPageRequest: PageRequest{},
+			expectedErr:         NewErrInvalidPageRequest("next and previous can't be specified together"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			pr, err := ParsePageRequest(httptest.NewRequest("GET", tc.url, nil))
+			require.Equal(t, tc.expectedPageRequest, pr)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestEncodePageRequest(t *testing.T) {
+	r, err := ParsePageRequest(httptest.NewRequest("GET", "/items?limit=5&previous=abc", nil))
+	require.NoError(t, err)
+	mr := r.MongoPageRequest()
+	require.Equal(t, mongo.PageRequest{Limit: 5, Previous: "abc"}, mr)
+	require.Equal(t, "limit=5&previous=abc", EncodePageRequest(mr).Encode())
+	require.Equal(t, "limit=5&next=def", EncodePageRequest(mr.WithToken("def", mongo.Forward)).Encode())
+}
+
+func TestWriteLinkHeaders(t *testing.T) {
+	var cases = []struct {
+		name          string
+		baseURL       string
+		cursor        mongo.Cursor
+		expectedLinks []string
+	}{
+		{
+			name:          "writes no links when there are no other pages",
+			baseURL:       "https://example.com/items?limit=2",
+			cursor:        mongo.Cursor{},
+			expectedLinks: nil,
+		},
+		{
+			name:    "writes next and prev links preserving other query parameters",
+			baseURL: "https://example.com/items?limit=2&next=old",
+			cursor:  mongo.Cursor{Next: "n1", HasNext: true, Previous: "p1", HasPrevious: true},
+			expectedLinks: []string{
+				`<https://example.com/items?limit=2&next=n1>; rel="next"`,
+				`<https://example.com/items?limit=2&previous=p1>; rel="prev"`,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := WriteLinkHeaders(w, tc.baseURL, tc.cursor)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedLinks, w.Header().Values("Link"))
+		})
+	}
+}
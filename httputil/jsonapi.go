@@ -0,0 +1,56 @@
+package httputil
+
+import (
+	"net/url"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+)
+
+type (
+	// JSONAPILinks holds the pagination links of a JSON:API document.
+	// See https://jsonapi.org/format/#fetching-pagination
+	JSONAPILinks struct {
+		Self  string `json:"self"`
+		First string `json:"first"`
+		Prev  string `json:"prev,omitempty"`
+		Next  string `json:"next,omitempty"`
+	}
+
+	// JSONAPIMeta holds the pagination meta information of a JSON:API document.
+	JSONAPIMeta struct {
+		// Total count of documents matching filter - only set if the cursor was computed with CountTotal
+		Count int `json:"count,omitempty"`
+		// "gte" when more documents than Count match the filter, see mongo.FindParams.CountLimit
+		CountRelation string `json:"countRelation,omitempty"`
+	}
+
+	// JSONAPIPagination holds the top level links and meta members of a paginated JSON:API document.
+	JSONAPIPagination struct {
+		Links JSONAPILinks `json:"links"`
+		Meta  JSONAPIMeta  `json:"meta"`
+	}
+)
+
+// NewJSONAPIPagination converts the specified cursor and request URL into the links and meta members of a
+// JSON:API document. The first link is the request URL without its next and previous query parameters.
+func NewJSONAPIPagination(requestURL *url.URL, cursor mongo.Cursor) JSONAPIPagination {
+	links := JSONAPILinks{
+		Self:  requestURL.String(),
+		First: pageURL(requestURL, "", ""),
+	}
+	if cursor.HasNext && cursor.Next != "" {
+		links.Next = pageURL(requestURL, NextParam, cursor.Next)
+	}
+	if cursor.HasPrevious && cursor.Previous != "" {
+		links.Prev = pageURL(requestURL, PreviousParam, cursor.Previous)
+	}
+
+	meta := JSONAPIMeta{Count: cursor.Count}
+	if !cursor.HasExactCount() {
+		meta.CountRelation = "gte"
+	}
+	return JSONAPIPagination{
+		Links: links,
+		Meta:  meta,
+	}
+}
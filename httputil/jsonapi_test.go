@@ -0,0 +1,65 @@
+package httputil
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJSONAPIPagination(t *testing.T) {
+	var cases = []struct {
+		name               string
+		requestURL         string
+		cursor             mongo.Cursor
+		expectedPagination JSONAPIPagination
+	}{
+		{
+			name:       "only sets self and first links on a single page",
+			requestURL: "https://example.com/items?limit=2",
+			cursor:     mongo.Cursor{Count: 2},
+			expectedPagination: JSONAPIPagination{
+				Links: JSONAPILinks{
+					Self:  "https://example.com/items?limit=2",
+					First: "https://example.com/items?limit=2",
+				},
+				Meta: JSONAPIMeta{Count: 2},
+			},
+		},
+		{
+			name:       "sets prev and next links when there are other pages",
+			requestURL: "https://example.com/items?limit=2&next=n0",
+			cursor:     mongo.Cursor{Next: "n1", HasNext: true, Previous: "p1", HasPrevious: true, Count: 6},
+			expectedPagination: JSONAPIPagination{
+				Links: JSONAPILinks{
+					Self:  "https://example.com/items?limit=2&next=n0",
+					First: "https://example.com/items?limit=2",
+					Prev:  "https://example.com/items?limit=2&previous=p1",
+					Next:  "https://example.com/items?limit=2&next=n1",
+				},
+				Meta: JSONAPIMeta{Count: 6},
+			},
+		},
+		{
+			name:       "sets the count relation of a truncated count",
+			requestURL: "https://example.com/items?limit=2",
+			cursor:     mongo.Cursor{Next: "n1", HasNext: true, Count: 10000, CountRelation: mongo.CountRelationGte},
+			expectedPagination: JSONAPIPagination{
+				Links: JSONAPILinks{
+					Self:  "https://example.com/items?limit=2",
+					First: "https://example.com/items?limit=2",
+					Next:  "https://example.com/items?limit=2&next=n1",
+				},
+				Meta: JSONAPIMeta{Count: 10000, CountRelation: "gte"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.requestURL)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPagination, NewJSONAPIPagination(u, tc.cursor))
+		})
+	}
+}
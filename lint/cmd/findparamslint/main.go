@@ -0,0 +1,15 @@
+// Command findparamslint checks for common misuse of mongocursorpagination FindParams.
+//
+// Usage:
+//
+//	go run github.com/qlik-oss/mongocursorpagination/lint/cmd/findparamslint ./...
+package main
+
+import (
+	"github.com/qlik-oss/mongocursorpagination/lint"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(lint.Analyzer)
+}
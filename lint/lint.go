@@ -0,0 +1,242 @@
+// Package lint provides a vet-style analyzer flagging common misuse of the mongocursorpagination
+// FindParams in consumer code.
+package lint
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+var findParamsPackages = map[string]bool{
+	"github.com/qlik-oss/mongocursorpagination/mongo":   true,
+	"github.com/qlik-oss/mongocursorpagination/mgo":     true,
+	"github.com/qlik-oss/mongocursorpagination/mongov2": true,
+}
+
+// Analyzer reports FindParams literals that:
+// 1. Don't set a Limit, neither in the literal nor later on the variable it is assigned to, which makes Find fail at runtime
+// 2. Have PaginatedFields and SortOrders literals of different lengths
+// 3. Exclude _id from the Projection without a TiebreakerField or PaginatedFieldIsUnique, as _id is then required to build the cursors
+// 4. Set CountTotal within a loop, which makes an additional count query on every iteration
+var Analyzer = &analysis.Analyzer{
+	Name:     "findparamslint",
+	Doc:      "check for common misuse of mongocursorpagination FindParams",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{(*ast.CompositeLit)(nil)}
+	insp.WithStack(nodeFilter, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+		lit := n.(*ast.CompositeLit)
+		if !isFindParams(pass.TypesInfo.TypeOf(lit)) {
+			return true
+		}
+
+		fields := keyedFields(lit)
+		if fields == nil {
+			// Unkeyed literals are already flagged by go vet's composites check
+			return true
+		}
+
+		if _, ok := fields["Limit"]; !ok && !limitSetLater(pass, lit, stack) {
+			pass.Reportf(lit.Pos(), "FindParams without a Limit: Find requires a limit of at least 1")
+		}
+
+		checkSortOrders(pass, fields)
+		checkProjection(pass, fields)
+
+		if countTotal, ok := fields["CountTotal"]; ok && isConstant(pass, countTotal, constant.MakeBool(true)) && inLoop(stack) {
+			pass.Reportf(countTotal.Pos(), "CountTotal within a loop makes an additional count query on every iteration")
+		}
+		return true
+	})
+
+	return nil, nil
+}
+
+// isFindParams returns true if t is the FindParams type of one of the mongocursorpagination packages
+func isFindParams(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && findParamsPackages[obj.Pkg().Path()] && obj.Name() == "FindParams"
+}
+
+// keyedFields returns the values of a keyed struct literal by field name, or nil if the literal is unkeyed
+func keyedFields(lit *ast.CompositeLit) map[string]ast.Expr {
+	fields := make(map[string]ast.Expr, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil
+		}
+		if key, ok := kv.Key.(*ast.Ident); ok {
+			fields[key.Name] = kv.Value
+		}
+	}
+	return fields
+}
+
+func checkSortOrders(pass *analysis.Pass, fields map[string]ast.Expr) {
+	paginatedFields, ok := fields["PaginatedFields"].(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+	sortOrders, ok := fields["SortOrders"].(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+	if len(paginatedFields.Elts) != len(sortOrders.Elts) {
+		pass.Reportf(sortOrders.Pos(), "SortOrders has %d elements but PaginatedFields has %d", len(sortOrders.Elts), len(paginatedFields.Elts))
+	}
+}
+
+// limitSetLater returns true if lit is assigned to a variable whose Limit field is set later in the enclosing function
+func limitSetLater(pass *analysis.Pass, lit *ast.CompositeLit, stack []ast.Node) bool {
+	obj, body := assignedVar(pass, lit, stack)
+	if obj == nil || body == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || found || assign.Pos() < lit.End() {
+			return !found
+		}
+		for _, lhs := range assign.Lhs {
+			sel, ok := lhs.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "Limit" {
+				continue
+			}
+			if ident, ok := sel.X.(*ast.Ident); ok && pass.TypesInfo.ObjectOf(ident) == obj {
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// assignedVar returns the variable lit, or its address, is assigned to along with the body of the enclosing function
+func assignedVar(pass *analysis.Pass, lit *ast.CompositeLit, stack []ast.Node) (types.Object, *ast.BlockStmt) {
+	var expr ast.Expr = lit
+	var obj types.Object
+	var body *ast.BlockStmt
+	for i := len(stack) - 2; i >= 0 && body == nil; i-- {
+		switch node := stack[i].(type) {
+		case *ast.UnaryExpr:
+			if node.Op != token.AND || obj != nil {
+				return nil, nil
+			}
+			expr = node
+		case *ast.AssignStmt:
+			if obj != nil {
+				continue
+			}
+			for j, rhs := range node.Rhs {
+				if rhs == expr && j < len(node.Lhs) {
+					if ident, ok := node.Lhs[j].(*ast.Ident); ok {
+						obj = pass.TypesInfo.ObjectOf(ident)
+					}
+				}
+			}
+			if obj == nil {
+				return nil, nil
+			}
+		case *ast.ValueSpec:
+			if obj != nil {
+				continue
+			}
+			for j, value := range node.Values {
+				if value == expr && j < len(node.Names) {
+					obj = pass.TypesInfo.ObjectOf(node.Names[j])
+				}
+			}
+			if obj == nil {
+				return nil, nil
+			}
+		case *ast.FuncDecl:
+			body = node.Body
+		case *ast.FuncLit:
+			body = node.Body
+		default:
+			if obj == nil {
+				return nil, nil
+			}
+		}
+	}
+	return obj, body
+}
+
+// checkProjection reports _id exclusions in bson.M{"_id": 0} and bson.D{{"_id", 0}} style projection literals,
+// unless a TiebreakerField or PaginatedFieldIsUnique makes the cursors independent of _id
+func checkProjection(pass *analysis.Pass, fields map[string]ast.Expr) {
+	projection, ok := fields["Projection"].(*ast.CompositeLit)
+	if !ok {
+		return
+	}
+	if _, ok := fields["TiebreakerField"]; ok {
+		return
+	}
+	if unique, ok := fields["PaginatedFieldIsUnique"]; ok && isConstant(pass, unique, constant.MakeBool(true)) {
+		return
+	}
+	for _, elt := range projection.Elts {
+		var key, value ast.Expr
+		switch e := elt.(type) {
+		case *ast.KeyValueExpr:
+			key, value = e.Key, e.Value
+		case *ast.CompositeLit:
+			if len(e.Elts) != 2 {
+				continue
+			}
+			key, value = e.Elts[0], e.Elts[1]
+			if kv, ok := key.(*ast.KeyValueExpr); ok {
+				key = kv.Value
+			}
+			if kv, ok := value.(*ast.KeyValueExpr); ok {
+				value = kv.Value
+			}
+		default:
+			continue
+		}
+		if isConstant(pass, key, constant.MakeString("_id")) &&
+			(isConstant(pass, value, constant.MakeInt64(0)) || isConstant(pass, value, constant.MakeBool(false))) {
+			pass.Reportf(elt.Pos(), "Projection excludes _id which is required to generate the pagination cursors")
+		}
+	}
+}
+
+func isConstant(pass *analysis.Pass, expr ast.Expr, expected constant.Value) bool {
+	tv, ok := pass.TypesInfo.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != expected.Kind() {
+		return false
+	}
+	return constant.Compare(tv.Value, token.EQL, expected)
+}
+
+func inLoop(stack []ast.Node) bool {
+	for i := len(stack) - 1; i >= 0; i-- {
+		switch stack[i].(type) {
+		case *ast.ForStmt, *ast.RangeStmt:
+			return true
+		case *ast.FuncDecl, *ast.FuncLit:
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package a
+
+import (
+	"github.com/qlik-oss/mongocursorpagination/mgo"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/qlik-oss/mongocursorpagination/mongov2"
+	"go.mongodb.org/mongo-driver/bson"
+	bsonv2 "go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func valid() {
+	_ = mongo.FindParams{
+		Limit:           10,
+		PaginatedFields: []string{"name", "createdAt"},
+		SortOrders:      []int{1, -1},
+		Projection:      bson.M{"name": 1, "_id": 1},
+		CountTotal:      true,
+	}
+}
+
+func missingLimit() {
+	_ = mongo.FindParams{PaginatedField: "name"} // want "FindParams without a Limit"
+	_ = &mgo.FindParams{}                        // want "FindParams without a Limit"
+	_ = mongov2.FindParams{}                     // want "FindParams without a Limit"
+
+	p := mongo.FindParams{PaginatedField: "name"} // want "FindParams without a Limit"
+	p.PaginatedField = "createdAt"
+	_ = p
+}
+
+func limitSetLater(limit int64) {
+	p := mongo.FindParams{PaginatedField: "name"}
+	p.Limit = limit
+	_ = p
+
+	var q = &mongov2.FindParams{}
+	q.Limit = limit
+	_ = q
+}
+
+func sortOrdersMismatch() {
+	_ = mgo.FindParams{
+		Limit:           10,
+		PaginatedFields: []string{"name", "createdAt"},
+		SortOrders:      []int{1}, // want "SortOrders has 1 elements but PaginatedFields has 2"
+	}
+}
+
+func projectionExcludesID() {
+	_ = mongo.FindParams{
+		Limit:      10,
+		Projection: bson.M{"name": 1, "_id": 0}, // want "Projection excludes _id"
+	}
+	_ = mongo.FindParams{
+		Limit:      10,
+		Projection: bson.D{{"_id", false}}, // want "Projection excludes _id"
+	}
+	_ = mongo.FindParams{
+		Limit:      10,
+		Projection: bson.D{{Key: "_id", Value: 0}}, // want "Projection excludes _id"
+	}
+	_ = mongov2.FindParams{
+		Limit:      10,
+		Projection: bsonv2.M{"_id": 0}, // want "Projection excludes _id"
+	}
+	_ = mongo.FindParams{
+		Limit:                  10,
+		Projection:             bson.M{"_id": 0}, // want "Projection excludes _id"
+		PaginatedFieldIsUnique: false,
+	}
+}
+
+func projectionExcludesIDWithoutIDCursors() {
+	_ = mongo.FindParams{
+		Limit:           10,
+		Projection:      bson.M{"name": 1, "_id": 0},
+		TiebreakerField: "sequence",
+	}
+	_ = mongo.FindParams{
+		Limit:                  10,
+		PaginatedField:         "email",
+		PaginatedFieldIsUnique: true,
+		Projection:             bson.D{{Key: "_id", Value: 0}},
+	}
+}
+
+func countTotalInLoop(pages int) {
+	for i := 0; i < pages; i++ {
+		_ = mongo.FindParams{
+			Limit:      10,
+			CountTotal: true, // want "CountTotal within a loop"
+		}
+		_ = func() mongo.FindParams {
+			return mongo.FindParams{Limit: 10, CountTotal: true}
+		}
+	}
+}
@@ -0,0 +1,8 @@
+package mgo
+
+type FindParams struct {
+	Limit           int
+	PaginatedFields []string
+	SortOrders      []int
+	CountTotal      bool
+}
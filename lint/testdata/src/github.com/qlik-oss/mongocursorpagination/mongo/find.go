@@ -0,0 +1,13 @@
+package mongo
+
+type FindParams struct {
+	Limit           int64
+	PaginatedField  string
+	PaginatedFields []string
+	SortOrders      []int
+	Projection      interface{}
+	CountTotal      bool
+
+	TiebreakerField        string
+	PaginatedFieldIsUnique bool
+}
@@ -0,0 +1,10 @@
+package mongov2
+
+type FindParams struct {
+	Limit           int64
+	PaginatedField  string
+	PaginatedFields []string
+	SortOrders      []int
+	Projection      interface{}
+	CountTotal      bool
+}
@@ -0,0 +1,10 @@
+package bson
+
+type (
+	M map[string]interface{}
+	E struct {
+		Key   string
+		Value interface{}
+	}
+	D []E
+)
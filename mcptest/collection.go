@@ -0,0 +1,329 @@
+// Package mcptest provides an in-memory fake mongo collection serving canned documents, so the pagination logic
+// of stores built on the mongo package can be unit tested without a mongo server.
+package mcptest
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"sync"
+
+	mcpmongo "github.com/qlik-oss/mongocursorpagination/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection is an in-memory mongo.Collection and mongo.AggregateCollection. Find and CountDocuments honor the
+// filter, sort, skip and limit options, Aggregate supports the $match, $sort, $skip, $limit, $count, $sample and
+// $facet stages. Filters support the $and, $or, $nor, $eq, $ne, $gt, $gte, $lt, $lte, $in, $nin and $exists
+// operators, regular expressions and $expr comparisons of field paths. Other options, such as the collation and
+// projection, are ignored.
+type Collection struct {
+	mu   sync.Mutex
+	docs []bson.D
+
+	// The error returned by Find when not nil
+	FindErr error
+	// The error returned by CountDocuments when not nil
+	CountErr error
+	// The error returned by Aggregate when not nil
+	AggregateErr error
+}
+
+var (
+	_ mcpmongo.Collection          = &Collection{}
+	_ mcpmongo.AggregateCollection = &Collection{}
+)
+
+// NewCollection returns a Collection holding the specified documents, which can be any value marshalable into a
+// bson document.
+func NewCollection(docs ...interface{}) (*Collection, error) {
+	c := &Collection{}
+	err := c.Insert(docs...)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewCursor returns a MongoCursor serving the specified documents
+func NewCursor(docs ...interface{}) (mcpmongo.MongoCursor, error) {
+	if docs == nil {
+		docs = []interface{}{}
+	}
+	return mongo.NewCursorFromDocuments(docs, nil, nil)
+}
+
+// Insert adds the specified documents to the collection
+func (c *Collection) Insert(docs ...interface{}) error {
+	normalized := make([]bson.D, 0, len(docs))
+	for _, doc := range docs {
+		d, err := toD(doc)
+		if err != nil {
+			return fmt.Errorf("invalid document %v: %s", doc, err)
+		}
+		normalized = append(normalized, d)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.docs = append(c.docs, normalized...)
+	return nil
+}
+
+// CountDocuments returns the number of documents matching the filter, skipped and limited as specified by the
+// options
+func (c *Collection) CountDocuments(_ context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	if c.CountErr != nil {
+		return 0, c.CountErr
+	}
+	docs, err := c.match(filter)
+	if err != nil {
+		return 0, err
+	}
+
+	co := options.MergeCountOptions(opts...)
+	if co.Skip != nil {
+		docs = skipDocs(docs, *co.Skip)
+	}
+	if co.Limit != nil && *co.Limit > 0 {
+		docs = limitDocs(docs, *co.Limit)
+	}
+	return int64(len(docs)), nil
+}
+
+// Find returns a cursor over the documents matching the filter, sorted, skipped and limited as specified by the
+// options
+func (c *Collection) Find(_ context.Context, filter interface{}, opts ...*options.FindOptions) (mcpmongo.MongoCursor, error) {
+	if c.FindErr != nil {
+		return nil, c.FindErr
+	}
+	docs, err := c.match(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	fo := options.MergeFindOptions(opts...)
+	if fo.Sort != nil {
+		var sortSpec bson.D
+		sortSpec, err = toD(fo.Sort)
+		if err != nil {
+			return nil, fmt.Errorf("invalid sort %v: %s", fo.Sort, err)
+		}
+		sortDocs(docs, sortSpec)
+	}
+	if fo.Skip != nil {
+		docs = skipDocs(docs, *fo.Skip)
+	}
+	if fo.Limit != nil && *fo.Limit > 0 {
+		docs = limitDocs(docs, *fo.Limit)
+	}
+	return newCursor(docs)
+}
+
+// Aggregate runs the $match, $sort, $skip, $limit, $count, $sample and $facet stages of the pipeline over the
+// collection's documents
+func (c *Collection) Aggregate(_ context.Context, pipeline interface{}, _ ...*options.AggregateOptions) (mcpmongo.MongoCursor, error) {
+	if c.AggregateErr != nil {
+		return nil, c.AggregateErr
+	}
+	var stages []bson.D
+	data, err := bson.Marshal(bson.M{"pipeline": pipeline})
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline: %s", err)
+	}
+	var wrapper struct {
+		Pipeline []bson.D `bson:"pipeline"`
+	}
+	err = bson.Unmarshal(data, &wrapper)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline: %s", err)
+	}
+	stages = wrapper.Pipeline
+
+	c.mu.Lock()
+	docs := append([]bson.D{}, c.docs...)
+	c.mu.Unlock()
+
+	for _, stage := range stages {
+		if len(stage) != 1 {
+			return nil, fmt.Errorf("a pipeline stage must have exactly one field: %v", stage)
+		}
+		docs, err = runStage(docs, stage[0])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newCursor(docs)
+}
+
+func runStage(docs []bson.D, stage bson.E) ([]bson.D, error) {
+	switch stage.Key {
+	case "$match":
+		filter, ok := stage.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("$match requires a document")
+		}
+		return filterDocs(docs, filter)
+	case "$sort":
+		sortSpec, ok := stage.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("$sort requires a document")
+		}
+		sorted := append([]bson.D{}, docs...)
+		sortDocs(sorted, sortSpec)
+		return sorted, nil
+	case "$skip":
+		n, ok := toInt64(stage.Value)
+		if !ok {
+			return nil, fmt.Errorf("$skip requires a number")
+		}
+		return skipDocs(docs, n), nil
+	case "$limit":
+		n, ok := toInt64(stage.Value)
+		if !ok {
+			return nil, fmt.Errorf("$limit requires a number")
+		}
+		return limitDocs(docs, n), nil
+	case "$sample":
+		spec, ok := stage.Value.(bson.D)
+		if !ok || len(spec) != 1 || spec[0].Key != "size" {
+			return nil, fmt.Errorf("$sample requires a size")
+		}
+		n, ok := toInt64(spec[0].Value)
+		if !ok {
+			return nil, fmt.Errorf("$sample requires a number")
+		}
+		sampled := append([]bson.D{}, docs...)
+		rand.Shuffle(len(sampled), func(i, j int) { sampled[i], sampled[j] = sampled[j], sampled[i] })
+		return limitDocs(sampled, n), nil
+	case "$facet":
+		spec, ok := stage.Value.(bson.D)
+		if !ok {
+			return nil, fmt.Errorf("$facet requires a document")
+		}
+		facets := make(bson.D, 0, len(spec))
+		for _, facet := range spec {
+			stages, ok := facet.Value.(bson.A)
+			if !ok {
+				return nil, fmt.Errorf("the $facet %s requires a pipeline", facet.Key)
+			}
+			facetDocs := docs
+			for _, facetStage := range stages {
+				s, ok := facetStage.(bson.D)
+				if !ok || len(s) != 1 {
+					return nil, fmt.Errorf("a pipeline stage must have exactly one field: %v", facetStage)
+				}
+				var err error
+				facetDocs, err = runStage(facetDocs, s[0])
+				if err != nil {
+					return nil, err
+				}
+			}
+			output := make(bson.A, 0, len(facetDocs))
+			for _, doc := range facetDocs {
+				output = append(output, doc)
+			}
+			facets = append(facets, bson.E{Key: facet.Key, Value: output})
+		}
+		return []bson.D{facets}, nil
+	case "$count":
+		field, ok := stage.Value.(string)
+		if !ok {
+			return nil, fmt.Errorf("$count requires a field name")
+		}
+		if len(docs) == 0 {
+			return nil, nil
+		}
+		return []bson.D{{{Key: field, Value: int32(len(docs))}}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported pipeline stage %s", stage.Key)
+	}
+}
+
+func (c *Collection) match(filter interface{}) ([]bson.D, error) {
+	f, err := toD(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %v: %s", filter, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return filterDocs(c.docs, f)
+}
+
+func filterDocs(docs []bson.D, filter bson.D) ([]bson.D, error) {
+	matched := make([]bson.D, 0, len(docs))
+	for _, doc := range docs {
+		ok, err := matches(doc, filter)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, doc)
+		}
+	}
+	return matched, nil
+}
+
+func sortDocs(docs []bson.D, sortSpec bson.D) {
+	// The documents are kept in their insertion order, their natural order
+	if len(sortSpec) > 0 && sortSpec[0].Key == "$natural" {
+		if order, _ := toInt64(sortSpec[0].Value); order < 0 {
+			slices.Reverse(docs)
+		}
+		return
+	}
+	sort.SliceStable(docs, func(i, j int) bool {
+		for _, e := range sortSpec {
+			order, _ := toInt64(e.Value)
+			a, _ := lookup(docs[i], e.Key)
+			b, _ := lookup(docs[j], e.Key)
+			if c := mcpmongo.CompareValues(a, b); c != 0 {
+				if order < 0 {
+					return c > 0
+				}
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+func skipDocs(docs []bson.D, n int64) []bson.D {
+	if n >= int64(len(docs)) {
+		return nil
+	}
+	return docs[n:]
+}
+
+func limitDocs(docs []bson.D, n int64) []bson.D {
+	if n < int64(len(docs)) {
+		return docs[:n]
+	}
+	return docs
+}
+
+func newCursor(docs []bson.D) (mcpmongo.MongoCursor, error) {
+	values := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		values = append(values, doc)
+	}
+	return NewCursor(values...)
+}
+
+// toD normalizes a document into a bson.D holding the bson primitive types
+func toD(doc interface{}) (bson.D, error) {
+	if doc == nil {
+		return bson.D{}, nil
+	}
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var d bson.D
+	err = bson.Unmarshal(data, &d)
+	return d, err
+}
@@ -0,0 +1,124 @@
+package mcptest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestFindPaginatesForwardAndBackward(t *testing.T) {
+	for _, dialect := range []mongo.Dialect{mongo.DialectMongo, mongo.DialectCosmos} {
+		items, col := NewItems(t)
+		p := mongo.FindParams{
+			Collection:     col,
+			Query:          bson.M{"name": primitive.Regex{Pattern: "^TEST", Options: "i"}},
+			Limit:          2,
+			PaginatedField: "count",
+			CountTotal:     true,
+			Dialect:        dialect,
+		}
+
+		var results []Item
+		cursor, err := mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []Item{items[0], items[4]}, results)
+		require.Equal(t, 4, cursor.Count)
+		require.True(t, cursor.HasNext)
+		require.False(t, cursor.HasPrevious)
+
+		p.Next = cursor.Next
+		cursor, err = mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []Item{items[2], items[1]}, results)
+		require.False(t, cursor.HasNext)
+		require.True(t, cursor.HasPrevious)
+
+		p.Next = ""
+		p.Previous = cursor.Previous
+		cursor, err = mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []Item{items[0], items[4]}, results)
+		require.True(t, cursor.HasNext)
+		require.False(t, cursor.HasPrevious)
+	}
+}
+
+func TestFindPaginatesDescending(t *testing.T) {
+	items, col := NewItems(t)
+	p := mongo.FindParams{
+		Collection:      col,
+		Query:           bson.M{"createdAt": bson.M{"$gte": items[1].CreatedAt}},
+		Limit:           2,
+		PaginatedFields: []string{"name"},
+		SortOrders:      []int{-1},
+	}
+
+	var results []Item
+	cursor, err := mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[4], items[2]}, results)
+	require.True(t, cursor.HasNext)
+
+	p.Next = cursor.Next
+	cursor, err = mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[1], items[3]}, results)
+	require.False(t, cursor.HasNext)
+}
+
+func TestAggregatePaginates(t *testing.T) {
+	items, col := NewItems(t)
+	p := mongo.AggregateParams{
+		Collection:     col,
+		Pipeline:       []bson.M{{"$match": bson.M{"count": bson.M{"$in": bson.A{1, 2}}}}},
+		Limit:          3,
+		PaginatedField: "createdAt",
+		SortAscending:  true,
+		CountTotal:     true,
+	}
+
+	var results []Item
+	cursor, err := mongo.Aggregate(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[0], items[1], items[2]}, results)
+	require.Equal(t, 4, cursor.Count)
+	require.True(t, cursor.HasNext)
+
+	p.Next = cursor.Next
+	cursor, err = mongo.Aggregate(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[4]}, results)
+	require.False(t, cursor.HasNext)
+	require.True(t, cursor.HasPrevious)
+}
+
+func TestFindHonorsSkipAndLimitOptions(t *testing.T) {
+	items, col := NewItems(t)
+	cursor, err := col.Find(context.Background(), bson.M{"count": bson.M{"$ne": 1}},
+		options.Find().SetSort(bson.D{{Key: "name", Value: 1}}).SetSkip(1).SetLimit(1))
+	require.NoError(t, err)
+
+	var results []Item
+	require.NoError(t, cursor.All(context.Background(), &results))
+	require.Equal(t, []Item{items[0]}, results)
+}
+
+func TestCollectionErrors(t *testing.T) {
+	_, col := NewItems(t)
+	expected := errors.New("find failed")
+	col.FindErr = expected
+
+	var results []Item
+	_, err := mongo.Find(context.Background(), mongo.FindParams{Collection: col, Limit: 1}, &results)
+	require.Equal(t, expected, err)
+
+	col.FindErr = nil
+	_, err = col.Find(context.Background(), bson.M{"$where": "true"})
+	require.EqualError(t, err, "unsupported operator $where")
+}
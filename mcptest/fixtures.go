@@ -0,0 +1,39 @@
+package mcptest
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Item is the document of the fixture collection returned by NewItems
+type Item struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	Name      string             `bson:"name"`
+	Count     int                `bson:"count"`
+	CreatedAt time.Time          `bson:"createdAt"`
+}
+
+// NewItems returns five items, created an hour apart, and a Collection holding them, for the tests paginating the
+// fake collection. The names of four of them start with "test item" and their counts tie.
+func NewItems(t testing.TB) ([]Item, *Collection) {
+	t.Helper()
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "test item 1", Count: 2, CreatedAt: now},
+		{ID: primitive.NewObjectID(), Name: "test item 2", Count: 1, CreatedAt: now.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), Name: "test item 3", Count: 1, CreatedAt: now.Add(2 * time.Hour)},
+		{ID: primitive.NewObjectID(), Name: "other item", Count: 3, CreatedAt: now.Add(3 * time.Hour)},
+		{ID: primitive.NewObjectID(), Name: "test item 4", Count: 1, CreatedAt: now.Add(4 * time.Hour)},
+	}
+	docs := make([]interface{}, 0, len(items))
+	for _, i := range items {
+		docs = append(docs, i)
+	}
+	col, err := NewCollection(docs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return items, col
+}
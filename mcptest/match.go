@@ -0,0 +1,262 @@
+package mcptest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	mcpmongo "github.com/qlik-oss/mongocursorpagination/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// matches returns whether doc matches the filter
+func matches(doc bson.D, filter bson.D) (bool, error) {
+	for _, e := range filter {
+		ok, err := matchesElement(doc, e)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchesElement(doc bson.D, e bson.E) (bool, error) {
+	switch e.Key {
+	case "$and", "$or", "$nor":
+		clauses, ok := e.Value.(bson.A)
+		if !ok {
+			return false, fmt.Errorf("%s requires an array", e.Key)
+		}
+		return matchesLogical(doc, e.Key, clauses)
+	case "$expr":
+		return matchesExpr(doc, e.Value)
+	}
+	if strings.HasPrefix(e.Key, "$") {
+		return false, fmt.Errorf("unsupported operator %s", e.Key)
+	}
+
+	value, found := lookup(doc, e.Key)
+	if ops, ok := e.Value.(bson.D); ok && len(ops) > 0 && strings.HasPrefix(ops[0].Key, "$") {
+		for _, op := range ops {
+			ok, err := matchesOperator(value, found, op)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	return matchesEqual(value, e.Value), nil
+}
+
+func matchesLogical(doc bson.D, op string, clauses bson.A) (bool, error) {
+	for _, clause := range clauses {
+		var filter bson.D
+		switch c := clause.(type) {
+		case bson.D:
+			filter = c
+		case nil:
+			// A nil query marshals as null, consider it as matching everything
+		default:
+			return false, fmt.Errorf("%s entries must be documents", op)
+		}
+		ok, err := matches(doc, filter)
+		if err != nil {
+			return false, err
+		}
+		switch {
+		case op == "$and" && !ok:
+			return false, nil
+		case op == "$or" && ok:
+			return true, nil
+		case op == "$nor" && ok:
+			return false, nil
+		}
+	}
+	return op != "$or", nil
+}
+
+// matchesExpr evaluates the $eq, $ne, $gt, $gte, $lt and $lte comparison expressions, whose arguments can be field
+// paths, literals or arrays of them
+func matchesExpr(doc bson.D, expr interface{}) (bool, error) {
+	d, ok := expr.(bson.D)
+	if !ok || len(d) != 1 {
+		return false, fmt.Errorf("unsupported $expr %v", expr)
+	}
+	args, ok := d[0].Value.(bson.A)
+	if !ok || len(args) != 2 {
+		return false, fmt.Errorf("%s expression requires two arguments", d[0].Key)
+	}
+	c := mcpmongo.CompareValues(evaluate(doc, args[0]), evaluate(doc, args[1]))
+	switch d[0].Key {
+	case "$eq":
+		return c == 0, nil
+	case "$ne":
+		return c != 0, nil
+	case "$gt":
+		return c > 0, nil
+	case "$gte":
+		return c >= 0, nil
+	case "$lt":
+		return c < 0, nil
+	case "$lte":
+		return c <= 0, nil
+	default:
+		return false, fmt.Errorf("unsupported expression operator %s", d[0].Key)
+	}
+}
+
+// evaluate returns the value of a field path or literal expression
+func evaluate(doc bson.D, expr interface{}) interface{} {
+	switch v := expr.(type) {
+	case string:
+		if strings.HasPrefix(v, "$") && !strings.HasPrefix(v, "$$") {
+			value, _ := lookup(doc, v[1:])
+			return value
+		}
+	case bson.A:
+		values := make(bson.A, 0, len(v))
+		for _, e := range v {
+			values = append(values, evaluate(doc, e))
+		}
+		return values
+	}
+	return expr
+}
+
+func matchesOperator(value interface{}, found bool, op bson.E) (bool, error) {
+	switch op.Key {
+	case "$eq":
+		return matchesEqual(value, op.Value), nil
+	case "$ne":
+		return !matchesEqual(value, op.Value), nil
+	case "$gt":
+		return matchesComparison(value, op.Value, func(c int) bool { return c > 0 }), nil
+	case "$gte":
+		return matchesComparison(value, op.Value, func(c int) bool { return c >= 0 }), nil
+	case "$lt":
+		return matchesComparison(value, op.Value, func(c int) bool { return c < 0 }), nil
+	case "$lte":
+		return matchesComparison(value, op.Value, func(c int) bool { return c <= 0 }), nil
+	case "$in", "$nin":
+		candidates, ok := op.Value.(bson.A)
+		if !ok {
+			return false, fmt.Errorf("%s requires an array", op.Key)
+		}
+		in := false
+		for _, candidate := range candidates {
+			if matchesEqual(value, candidate) {
+				in = true
+				break
+			}
+		}
+		return in == (op.Key == "$in"), nil
+	case "$exists":
+		exists, ok := op.Value.(bool)
+		if !ok {
+			n, isNumber := toInt64(op.Value)
+			if !isNumber {
+				return false, fmt.Errorf("$exists requires a boolean")
+			}
+			exists = n != 0
+		}
+		return found == exists, nil
+	default:
+		return false, fmt.Errorf("unsupported operator %s", op.Key)
+	}
+}
+
+// matchesEqual returns whether value equals expected, or holds it when value is an array. A regular expression
+// matches the strings it matches.
+func matchesEqual(value, expected interface{}) bool {
+	if re, ok := expected.(primitive.Regex); ok {
+		return matchesRegex(value, re)
+	}
+	if arr, ok := value.(bson.A); ok {
+		if _, isArray := expected.(bson.A); !isArray {
+			for _, v := range arr {
+				if matchesEqual(v, expected) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return mcpmongo.ComparableValues(value, expected) && mcpmongo.CompareValues(value, expected) == 0
+}
+
+func matchesRegex(value interface{}, re primitive.Regex) bool {
+	switch v := value.(type) {
+	case string:
+		pattern := re.Pattern
+		var flags string
+		for _, o := range re.Options {
+			if strings.ContainsRune("imsU", o) {
+				flags += string(o)
+			}
+		}
+		if flags != "" {
+			pattern = "(?" + flags + ")" + pattern
+		}
+		matched, err := regexp.MatchString(pattern, v)
+		return err == nil && matched
+	case bson.A:
+		for _, e := range v {
+			if matchesRegex(e, re) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesComparison compares values of the same type bracket only, as mongo does
+func matchesComparison(value, expected interface{}, cmp func(int) bool) bool {
+	if arr, ok := value.(bson.A); ok {
+		for _, v := range arr {
+			if matchesComparison(v, expected, cmp) {
+				return true
+			}
+		}
+		return false
+	}
+	if !mcpmongo.ComparableValues(value, expected) {
+		return false
+	}
+	return cmp(mcpmongo.CompareValues(value, expected))
+}
+
+// lookup returns the value of the possibly dotted field path in doc and whether it was found
+func lookup(doc bson.D, path string) (interface{}, bool) {
+	var current interface{} = doc
+	for _, key := range strings.Split(path, ".") {
+		d, ok := current.(bson.D)
+		if !ok {
+			return nil, false
+		}
+		found := false
+		for _, e := range d {
+			if e.Key == key {
+				current = e.Value
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	}
+	return 0, false
+}
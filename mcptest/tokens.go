@@ -0,0 +1,83 @@
+package mcptest
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	mcpmongo "github.com/qlik-oss/mongocursorpagination/mongo"
+)
+
+// TokenSample is a sanitized cursor or page token issued in production by a previous version of the package,
+// with the params of the query it was issued for. Checking the samples after upgrading the package guards
+// against invalidating the tokens held by clients.
+type TokenSample struct {
+	// The name of the sample, e.g. the endpoint and version it was issued by
+	Name string
+	// The cursor, i.e. the Next or Previous value of a Cursor. Ignored when PageToken is set
+	Cursor string
+	// The direction of the page requested from the cursor, Forward to pass it as Next and Backward as Previous
+	Direction mcpmongo.Direction
+	// The page token generated by Cursor.NextToken or Cursor.PreviousToken, applied with mongo.ApplyToken
+	PageToken string
+	// The params of the query the token was issued for
+	Params mcpmongo.FindParams
+	// A pointer to a slice of the type the query results are decoded to
+	Results interface{}
+}
+
+var (
+	tokenSamplesMu sync.Mutex
+	tokenSamples   []TokenSample
+)
+
+// RegisterTokenSamples registers the samples checked by RunTokenSamples, e.g. from the init function of a test
+// file listing the tokens issued by each release
+func RegisterTokenSamples(samples ...TokenSample) {
+	tokenSamplesMu.Lock()
+	defer tokenSamplesMu.Unlock()
+	tokenSamples = append(tokenSamples, samples...)
+}
+
+// RunTokenSamples checks each registered sample in a subtest, failing it if the sample isn't accepted anymore
+func RunTokenSamples(t *testing.T) {
+	tokenSamplesMu.Lock()
+	samples := append([]TokenSample{}, tokenSamples...)
+	tokenSamplesMu.Unlock()
+
+	if len(samples) == 0 {
+		t.Fatal("no token samples registered")
+	}
+	for _, sample := range samples {
+		sample := sample
+		t.Run(sample.Name, func(t *testing.T) {
+			if err := CheckTokenSample(sample); err != nil {
+				t.Errorf("token sample %q isn't accepted anymore: %s", sample.Name, err)
+			}
+		})
+	}
+}
+
+// CheckTokenSample verifies that the sample's token is still parsed and validated by mongo.Find for the
+// sample's params, without querying
+func CheckTokenSample(sample TokenSample) error {
+	p := sample.Params
+	if sample.PageToken != "" {
+		var err error
+		p, err = mcpmongo.ApplyToken(p, sample.PageToken)
+		if err != nil {
+			return err
+		}
+	} else {
+		p.Next, p.Previous = "", ""
+		if sample.Direction == mcpmongo.Backward {
+			p.Previous = sample.Cursor
+		} else {
+			p.Next = sample.Cursor
+		}
+	}
+	if p.Next == "" && p.Previous == "" {
+		return errors.New("the sample holds no cursor")
+	}
+	return mcpmongo.ValidateCursor(p, sample.Results)
+}
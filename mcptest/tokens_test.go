@@ -0,0 +1,67 @@
+package mcptest
+
+import (
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+// The tokens issued by previous releases, which must keep being accepted
+func init() {
+	RegisterTokenSamples(
+		TokenSample{
+			Name:    "_id cursor",
+			Cursor:  "FgAAAAdfaWQAXxorPE1eb3qLnA0eAA",
+			Params:  mongo.FindParams{Limit: 10},
+			Results: &[]Item{},
+		},
+		TokenSample{
+			Name:      "name cursor backward",
+			Cursor:    "LAAAAAJuYW1lAAwAAAB0ZXN0IGl0ZW0gMQAHX2lkAF8aKzxNXm96i5wNHgA",
+			Direction: mongo.Backward,
+			Params:    mongo.FindParams{Limit: 10, PaginatedField: "name"},
+			Results:   &[]Item{},
+		},
+		TokenSample{
+			Name:    "multiple fields cursor",
+			Cursor:  "NAAAABBjb3VudAACAAAACWNyZWF0ZWRBdAAA5UOAcwEAAAdfaWQAXxorPE1eb3qLnA0eAA",
+			Params:  mongo.FindParams{Limit: 10, PaginatedFields: []string{"count", "createdAt"}, SortOrders: []int{1, -1}},
+			Results: &[]Item{},
+		},
+		TokenSample{
+			Name:      "name page token",
+			PageToken: "XAAAAAJkAAIAAABuABJsAAoAAAAAAAAAAmMAPAAAAExBQUFBQUp1WVcxbEFBd0FBQUIwWlhOMElHbDBaVzBnTVFBSFgybGtBRjhhS3p4TlhtOTZpNXdOSGdBAAA",
+			Params:    mongo.FindParams{Limit: 10, PaginatedField: "name"},
+			Results:   &[]Item{},
+		},
+	)
+}
+
+func TestTokenSamples(t *testing.T) {
+	RunTokenSamples(t)
+}
+
+func TestCheckTokenSampleErrors(t *testing.T) {
+	// A cursor issued for other paginated fields
+	err := CheckTokenSample(TokenSample{
+		Cursor:  "FgAAAAdfaWQAXxorPE1eb3qLnA0eAA",
+		Params:  mongo.FindParams{Limit: 10, PaginatedField: "name"},
+		Results: &[]Item{},
+	})
+	require.IsType(t, &mongo.CursorError{}, err)
+
+	// A cursor whose values have other types
+	err = CheckTokenSample(TokenSample{
+		Cursor:  "LAAAAAJuYW1lAAwAAAB0ZXN0IGl0ZW0gMQAHX2lkAF8aKzxNXm96i5wNHgA",
+		Params:  mongo.FindParams{Limit: 10, PaginatedField: "count"},
+		Results: &[]Item{},
+	})
+	require.Error(t, err)
+
+	err = CheckTokenSample(TokenSample{PageToken: "invalid", Results: &[]Item{}})
+	require.IsType(t, &mongo.CursorError{}, err)
+
+	err = CheckTokenSample(TokenSample{Results: &[]Item{}})
+	require.EqualError(t, err, "the sample holds no cursor")
+}
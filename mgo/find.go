@@ -1,18 +1,23 @@
-// Package mongocursorpagination eases the computation of pagination information of a find mongo query
-// by augmenting the base query with cursor information and returning a cursor.
+// Package mgo eases the computation of pagination information of a find mongo query using the mgo driver
+// (github.com/globalsign/mgo) by augmenting
+// the base query with cursor information and returning a cursor.
 package mgo
 
 import (
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/globalsign/mgo"
 	"github.com/globalsign/mgo/bson"
 	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
 )
 
+// defaultCursorTimeout is the maxTimeMS of the queries whose params don't set a Timeout
+const defaultCursorTimeout = 45 * time.Second
+
 type (
 	MgoDb interface {
 		C(string) *mgo.Collection
@@ -54,7 +59,8 @@ type (
 		PaginatedField string
 		// The collation to use for the sort ordering.
 		// See https://docs.mongodb.com/manual/reference/collation-locales-defaults/#supported-languages-and-locales
-		// This is ignored if PaginatedField is empty
+		// This is ignored when paginating on the _id only. The index on the paginated fields must be created with
+		// the same collation to be used for the sort
 		Collation *mgo.Collation
 		// The value to start querying the page
 		Next string
@@ -67,6 +73,13 @@ type (
 		PaginatedFields []string
 		// The sort orders corresponding to PaginatedFields. Each value must be either 1 or -1
 		SortOrders []int
+		// This parameter will set the maxTimeMS option on the page and count queries, making sure we add a limit to
+		// the amount of time mongo can process them on the backend. Will default to 45 seconds. The socket timeout of
+		// the session should be longer for the server to abort a long scan before the socket does
+		Timeout time.Duration
+		// true, if the page starting at Next or Previous should include the anchor document the cursor points at,
+		// e.g. to resume exactly at a bookmarked document
+		IncludeAnchor bool
 	}
 
 	// Cursor holds the pagination data about the find mongo query that was performed.
@@ -137,7 +150,7 @@ func Find(p FindParams, results interface{}) (Cursor, error) {
 	// Compute total count of documents matching filter - only computed if CountTotal is True
 	var count int
 	if p.CountTotal {
-		count, err = executeCountQuery(p.DB, p.CollectionName, queries)
+		count, err = executeCountQuery(p.DB, p.CollectionName, queries, p.Timeout)
 		if err != nil {
 			return Cursor{}, err
 		}
@@ -152,7 +165,11 @@ func Find(p FindParams, results interface{}) (Cursor, error) {
 			cursorValues = previousCursorValues
 		}
 		var cursorQuery bson.M
-		cursorQuery, err = mcpbson.GenerateCursorQuery(p.PaginatedFields, comparisonOps, cursorValues)
+		if p.IncludeAnchor {
+			cursorQuery, err = mcpbson.GenerateInclusiveCursorQuery(p.PaginatedFields, comparisonOps, cursorValues)
+		} else {
+			cursorQuery, err = mcpbson.GenerateCursorQuery(p.PaginatedFields, comparisonOps, cursorValues)
+		}
 		if err != nil {
 			return Cursor{}, err
 		}
@@ -170,7 +187,7 @@ func Find(p FindParams, results interface{}) (Cursor, error) {
 	}
 
 	// Execute the augmented query, get an additional element to see if there's another page
-	err = executeCursorQuery(p.DB, p.CollectionName, queries, sort, p.Limit, p.Collation, results)
+	err = executeCursorQuery(p.DB, p.CollectionName, queries, sort, p.Limit, p.Collation, p.Timeout, results)
 	if err != nil {
 		return Cursor{}, err
 	}
@@ -195,11 +212,7 @@ func Find(p FindParams, results interface{}) (Cursor, error) {
 	if resultsVal.Len() > 0 {
 		// If we sorted reverse to get the previous page, correct the sort order
 		if p.Previous != "" {
-			for left, right := 0, resultsVal.Len()-1; left < right; left, right = left+1, right-1 {
-				leftValue := resultsVal.Index(left).Interface()
-				resultsVal.Index(left).Set(resultsVal.Index(right))
-				resultsVal.Index(right).Set(reflect.ValueOf(leftValue))
-			}
+			mcpbson.ReverseResults(resultsVal)
 		}
 
 		// Generate the previous cursor
@@ -237,48 +250,21 @@ func Find(p FindParams, results interface{}) (Cursor, error) {
 }
 
 func generateComparisonOps(p FindParams) []string {
-	comparisonOps := make([]string, 0, len(p.SortOrders))
-	for i := range p.SortOrders {
-		// Figure out the sort direction and comparison operator that will be used in the augmented query
-		sortAsc := (p.SortOrders[i] == -1 && p.Previous != "") || (p.SortOrders[i] == 1 && p.Previous == "")
-		if sortAsc {
-			comparisonOps = append(comparisonOps, "$gt")
-			p.SortOrders[i] = 1
-		} else {
-			comparisonOps = append(comparisonOps, "$lt")
-			p.SortOrders[i] = -1
-		}
-	}
-	return comparisonOps
+	return mcpbson.ComparisonOps(p.SortOrders, p.Previous != "")
 }
 
 func ensureMandatoryParams(p FindParams) FindParams {
+	if p.Timeout <= time.Duration(0) {
+		p.Timeout = defaultCursorTimeout
+	}
 	if p.PaginatedField == "" {
 		p.PaginatedField = "_id"
-		p.Collation = nil
-	}
-	if len(p.PaginatedFields) == 0 {
-		if p.PaginatedField == "_id" {
-			p.PaginatedFields = []string{"_id"}
-		} else {
-			p.PaginatedFields = []string{p.PaginatedField, "_id"}
-		}
-	} else if p.PaginatedFields[len(p.PaginatedFields)-1] != "_id" {
-		p.PaginatedFields = append(p.PaginatedFields, "_id")
-		p.SortOrders = append(p.SortOrders, 1)
-	}
-	if len(p.SortOrders) == 0 {
-		p.SortOrders = []int{}
-		if p.SortAscending {
-			for i := 0; i < len(p.PaginatedFields); i++ {
-				p.SortOrders = append(p.SortOrders, 1)
-			}
-		} else {
-			for i := 0; i < len(p.PaginatedFields); i++ {
-				p.SortOrders = append(p.SortOrders, -1)
-			}
+		// The collation only matters when sorting on other fields than the _id
+		if len(p.PaginatedFields) == 0 {
+			p.Collation = nil
 		}
 	}
+	p.PaginatedFields, p.SortOrders = mcpbson.EnsurePaginatedFields(p.PaginatedField, p.PaginatedFields, p.SortOrders, p.SortAscending, "_id")
 	return p
 }
 
@@ -289,11 +275,9 @@ var parseCursor = func(cursor string, numPaginatedFields int) ([]interface{}, er
 		if err != nil {
 			return nil, err
 		}
-		if len(parsedCursor) != numPaginatedFields {
-			if numPaginatedFields == 1 {
-				return nil, errors.New("expecting a cursor with a single element")
-			}
-			return nil, fmt.Errorf("expecting a cursor with %d elements", numPaginatedFields)
+		err = mcpbson.CheckCursorLength(len(parsedCursor), numPaginatedFields)
+		if err != nil {
+			return nil, err
 		}
 		for _, obj := range parsedCursor {
 			cursorValues = append(cursorValues, obj.Value)
@@ -306,7 +290,8 @@ var parseCursor = func(cursor string, numPaginatedFields int) ([]interface{}, er
 // decodeCursor decodes cursor data that was previously encoded with createCursor
 func decodeCursor(cursor string) (bson.D, error) {
 	var cursorData bson.D
-	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	// Validating the document first rejects the truncated or hostile cursors without unmarshaling them
+	data, err := mcpbson.DecodeCursor(cursor, mcpbson.DefaultMaxCursorSize, mcpbson.DefaultMaxCursorDepth)
 	if err != nil {
 		return cursorData, err
 	}
@@ -315,26 +300,23 @@ func decodeCursor(cursor string) (bson.D, error) {
 	return cursorData, err
 }
 
-var executeCountQuery = func(db MgoDb, collectionName string, queries []bson.M) (int, error) {
-	return db.C(collectionName).Find(bson.M{"$and": queries}).Count()
+var executeCountQuery = func(db MgoDb, collectionName string, queries []bson.M, timeout time.Duration) (int, error) {
+	return db.C(collectionName).Find(bson.M{"$and": queries}).SetMaxTime(timeout).Count()
 }
 
-var executeCursorQuery = func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, results interface{}) error {
+var executeCursorQuery = func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, timeout time.Duration, results interface{}) error {
+	q := db.C(collectionName).Find(bson.M{"$and": query}).Sort(sort...).SetMaxTime(timeout)
 	if collation == nil {
-		return db.C(collectionName).Find(bson.M{"$and": query}).Sort(sort...).Limit(limit + 1).All(results)
+		return q.Limit(limit + 1).All(results)
 	}
-	return db.C(collectionName).Find(bson.M{"$and": query}).Sort(sort...).Collation(collation).Limit(limit + 1).All(results)
+	return q.Collation(collation).Limit(limit + 1).All(results)
 }
 
+// generateCursor returns the cursor of the paginated fields of result
 func generateCursor(result interface{}, paginatedFields []string) (string, error) {
 	if result == nil {
 		return "", fmt.Errorf("the specified result must be a non nil value")
 	}
-	// Handle pointer values and reduce number of times reflection is done on the same type.
-	val := reflect.ValueOf(result)
-	if val.Kind() == reflect.Ptr {
-		_ = reflect.Indirect(val)
-	}
 
 	var recordAsBytes []byte
 	var err error
@@ -349,33 +331,11 @@ func generateCursor(result interface{}, paginatedFields []string) (string, error
 		}
 	}
 
-	var recordAsMap map[string]interface{}
-	err = bson.Unmarshal(recordAsBytes, &recordAsMap)
+	cursorData, err := mcpbson.CursorDocument(recordAsBytes, paginatedFields)
 	if err != nil {
 		return "", err
 	}
-
-	// Set the cursor data
-	cursorData := make(bson.D, 0, len(paginatedFields))
-	for i := range paginatedFields {
-		paginatedFieldValue := recordAsMap[paginatedFields[i]]
-		if paginatedFieldValue != nil {
-			cursorData = append(cursorData, bson.DocElem{Name: paginatedFields[i], Value: paginatedFieldValue})
-		}
-	}
-	// Encode the cursor data into a url safe string
-	cursor, err := encodeCursor(cursorData)
-	if err != nil {
-		return "", fmt.Errorf("failed to encode cursor using %v: %s", cursorData, err)
-	}
-
-	return cursor, nil
-}
-
-// encodeCursor encodes and returns cursor data that is url safe
-var encodeCursor = func(cursorData bson.D) (string, error) {
-	data, err := bson.Marshal(cursorData)
-	return base64.RawURLEncoding.EncodeToString(data), err
+	return mcpbson.EncodeCursor(cursorData), nil
 }
 
 // validate verifies that the results array is of a supported type and that its underlying struct has a bson tag that
@@ -407,6 +367,11 @@ func validate(results interface{}, paginatedFields []string) error {
 		return nil
 	}
 
+	// Nor maps such as bson.M, whose cursor values are looked up by key
+	if elem.Kind() == reflect.Map && elem.Key().Kind() == reflect.String {
+		return nil
+	}
+
 	// If the slice contains pointers to structs, dereference to get the struct type
 	if elem.Kind() == reflect.Ptr {
 		elem = elem.Elem()
@@ -414,23 +379,71 @@ func validate(results interface{}, paginatedFields []string) error {
 
 	// Ensure that elem is now a struct
 	if elem.Kind() != reflect.Struct {
-		return NewErrInvalidResults("expected results' element to be a struct or struct pointer")
+		return NewErrInvalidResults("expected results' element to be a struct, struct pointer or string keyed map")
 	}
 
 	for _, paginatedField := range paginatedFields {
-		paginatedFieldFound := false
-		for i := 0; i < elem.NumField(); i++ {
-			field := elem.Field(i)
-			tag := field.Tag.Get("bson")
-
-			if tag == paginatedField {
-				paginatedFieldFound = true
-				break
-			}
-		}
-		if !paginatedFieldFound {
+		if !hasPaginatedField(elem, paginatedField) {
 			return NewErrPaginatedFieldNotFound(paginatedField)
 		}
 	}
 	return nil
 }
+
+// hasPaginatedField returns whether the struct type elem has a single field marshaled to the paginated field, looking
+// into its inlined structs breadth first as mgo does. An ambiguous field, found more than once at the same depth, is
+// dropped by mgo and not found.
+func hasPaginatedField(elem reflect.Type, paginatedField string) bool {
+	level := []reflect.Type{elem}
+	visited := map[reflect.Type]bool{elem: true}
+	for len(level) > 0 {
+		var inlined []reflect.Type
+		matches := 0
+		for _, t := range level {
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				if field.PkgPath != "" && !field.Anonymous {
+					continue
+				}
+				fieldName, inline := parseBSONTag(field.Tag.Get("bson"))
+				if fieldName == "-" {
+					continue
+				}
+				if inline {
+					inlineType := field.Type
+					if inlineType.Kind() == reflect.Ptr {
+						inlineType = inlineType.Elem()
+					}
+					if inlineType.Kind() == reflect.Struct && !visited[inlineType] {
+						visited[inlineType] = true
+						inlined = append(inlined, inlineType)
+					}
+					continue
+				}
+				// mgo keys the untagged fields by their lowercased name
+				if fieldName == "" {
+					fieldName = strings.ToLower(field.Name)
+				}
+				if fieldName == paginatedField {
+					matches++
+				}
+			}
+		}
+		if matches > 0 {
+			return matches == 1
+		}
+		level = inlined
+	}
+	return false
+}
+
+// parseBSONTag returns the field name of a bson struct tag and whether it has the inline flag
+func parseBSONTag(tag string) (fieldName string, inline bool) {
+	name, flags, _ := strings.Cut(tag, ",")
+	for flags != "" && !inline {
+		var flag string
+		flag, flags, _ = strings.Cut(flags, ",")
+		inline = flag == "inline"
+	}
+	return name, inline
+}
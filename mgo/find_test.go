@@ -24,8 +24,8 @@ func TestFind(t *testing.T) {
 		name               string
 		findParams         FindParams
 		results            interface{}
-		executeCountQuery  func(db MgoDb, collectionName string, queries []bson.M) (int, error)
-		executeCursorQuery func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, results interface{}) error
+		executeCountQuery  func(db MgoDb, collectionName string, queries []bson.M, timeout time.Duration) (int, error)
+		executeCursorQuery func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, timeout time.Duration, results interface{}) error
 		expectedCursor     Cursor
 		expectedErr        error
 	}{
@@ -100,7 +100,7 @@ func TestFind(t *testing.T) {
 				CountTotal:     true,
 			},
 			results: &[]item{},
-			executeCountQuery: func(db MgoDb, collectionName string, queries []bson.M) (int, error) {
+			executeCountQuery: func(db MgoDb, collectionName string, queries []bson.M, timeout time.Duration) (int, error) {
 				return 0, errors.New("error")
 			},
 			executeCursorQuery: nil,
@@ -119,10 +119,10 @@ func TestFind(t *testing.T) {
 				CountTotal:     true,
 			},
 			results: &[]item{},
-			executeCountQuery: func(db MgoDb, collectionName string, queries []bson.M) (int, error) {
+			executeCountQuery: func(db MgoDb, collectionName string, queries []bson.M, timeout time.Duration) (int, error) {
 				return 2, nil
 			},
-			executeCursorQuery: func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, results interface{}) error {
+			executeCursorQuery: func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, timeout time.Duration, results interface{}) error {
 				return errors.New("error")
 			},
 			expectedCursor: Cursor{},
@@ -140,10 +140,10 @@ func TestFind(t *testing.T) {
 				CountTotal:     true,
 			},
 			results: &[]*item{},
-			executeCountQuery: func(db MgoDb, collectionName string, queries []bson.M) (int, error) {
+			executeCountQuery: func(db MgoDb, collectionName string, queries []bson.M, timeout time.Duration) (int, error) {
 				return 3, nil
 			},
-			executeCursorQuery: func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, results interface{}) error {
+			executeCursorQuery: func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, timeout time.Duration, results interface{}) error {
 				resultv := reflect.ValueOf(results)
 				resultv.Elem().Set(reflect.ValueOf([]*item{
 					&item{ID: bson.ObjectIdHex("1addf533e81549de7696cb04"), Name: "test item 1", CreatedAt: time.Now()},
@@ -174,10 +174,10 @@ func TestFind(t *testing.T) {
 				CountTotal:     true,
 			},
 			results: &[]item{},
-			executeCountQuery: func(db MgoDb, collectionName string, queries []bson.M) (int, error) {
+			executeCountQuery: func(db MgoDb, collectionName string, queries []bson.M, timeout time.Duration) (int, error) {
 				return 2, nil
 			},
-			executeCursorQuery: func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, results interface{}) error {
+			executeCursorQuery: func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, timeout time.Duration, results interface{}) error {
 				resultv := reflect.ValueOf(results)
 				resultv.Elem().Set(reflect.ValueOf([]item{
 					{ID: bson.ObjectIdHex("1addf533e81549de7696cb04"), Name: "test item 1", CreatedAt: time.Now()},
@@ -207,10 +207,10 @@ func TestFind(t *testing.T) {
 				CountTotal:     true,
 			},
 			results: &[]item{},
-			executeCountQuery: func(db MgoDb, collectionName string, queries []bson.M) (int, error) {
+			executeCountQuery: func(db MgoDb, collectionName string, queries []bson.M, timeout time.Duration) (int, error) {
 				return 2, nil
 			},
-			executeCursorQuery: func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, results interface{}) error {
+			executeCursorQuery: func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, timeout time.Duration, results interface{}) error {
 				resultv := reflect.ValueOf(results)
 				resultv.Elem().Set(reflect.ValueOf([]item{
 					{ID: bson.ObjectIdHex("1addf533e81549de7696cb04"), Name: "test item 1", CreatedAt: time.Now()},
@@ -239,7 +239,7 @@ func TestFind(t *testing.T) {
 			},
 			results:           &[]item{},
 			executeCountQuery: nil,
-			executeCursorQuery: func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, results interface{}) error {
+			executeCursorQuery: func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, timeout time.Duration, results interface{}) error {
 				resultv := reflect.ValueOf(results)
 				resultv.Elem().Set(reflect.ValueOf([]item{
 					{ID: bson.ObjectIdHex("1addf533e81549de7696cb04"), Name: "test item 1", CreatedAt: time.Now()},
@@ -291,6 +291,101 @@ func TestFind(t *testing.T) {
 	}
 }
 
+func TestFindTimeout(t *testing.T) {
+	executeCountQueryOri := executeCountQuery
+	executeCursorQueryOri := executeCursorQuery
+	defer func() {
+		executeCountQuery = executeCountQueryOri
+		executeCursorQuery = executeCursorQueryOri
+	}()
+
+	var countTimeout, cursorTimeout time.Duration
+	executeCountQuery = func(db MgoDb, collectionName string, queries []bson.M, timeout time.Duration) (int, error) {
+		countTimeout = timeout
+		return 0, nil
+	}
+	executeCursorQuery = func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, timeout time.Duration, results interface{}) error {
+		cursorTimeout = timeout
+		return nil
+	}
+
+	p := FindParams{DB: &mgo.Database{}, CollectionName: "items", Limit: 2, CountTotal: true}
+	_, err := Find(p, &[]item{})
+	require.NoError(t, err)
+	require.Equal(t, defaultCursorTimeout, countTimeout)
+	require.Equal(t, defaultCursorTimeout, cursorTimeout)
+
+	p.Timeout = 5 * time.Second
+	_, err = Find(p, &[]item{})
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Second, countTimeout)
+	require.Equal(t, 5*time.Second, cursorTimeout)
+}
+
+func TestFindIncludeAnchor(t *testing.T) {
+	executeCursorQueryOri := executeCursorQuery
+	defer func() { executeCursorQuery = executeCursorQueryOri }()
+
+	var queries []bson.M
+	executeCursorQuery = func(db MgoDb, collectionName string, query []bson.M, sort []string, limit int, collation *mgo.Collation, timeout time.Duration, results interface{}) error {
+		queries = query
+		return nil
+	}
+
+	id := bson.NewObjectId()
+	next, err := generateCursor(item{ID: id}, []string{"_id"})
+	require.NoError(t, err)
+	p := FindParams{DB: &mgo.Database{}, CollectionName: "items", Limit: 2, Next: next}
+	_, err = Find(p, &[]item{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"_id": map[string]interface{}{"$lt": id}}, map[string]interface{}(queries[1]))
+
+	// The page resumes at the document the cursor points at
+	p.IncludeAnchor = true
+	_, err = Find(p, &[]item{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"_id": map[string]interface{}{"$lte": id}}, map[string]interface{}(queries[1]))
+}
+
+func TestValidate(t *testing.T) {
+	type audit struct {
+		UpdatedAt time.Time `bson:"updatedAt"`
+		Owner     string
+	}
+	type inlinedItem struct {
+		ID    bson.ObjectId `bson:"_id"`
+		Audit audit         `bson:",inline"`
+		Skip  string        `bson:"-"`
+	}
+	type ambiguousItem struct {
+		ID    bson.ObjectId `bson:"_id"`
+		Name  string        `bson:"name"`
+		Other string        `bson:"name"`
+	}
+
+	var cases = []struct {
+		name            string
+		results         interface{}
+		paginatedFields []string
+		expectedErr     error
+	}{
+		{"finds a tagged field with options", &[]item{}, []string{"userId", "_id"}, nil},
+		{"finds the fields of inlined structs", &[]*inlinedItem{}, []string{"updatedAt", "owner", "_id"}, nil},
+		{"skips the ignored fields", &[]inlinedItem{}, []string{"skip", "_id"}, NewErrPaginatedFieldNotFound("skip")},
+		{"errors on ambiguous fields", &[]ambiguousItem{}, []string{"name", "_id"}, NewErrPaginatedFieldNotFound("name")},
+		{"accepts maps", &[]bson.M{}, []string{"name", "_id"}, nil},
+		{"accepts raw documents", &[]bson.Raw{}, []string{"name", "_id"}, nil},
+		{"errors on a missing field", &[]item{}, []string{"missing", "_id"}, NewErrPaginatedFieldNotFound("missing")},
+		{"errors when results isn't a pointer", []item{}, []string{"_id"}, NewErrInvalidResults("expected results to be a slice pointer")},
+		{"errors when results' elements aren't structs", &[]string{}, []string{"_id"}, NewErrInvalidResults("expected results' element to be a struct, struct pointer or string keyed map")},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedErr, validate(tc.results, tc.paginatedFields))
+		})
+	}
+}
+
 func TestParseCursor(t *testing.T) {
 	var cases = []struct {
 		name                      string
@@ -380,7 +475,6 @@ func TestGenerateCursor(t *testing.T) {
 		paginatedField          string
 		paginatedFields         []string
 		shouldSecondarySortOnID bool
-		encodeCursor            func(cursorData bson.D) (string, error)
 		expectedCursor          string
 		expectedErr             error
 	}{
@@ -390,7 +484,6 @@ func TestGenerateCursor(t *testing.T) {
 			"_id",
 			[]string{"_id"},
 			false,
-			nil,
 			"FgAAAAdfaWQAWt31M-gVSd52lssEAA",
 			nil,
 		},
@@ -400,7 +493,6 @@ func TestGenerateCursor(t *testing.T) {
 			"name",
 			[]string{"name", "_id"},
 			true,
-			nil,
 			"LAAAAAJuYW1lAAwAAAB0ZXN0IGl0ZW0gMQAHX2lkABrd9TPoFUnedpbLBAA",
 			nil,
 		},
@@ -410,7 +502,6 @@ func TestGenerateCursor(t *testing.T) {
 			"_id",
 			[]string{"_id"},
 			false,
-			nil,
 			"",
 			errors.New("ObjectIDs must be exactly 12 bytes long (got 3)"),
 		},
@@ -420,7 +511,6 @@ func TestGenerateCursor(t *testing.T) {
 			"_id",
 			[]string{"_id"},
 			false,
-			nil,
 			"",
 			errors.New("the specified result must be a non nil value"),
 		},
@@ -430,33 +520,12 @@ func TestGenerateCursor(t *testing.T) {
 			"creatorId",
 			[]string{"creatorId", "_id"},
 			false,
-			nil,
 			"BQAAAAA",
 			nil,
 		},
-		{
-			"errors when encoding fails",
-			item{ID: bson.ObjectIdHex("1addf533e81549de7696cb04"), Name: "test item", CreatedAt: time.Now()},
-			"name",
-			[]string{"name"},
-			false,
-			func(cursorData bson.D) (string, error) {
-				return "", errors.New("error")
-			},
-			"",
-			errors.New("failed to encode cursor using [{name test item}]: error"),
-		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			if tc.encodeCursor != nil {
-				encodeCursorOri := encodeCursor
-				encodeCursor = tc.encodeCursor
-				defer func() {
-					encodeCursor = encodeCursorOri
-				}()
-			}
-
 			cursor, err := generateCursor(tc.result, tc.paginatedFields)
 			require.Equal(t, tc.expectedCursor, cursor)
 			require.Equal(t, tc.expectedErr, err)
@@ -464,26 +533,14 @@ func TestGenerateCursor(t *testing.T) {
 	}
 }
 
-func TestEncodeCursorCursor(t *testing.T) {
-	var cases = []struct {
-		name           string
-		cursorData     bson.D
-		expectedCursor string
-		expectedErr    error
-	}{
-		{
-			"encode cursor when valid input cursor data provided",
-			bson.D{bson.DocElem{Name: "_id", Value: bson.ObjectIdHex("5addf533e81549de7696cb04")}},
-			"FgAAAAdfaWQAWt31M-gVSd52lssEAA",
-			nil,
-		},
-		// 	Can't find an easy way to make bson marshaling error so skipping that test case
-	}
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			cursor, err := encodeCursor(tc.cursorData)
-			require.Equal(t, tc.expectedCursor, cursor)
-			require.Equal(t, tc.expectedErr, err)
-		})
-	}
+func FuzzDecodeCursor(f *testing.F) {
+	cursor, err := generateCursor(item{ID: bson.NewObjectId(), Name: "a"}, []string{"name", "_id"})
+	require.NoError(f, err)
+	f.Add(cursor)
+	f.Add(cursor[:len(cursor)/2])
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, cursor string) {
+		_, _ = decodeCursor(cursor)
+	})
 }
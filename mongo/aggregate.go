@@ -0,0 +1,315 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	AggregateCollection interface {
+		Aggregate(context.Context, interface{}, ...*options.AggregateOptions) (MongoCursor, error)
+	}
+
+	// AggregateParams holds the parameters to be used in a paginated aggregate mongo query that will return a
+	// Cursor.
+	AggregateParams struct {
+		Collection AggregateCollection
+		// The mongo collection to query when Collection is nil, sparing the need to wrap it in an AggregateCollection
+		MongoCollection *mongodriver.Collection
+
+		// The filter of the aggregated documents, injected as the leading $match stage of the pipeline so that
+		// the count and the page share it, as the Query of FindParams. No $match is injected if empty
+		Query bson.M
+		// The pipeline stages to augment with pagination. The documents output by the pipeline must hold the
+		// paginated fields, which may be computed by the pipeline
+		Pipeline []bson.M
+		// The number of results to fetch, should be > 0
+		Limit int64
+		// true, if the results should be sort ascending, false otherwise
+		SortAscending bool
+		// The name of the field being paginated and sorted on, see FindParams.PaginatedField
+		PaginatedField string
+		// The names of multiple fields being paginated and sorted on. Takes precedence over PaginatedField
+		PaginatedFields []string
+		// The sort orders corresponding to PaginatedFields. Each value must be either 1 or -1
+		SortOrders []int
+		// The collation to use for the sort ordering and the pipeline's stages
+		Collation *options.Collation
+		// The value to start querying the page
+		Next string
+		// The value to start querying previous page
+		Previous string
+		// true, if the page starting at Next or Previous should include the anchor document the cursor points at,
+		// see FindParams.IncludeAnchor
+		IncludeAnchor bool
+		// Whether to include total count of documents output by the pipeline in the cursor
+		// Specifying true makes an additional query
+		CountTotal bool
+		// How the total count is computed for the pages queried with Next or Previous, see FindParams.CountPolicy
+		CountPolicy CountPolicy
+		// The maximum number of documents counted when CountTotal is set, see FindParams.CountLimit
+		CountLimit int64
+		// The index to use for the aggregation, either the index name as a string or the index specification as
+		// a document. The default value is nil, which means that no hint will be sent.
+		Hint interface{}
+		// This parameter will set the maxTimeMS option on the aggregate cursor and count. Will default to 45 seconds
+		Timeout time.Duration
+		// The shape of the cursor $match stage, defaults to DialectMongo
+		Dialect Dialect
+		// How the cursor $match stage compares the paginated fields, defaults to PredicateNestedOr
+		PredicateStrategy PredicateStrategy
+		// The version of the mongo server, e.g. "4.2.1", used by PredicateAuto. $expr isn't used when unknown
+		ServerVersion string
+		// The unique field breaking the ties of the paginated fields, defaults to _id, see
+		// FindParams.TiebreakerField
+		TiebreakerField string
+		// Adjusts the options of the aggregation of the page once the package has set them, see
+		// FindParams.RawFindOptions
+		RawAggregateOptions func(*options.AggregateOptions)
+		// The name of the aggregated collection, identifying it for the Executor
+		CollectionName string
+		// Retries the aggregations of the page failing with a transient error, see FindParams.RetryPolicy
+		RetryPolicy *RetryPolicy
+		// Runs the aggregations of the page, see FindParams.Executor
+		Executor Executor
+		// The number of documents at each end of a page excluded from the next or previous page, see
+		// FindParams.DedupWindow
+		DedupWindow int
+		// Decodes each document of the page into its concrete type, see FindParams.DecodeFunc
+		DecodeFunc DecodeFunc
+	}
+)
+
+// Aggregate executes an aggregate mongo query by augmenting the provided AggregateParams' pipeline with
+// pagination stages, fills the passed in result slice pointer and returns a Cursor. The cursors are computed
+// from the raw documents so the paginated fields don't need to be part of the results' type.
+func Aggregate(ctx context.Context, p AggregateParams, results interface{}) (Cursor, error) {
+	var err error
+	if p.DecodeFunc != nil {
+		err = validateDecodedResults(results)
+	} else {
+		err = bsonEncoding{}.validate(results, nil)
+	}
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	p, fp, err := ensureAggregateParams(p)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	// Compute total count of documents output by the pipeline - only computed if CountTotal is True
+	count, countSource, err := countTotal(fp, func() (count int, err error) {
+		err = fp.execute(ctx, QueryAggregateCount, func(ctx context.Context) error {
+			count, err = executeAggregateCountQuery(ctx, p.Collection, p.Pipeline, fp.CountLimit, p.Collation, fp.Timeout)
+			return err
+		})
+		return count, err
+	})
+	if err != nil {
+		return Cursor{}, err
+	}
+	fp = fp.carryCount(count, countSource)
+
+	pipeline, err := buildPipeline(p.Pipeline, fp)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	var rawResults []bson.Raw
+	err = fp.execute(ctx, QueryAggregate, func(ctx context.Context) error {
+		return executeAggregateQuery(ctx, p.Collection, pipeline, p.Collation, p.Hint, fp.Timeout, p.RawAggregateOptions, &rawResults)
+	})
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	hasMore := len(rawResults) > int(fp.Limit)
+	rawResults = orderPage(fp, rawResults)
+	fp, err = fp.carrySeen(reflect.ValueOf(rawResults))
+	if err != nil {
+		return Cursor{}, err
+	}
+	first, last := firstAndLast(rawResults)
+	cursor, err := pageCursor(fp, first, last, hasMore)
+	if err != nil {
+		return Cursor{}, err
+	}
+	cursor.Count, cursor.CountRelation = fp.cappedCount(count)
+	cursor.CountSource = countSource
+
+	if p.DecodeFunc != nil {
+		err = decodeResultsWith(rawResults, p.DecodeFunc, results)
+	} else {
+		err = decodeResults(rawResults, fp.settings().Registry, results)
+	}
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	return cursor, nil
+}
+
+// BuildPipeline builds the pipeline augmented with the cursor $match, $sort and $limit stages without executing
+// it
+func BuildPipeline(ctx context.Context, p AggregateParams) ([]bson.M, error) {
+	p, fp, err := ensureAggregateParams(p)
+	if err != nil {
+		return nil, err
+	}
+	return buildPipeline(p.Pipeline, fp)
+}
+
+// ensureAggregateParams resolves the collection of p, checks its limit, prepends the $match stage of its Query to
+// its pipeline and returns it along with the ensured FindParams holding its pagination parameters
+func ensureAggregateParams(p AggregateParams) (AggregateParams, FindParams, error) {
+	if p.Collection == nil && p.MongoCollection != nil {
+		p.Collection = &driverCollection{collection: p.MongoCollection}
+	}
+	if p.Collection == nil {
+		return p, FindParams{}, errors.New("Collection can't be nil")
+	}
+
+	if p.Limit <= 0 {
+		return p, FindParams{}, errors.New("a limit of at least 1 is required")
+	}
+
+	if len(p.Query) > 0 {
+		pipeline := make([]bson.M, 0, len(p.Pipeline)+1)
+		pipeline = append(pipeline, bson.M{"$match": p.Query})
+		p.Pipeline = append(pipeline, p.Pipeline...)
+		p.Query = nil
+	}
+
+	fp := ensureMandatoryParams(p.findParams())
+	if fp.Executor != nil {
+		fp.fingerprint = computeQueryFingerprint(fp, p.Pipeline)
+	}
+	return p, fp, nil
+}
+
+// findParams returns the FindParams holding the pagination parameters of p
+func (p AggregateParams) findParams() FindParams {
+	return FindParams{
+		Limit:             p.Limit,
+		CountTotal:        p.CountTotal,
+		CountPolicy:       p.CountPolicy,
+		CountLimit:        p.CountLimit,
+		Timeout:           p.Timeout,
+		SortAscending:     p.SortAscending,
+		PaginatedField:    p.PaginatedField,
+		PaginatedFields:   p.PaginatedFields,
+		SortOrders:        p.SortOrders,
+		Next:              p.Next,
+		Previous:          p.Previous,
+		IncludeAnchor:     p.IncludeAnchor,
+		Dialect:           p.Dialect,
+		PredicateStrategy: p.PredicateStrategy,
+		ServerVersion:     p.ServerVersion,
+		TiebreakerField:   p.TiebreakerField,
+		CollectionName:    p.CollectionName,
+		RetryPolicy:       p.RetryPolicy,
+		Executor:          p.Executor,
+		DedupWindow:       p.DedupWindow,
+	}
+}
+
+// buildPipeline appends the cursor $match, $sort and $limit stages to the pipeline
+func buildPipeline(pipeline []bson.M, fp FindParams) ([]bson.M, error) {
+	cursorQuery, sort, err := buildCursorQuery(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	stages := make([]bson.M, 0, len(pipeline)+3)
+	stages = append(stages, pipeline...)
+	if cursorQuery != nil {
+		stages = append(stages, bson.M{"$match": cursorQuery})
+	}
+	stages = append(stages, bson.M{"$sort": sort}, bson.M{"$limit": fp.Limit + 1})
+	return stages, nil
+}
+
+// decodeResults decodes the raw documents into the results slice pointer with the registry, the driver's default
+// registry if nil
+func decodeResults(rawResults []bson.Raw, registry *bsoncodec.Registry, results interface{}) error {
+	resultsVal := reflect.ValueOf(results).Elem()
+	elemType := resultsVal.Type().Elem()
+	decoded := reflect.MakeSlice(resultsVal.Type(), 0, len(rawResults))
+	for _, raw := range rawResults {
+		elem := reflect.New(elemType)
+		var err error
+		if registry != nil {
+			err = bson.UnmarshalWithRegistry(registry, raw, elem.Interface())
+		} else {
+			err = bson.Unmarshal(raw, elem.Interface())
+		}
+		if err != nil {
+			return err
+		}
+		decoded = reflect.Append(decoded, elem.Elem())
+	}
+	resultsVal.Set(decoded)
+	return nil
+}
+
+func newAggregateOptions(collation *options.Collation, hint interface{}, timeout time.Duration) *options.AggregateOptions {
+	options := options.Aggregate()
+	if collation != nil {
+		options.SetCollation(collation)
+	}
+	if hint != nil {
+		options.SetHint(hint)
+	}
+	if timeout > time.Duration(0) {
+		options.SetMaxTime(timeout)
+	}
+	return options
+}
+
+// executeAggregateCountQuery counts the documents output by the pipeline, up to one more than the limit unless it
+// is 0
+var executeAggregateCountQuery = func(ctx context.Context, c AggregateCollection, pipeline []bson.M, limit int64, collation *options.Collation, timeout time.Duration) (int, error) {
+	stages := make([]bson.M, 0, len(pipeline)+2)
+	stages = append(stages, pipeline...)
+	if limit > 0 {
+		stages = append(stages, bson.M{"$limit": limit + 1})
+	}
+	stages = append(stages, bson.M{"$count": "count"})
+
+	cursor, err := c.Aggregate(ctx, stages, newAggregateOptions(collation, nil, timeout))
+	if err != nil {
+		return 0, err
+	}
+	var counts []struct {
+		Count int `bson:"count"`
+	}
+	err = cursor.All(ctx, &counts)
+	if err != nil {
+		return 0, err
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+	return counts[0].Count, nil
+}
+
+func executeAggregateQuery(ctx context.Context, c AggregateCollection, pipeline []bson.M, collation *options.Collation, hint interface{}, timeout time.Duration, rawOptions func(*options.AggregateOptions), results *[]bson.Raw) error {
+	options := newAggregateOptions(collation, hint, timeout)
+	if rawOptions != nil {
+		rawOptions(options)
+	}
+	cursor, err := c.Aggregate(ctx, pipeline, options)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, results)
+}
@@ -0,0 +1,33 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestAggregateCountLimit(t *testing.T) {
+	_, col := mcptest.NewItems(t)
+	match := bson.M{"$match": bson.M{"count": bson.M{"$gte": 1}}}
+	for _, tc := range []struct {
+		countLimit       int64
+		expectedCount    int
+		expectedRelation mongo.CountRelation
+	}{
+		{0, 5, mongo.CountRelationEq},
+		{5, 5, mongo.CountRelationEq},
+		{3, 3, mongo.CountRelationGte},
+	} {
+		p := mongo.AggregateParams{Collection: col, Pipeline: []bson.M{match}, Limit: 2, CountTotal: true, CountLimit: tc.countLimit}
+		var results []mcptest.Item
+		cursor, err := mongo.Aggregate(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.Equal(t, tc.expectedCount, cursor.Count)
+		require.Equal(t, tc.expectedRelation, cursor.CountRelation)
+	}
+}
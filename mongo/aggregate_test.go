@@ -0,0 +1,168 @@
+package mongo
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestAggregate(t *testing.T) {
+	type shuffledItem struct {
+		ID      primitive.ObjectID `bson:"_id"`
+		Name    string             `bson:"name"`
+		Shuffle int64              `bson:"_shuffle"`
+	}
+	items := []shuffledItem{
+		{ID: primitive.NewObjectID(), Name: "a", Shuffle: -5},
+		{ID: primitive.NewObjectID(), Name: "b", Shuffle: 3},
+		{ID: primitive.NewObjectID(), Name: "c", Shuffle: 7},
+	}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1], items[2]}}
+	match := bson.M{"$match": bson.M{"name": bson.M{"$exists": true}}}
+	p := Shuffle(AggregateParams{Collection: col, Pipeline: []bson.M{match}, Limit: 2}, "seed")
+
+	// The results' type doesn't need to hold the paginated fields
+	var results []Item
+	cursor, err := Aggregate(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{{ID: items[0].ID, Name: "a"}, {ID: items[1].ID, Name: "b"}}, results)
+	require.True(t, cursor.HasNext)
+	require.False(t, cursor.HasPrevious)
+	require.Equal(t, []bson.M{
+		match,
+		ShuffleStage("seed"),
+		{"$sort": bson.D{{Key: "_shuffle", Value: 1}, {Key: "_id", Value: 1}}},
+		{"$limit": int64(3)},
+	}, col.pipeline)
+
+	col.docs = col.docs[2:]
+	p.Next = cursor.Next
+	var rawResults []bson.Raw
+	cursor, err = Aggregate(context.Background(), p, &rawResults)
+	require.NoError(t, err)
+	require.Len(t, rawResults, 1)
+	require.Equal(t, "c", rawResults[0].Lookup("name").StringValue())
+	require.False(t, cursor.HasNext)
+	require.True(t, cursor.HasPrevious)
+
+	var mapResults []bson.M
+	_, err = Aggregate(context.Background(), p, &mapResults)
+	require.NoError(t, err)
+	require.Equal(t, []bson.M{{"_id": items[2].ID, "name": "c", "_shuffle": int64(7)}}, mapResults)
+	require.Equal(t, []bson.M{
+		match,
+		ShuffleStage("seed"),
+		{"$match": bson.M{"$or": []map[string]interface{}{
+			{"_shuffle": map[string]interface{}{"$gt": int64(3)}},
+			{"$and": []map[string]interface{}{
+				{"_shuffle": map[string]interface{}{"$gte": int64(3)}},
+				{"_id": map[string]interface{}{"$gt": items[1].ID}},
+			}},
+		}}},
+		{"$sort": bson.D{{Key: "_shuffle", Value: 1}, {Key: "_id", Value: 1}}},
+		{"$limit": int64(3)},
+	}, col.pipeline)
+}
+
+func TestAggregateDecodesWithTheRegistry(t *testing.T) {
+	type order struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		Amount cents              `bson:"amount"`
+	}
+	registry := bson.NewRegistry()
+	registry.RegisterTypeDecoder(reflect.TypeOf(cents(0)), bsoncodec.ValueDecoderFunc(func(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, v reflect.Value) error {
+		s, err := vr.ReadString()
+		if err != nil {
+			return err
+		}
+		amount, err := strconv.ParseInt(strings.Replace(s, ".", "", 1), 10, 64)
+		v.SetInt(amount)
+		return err
+	}))
+	setDefaults(t, Defaults{Registry: registry})
+
+	id := primitive.NewObjectID()
+	col := &fakeCollection{docs: []interface{}{bson.M{"_id": id, "amount": "12.34"}}}
+	var results []order
+	_, err := Aggregate(context.Background(), AggregateParams{Collection: col, Limit: 2}, &results)
+	require.NoError(t, err)
+	require.Equal(t, []order{{ID: id, Amount: 1234}}, results)
+}
+
+func TestShuffleDoesNotModifyPipeline(t *testing.T) {
+	pipeline := make([]bson.M, 1, 2)
+	pipeline[0] = bson.M{"$match": bson.M{}}
+	_ = Shuffle(AggregateParams{Pipeline: pipeline}, "seed")
+	require.Equal(t, []bson.M{{"$match": bson.M{}}}, pipeline)
+	require.Equal(t, bson.M{"$addFields": bson.M{
+		"_shuffle": bson.M{"$toHashedIndexKey": bson.M{"$concat": bson.A{"seed", bson.M{"$toString": "$_id"}}}},
+	}}, ShuffleStage("seed"))
+}
+
+func TestBuildPipeline(t *testing.T) {
+	id := primitive.NewObjectID()
+	previous, err := Base64CursorCodec{}.Encode(bson.D{{Key: "_id", Value: id}})
+	require.NoError(t, err)
+	match := bson.M{"$match": bson.M{"name": "a"}}
+	col := &fakeCollection{}
+
+	pipeline, err := BuildPipeline(context.Background(), AggregateParams{Collection: col, Pipeline: []bson.M{match}, Limit: 2, Previous: previous})
+	require.NoError(t, err)
+	require.Equal(t, []bson.M{
+		match,
+		{"$match": bson.M{"_id": map[string]interface{}{"$gt": id}}},
+		{"$sort": bson.D{{Key: "_id", Value: 1}}},
+		{"$limit": int64(3)},
+	}, pipeline)
+	require.Nil(t, col.pipeline)
+
+	// The page resumes at the document the cursor points at
+	pipeline, err = BuildPipeline(context.Background(), AggregateParams{Collection: col, Limit: 2, Previous: previous, IncludeAnchor: true})
+	require.NoError(t, err)
+	require.Equal(t, bson.M{"$match": bson.M{"_id": map[string]interface{}{"$gte": id}}}, pipeline[0])
+
+	_, err = BuildPipeline(context.Background(), AggregateParams{Pipeline: []bson.M{match}, Limit: 2})
+	require.EqualError(t, err, "Collection can't be nil")
+	_, err = BuildPipeline(context.Background(), AggregateParams{Collection: col, Previous: previous})
+	require.EqualError(t, err, "a limit of at least 1 is required")
+}
+
+// pipelinesCollection records the pipelines of all its aggregations
+type pipelinesCollection struct {
+	fakeCollection
+	pipelines []interface{}
+}
+
+func (c *pipelinesCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (MongoCursor, error) {
+	c.pipelines = append(c.pipelines, pipeline)
+	return c.fakeCollection.Aggregate(ctx, pipeline, opts...)
+}
+
+func TestAggregateQuery(t *testing.T) {
+	query := bson.M{"name": "a"}
+	group := bson.M{"$group": bson.M{"_id": "$name"}}
+	col := &pipelinesCollection{}
+	p := AggregateParams{Collection: col, Query: query, Pipeline: []bson.M{group}, Limit: 2, CountTotal: true}
+
+	var results []bson.Raw
+	_, err := Aggregate(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		[]bson.M{{"$match": query}, group, {"$count": "count"}},
+		[]bson.M{{"$match": query}, group, {"$sort": bson.D{{Key: "_id", Value: -1}}}, {"$limit": int64(3)}},
+	}, col.pipelines)
+	require.Equal(t, []bson.M{group}, p.Pipeline)
+
+	pipeline, err := BuildPipeline(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, col.pipelines[1], pipeline)
+}
@@ -0,0 +1,213 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+// cursorIndexKey is the key of the cursor element holding the array index of an ArrayParams cursor
+const cursorIndexKey = "$index"
+
+// ArrayParams holds the parameters of the pagination of the elements of an array embedded in a single parent
+// document, for the models storing too many sub-items per document to fetch them at once
+type ArrayParams struct {
+	Collection AggregateCollection
+	// The mongo collection to query when Collection is nil, sparing the need to wrap it in an AggregateCollection
+	MongoCollection *mongodriver.Collection
+	// The filter of the parent document, e.g. bson.M{"_id": id}. The first document matched is paginated
+	Query bson.M
+	// The name of the array field
+	Field string
+	// The key of the elements continuing the pages, e.g. "createdAt" or "id". The elements must be stored in
+	// ascending order of their unique key, and the cursors hold the key of the boundary elements so that the
+	// pages don't shift when elements are inserted or removed. The cursors hold the index of the boundary
+	// elements if empty, which only suits append only arrays
+	ElementKey string
+	// The number of elements to fetch, should be > 0
+	Limit int64
+	// The value to start querying the page
+	Next string
+	// The value to start querying previous page
+	Previous string
+	// Whether to include the number of elements of the array in the cursor
+	CountTotal bool
+	// This parameter will set the maxTimeMS option on the aggregation of the page
+	Timeout time.Duration
+	// The name of the aggregated collection, identifying it for the Executor
+	CollectionName string
+}
+
+// FindArrayElements paginates the elements of the array Field of the parent document matched by the Query of
+// the provided ArrayParams with $slice, filling the passed in results slice pointer with the elements of the
+// page and returning its Cursor. An empty page is returned when no parent document matches.
+func FindArrayElements(ctx context.Context, p ArrayParams, results interface{}) (Cursor, error) {
+	err := bsonEncoding{}.validate(results, nil)
+	if err != nil {
+		return Cursor{}, err
+	}
+	if p.Collection == nil && p.MongoCollection != nil {
+		p.Collection = &driverCollection{collection: p.MongoCollection}
+	}
+	if p.Collection == nil {
+		return Cursor{}, errors.New("Collection can't be nil")
+	}
+	if p.Field == "" {
+		return Cursor{}, errors.New("Field can't be empty")
+	}
+	if p.Limit <= 0 {
+		return Cursor{}, errors.New("a limit of at least 1 is required")
+	}
+	if p.Next != "" && p.Previous != "" {
+		return Cursor{}, errors.New("next and previous cursors can't be specified together")
+	}
+	fp := applyDefaults(FindParams{Limit: p.Limit, Timeout: p.Timeout, CollectionName: p.CollectionName})
+	p.Limit = fp.Limit
+	codec := fp.baseCursorCodec()
+
+	elements, start, err := arrayElements(p, codec)
+	if err != nil {
+		return Cursor{}, err
+	}
+	pipeline := []bson.M{
+		{"$match": p.Query},
+		{"$limit": 1},
+		{"$project": bson.M{
+			"_id":      0,
+			"elements": elements,
+			"size":     bson.M{"$size": bson.M{"$ifNull": bson.A{"$" + p.Field, bson.A{}}}},
+		}},
+	}
+	var parents []bson.Raw
+	err = fp.execute(ctx, QueryAggregate, func(ctx context.Context) error {
+		return executeAggregateQuery(ctx, p.Collection, pipeline, nil, nil, fp.Timeout, nil, &parents)
+	})
+	if err != nil {
+		return Cursor{}, err
+	}
+	var parent struct {
+		Elements []bson.Raw `bson:"elements"`
+		Size     int        `bson:"size"`
+	}
+	if len(parents) > 0 {
+		err = bson.Unmarshal(parents[0], &parent)
+		if err != nil {
+			return Cursor{}, err
+		}
+	}
+
+	page := parent.Elements
+	hasMore := len(page) > int(p.Limit)
+	var cursor Cursor
+	if p.Previous != "" {
+		if hasMore {
+			page = page[1:]
+		}
+		start -= int64(len(page))
+		cursor.HasPrevious = hasMore || (p.ElementKey == "" && start > 0)
+		cursor.HasNext = true
+	} else {
+		if hasMore {
+			page = page[:p.Limit]
+		}
+		cursor.HasNext = hasMore
+		cursor.HasPrevious = p.Next != ""
+	}
+	if len(page) > 0 {
+		if cursor.HasPrevious {
+			cursor.Previous, err = arrayCursor(p, codec, page[0], start)
+			if err != nil {
+				return Cursor{}, err
+			}
+		}
+		if cursor.HasNext {
+			cursor.Next, err = arrayCursor(p, codec, page[len(page)-1], start+int64(len(page)))
+			if err != nil {
+				return Cursor{}, err
+			}
+		}
+	}
+	if p.CountTotal {
+		cursor.Count = parent.Size
+		cursor.CountSource = CountSourceQuery
+	}
+
+	err = decodeResults(page, fp.settings().Registry, results)
+	if err != nil {
+		return Cursor{}, err
+	}
+	return cursor, nil
+}
+
+// arrayElements returns the expression of the elements of the array fetched for the page of p, along with the
+// index of the first element after the Previous cursor or of the Next cursor for the index cursors. One more
+// element than the limit is fetched to see if there's another page.
+func arrayElements(p ArrayParams, codec CursorCodec) (interface{}, int64, error) {
+	cursor := p.Next
+	if cursor == "" {
+		cursor = p.Previous
+	}
+	var cursorValue interface{}
+	if cursor != "" {
+		cursorData, err := codec.Decode(cursor)
+		if err != nil {
+			return nil, 0, &CursorError{fmt.Errorf("cursor parse failed: %w", err)}
+		}
+		key := p.ElementKey
+		if key == "" {
+			key = cursorIndexKey
+		}
+		if len(cursorData) != 1 || cursorData[0].Key != key {
+			return nil, 0, &CursorError{errors.New("cursor parse failed: the cursor doesn't hold the element key")}
+		}
+		cursorValue = cursorData[0].Value
+	}
+	array := "$" + p.Field
+	fetched := p.Limit + 1
+
+	if p.ElementKey == "" {
+		var index int64
+		if cursorValue != nil {
+			var ok bool
+			index, ok = cursorValue.(int64)
+			if !ok || index < 0 {
+				return nil, 0, &CursorError{errors.New("cursor parse failed: invalid index")}
+			}
+		}
+		if p.Previous != "" {
+			if index == 0 {
+				return bson.M{"$literal": bson.A{}}, index, nil
+			}
+			from := max(index-fetched, 0)
+			return bson.M{"$slice": bson.A{bson.M{"$ifNull": bson.A{array, bson.A{}}}, from, index - from}}, index, nil
+		}
+		return bson.M{"$slice": bson.A{bson.M{"$ifNull": bson.A{array, bson.A{}}}, index, fetched}}, index, nil
+	}
+
+	elements := bson.M{"$ifNull": bson.A{array, bson.A{}}}
+	switch {
+	case p.Next != "":
+		elements = bson.M{"$filter": bson.M{"input": elements, "cond": bson.M{"$gt": bson.A{"$$this." + p.ElementKey, cursorValue}}}}
+	case p.Previous != "":
+		elements = bson.M{"$filter": bson.M{"input": elements, "cond": bson.M{"$lt": bson.A{"$$this." + p.ElementKey, cursorValue}}}}
+		return bson.M{"$slice": bson.A{elements, -fetched}}, 0, nil
+	}
+	return bson.M{"$slice": bson.A{elements, fetched}}, 0, nil
+}
+
+// arrayCursor returns the cursor of the element at the index of the array of p
+func arrayCursor(p ArrayParams, codec CursorCodec, element bson.Raw, index int64) (string, error) {
+	if p.ElementKey == "" {
+		return codec.Encode(bson.D{{Key: cursorIndexKey, Value: index}})
+	}
+	value, err := element.LookupErr(strings.Split(p.ElementKey, ".")...)
+	if err != nil {
+		return "", fmt.Errorf("could not create a cursor: the element has no %s", p.ElementKey)
+	}
+	return codec.Encode(bson.D{{Key: p.ElementKey, Value: value}})
+}
@@ -0,0 +1,84 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFindArrayElements(t *testing.T) {
+	type element struct {
+		ID int `bson:"id"`
+	}
+	parent := func(size int, ids ...int) []interface{} {
+		elements := bson.A{}
+		for _, id := range ids {
+			elements = append(elements, bson.M{"id": id})
+		}
+		return []interface{}{bson.M{"elements": elements, "size": size}}
+	}
+	array := bson.M{"$ifNull": bson.A{"$elements", bson.A{}}}
+	col := &fakeCollection{docs: parent(5, 1, 2, 3)}
+	p := ArrayParams{Collection: col, Query: bson.M{"name": "a"}, Field: "elements", Limit: 2, CountTotal: true}
+
+	var results []element
+	cursor, err := FindArrayElements(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []element{{1}, {2}}, results)
+	require.True(t, cursor.HasNext)
+	require.False(t, cursor.HasPrevious)
+	require.Equal(t, 5, cursor.Count)
+	require.Equal(t, []bson.M{
+		{"$match": bson.M{"name": "a"}},
+		{"$limit": 1},
+		{"$project": bson.M{"_id": 0, "elements": bson.M{"$slice": bson.A{array, int64(0), int64(3)}}, "size": bson.M{"$size": array}}},
+	}, col.pipeline)
+
+	// The index cursors continue from the index of the boundary elements
+	col.docs = parent(5, 3, 4, 5)
+	p.Next = cursor.Next
+	cursor, err = FindArrayElements(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []element{{3}, {4}}, results)
+	require.Equal(t, bson.M{"$slice": bson.A{array, int64(2), int64(3)}}, col.pipeline.([]bson.M)[2]["$project"].(bson.M)["elements"])
+
+	col.docs = parent(5, 1, 2)
+	p.Next = ""
+	p.Previous = cursor.Previous
+	cursor, err = FindArrayElements(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []element{{1}, {2}}, results)
+	require.False(t, cursor.HasPrevious)
+	require.True(t, cursor.HasNext)
+	require.Equal(t, bson.M{"$slice": bson.A{array, int64(0), int64(2)}}, col.pipeline.([]bson.M)[2]["$project"].(bson.M)["elements"])
+
+	// The element key cursors continue from the key of the boundary elements
+	p = ArrayParams{Collection: col, Query: bson.M{"name": "a"}, Field: "elements", ElementKey: "id", Limit: 2}
+	col.docs = parent(5, 1, 2, 3)
+	cursor, err = FindArrayElements(context.Background(), p, &results)
+	require.NoError(t, err)
+	p.Next = cursor.Next
+	col.docs = parent(5, 3)
+	cursor, err = FindArrayElements(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []element{{3}}, results)
+	require.False(t, cursor.HasNext)
+	require.True(t, cursor.HasPrevious)
+	require.Equal(t, bson.M{"$slice": bson.A{
+		bson.M{"$filter": bson.M{"input": array, "cond": bson.M{"$gt": bson.A{"$$this.id", int32(2)}}}},
+		int64(3),
+	}}, col.pipeline.([]bson.M)[2]["$project"].(bson.M)["elements"])
+
+	// No parent document is an empty page
+	col.docs = nil
+	cursor, err = FindArrayElements(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Empty(t, results)
+	require.Equal(t, Cursor{HasPrevious: true}, cursor)
+
+	p.Next = "invalid"
+	_, err = FindArrayElements(context.Background(), p, &results)
+	require.IsType(t, &CursorError{}, err)
+}
@@ -0,0 +1,155 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// ScanBudget emulates the maxScan option removed from modern mongo servers by bounding the work a
+	// paginated find mongo query can do.
+	ScanBudget struct {
+		// The maxTimeMS applied to the find query, the smaller of MaxTime and FindParams.Timeout is used when
+		// both are set. Documents fetched before the time is blown are returned as a partial page
+		MaxTime time.Duration
+		// The number of documents fetched per batch, so partial pages can be returned when MaxTime is blown
+		// in a getMore
+		BatchSize int32
+		// The maximum number of documents the server may examine to compute the page. This is only enforced if
+		// the Collection implements DocsExaminedEstimator. When the estimate exceeds it, the page is empty and
+		// its cursor is the one of the FindParams, so the query can be retried, e.g. with a larger budget
+		MaxDocsExamined int64
+	}
+
+	// DocsExaminedEstimator can be implemented by a Collection to report the number of documents the server
+	// examines to execute a find query, e.g. by sampling explain's executionStats with ExplainDocsExamined.
+	DocsExaminedEstimator interface {
+		EstimateDocsExamined(context.Context, interface{}, *options.FindOptions) (int64, error)
+	}
+)
+
+// ExplainDocsExamined runs the find query with explain's executionStats verbosity on the specified
+// collection and returns the total number of documents examined. As the verbosity executes the query, the explain
+// is bounded by the MaxTime of the options, e.g. the MaxTime of the ScanBudget, failing with a MaxTimeMSExpired
+// error once it is blown.
+func ExplainDocsExamined(ctx context.Context, c *mongodriver.Collection, filter interface{}, opts *options.FindOptions) (int64, error) {
+	var explain struct {
+		ExecutionStats struct {
+			TotalDocsExamined int64 `bson:"totalDocsExamined"`
+		} `bson:"executionStats"`
+	}
+	err := c.Database().RunCommand(ctx, explainCommand(c.Name(), filter, opts)).Decode(&explain)
+	if err != nil {
+		return 0, err
+	}
+	return explain.ExecutionStats.TotalDocsExamined, nil
+}
+
+// explainCommand returns the command explaining the find query of the collection with the executionStats
+// verbosity, bounded by the MaxTime of the options
+func explainCommand(collection string, filter interface{}, opts *options.FindOptions) bson.D {
+	find := bson.D{{Key: "find", Value: collection}, {Key: "filter", Value: filter}}
+	if opts.Sort != nil {
+		find = append(find, bson.E{Key: "sort", Value: opts.Sort})
+	}
+	if opts.Limit != nil {
+		find = append(find, bson.E{Key: "limit", Value: *opts.Limit})
+	}
+	if opts.Hint != nil {
+		find = append(find, bson.E{Key: "hint", Value: opts.Hint})
+	}
+	if opts.Collation != nil {
+		find = append(find, bson.E{Key: "collation", Value: opts.Collation})
+	}
+
+	cmd := bson.D{{Key: "explain", Value: find}, {Key: "verbosity", Value: "executionStats"}}
+	if opts.MaxTime != nil && *opts.MaxTime > 0 {
+		cmd = append(cmd, bson.E{Key: "maxTimeMS", Value: opts.MaxTime.Milliseconds()})
+	}
+	return cmd
+}
+
+// executeBudgetedCursorQuery executes the find query within the specified budget, decoding the documents one
+// by one into results so the documents fetched before the budget is blown are kept, see decodeReversed for the
+// documents fetched in reverse order. It returns true if the budget was exceeded.
+func executeBudgetedCursorQuery(ctx context.Context, c Collection, query []bson.M, sort bson.D, fetchLimit int64, reverse bool, collation *options.Collation, hint interface{}, projection interface{}, budget ScanBudget, timeout time.Duration, rawOptions func(*options.FindOptions), results interface{}) (bool, error) {
+	options := newFindOptions(sort, fetchLimit, collation, hint, projection, budget.maxTime(timeout))
+	if budget.BatchSize > 0 {
+		options.SetBatchSize(budget.BatchSize)
+	}
+	if rawOptions != nil {
+		rawOptions(options)
+	}
+	filter := MergeQueries(query)
+	// The results are empty if the budget is exceeded before any document is fetched
+	resultsVal := reflect.ValueOf(results).Elem()
+	resultsVal.Set(resultsVal.Slice(0, 0))
+
+	if estimator, ok := c.(DocsExaminedEstimator); ok && budget.MaxDocsExamined > 0 {
+		docsExamined, err := estimator.EstimateDocsExamined(ctx, filter, options)
+		if err != nil {
+			// The estimate executing the query blew the MaxTime before the documents were counted
+			if isMaxTimeMSExpired(err) {
+				return true, nil
+			}
+			return false, fmt.Errorf("could not estimate the documents examined: %s", err)
+		}
+		if docsExamined > budget.MaxDocsExamined {
+			return true, nil
+		}
+	}
+
+	findCtx, cancel := serverSelectionContext(ctx)
+	cursor, err := c.Find(findCtx, filter, options)
+	cancel()
+	if err != nil {
+		if isMaxTimeMSExpired(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	defer cursor.Close(ctx)
+
+	if reverse {
+		err = decodeReversed(ctx, cursor, fetchLimit, results)
+	} else {
+		elemType := resultsVal.Type().Elem()
+		for cursor.Next(ctx) {
+			elem := reflect.New(elemType)
+			err = cursor.Decode(elem.Interface())
+			if err != nil {
+				return false, err
+			}
+			resultsVal.Set(reflect.Append(resultsVal, elem.Elem()))
+		}
+		err = cursor.Err()
+	}
+	if err != nil {
+		if isMaxTimeMSExpired(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
+
+// maxTime returns the maxTimeMS of the budgeted find query, the smaller of MaxTime and the timeout of the
+// FindParams when both are set
+func (b ScanBudget) maxTime(timeout time.Duration) time.Duration {
+	if b.MaxTime <= 0 || (timeout > 0 && timeout < b.MaxTime) {
+		return timeout
+	}
+	return b.MaxTime
+}
+
+func isMaxTimeMSExpired(err error) bool {
+	var ce mongodriver.CommandError
+	return errors.As(err, &ce) && ce.IsMaxTimeMSExpiredError()
+}
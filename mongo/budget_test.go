@@ -0,0 +1,179 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type estimatingCollection struct {
+	fakeCollection
+	docsExamined int64
+	estimateErr  error
+}
+
+func (c *estimatingCollection) EstimateDocsExamined(context.Context, interface{}, *options.FindOptions) (int64, error) {
+	return c.docsExamined, c.estimateErr
+}
+
+func TestFindWithScanBudget(t *testing.T) {
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	maxTimeMSExpired := mongodriver.CommandError{Code: 50, Name: "MaxTimeMSExpired"}
+
+	var cases = []struct {
+		name              string
+		collection        Collection
+		budget            ScanBudget
+		expectedResults   []Item
+		expectedNext      bool
+		expectedExceeded  bool
+		expectedErr       error
+		expectedBatchSize *int32
+	}{
+		{
+			name:              "returns the full page when the budget is not exceeded",
+			collection:        &fakeCollection{docs: []interface{}{items[0], items[1]}},
+			budget:            ScanBudget{MaxTime: time.Second, BatchSize: 10},
+			expectedResults:   []Item{items[0], items[1]},
+			expectedNext:      false,
+			expectedExceeded:  false,
+			expectedErr:       nil,
+			expectedBatchSize: func() *int32 { b := int32(10); return &b }(),
+		},
+		{
+			name:             "returns a partial page when max time is blown while iterating",
+			collection:       &fakeCollection{docs: []interface{}{items[0]}, err: maxTimeMSExpired},
+			budget:           ScanBudget{MaxTime: time.Second},
+			expectedResults:  []Item{items[0]},
+			expectedNext:     true,
+			expectedExceeded: true,
+			expectedErr:      nil,
+		},
+		{
+			name:             "aborts when the estimated documents examined exceed the budget",
+			collection:       &estimatingCollection{fakeCollection: fakeCollection{docs: []interface{}{items[0]}}, docsExamined: 1000},
+			budget:           ScanBudget{MaxDocsExamined: 100},
+			expectedResults:  nil,
+			expectedNext:     true,
+			expectedExceeded: true,
+			expectedErr:      nil,
+		},
+		{
+			name:             "aborts when the estimate blows the max time",
+			collection:       &estimatingCollection{fakeCollection: fakeCollection{docs: []interface{}{items[0]}}, estimateErr: maxTimeMSExpired},
+			budget:           ScanBudget{MaxTime: time.Second, MaxDocsExamined: 100},
+			expectedResults:  nil,
+			expectedNext:     true,
+			expectedExceeded: true,
+			expectedErr:      nil,
+		},
+		{
+			name:             "runs the query when the estimated documents examined are within the budget",
+			collection:       &estimatingCollection{fakeCollection: fakeCollection{docs: []interface{}{items[2]}}, docsExamined: 10},
+			budget:           ScanBudget{MaxDocsExamined: 100},
+			expectedResults:  []Item{items[2]},
+			expectedNext:     false,
+			expectedExceeded: false,
+			expectedErr:      nil,
+		},
+		{
+			name:             "errors on other errors",
+			collection:       &fakeCollection{docs: []interface{}{items[0]}, err: errors.New("error")},
+			budget:           ScanBudget{},
+			expectedResults:  nil,
+			expectedNext:     false,
+			expectedExceeded: false,
+			expectedErr:      errors.New("error"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var results []Item
+			budget := tc.budget
+			cursor, err := Find(context.Background(), FindParams{Collection: tc.collection, Limit: 2, ScanBudget: &budget}, &results)
+			require.Equal(t, tc.expectedErr, err)
+			if err != nil {
+				return
+			}
+			require.Equal(t, tc.expectedResults, results)
+			require.Equal(t, tc.expectedNext, cursor.HasNext)
+			require.Equal(t, tc.expectedExceeded, cursor.BudgetExceeded)
+			if tc.expectedBatchSize != nil {
+				require.Equal(t, tc.expectedBatchSize, tc.collection.(*fakeCollection).findOptions.BatchSize)
+			}
+		})
+	}
+}
+
+func TestExplainCommandIsBoundedByMaxTime(t *testing.T) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}}).SetLimit(3).SetMaxTime(1500 * time.Millisecond)
+	require.Equal(t, bson.D{
+		{Key: "explain", Value: bson.D{
+			{Key: "find", Value: "items"},
+			{Key: "filter", Value: bson.M{"name": "a"}},
+			{Key: "sort", Value: bson.D{{Key: "name", Value: 1}}},
+			{Key: "limit", Value: int64(3)},
+		}},
+		{Key: "verbosity", Value: "executionStats"},
+		{Key: "maxTimeMS", Value: int64(1500)},
+	}, explainCommand("items", bson.M{"name": "a"}, opts))
+
+	// The explain is unbounded without a max time
+	require.Len(t, explainCommand("items", bson.M{}, options.Find()), 2)
+}
+
+func TestFindWithScanBudgetMaxTime(t *testing.T) {
+	var cases = []struct {
+		name            string
+		budget          ScanBudget
+		timeout         time.Duration
+		expectedMaxTime *time.Duration
+	}{
+		{name: "uses the max time of the budget", budget: ScanBudget{MaxTime: time.Second}, expectedMaxTime: durationPtr(time.Second)},
+		{name: "falls back to the timeout", budget: ScanBudget{BatchSize: 10}, timeout: 2 * time.Second, expectedMaxTime: durationPtr(2 * time.Second)},
+		{name: "uses the smaller timeout", budget: ScanBudget{MaxTime: time.Second}, timeout: 500 * time.Millisecond, expectedMaxTime: durationPtr(500 * time.Millisecond)},
+		{name: "uses the smaller max time", budget: ScanBudget{MaxTime: time.Second}, timeout: 2 * time.Second, expectedMaxTime: durationPtr(time.Second)},
+		{name: "falls back to the default timeout", budget: ScanBudget{BatchSize: 10}, expectedMaxTime: durationPtr(NewDefaults().Timeout)},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var results []Item
+			col := &fakeCollection{}
+			budget := tc.budget
+			_, err := Find(context.Background(), FindParams{Collection: col, Limit: 2, Timeout: tc.timeout, ScanBudget: &budget}, &results)
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedMaxTime, col.findOptions.MaxTime)
+		})
+	}
+}
+
+func TestFindWithScanBudgetResumesFromTheCursor(t *testing.T) {
+	items := []Item{{ID: primitive.NewObjectID(), Name: "a"}, {ID: primitive.NewObjectID(), Name: "b"}}
+	var results []Item
+	cursor, err := Find(context.Background(), FindParams{Collection: &fakeCollection{docs: []interface{}{items[0], items[1]}}, Limit: 1}, &results)
+	require.NoError(t, err)
+
+	// The estimate blows the budget before anything is fetched, the next query starts at the same cursor
+	col := &estimatingCollection{fakeCollection: fakeCollection{docs: []interface{}{items[1]}}, docsExamined: 1000}
+	exceeded, err := Find(context.Background(), FindParams{Collection: col, Limit: 1, Next: cursor.Next, ScanBudget: &ScanBudget{MaxDocsExamined: 100}}, &results)
+	require.NoError(t, err)
+	require.Empty(t, results)
+	require.True(t, exceeded.BudgetExceeded)
+	require.True(t, exceeded.HasNext)
+	require.Equal(t, cursor.Next, exceeded.Next)
+}
+
+func durationPtr(d time.Duration) *time.Duration {
+	return &d
+}
@@ -0,0 +1,78 @@
+package mongo
+
+import (
+	"reflect"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CanonicalCursorData returns the cursor data with the maps of its values, at any depth, converted to documents
+// sorted by key. The driver encodes maps in their random iteration order, so custom CursorCodecs should encode the
+// canonical cursor data, as Base64CursorCodec does, for the same cursor to always be encoded to the same bytes,
+// e.g. when cursors are cache keys or signed. The cursor data is returned as is when it holds no maps.
+func CanonicalCursorData(cursorData bson.D) bson.D {
+	canonical, _ := canonicalDocument(cursorData)
+	return canonical
+}
+
+// canonicalDocument returns the document with canonical values and whether it differs from doc, copying doc
+// only when it does
+func canonicalDocument(doc bson.D) (bson.D, bool) {
+	for i := range doc {
+		value, changed := canonicalValue(doc[i].Value)
+		if !changed {
+			continue
+		}
+		canonical := make(bson.D, len(doc))
+		copy(canonical, doc)
+		canonical[i].Value = value
+		for j := i + 1; j < len(canonical); j++ {
+			canonical[j].Value, _ = canonicalValue(canonical[j].Value)
+		}
+		return canonical, true
+	}
+	return doc, false
+}
+
+// canonicalValue returns the value with its maps converted to documents sorted by key and whether it differs from
+// v. Values that can't hold maps are returned as is without reflection.
+func canonicalValue(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case nil, bson.RawValue, bson.Raw, string, bool, int32, int64, float64, []byte:
+		return v, false
+	case bson.D:
+		return canonicalDocument(v)
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Map:
+		if val.Type().Key().Kind() != reflect.String {
+			return v, false
+		}
+		doc := make(bson.D, 0, val.Len())
+		iter := val.MapRange()
+		for iter.Next() {
+			value, _ := canonicalValue(iter.Value().Interface())
+			doc = append(doc, bson.E{Key: iter.Key().String(), Value: value})
+		}
+		sort.Slice(doc, func(i, j int) bool { return doc[i].Key < doc[j].Key })
+		return doc, true
+	case reflect.Slice, reflect.Array:
+		if val.Type().Elem().Kind() == reflect.Uint8 {
+			return v, false
+		}
+		for i := 0; i < val.Len(); i++ {
+			if _, changed := canonicalValue(val.Index(i).Interface()); !changed {
+				continue
+			}
+			array := make(bson.A, val.Len())
+			for j := range array {
+				array[j], _ = canonicalValue(val.Index(j).Interface())
+			}
+			return array, true
+		}
+	}
+	return v, false
+}
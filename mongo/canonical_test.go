@@ -0,0 +1,60 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// The cursors must stay byte identical across Go and driver versions, as they may be cache keys or signed
+func TestCursorEncodingGolden(t *testing.T) {
+	id, err := primitive.ObjectIDFromHex("5f1a2b3c4d5e6f7a8b9c0d1e")
+	require.NoError(t, err)
+	createdAt := time.UnixMilli(1700000000123).UTC()
+	item := Item{ID: id, Name: "test item 1", CreatedAt: createdAt}
+	raw, err := bson.Marshal(item)
+	require.NoError(t, err)
+	labels := bson.M{"env": "prod", "team": "core", "region": "eu", "tier": int32(1), "owner": bson.M{"b": 2, "a": 1}}
+
+	itemCursor := "PwAAAAJuYW1lAAwAAAB0ZXN0IGl0ZW0gMQAJY3JlYXRlZEF0AHto5c-LAQAAB19pZABfGis8TV5veoucDR4A"
+	labelsCursor := "cwAAAANsYWJlbHMAVQAAAAJlbnYABQAAAHByb2QAA293bmVyABMAAAAQYQABAAAAEGIAAgAAAAACcmVnaW9uAAMAAABldQACdGVhbQAFAAAAY29yZQAQdGllcgABAAAAAAdfaWQAXxorPE1eb3qLnA0eAA"
+	var cases = []struct {
+		name            string
+		doc             interface{}
+		paginatedFields []string
+		expected        string
+	}{
+		{name: "struct", doc: item, paginatedFields: []string{"name", "createdAt", "_id"}, expected: itemCursor},
+		{name: "struct pointer", doc: &item, paginatedFields: []string{"name", "createdAt", "_id"}, expected: itemCursor},
+		{name: "raw document", doc: bson.Raw(raw), paginatedFields: []string{"name", "createdAt", "_id"}, expected: itemCursor},
+		{name: "map", doc: bson.M{"_id": id, "labels": labels}, paginatedFields: []string{"labels", "_id"}, expected: labelsCursor},
+		{name: "ordered document", doc: bson.D{{Key: "_id", Value: id}, {Key: "labels", Value: labels}}, paginatedFields: []string{"labels", "_id"}, expected: labelsCursor},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Maps are iterated in a random order, which mustn't change the cursor
+			for i := 0; i < 20; i++ {
+				cursor, err := generateCursor(tc.doc, FindParams{PaginatedFields: tc.paginatedFields})
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, cursor)
+			}
+		})
+	}
+}
+
+func TestCanonicalCursorData(t *testing.T) {
+	cursorData := bson.D{{Key: "labels", Value: map[string]interface{}{"b": []bson.M{{"y": 1, "x": 2}}, "a": "v"}}}
+	require.Equal(t, bson.D{{Key: "labels", Value: bson.D{
+		{Key: "a", Value: "v"},
+		{Key: "b", Value: bson.A{bson.D{{Key: "x", Value: 2}, {Key: "y", Value: 1}}}},
+	}}}, CanonicalCursorData(cursorData))
+
+	// The cursor data without maps isn't copied
+	cursorData = bson.D{{Key: "tags", Value: []string{"b", "a"}}, {Key: "_id", Value: primitive.NewObjectID()}}
+	canonical := CanonicalCursorData(cursorData)
+	require.Equal(t, cursorData, canonical)
+	require.Same(t, &cursorData[0], &canonical[0])
+}
@@ -0,0 +1,136 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	simpleCollation = "simple"
+	// The strength used by mongo when a collation doesn't specify it
+	defaultCollationStrength = 3
+)
+
+type (
+	// IndexLister lists the indexes of a collection, e.g. the IndexView returned by mongo.Collection.Indexes
+	IndexLister interface {
+		List(context.Context, ...*options.ListIndexesOptions) (*mongodriver.Cursor, error)
+	}
+
+	indexSpec struct {
+		Key       bson.D `bson:"key"`
+		Collation *struct {
+			Locale   string `bson:"locale"`
+			Strength int    `bson:"strength"`
+		} `bson:"collation"`
+	}
+)
+
+// ValidateCollationIndex returns an ErrNoSupportingIndex when none of the listed indexes supports the sort of the
+// FindParams with its collation. As the cursor query compares the paginated fields with the collation of the sort,
+// both can only use an index created with the same collation, otherwise mongo sorts the documents in memory.
+func ValidateCollationIndex(ctx context.Context, indexes IndexLister, p FindParams) error {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return err
+	}
+	return validateSupportingIndex(ctx, indexes, ensureMandatoryParams(p))
+}
+
+// validateSupportingIndex returns an ErrNoSupportingIndex when none of the listed indexes supports the sort of the
+// ensured FindParams with its collation
+func validateSupportingIndex(ctx context.Context, indexes IndexLister, p FindParams) error {
+	cursor, err := indexes.List(ctx)
+	if err != nil {
+		return err
+	}
+	var specs []indexSpec
+	err = cursor.All(ctx, &specs)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range specs {
+		if indexSupportsSort(spec.Key, p) && indexMatchesCollation(spec, p.Collation) {
+			return nil
+		}
+	}
+
+	sort := make(bson.D, 0, len(p.PaginatedFields))
+	for i := range p.PaginatedFields {
+		sort = append(sort, bson.E{Key: p.PaginatedFields[i], Value: p.SortOrders[i]})
+	}
+	collation := simpleCollation
+	if p.Collation != nil {
+		collation = fmt.Sprintf("%s strength %d", p.Collation.Locale, collationStrength(p.Collation.Strength))
+	}
+	return NewErrNoSupportingIndex(sort, collation)
+}
+
+// indexSupportsSort returns whether the index keys start with the paginated fields of p, in the sort orders or
+// their reverse, after the fields its Query matches by equality
+func indexSupportsSort(key bson.D, p FindParams) bool {
+	for len(key) > 0 && key[0].Key != p.PaginatedFields[0] && matchesEquality(p.Query[key[0].Key]) {
+		key = key[1:]
+	}
+	if len(key) < len(p.PaginatedFields) {
+		return false
+	}
+	var direction int64
+	for i, field := range p.PaginatedFields {
+		order, ok := toSortOrder(key[i].Value)
+		if !ok || key[i].Key != field {
+			return false
+		}
+		d := order * int64(p.SortOrders[i])
+		if direction == 0 {
+			direction = d
+		} else if d != direction {
+			return false
+		}
+	}
+	return true
+}
+
+func indexMatchesCollation(spec indexSpec, collation *options.Collation) bool {
+	if spec.Collation == nil || spec.Collation.Locale == simpleCollation {
+		return collation == nil || collation.Locale == simpleCollation
+	}
+	if collation == nil {
+		return false
+	}
+	return spec.Collation.Locale == collation.Locale &&
+		collationStrength(spec.Collation.Strength) == collationStrength(collation.Strength)
+}
+
+func collationStrength(strength int) int {
+	if strength == 0 {
+		return defaultCollationStrength
+	}
+	return strength
+}
+
+func toSortOrder(v interface{}) (int64, bool) {
+	var order int64
+	switch n := v.(type) {
+	case int32:
+		order = int64(n)
+	case int64:
+		order = n
+	case float64:
+		order = int64(n)
+	default:
+		return 0, false
+	}
+	if order > 0 {
+		return 1, true
+	}
+	if order < 0 {
+		return -1, true
+	}
+	return 0, false
+}
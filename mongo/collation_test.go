@@ -0,0 +1,77 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type fakeIndexLister struct {
+	specs []interface{}
+}
+
+func (l *fakeIndexLister) List(context.Context, ...*options.ListIndexesOptions) (*mongodriver.Cursor, error) {
+	return mongodriver.NewCursorFromDocuments(l.specs, nil, nil)
+}
+
+func TestEnsureMandatoryParamsKeepsCollationOfPaginatedFields(t *testing.T) {
+	collation := &options.Collation{Locale: "en", Strength: 2}
+	p := ensureMandatoryParams(FindParams{PaginatedFields: []string{"name"}, SortOrders: []int{1}, Collation: collation})
+	require.Equal(t, collation, p.Collation)
+
+	p = ensureMandatoryParams(FindParams{Collation: collation})
+	require.Nil(t, p.Collation)
+}
+
+func TestValidateCollationIndex(t *testing.T) {
+	idIndex := bson.M{"name": "_id_", "key": bson.D{{Key: "_id", Value: int32(1)}}}
+	nameIndex := bson.M{"name": "name_1__id_1", "key": bson.D{{Key: "name", Value: int32(1)}, {Key: "_id", Value: int32(1)}}}
+	collatedNameIndex := bson.M{
+		"name":      "name_1__id_1_en",
+		"key":       bson.D{{Key: "name", Value: int32(-1)}, {Key: "_id", Value: int32(-1)}, {Key: "data", Value: int32(1)}},
+		"collation": bson.M{"locale": "en", "strength": int32(2)},
+	}
+	collation := &options.Collation{Locale: "en", Strength: 2}
+
+	var cases = []struct {
+		name        string
+		specs       []interface{}
+		p           FindParams
+		expectedErr error
+	}{
+		{
+			name:        "passes when an index supports the sort without collation",
+			specs:       []interface{}{idIndex, nameIndex},
+			p:           FindParams{PaginatedField: "name", SortAscending: true},
+			expectedErr: nil,
+		},
+		{
+			name:        "passes when a collated index supports the reverse sort",
+			specs:       []interface{}{idIndex, nameIndex, collatedNameIndex},
+			p:           FindParams{PaginatedField: "name", SortAscending: true, Collation: collation},
+			expectedErr: nil,
+		},
+		{
+			name:        "errors when the index collation differs",
+			specs:       []interface{}{idIndex, nameIndex},
+			p:           FindParams{PaginatedField: "name", Collation: collation},
+			expectedErr: NewErrNoSupportingIndex(bson.D{{Key: "name", Value: -1}, {Key: "_id", Value: -1}}, "en strength 2"),
+		},
+		{
+			name:        "errors when no index matches the sort",
+			specs:       []interface{}{idIndex, collatedNameIndex},
+			p:           FindParams{PaginatedFields: []string{"name"}, SortOrders: []int{-1}, Collation: collation},
+			expectedErr: NewErrNoSupportingIndex(bson.D{{Key: "name", Value: -1}, {Key: "_id", Value: 1}}, "en strength 2"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateCollationIndex(context.Background(), &fakeIndexLister{specs: tc.specs}, tc.p)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package mongo
+
+import (
+	"bytes"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ComparableValues returns whether a and b belong to the same bson type bracket, as mongo's comparison query
+// operators only match values of the bracket they're given, except MinKey and MaxKey that compare to every value
+func ComparableValues(a, b interface{}) bool {
+	ta, tb := typeOrder(a), typeOrder(b)
+	return ta == tb || ta == 1 || tb == 1 || ta == 13 || tb == 13
+}
+
+// typeOrder returns the rank of the value's type in the mongo comparison order
+func typeOrder(v interface{}) int {
+	switch v.(type) {
+	case primitive.MinKey:
+		return 1
+	case nil, primitive.Null, primitive.Undefined:
+		return 2
+	case int32, int64, float64, primitive.Decimal128:
+		return 3
+	case string, primitive.Symbol:
+		return 4
+	case bson.D:
+		return 5
+	case bson.A:
+		return 6
+	case primitive.Binary:
+		return 7
+	case primitive.ObjectID:
+		return 8
+	case bool:
+		return 9
+	case primitive.DateTime:
+		return 10
+	case primitive.Timestamp:
+		return 11
+	case primitive.Regex:
+		return 12
+	case primitive.MaxKey:
+		return 13
+	default:
+		return 0
+	}
+}
+
+// CompareValues returns -1, 0 or 1 depending on whether a sorts before, as or after b in mongo's sort order.
+// The values must be of the types decoded from bson into an interface{}, e.g. the cursor values or the fields of a
+// document unmarshaled into a bson.D.
+func CompareValues(a, b interface{}) int {
+	ta, tb := typeOrder(a), typeOrder(b)
+	if ta != tb {
+		return compareInts(int64(ta), int64(tb))
+	}
+
+	switch av := a.(type) {
+	case int32, int64, float64, primitive.Decimal128:
+		// Decimals can't be converted to float64 without losing precision, compare them exactly with integers
+		if c, ok := compareDecimals(av, b); ok {
+			return c
+		}
+		return compareFloats(toFloat64(av), toFloat64(b))
+	case string:
+		return strings.Compare(av, toString(b))
+	case primitive.Symbol:
+		return strings.Compare(string(av), toString(b))
+	case bson.D:
+		bv := b.(bson.D)
+		// Like the server, compare the type of each field before its name and value
+		for i := 0; i < len(av) && i < len(bv); i++ {
+			if c := compareInts(int64(typeOrder(av[i].Value)), int64(typeOrder(bv[i].Value))); c != 0 {
+				return c
+			}
+			if c := strings.Compare(av[i].Key, bv[i].Key); c != 0 {
+				return c
+			}
+			if c := CompareValues(av[i].Value, bv[i].Value); c != 0 {
+				return c
+			}
+		}
+		return compareInts(int64(len(av)), int64(len(bv)))
+	case bson.A:
+		bv := b.(bson.A)
+		for i := 0; i < len(av) && i < len(bv); i++ {
+			if c := CompareValues(av[i], bv[i]); c != 0 {
+				return c
+			}
+		}
+		return compareInts(int64(len(av)), int64(len(bv)))
+	case primitive.Binary:
+		bv := b.(primitive.Binary)
+		if c := compareInts(int64(len(av.Data)), int64(len(bv.Data))); c != 0 {
+			return c
+		}
+		if c := compareInts(int64(av.Subtype), int64(bv.Subtype)); c != 0 {
+			return c
+		}
+		return bytes.Compare(av.Data, bv.Data)
+	case primitive.ObjectID:
+		bv := b.(primitive.ObjectID)
+		return bytes.Compare(av[:], bv[:])
+	case bool:
+		bv := b.(bool)
+		switch {
+		case av == bv:
+			return 0
+		case !av:
+			return -1
+		default:
+			return 1
+		}
+	case primitive.DateTime:
+		return compareInts(int64(av), int64(b.(primitive.DateTime)))
+	case primitive.Timestamp:
+		bv := b.(primitive.Timestamp)
+		if c := compareInts(int64(av.T), int64(bv.T)); c != 0 {
+			return c
+		}
+		return compareInts(int64(av.I), int64(bv.I))
+	case primitive.Regex:
+		bv := b.(primitive.Regex)
+		if c := strings.Compare(av.Pattern, bv.Pattern); c != 0 {
+			return c
+		}
+		return strings.Compare(av.Options, bv.Options)
+	default:
+		return 0
+	}
+}
+
+func compareInts(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// compareDecimals compares the numbers exactly when one is a finite decimal128 and the other a finite decimal128
+// or an integer. ok is false otherwise.
+func compareDecimals(a, b interface{}) (c int, ok bool) {
+	_, aDecimal := a.(primitive.Decimal128)
+	_, bDecimal := b.(primitive.Decimal128)
+	if !aDecimal && !bDecimal {
+		return 0, false
+	}
+	ac, ae, ok := toBigDecimal(a)
+	if !ok {
+		return 0, false
+	}
+	bc, be, ok := toBigDecimal(b)
+	if !ok {
+		return 0, false
+	}
+	// Scale the coefficient of the larger exponent so both have the same exponent
+	ten := big.NewInt(10)
+	if ae > be {
+		ac = new(big.Int).Mul(ac, new(big.Int).Exp(ten, big.NewInt(int64(ae-be)), nil))
+	} else if be > ae {
+		bc = new(big.Int).Mul(bc, new(big.Int).Exp(ten, big.NewInt(int64(be-ae)), nil))
+	}
+	return ac.Cmp(bc), true
+}
+
+// toBigDecimal returns the coefficient and exponent of the finite decimal128 or integer v
+func toBigDecimal(v interface{}) (*big.Int, int, bool) {
+	switch n := v.(type) {
+	case int32:
+		return big.NewInt(int64(n)), 0, true
+	case int64:
+		return big.NewInt(n), 0, true
+	case primitive.Decimal128:
+		coefficient, exponent, err := n.BigInt()
+		if err != nil {
+			return nil, 0, false
+		}
+		return coefficient, exponent, true
+	}
+	return nil, 0, false
+}
+
+func toString(v interface{}) string {
+	if s, ok := v.(primitive.Symbol); ok {
+		return string(s)
+	}
+	s, _ := v.(string)
+	return s
+}
+
+func toFloat64(v interface{}) float64 {
+	switch n := v.(type) {
+	case int32:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	case primitive.Decimal128:
+		f, err := strconv.ParseFloat(n.String(), 64)
+		if err != nil {
+			return math.NaN()
+		}
+		return f
+	}
+	return math.NaN()
+}
@@ -0,0 +1,114 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type (
+	// Config holds the settings of Defaults in a form that can be unmarshaled from JSON or YAML, or loaded from the
+	// environment with ConfigFromEnv, so that the pagination policy of a service is set by its configuration. The
+	// settings left unset keep the value of NewDefaults.
+	Config struct {
+		// The default timeout as parsed by time.ParseDuration, e.g. "30s"
+		Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+		// The maximum limit of the queries, 0 for no maximum
+		MaxLimit *int64 `json:"maxLimit,omitempty" yaml:"maxLimit,omitempty"`
+		// Whether to compute the total count of documents of every query
+		CountTotal *bool `json:"countTotal,omitempty" yaml:"countTotal,omitempty"`
+		// Whether to reject the cursors whose value types don't match the results' paginated fields
+		StrictCursorTypes *bool `json:"strictCursorTypes,omitempty" yaml:"strictCursorTypes,omitempty"`
+		// The reference of the secret holding the key of the cursor codec, e.g. a vault path, which is resolved
+		// by the SecretResolver passed to Defaults rather than held by the configuration
+		CursorKeyRef string `json:"cursorKeyRef,omitempty" yaml:"cursorKeyRef,omitempty"`
+	}
+
+	// SecretResolver returns the secret a reference points at, e.g. from a vault or a mounted file
+	SecretResolver func(ref string) ([]byte, error)
+
+	// CursorCodecFactory returns the CursorCodec of the cursors encoded with key
+	CursorCodecFactory func(key []byte) (CursorCodec, error)
+)
+
+// The names of the environment variables read by ConfigFromEnv, following the prefix
+const (
+	envTimeout           = "TIMEOUT"
+	envMaxLimit          = "MAX_LIMIT"
+	envCountTotal        = "COUNT_TOTAL"
+	envStrictCursorTypes = "STRICT_CURSOR_TYPES"
+	envCursorKeyRef      = "CURSOR_KEY_REF"
+)
+
+// ConfigFromEnv loads a Config from the environment variables named after its settings with the specified
+// prefix, e.g. PAGINATION_TIMEOUT, PAGINATION_MAX_LIMIT, PAGINATION_COUNT_TOTAL, PAGINATION_STRICT_CURSOR_TYPES and
+// PAGINATION_CURSOR_KEY_REF for the "PAGINATION_" prefix. The unset variables leave their settings unset.
+func ConfigFromEnv(prefix string) (Config, error) {
+	var c Config
+	c.Timeout = os.Getenv(prefix + envTimeout)
+	c.CursorKeyRef = os.Getenv(prefix + envCursorKeyRef)
+
+	if value, ok := os.LookupEnv(prefix + envMaxLimit); ok {
+		maxLimit, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid %s%s %q: %s", prefix, envMaxLimit, value, err)
+		}
+		c.MaxLimit = &maxLimit
+	}
+	for name, setting := range map[string]**bool{envCountTotal: &c.CountTotal, envStrictCursorTypes: &c.StrictCursorTypes} {
+		if value, ok := os.LookupEnv(prefix + name); ok {
+			b, err := strconv.ParseBool(strings.TrimSpace(value))
+			if err != nil {
+				return Config{}, fmt.Errorf("invalid %s%s %q: %s", prefix, name, value, err)
+			}
+			*setting = &b
+		}
+	}
+	return c, nil
+}
+
+// Defaults returns NewDefaults overridden by the settings of c, to be passed to SetDefaults. When c references a
+// cursor key, the key is resolved with resolve and the cursor codec is built by newCodec.
+func (c Config) Defaults(resolve SecretResolver, newCodec CursorCodecFactory) (Defaults, error) {
+	d := NewDefaults()
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return Defaults{}, fmt.Errorf("invalid timeout %q: %s", c.Timeout, err)
+		}
+		if timeout < 0 {
+			return Defaults{}, fmt.Errorf("invalid timeout %q: it can't be negative", c.Timeout)
+		}
+		d.Timeout = timeout
+	}
+	if c.MaxLimit != nil {
+		if *c.MaxLimit < 0 {
+			return Defaults{}, fmt.Errorf("invalid maximum limit %d: it can't be negative", *c.MaxLimit)
+		}
+		d.MaxLimit = *c.MaxLimit
+	}
+	if c.CountTotal != nil {
+		d.CountTotal = *c.CountTotal
+	}
+	if c.StrictCursorTypes != nil {
+		d.StrictCursorTypes = *c.StrictCursorTypes
+	}
+
+	if c.CursorKeyRef != "" {
+		if resolve == nil || newCodec == nil {
+			return Defaults{}, errors.New("a cursor key reference requires a SecretResolver and a CursorCodecFactory")
+		}
+		key, err := resolve(c.CursorKeyRef)
+		if err != nil {
+			return Defaults{}, fmt.Errorf("could not resolve the cursor key %q: %s", c.CursorKeyRef, err)
+		}
+		d.CursorCodec, err = newCodec(key)
+		if err != nil {
+			return Defaults{}, fmt.Errorf("could not create the cursor codec: %s", err)
+		}
+	}
+	return d, nil
+}
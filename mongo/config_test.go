@@ -0,0 +1,61 @@
+package mongo
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	var c Config
+	err := json.Unmarshal([]byte(`{"timeout": "10s", "maxLimit": 100, "strictCursorTypes": false, "cursorKeyRef": "vault:pagination/key"}`), &c)
+	require.NoError(t, err)
+
+	var resolvedRef string
+	resolve := func(ref string) ([]byte, error) {
+		resolvedRef = ref
+		return []byte("v1."), nil
+	}
+	newCodec := func(key []byte) (CursorCodec, error) {
+		require.Equal(t, []byte("v1."), key)
+		return prefixCodec{}, nil
+	}
+	d, err := c.Defaults(resolve, newCodec)
+	require.NoError(t, err)
+	require.Equal(t, "vault:pagination/key", resolvedRef)
+	require.Equal(t, Defaults{Timeout: 10 * time.Second, MaxLimit: 100, CursorCodec: prefixCodec{}}, d)
+
+	// The settings left unset keep their default value
+	d, err = Config{}.Defaults(nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, NewDefaults(), d)
+
+	_, err = Config{Timeout: "soon"}.Defaults(nil, nil)
+	require.EqualError(t, err, `invalid timeout "soon": time: invalid duration "soon"`)
+	_, err = Config{CursorKeyRef: "vault:pagination/key"}.Defaults(nil, nil)
+	require.EqualError(t, err, "a cursor key reference requires a SecretResolver and a CursorCodecFactory")
+	_, err = Config{CursorKeyRef: "vault:pagination/key"}.Defaults(func(string) ([]byte, error) { return nil, errors.New("sealed") }, newCodec)
+	require.EqualError(t, err, `could not resolve the cursor key "vault:pagination/key": sealed`)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("PAGINATION_TIMEOUT", "1m")
+	t.Setenv("PAGINATION_MAX_LIMIT", "50")
+	t.Setenv("PAGINATION_COUNT_TOTAL", "true")
+	c, err := ConfigFromEnv("PAGINATION_")
+	require.NoError(t, err)
+	countTotal := true
+	maxLimit := int64(50)
+	require.Equal(t, Config{Timeout: "1m", MaxLimit: &maxLimit, CountTotal: &countTotal}, c)
+
+	d, err := c.Defaults(nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, Defaults{Timeout: time.Minute, MaxLimit: 50, CountTotal: true, StrictCursorTypes: true, CursorCodec: Base64CursorCodec{}}, d)
+
+	t.Setenv("PAGINATION_STRICT_CURSOR_TYPES", "sometimes")
+	_, err = ConfigFromEnv("PAGINATION_")
+	require.EqualError(t, err, `invalid PAGINATION_STRICT_CURSOR_TYPES "sometimes": strconv.ParseBool: parsing "sometimes": invalid syntax`)
+}
@@ -0,0 +1,45 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Consistency is how exactly the HasPrevious and HasNext values of a Cursor reflect the documents on the other
+// side of the cursor a page was queried from
+type Consistency int
+
+const (
+	// ConsistencyFast assumes that documents precede the page queried with Next, and follow the page queried with
+	// Previous, which no longer holds once they're deleted. It doesn't cost an additional query.
+	ConsistencyFast Consistency = iota
+	// ConsistencyExact probes for a document preceding the page queried with Next, or following the page queried
+	// with Previous, with an additional find query of a single _id. The Previous or Next cursor is then empty if
+	// there's none, e.g. when the documents of the first page were all deleted.
+	ConsistencyExact
+)
+
+// probeBeyondCursor returns whether a document matches the query of p on the other side of its Next or Previous
+// cursor than the page, i.e. before the page queried with Next or after the one queried with Previous
+func probeBeyondCursor(ctx context.Context, p FindParams) (bool, error) {
+	probe := p
+	probe.Next, probe.Previous = p.Previous, p.Next
+	// The anchor document is on the other side of the cursor unless the page includes it
+	probe.IncludeAnchor = !p.IncludeAnchor
+	cursorQuery, sort, err := buildCursorQuery(probe)
+	if err != nil {
+		return false, err
+	}
+
+	options := newFindOptions(sort, 1, p.Collation, p.Hint, bson.M{"_id": 1}, p.Timeout)
+	mongoCursor, err := p.Collection.Find(ctx, MergeQueries([]bson.M{p.Query, cursorQuery}), options)
+	if err != nil {
+		return false, err
+	}
+	defer mongoCursor.Close(ctx)
+	if mongoCursor.Next(ctx) {
+		return true, nil
+	}
+	return false, mongoCursor.Err()
+}
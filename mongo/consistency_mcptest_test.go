@@ -0,0 +1,50 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindExactConsistencyAfterDeletions(t *testing.T) {
+	items, col := mcptest.NewItems(t)
+	p := mongo.FindParams{Collection: col, Limit: 2}
+
+	var results []mcptest.Item
+	firstPage, err := mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []mcptest.Item{items[4], items[3]}, results)
+
+	// The documents of the first page are deleted
+	remaining, err := mcptest.NewCollection(items[0], items[1], items[2])
+	require.NoError(t, err)
+	for _, consistency := range []mongo.Consistency{mongo.ConsistencyFast, mongo.ConsistencyExact} {
+		p := mongo.FindParams{Collection: remaining, Limit: 2, Next: firstPage.Next, Consistency: consistency}
+		cursor, err := mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []mcptest.Item{items[2], items[1]}, results)
+		require.True(t, cursor.HasNext)
+		require.Equal(t, consistency == mongo.ConsistencyFast, cursor.HasPrevious)
+		require.Equal(t, consistency == mongo.ConsistencyFast, cursor.Previous != "")
+	}
+
+	// The document following the page queried with Previous is deleted
+	next, err := mongo.GenerateCursor(items[1], p)
+	require.NoError(t, err)
+	lastPage, err := mongo.Find(context.Background(), mongo.FindParams{Collection: col, Limit: 2, Next: next}, &results)
+	require.NoError(t, err)
+	require.Equal(t, []mcptest.Item{items[0]}, results)
+	remaining, err = mcptest.NewCollection(items[1], items[2], items[3], items[4])
+	require.NoError(t, err)
+	for _, consistency := range []mongo.Consistency{mongo.ConsistencyFast, mongo.ConsistencyExact} {
+		p := mongo.FindParams{Collection: remaining, Limit: 2, Previous: lastPage.Previous, Consistency: consistency}
+		cursor, err := mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []mcptest.Item{items[2], items[1]}, results)
+		require.True(t, cursor.HasPrevious)
+		require.Equal(t, consistency == mongo.ConsistencyFast, cursor.HasNext)
+	}
+}
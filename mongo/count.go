@@ -0,0 +1,147 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// CountPolicy is how the total count of documents is computed for the pages queried with a Next or Previous
+// cursor when CountTotal is set, as most clients only need it once
+type CountPolicy int
+
+const (
+	// CountEveryPage counts the documents of every page with an additional query
+	CountEveryPage CountPolicy = iota
+	// CountFirstPage only counts the documents of the page queried without cursor, the Count of the other pages
+	// is 0
+	CountFirstPage
+	// CountCarried counts the documents of the page queried without cursor and carries the count in its cursors,
+	// serving it to the following pages without querying. The count may be stale, and may be tampered with
+	// unless the CursorCodec authenticates the cursors. The documents are counted when the cursor carries no count
+	CountCarried
+)
+
+// CountSource tells where the Count of a Cursor comes from
+type CountSource int
+
+const (
+	// CountSourceNone is set when CountTotal isn't set
+	CountSourceNone CountSource = iota
+	// CountSourceQuery is set when the documents were counted
+	CountSourceQuery
+	// CountSourceCursor is set when the count was carried by the Next or Previous cursor, see CountCarried
+	CountSourceCursor
+	// CountSourceSkipped is set when the documents weren't counted by the CountPolicy
+	CountSourceSkipped
+)
+
+// CountRelation tells how the Count of a Cursor relates to the total count of documents, as the relation of the
+// total hits of Elasticsearch
+type CountRelation int
+
+const (
+	// CountRelationEq is set when the Count is exact
+	CountRelationEq CountRelation = iota
+	// CountRelationGte is set when more documents than the CountLimit match, the Count being the CountLimit
+	CountRelationGte
+)
+
+// cursorCountKey is the key of the cursor element carrying the count of the documents, see CountCarried
+const cursorCountKey = "$count"
+
+// countCursorCodec carries a count in the cursors of a CursorCodec, stripping it from the decoded cursors
+type countCursorCodec struct {
+	codec CursorCodec
+	// The count carried by the encoded cursors, none if nil
+	count *int
+}
+
+// Encode implements CursorCodec
+func (c countCursorCodec) Encode(cursorData bson.D) (string, error) {
+	if c.count == nil {
+		return c.codec.Encode(cursorData)
+	}
+	counted := make(bson.D, 0, len(cursorData)+1)
+	counted = append(counted, cursorData...)
+	return c.codec.Encode(append(counted, bson.E{Key: cursorCountKey, Value: int64(*c.count)}))
+}
+
+// Decode implements CursorCodec
+func (c countCursorCodec) Decode(cursor string) (bson.D, error) {
+	cursorData, err := c.codec.Decode(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := carriedCount(cursorData); ok {
+		return cursorData[:len(cursorData)-1], nil
+	}
+	return cursorData, nil
+}
+
+// carriedCount returns the count carried by the decoded cursor data, if any
+func carriedCount(cursorData bson.D) (int, bool) {
+	if len(cursorData) == 0 || cursorData[len(cursorData)-1].Key != cursorCountKey {
+		return 0, false
+	}
+	switch count := cursorData[len(cursorData)-1].Value.(type) {
+	case int64:
+		return int(count), true
+	case int32:
+		return int(count), true
+	}
+	return 0, false
+}
+
+// countTotal returns the total count of documents of p, as computed by count, and where it comes from according
+// to the CountPolicy of p
+func countTotal(p FindParams, count func() (int, error)) (int, CountSource, error) {
+	if !p.CountTotal {
+		return 0, CountSourceNone, nil
+	}
+	cursor := p.Next
+	if cursor == "" {
+		cursor = p.Previous
+	}
+	if cursor != "" {
+		switch p.CountPolicy {
+		case CountFirstPage:
+			return 0, CountSourceSkipped, nil
+		case CountCarried:
+			// An invalid cursor is rejected when building the queries
+			cursorData, err := p.baseCursorCodec().Decode(cursor)
+			if err == nil {
+				if n, ok := carriedCount(cursorData); ok {
+					return n, CountSourceCursor, nil
+				}
+			}
+		}
+	}
+
+	n, err := count()
+	if err != nil {
+		return 0, CountSourceNone, err
+	}
+	return n, CountSourceQuery, nil
+}
+
+// carryCount returns p whose cursors carry the count from source when its CountPolicy is CountCarried
+func (p FindParams) carryCount(count int, source CountSource) FindParams {
+	if source == CountSourceQuery || source == CountSourceCursor {
+		p.carriedCount = &count
+	}
+	return p
+}
+
+// cappedCount returns the Count of the Cursor of p and its CountRelation, the documents being counted up to one more
+// than the CountLimit of p
+func (p FindParams) cappedCount(count int) (int, CountRelation) {
+	if p.CountLimit > 0 && int64(count) > p.CountLimit {
+		return int(p.CountLimit), CountRelationGte
+	}
+	return count, CountRelationEq
+}
+
+// HasExactCount returns whether the Count of the Cursor is the exact count of documents rather than truncated to
+// the CountLimit of the FindParams
+func (c Cursor) HasExactCount() bool {
+	return c.CountRelation == CountRelationEq
+}
@@ -0,0 +1,130 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// countingCollection counts the count queries of a fakeCollection
+type countingCollection struct {
+	*fakeCollection
+	counts int
+}
+
+func (c *countingCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	c.counts++
+	return c.fakeCollection.CountDocuments(ctx, filter, opts...)
+}
+
+func TestFindCountPolicies(t *testing.T) {
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	var cases = []struct {
+		policy              CountPolicy
+		expectedCount       int
+		expectedCountSource CountSource
+		expectedCounts      int
+	}{
+		{CountEveryPage, 3, CountSourceQuery, 2},
+		{CountFirstPage, 0, CountSourceSkipped, 1},
+		{CountCarried, 3, CountSourceCursor, 1},
+	}
+	for _, tc := range cases {
+		col := &countingCollection{fakeCollection: &fakeCollection{docs: []interface{}{items[2], items[1], items[0]}}}
+		p := FindParams{Collection: col, Limit: 1, CountTotal: true, CountPolicy: tc.policy}
+
+		var results []Item
+		cursor, err := Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, 3, cursor.Count)
+		require.Equal(t, CountSourceQuery, cursor.CountSource)
+
+		p.Next = cursor.Next
+		cursor, err = Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, tc.expectedCount, cursor.Count)
+		require.Equal(t, tc.expectedCountSource, cursor.CountSource)
+		require.Equal(t, tc.expectedCounts, col.counts)
+
+		// The count is carried from page to page
+		p.Next = ""
+		p.Previous = cursor.Previous
+		cursor, err = Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, tc.expectedCount, cursor.Count)
+		require.Equal(t, tc.expectedCountSource, cursor.CountSource)
+	}
+
+	cursor, err := Find(context.Background(), FindParams{Collection: &fakeCollection{}, Limit: 1}, &[]Item{})
+	require.NoError(t, err)
+	require.Equal(t, CountSourceNone, cursor.CountSource)
+}
+
+func TestFindCountsWhenCursorCarriesNoCount(t *testing.T) {
+	item := Item{ID: primitive.NewObjectID(), Name: "a"}
+	col := &countingCollection{fakeCollection: &fakeCollection{docs: []interface{}{item}}}
+	p := FindParams{Collection: col, Limit: 1, CountTotal: true}
+	next, err := GenerateCursor(item, p)
+	require.NoError(t, err)
+
+	p.CountPolicy = CountCarried
+	p.Next = next
+	cursor, err := Find(context.Background(), p, &[]Item{})
+	require.NoError(t, err)
+	require.Equal(t, 1, cursor.Count)
+	require.Equal(t, CountSourceQuery, cursor.CountSource)
+	require.Equal(t, 1, col.counts)
+}
+
+// limitedCountCollection counts at most the limit of the count queries of a fakeCollection
+type limitedCountCollection struct {
+	*fakeCollection
+}
+
+func (c *limitedCountCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	count, err := c.fakeCollection.CountDocuments(ctx, filter, opts...)
+	if opts[0].Limit != nil {
+		count = min(count, *opts[0].Limit)
+	}
+	return count, err
+}
+
+func TestFindCountLimit(t *testing.T) {
+	var docs []interface{}
+	for i := 0; i < 5; i++ {
+		docs = append(docs, Item{ID: primitive.NewObjectID()})
+	}
+	col := &limitedCountCollection{fakeCollection: &fakeCollection{docs: docs}}
+	for _, tc := range []struct {
+		countLimit       int64
+		expectedCount    int
+		expectedRelation CountRelation
+	}{
+		{0, 5, CountRelationEq},
+		{5, 5, CountRelationEq},
+		{4, 4, CountRelationGte},
+	} {
+		p := FindParams{Collection: col, Limit: 2, CountTotal: true, CountLimit: tc.countLimit, CountPolicy: CountCarried}
+		var results []Item
+		cursor, err := Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, tc.expectedCount, cursor.Count)
+		require.Equal(t, tc.expectedRelation, cursor.CountRelation)
+		require.Equal(t, tc.expectedRelation == CountRelationEq, cursor.HasExactCount())
+
+		// The relation of a carried count is kept
+		p.Next = cursor.Next
+		cursor, err = Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, CountSourceCursor, cursor.CountSource)
+		require.Equal(t, tc.expectedCount, cursor.Count)
+		require.Equal(t, tc.expectedRelation, cursor.CountRelation)
+	}
+}
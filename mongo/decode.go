@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// findDecoded executes the find query of p for raw documents and fills the passed in result slice pointer with
+// the values its DecodeFunc decodes them into
+func findDecoded(ctx context.Context, p FindParams, results interface{}) (Cursor, error) {
+	err := validateDecodedResults(results)
+	if err != nil {
+		return Cursor{}, err
+	}
+	decode := p.DecodeFunc
+	p.DecodeFunc = nil
+	var rawResults []bson.Raw
+	cursor, err := Find(ctx, p, &rawResults)
+	if err != nil {
+		return Cursor{}, err
+	}
+	err = decodeResultsWith(rawResults, decode, results)
+	if err != nil {
+		return Cursor{}, err
+	}
+	return cursor, nil
+}
+
+// validateDecodedResults returns an error if results isn't a pointer to a slice of an interface type, holding the
+// values decoded by a DecodeFunc
+func validateDecodedResults(results interface{}) error {
+	val := reflect.TypeOf(results)
+	if val == nil || val.Kind() != reflect.Ptr || val.Elem().Kind() != reflect.Slice {
+		return NewErrInvalidResults("expected results to be a slice pointer")
+	}
+	if val.Elem().Elem().Kind() != reflect.Interface {
+		return NewErrInvalidResults("expected results' element to be an interface with a DecodeFunc")
+	}
+	return nil
+}
+
+// decodeResultsWith decodes the raw documents with the DecodeFunc into the results slice pointer
+func decodeResultsWith(rawResults []bson.Raw, decode DecodeFunc, results interface{}) error {
+	resultsVal := reflect.ValueOf(results).Elem()
+	elemType := resultsVal.Type().Elem()
+	decoded := reflect.MakeSlice(resultsVal.Type(), 0, len(rawResults))
+	for i, raw := range rawResults {
+		v, err := decode(raw)
+		if err != nil {
+			return fmt.Errorf("could not decode document %d: %w", i, err)
+		}
+		elem := reflect.New(elemType).Elem()
+		if v != nil {
+			value := reflect.ValueOf(v)
+			if !value.Type().AssignableTo(elemType) {
+				return NewErrInvalidResults(fmt.Sprintf("decoded %T is not a %s", v, elemType))
+			}
+			elem.Set(value)
+		}
+		decoded = reflect.Append(decoded, elem)
+	}
+	resultsVal.Set(decoded)
+	return nil
+}
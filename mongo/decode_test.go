@@ -0,0 +1,79 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	shape interface{ area() float64 }
+
+	circle struct {
+		ID     primitive.ObjectID `bson:"_id"`
+		Radius float64            `bson:"radius"`
+	}
+
+	square struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Side float64            `bson:"side"`
+	}
+)
+
+func (c circle) area() float64 { return 3 * c.Radius * c.Radius }
+func (s square) area() float64 { return s.Side * s.Side }
+
+// decodeShape decodes a shape by its kind discriminator field
+func decodeShape(raw bson.Raw) (interface{}, error) {
+	switch raw.Lookup("kind").StringValue() {
+	case "circle":
+		var c circle
+		return c, bson.Unmarshal(raw, &c)
+	case "square":
+		var s square
+		return s, bson.Unmarshal(raw, &s)
+	default:
+		return nil, errors.New("unknown kind")
+	}
+}
+
+func TestFindDecodeFunc(t *testing.T) {
+	ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+	col := &fakeCollection{docs: []interface{}{
+		bson.D{{Key: "_id", Value: ids[0]}, {Key: "kind", Value: "circle"}, {Key: "radius", Value: 1.0}},
+		bson.D{{Key: "_id", Value: ids[1]}, {Key: "kind", Value: "square"}, {Key: "side", Value: 2.0}},
+		bson.D{{Key: "_id", Value: ids[2]}, {Key: "kind", Value: "circle"}, {Key: "radius", Value: 3.0}},
+	}}
+	p := FindParams{Collection: col, Limit: 2, DecodeFunc: decodeShape}
+
+	var shapes []shape
+	cursor, err := Find(context.Background(), p, &shapes)
+	require.NoError(t, err)
+	require.Equal(t, []shape{circle{ID: ids[0], Radius: 1}, square{ID: ids[1], Side: 2}}, shapes)
+	cursorData, err := Base64CursorCodec{}.Decode(cursor.Next)
+	require.NoError(t, err)
+	require.Equal(t, bson.D{{Key: "_id", Value: ids[1]}}, cursorData)
+
+	var values []interface{}
+	_, err = Aggregate(context.Background(), AggregateParams{Collection: col, Limit: 3, DecodeFunc: decodeShape}, &values)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{circle{ID: ids[0], Radius: 1}, square{ID: ids[1], Side: 2}, circle{ID: ids[2], Radius: 3}}, values)
+
+	var items []Item
+	_, err = Find(context.Background(), p, &items)
+	require.EqualError(t, err, "expected results' element to be an interface with a DecodeFunc")
+
+	p.DecodeFunc = func(bson.Raw) (interface{}, error) { return "not a shape", nil }
+	_, err = Find(context.Background(), p, &shapes)
+	require.EqualError(t, err, "decoded string is not a mongo.shape")
+
+	col.docs = append(col.docs, bson.D{{Key: "_id", Value: primitive.NewObjectID()}, {Key: "kind", Value: "triangle"}})
+	col.docs = col.docs[2:]
+	p.DecodeFunc = decodeShape
+	_, err = Find(context.Background(), p, &shapes)
+	require.EqualError(t, err, "could not decode document 1: unknown kind")
+}
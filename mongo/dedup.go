@@ -0,0 +1,94 @@
+package mongo
+
+import (
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// cursorSeenKey is the key of the cursor element holding the tiebreaker values of the documents at the ends of
+// the page, see FindParams.DedupWindow
+const cursorSeenKey = "$seen"
+
+// seenCursorCodec carries the tiebreaker values of the documents delivered at the ends of a page in the cursors
+// of a CursorCodec, stripping them from the decoded cursors
+type seenCursorCodec struct {
+	codec CursorCodec
+	// The tiebreaker values carried by the encoded cursors, none if empty
+	seen bson.A
+}
+
+// Encode implements CursorCodec
+func (c seenCursorCodec) Encode(cursorData bson.D) (string, error) {
+	if len(c.seen) == 0 {
+		return c.codec.Encode(cursorData)
+	}
+	seen := make(bson.D, 0, len(cursorData)+1)
+	seen = append(seen, cursorData...)
+	return c.codec.Encode(append(seen, bson.E{Key: cursorSeenKey, Value: c.seen}))
+}
+
+// Decode implements CursorCodec
+func (c seenCursorCodec) Decode(cursor string) (bson.D, error) {
+	cursorData, err := c.codec.Decode(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := carriedSeen(cursorData); ok {
+		return cursorData[:len(cursorData)-1], nil
+	}
+	return cursorData, nil
+}
+
+// carriedSeen returns the tiebreaker values carried by the decoded cursor data, if any
+func carriedSeen(cursorData bson.D) (bson.A, bool) {
+	if len(cursorData) == 0 || cursorData[len(cursorData)-1].Key != cursorSeenKey {
+		return nil, false
+	}
+	seen, ok := cursorData[len(cursorData)-1].Value.(bson.A)
+	return seen, ok
+}
+
+// seenQuery returns the query excluding the documents whose tiebreaker values are carried by the Next or Previous
+// cursor of p, or nil when it carries none
+func seenQuery(p FindParams) bson.M {
+	cursor := p.Next
+	if cursor == "" {
+		cursor = p.Previous
+	}
+	if p.DedupWindow <= 0 || cursor == "" {
+		return nil
+	}
+	// The count carried after the tiebreaker values is stripped first, an invalid cursor is rejected when parsed
+	cursorData, err := countCursorCodec{codec: p.baseCursorCodec()}.Decode(cursor)
+	if err != nil {
+		return nil
+	}
+	seen, ok := carriedSeen(cursorData)
+	if !ok || len(seen) == 0 {
+		return nil
+	}
+	return bson.M{p.tiebreaker(): bson.M{"$nin": seen}}
+}
+
+// carrySeen returns p whose cursors carry the tiebreaker values of the DedupWindow first and last documents of
+// the page when it is set
+func (p FindParams) carrySeen(page reflect.Value) (FindParams, error) {
+	if p.DedupWindow <= 0 {
+		return p, nil
+	}
+	n := page.Len()
+	seen := bson.A{}
+	for i := 0; i < n; i++ {
+		if i >= p.DedupWindow && i < n-p.DedupWindow {
+			continue
+		}
+		values, err := paginatedValues(page.Index(i).Interface(), p)
+		if err != nil {
+			return p, err
+		}
+		seen = append(seen, values[len(values)-1])
+	}
+	p.seen = seen
+	return p, nil
+}
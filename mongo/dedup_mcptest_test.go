@@ -0,0 +1,36 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindDedupWindowSkipsMovedDocuments(t *testing.T) {
+	items, col := mcptest.NewItems(t)
+	for dedupWindow, expected := range map[int][]mcptest.Item{0: {items[2], items[1]}, 1: {items[2], items[3]}} {
+		p := mongo.FindParams{Collection: col, Limit: 2, PaginatedField: "createdAt", SortAscending: true, DedupWindow: dedupWindow}
+		var results []mcptest.Item
+		cursor, err := mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, items[:2], results)
+
+		// The last document of the page is updated past the cursor before the next page is requested
+		moved := items[1]
+		moved.CreatedAt = items[2].CreatedAt.Add(time.Minute)
+		updated, err := mcptest.NewCollection(items[0], moved, items[2], items[3], items[4])
+		require.NoError(t, err)
+
+		p.Collection = updated
+		p.Next = cursor.Next
+		_, err = mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		expectedIDs := []primitive.ObjectID{expected[0].ID, expected[1].ID}
+		require.Equal(t, expectedIDs, []primitive.ObjectID{results[0].ID, results[1].ID})
+	}
+}
@@ -0,0 +1,209 @@
+package mongo
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+)
+
+type (
+	// CursorCodec converts the paginated field values of a document to the cursor string handed to clients and
+	// back.
+	CursorCodec interface {
+		Encode(cursorData bson.D) (string, error)
+		Decode(cursor string) (bson.D, error)
+	}
+
+	// Base64CursorCodec is the default CursorCodec, encoding the cursor data as base64 URL encoded BSON. The
+	// cursors come from untrusted clients, so it rejects the cursors beyond its limits, the malformed BSON
+	// documents and the cursors holding code, symbols, db pointers or undefined values before unmarshaling them.
+	Base64CursorCodec struct {
+		// The maximum size of a decoded cursor in bytes, mcpbson.DefaultMaxCursorSize if 0
+		MaxSize int
+		// The maximum nesting depth of the documents and arrays of a cursor, mcpbson.DefaultMaxCursorDepth if 0
+		MaxDepth int
+	}
+
+	// Defaults holds the package wide settings applied to every query, e.g. to enforce an organization wide
+	// policy from a small wrapper module at init. They are snapshotted when a query starts, so changing them
+	// doesn't affect the queries in progress.
+	Defaults struct {
+		// The maxTimeMS of the queries whose params don't set a Timeout. No maxTimeMS is set if it is 0
+		Timeout time.Duration
+		// The maximum Limit of the queries, larger limits are lowered to it. There is no maximum if it is 0
+		MaxLimit int64
+		// true to compute the total count of documents matching the filter of every query, as if their params
+		// set CountTotal
+		CountTotal bool
+		// true to reject the cursors whose values don't have the bson types of the results' paginated fields
+		// with an ErrCursorTypeMismatch
+		StrictCursorTypes bool
+		// The codec of the cursors, Base64CursorCodec if nil
+		CursorCodec CursorCodec
+		// The registry marshaling the results into cursors, which should be the one of the mongo client decoding
+		// them, e.g. when it registers codecs of custom field types. The driver's default registry if nil
+		Registry *bsoncodec.Registry
+		// The resolver of the bson keys of the results' struct fields, which should resolve them as the struct
+		// codec of the Registry does, e.g. when it is built with a custom StructTagParser. The keys are resolved
+		// from the bson tags as the driver's default struct codec does if nil
+		FieldNameResolver FieldNameResolver
+		// The RetryPolicy of the queries whose params don't set one. The queries aren't retried if nil
+		RetryPolicy *RetryPolicy
+		// The Executor of the queries whose params don't set one. The queries are run directly if nil
+		Executor Executor
+		// The IndexCheck of the queries whose params leave it off, e.g. IndexCheckStrict in staging
+		IndexCheck IndexCheck
+		// Reports the sorts no index supports with IndexCheckWarn, e.g. to a logger. They aren't reported if nil
+		IndexWarning func(ctx context.Context, err error)
+		// Resolves the collation of the first page of Find whose params don't set one, e.g. from the language of
+		// the user. The collation is carried by the cursors so that the following pages keep the same order
+		CollationResolver CollationResolver
+		// The PredicateExperiment of the queries whose params don't set one
+		PredicateExperiment *PredicateExperiment
+		// true to decode the cursors of every query into the PreviousValues and NextValues of their Cursor, as if
+		// their params set DebugCursors
+		DebugCursors bool
+	}
+
+	// FieldNameResolver returns the bson key of a struct field and whether the field is inlined. An empty key
+	// skips the field.
+	FieldNameResolver func(field reflect.StructField) (key string, inline bool)
+
+	// bsonEncoding is how the results are encoded to bson, the driver's defaults when zero
+	bsonEncoding struct {
+		registry *bsoncodec.Registry
+		resolve  FieldNameResolver
+	}
+)
+
+var (
+	defaultsMu sync.RWMutex
+	defaults   = NewDefaults()
+)
+
+// NewDefaults returns the Defaults the package starts with
+func NewDefaults() Defaults {
+	return Defaults{
+		Timeout:           45 * time.Second,
+		StrictCursorTypes: true,
+		CursorCodec:       Base64CursorCodec{},
+	}
+}
+
+// SetDefaults replaces the package wide Defaults
+func SetDefaults(d Defaults) {
+	defaultsMu.Lock()
+	defer defaultsMu.Unlock()
+	defaults = d
+}
+
+// CurrentDefaults returns the package wide Defaults
+func CurrentDefaults() Defaults {
+	defaultsMu.RLock()
+	defer defaultsMu.RUnlock()
+	return defaults
+}
+
+// Encode implements CursorCodec, encoding the canonical cursor data so that the same cursor data is always encoded
+// to the same string
+func (Base64CursorCodec) Encode(cursorData bson.D) (string, error) {
+	data, err := bson.Marshal(CanonicalCursorData(cursorData))
+	return mcpbson.EncodeCursor(data), err
+}
+
+// Decode implements CursorCodec
+func (c Base64CursorCodec) Decode(cursor string) (bson.D, error) {
+	var cursorData bson.D
+	maxSize := c.MaxSize
+	if maxSize <= 0 {
+		maxSize = mcpbson.DefaultMaxCursorSize
+	}
+	maxDepth := c.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = mcpbson.DefaultMaxCursorDepth
+	}
+	data, err := mcpbson.DecodeCursor(cursor, maxSize, maxDepth)
+	if err != nil {
+		return cursorData, err
+	}
+	err = bson.Unmarshal(data, &cursorData)
+	return cursorData, err
+}
+
+// applyDefaults snapshots the package wide Defaults into p, unless p already holds a snapshot
+func applyDefaults(p FindParams) FindParams {
+	if p.defaults != nil {
+		return p
+	}
+	d := CurrentDefaults()
+	if d.CursorCodec == nil {
+		d.CursorCodec = Base64CursorCodec{}
+	}
+	p.defaults = &d
+
+	if p.Timeout <= 0 {
+		p.Timeout = d.Timeout
+	}
+	if d.MaxLimit > 0 && p.Limit > d.MaxLimit {
+		p.Limit = d.MaxLimit
+	}
+	p.CountTotal = p.CountTotal || d.CountTotal
+	p.DebugCursors = p.DebugCursors || d.DebugCursors
+	if p.RetryPolicy == nil {
+		p.RetryPolicy = d.RetryPolicy
+	}
+	if p.Executor == nil {
+		p.Executor = d.Executor
+	}
+	if p.IndexCheck == IndexCheckOff {
+		p.IndexCheck = d.IndexCheck
+	}
+	if p.PredicateExperiment == nil {
+		p.PredicateExperiment = d.PredicateExperiment
+	}
+	return p
+}
+
+// settings returns the Defaults snapshotted into p, or the current Defaults if p holds no snapshot
+func (p FindParams) settings() *Defaults {
+	return applyDefaults(p).defaults
+}
+
+// encoding returns the bsonEncoding of the settings of p
+func (p FindParams) encoding() bsonEncoding {
+	d := p.settings()
+	return bsonEncoding{registry: d.Registry, resolve: d.FieldNameResolver}
+}
+
+// cursorCodec returns the CursorCodec of the cursors of p, carrying the count of the documents, the tiebreaker
+// values of the documents at the ends of the page and the resolved collation if required
+func (p FindParams) cursorCodec() CursorCodec {
+	codec := p.baseCursorCodec()
+	if p.CountPolicy == CountCarried {
+		codec = countCursorCodec{codec: codec, count: p.carriedCount}
+	}
+	if p.DedupWindow > 0 {
+		codec = seenCursorCodec{codec: codec, seen: p.seen}
+	}
+	if p.resolvedCollation {
+		codec = collationCursorCodec{codec: codec, collation: p.Collation}
+	}
+	return codec
+}
+
+// baseCursorCodec returns the CursorCodec of the settings of p, bound to the scope of p and expiring if required
+func (p FindParams) baseCursorCodec() CursorCodec {
+	codec := p.settings().CursorCodec
+	if p.BindCursorScope {
+		codec = scopedCursorCodec{codec: codec, scope: p.cursorScope()}
+	}
+	if p.CursorTTL > time.Duration(0) {
+		codec = expiringCursorCodec{codec: codec, ttl: p.CursorTTL, clockSkew: p.CursorClockSkew}
+	}
+	return codec
+}
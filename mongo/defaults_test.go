@@ -0,0 +1,208 @@
+package mongo
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// prefixCodec wraps the default codec to tell its cursors apart
+type prefixCodec struct{}
+
+func (prefixCodec) Encode(cursorData bson.D) (string, error) {
+	cursor, err := Base64CursorCodec{}.Encode(cursorData)
+	return "v1." + cursor, err
+}
+
+func (prefixCodec) Decode(cursor string) (bson.D, error) {
+	if !strings.HasPrefix(cursor, "v1.") {
+		return nil, errors.New("unknown cursor version")
+	}
+	return Base64CursorCodec{}.Decode(strings.TrimPrefix(cursor, "v1."))
+}
+
+func setDefaults(t *testing.T, d Defaults) {
+	previous := CurrentDefaults()
+	SetDefaults(d)
+	t.Cleanup(func() { SetDefaults(previous) })
+}
+
+func TestFindAppliesDefaults(t *testing.T) {
+	setDefaults(t, Defaults{Timeout: 5 * time.Second, MaxLimit: 2, CountTotal: true, CursorCodec: prefixCodec{}})
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1], items[2]}}
+
+	var results []Item
+	cursor, err := Find(context.Background(), FindParams{Collection: col, Limit: 10}, &results)
+	require.NoError(t, err)
+	require.Equal(t, items[:2], results)
+	require.Equal(t, int64(3), *col.findOptions.Limit)
+	require.Equal(t, 5*time.Second, *col.findOptions.MaxTime)
+	require.Equal(t, 3, cursor.Count)
+	require.True(t, strings.HasPrefix(cursor.Next, "v1."))
+
+	// The params take precedence over the default timeout
+	_, err = Find(context.Background(), FindParams{Collection: col, Limit: 1, Timeout: time.Second, Next: cursor.Next}, &results)
+	require.NoError(t, err)
+	require.Equal(t, time.Second, *col.findOptions.MaxTime)
+
+	// Cursors of another codec are rejected
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "_id", Value: items[0].ID}})
+	require.NoError(t, err)
+	_, err = Find(context.Background(), FindParams{Collection: col, Limit: 1, Next: next}, &results)
+	require.IsType(t, &CursorError{}, err)
+}
+
+func TestFindWithoutDefaultTimeout(t *testing.T) {
+	setDefaults(t, Defaults{})
+	col := &fakeCollection{}
+
+	var results []Item
+	_, err := Find(context.Background(), FindParams{Collection: col, Limit: 1}, &results)
+	require.NoError(t, err)
+	require.Nil(t, col.findOptions.MaxTime)
+
+	// Cursor types aren't validated unless strict
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "_id", Value: "not an object id"}})
+	require.NoError(t, err)
+	_, err = Find(context.Background(), FindParams{Collection: col, Limit: 1, Next: next}, &results)
+	require.NoError(t, err)
+}
+
+func TestDefaultsAreSnapshotted(t *testing.T) {
+	setDefaults(t, Defaults{MaxLimit: 2})
+	p := applyDefaults(FindParams{Limit: 10})
+	require.Equal(t, int64(2), p.Limit)
+
+	SetDefaults(Defaults{MaxLimit: 1, CountTotal: true})
+	p = ensureMandatoryParams(p)
+	require.Equal(t, int64(2), p.Limit)
+	require.False(t, p.CountTotal)
+	require.Equal(t, Base64CursorCodec{}, p.cursorCodec())
+}
+
+func TestBase64CursorCodecRejectsHostileCursors(t *testing.T) {
+	encode := func(doc interface{}) string {
+		data, err := bson.Marshal(doc)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	valid := encode(bson.D{{Key: "name", Value: "a"}, {Key: "_id", Value: primitive.NewObjectID()}})
+	nested := bson.D{{Key: "_id", Value: "x"}}
+	for i := 0; i < 8; i++ {
+		nested = bson.D{{Key: "a", Value: nested}}
+	}
+
+	_, err := Base64CursorCodec{}.Decode(valid)
+	require.NoError(t, err)
+	_, err = Base64CursorCodec{MaxSize: 16}.Decode(valid)
+	require.EqualError(t, err, "cursor is larger than 16 bytes")
+	_, err = Base64CursorCodec{}.Decode(strings.Repeat("A", 8192))
+	require.EqualError(t, err, "cursor is larger than 4096 bytes")
+	_, err = Base64CursorCodec{}.Decode(valid[:len(valid)-4])
+	require.Error(t, err)
+	_, err = Base64CursorCodec{}.Decode(encode(bson.D{{Key: "_id", Value: primitive.JavaScript("while(1){}")}}))
+	require.EqualError(t, err, "unexpected javascript element _id in cursor")
+	_, err = Base64CursorCodec{}.Decode(encode(nested))
+	require.EqualError(t, err, "cursor is nested too deeply")
+	_, err = Base64CursorCodec{MaxDepth: 9}.Decode(encode(nested))
+	require.NoError(t, err)
+}
+
+func FuzzBase64CursorCodecDecode(f *testing.F) {
+	for _, doc := range []bson.D{
+		{{Key: "_id", Value: primitive.NewObjectID()}},
+		{{Key: "name", Value: "a"}, {Key: "tags", Value: bson.A{"x", int32(1)}}, {Key: "_id", Value: int64(7)}},
+		{{Key: "owner", Value: bson.D{{Key: "a", Value: 1.5}}}, {Key: "_id", Value: primitive.NewDateTimeFromTime(time.Now())}},
+	} {
+		cursor, err := Base64CursorCodec{}.Encode(doc)
+		require.NoError(f, err)
+		f.Add(cursor)
+		f.Add(cursor[:len(cursor)/2])
+	}
+	f.Add("")
+	f.Add("AAAAAA")
+
+	f.Fuzz(func(t *testing.T, cursor string) {
+		cursorData, err := Base64CursorCodec{}.Decode(cursor)
+		if err != nil {
+			return
+		}
+		// The decoded cursors can be parsed and encoded again
+		_, err = parseCursor(cursor, len(cursorData), Base64CursorCodec{})
+		require.NoError(t, err)
+		_, err = Base64CursorCodec{}.Encode(cursorData)
+		require.NoError(t, err)
+	})
+}
+
+// cents is encoded as a decimal string by the registries of TestDefaultsRegistryAndFieldNameResolver and
+// TestAggregateDecodesWithTheRegistry
+type cents int64
+
+func TestDefaultsRegistryAndFieldNameResolver(t *testing.T) {
+	// Untagged fields are keyed by their snake cased name
+	resolve := func(field reflect.StructField) (string, bool) {
+		if key, inline := mcpbson.ParseBSONTag(field.Tag.Get("bson")); key != "" || inline {
+			return key, inline
+		}
+		var key strings.Builder
+		for i, r := range field.Name {
+			if unicode.IsUpper(r) && i > 0 {
+				key.WriteByte('_')
+			}
+			key.WriteRune(unicode.ToLower(r))
+		}
+		return key.String(), false
+	}
+	structCodec, err := bsoncodec.NewStructCodec(bsoncodec.StructTagParserFunc(func(field reflect.StructField) (bsoncodec.StructTags, error) {
+		key, inline := resolve(field)
+		return bsoncodec.StructTags{Name: key, Inline: inline}, nil
+	}))
+	require.NoError(t, err)
+	registry := bson.NewRegistry()
+	registry.RegisterKindEncoder(reflect.Struct, structCodec)
+	registry.RegisterTypeEncoder(reflect.TypeOf(cents(0)), bsoncodec.ValueEncoderFunc(func(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, v reflect.Value) error {
+		return vw.WriteString(fmt.Sprintf("%d.%02d", v.Int()/100, v.Int()%100))
+	}))
+
+	type order struct {
+		ID          primitive.ObjectID `bson:"_id"`
+		TotalAmount cents
+		CreatedAt   time.Time
+	}
+	o := order{ID: primitive.NewObjectID(), TotalAmount: 1234, CreatedAt: time.Now()}
+	p := FindParams{PaginatedFields: []string{"total_amount"}, SortOrders: []int{-1}}
+	require.Equal(t, NewErrPaginatedFieldNotFound("total_amount"), ValidateCursor(p, &[]order{}))
+
+	setDefaults(t, Defaults{StrictCursorTypes: true, Registry: registry, FieldNameResolver: resolve})
+	cursor, err := GenerateCursor(o, p)
+	require.NoError(t, err)
+	cursorData, err := Base64CursorCodec{}.Decode(cursor)
+	require.NoError(t, err)
+	require.Equal(t, bson.D{{Key: "total_amount", Value: "12.34"}, {Key: "_id", Value: o.ID}}, cursorData)
+
+	// The cursor values are checked against the bson types of the registry
+	p.Next = cursor
+	require.NoError(t, ValidateCursor(p, &[]order{}))
+	p.Next, err = Base64CursorCodec{}.Encode(bson.D{{Key: "total_amount", Value: int64(1234)}, {Key: "_id", Value: o.ID}})
+	require.NoError(t, err)
+	require.ErrorAs(t, ValidateCursor(p, &[]order{}), new(*CursorError))
+}
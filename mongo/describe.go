@@ -0,0 +1,84 @@
+package mongo
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type (
+	// CursorInfo describes the content of a cursor in the default wire format, e.g. for the ops tooling debugging
+	// the pagination issues reported by customers, see DescribeCursor
+	CursorInfo struct {
+		// The elements of the cursor, in order: the values of the paginated fields followed by the metadata of the
+		// cursor if any, e.g. its scope or issue time
+		Fields []CursorFieldInfo `json:"fields"`
+	}
+
+	// CursorFieldInfo describes an element of a cursor
+	CursorFieldInfo struct {
+		// The key of the element, the name of a paginated field or of the metadata
+		Name string `json:"name"`
+		// The BSON type of the value, by its $type alias, e.g. "string", "objectId" or "date"
+		Type string `json:"type"`
+		// The value, human readable: the hex of an ObjectID, a date or the issue time of the cursor formatted as
+		// RFC 3339 and the extended JSON of the values that aren't strings, numbers or booleans
+		Value string `json:"value"`
+	}
+)
+
+// DescribeCursor returns the elements of the cursor in the default wire format, a base64 URL encoded BSON document
+// without padding, without verifying that it's a cursor of a query. Cursors of a custom CursorCodec, e.g. signed or
+// encrypted, can't be described.
+func DescribeCursor(token string) (CursorInfo, error) {
+	data, err := mcpbson.DecodeCursor(token, mcpbson.DefaultMaxCursorSize, mcpbson.DefaultMaxCursorDepth)
+	if err != nil {
+		return CursorInfo{}, &CursorError{fmt.Errorf("cursor parse failed: %s", err)}
+	}
+	elements, err := bson.Raw(data).Elements()
+	if err != nil {
+		return CursorInfo{}, &CursorError{fmt.Errorf("cursor parse failed: %s", err)}
+	}
+
+	info := CursorInfo{Fields: make([]CursorFieldInfo, 0, len(elements))}
+	for _, element := range elements {
+		key, value := element.Key(), element.Value()
+		info.Fields = append(info.Fields, CursorFieldInfo{
+			Name:  key,
+			Type:  bsonTypeAliases[value.Type],
+			Value: describeValue(key, value),
+		})
+	}
+	return info, nil
+}
+
+// describeValue returns the human readable value of the cursor element of the key
+func describeValue(key string, value bson.RawValue) string {
+	switch value.Type {
+	case bson.TypeString:
+		return value.StringValue()
+	case bson.TypeObjectID:
+		return value.ObjectID().Hex()
+	case bson.TypeDateTime:
+		return value.Time().UTC().Format(time.RFC3339Nano)
+	case bson.TypeBoolean:
+		return strconv.FormatBool(value.Boolean())
+	case bson.TypeDouble:
+		return strconv.FormatFloat(value.Double(), 'g', -1, 64)
+	case bson.TypeInt32:
+		return strconv.FormatInt(int64(value.Int32()), 10)
+	case bson.TypeInt64:
+		if key == cursorIssuedKey {
+			return time.UnixMilli(value.Int64()).UTC().Format(time.RFC3339Nano)
+		}
+		return strconv.FormatInt(value.Int64(), 10)
+	case bson.TypeDecimal128:
+		return value.Decimal128().String()
+	case bson.TypeNull:
+		return "null"
+	}
+	return value.String()
+}
@@ -0,0 +1,45 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDescribeCursor(t *testing.T) {
+	objectID, err := primitive.ObjectIDFromHex("5addf533e81549de7696cb04")
+	require.NoError(t, err)
+	date := time.Date(2024, 2, 29, 12, 30, 45, 123000000, time.UTC)
+	token, err := Base64CursorCodec{}.Encode(bson.D{
+		{Key: "name", Value: "test item"},
+		{Key: "createdAt", Value: primitive.NewDateTimeFromTime(date)},
+		{Key: "count", Value: int32(3)},
+		{Key: "score", Value: 0.5},
+		{Key: "deleted", Value: nil},
+		{Key: "_id", Value: objectID},
+		{Key: cursorIssuedKey, Value: date.UnixMilli()},
+	})
+	require.NoError(t, err)
+
+	info, err := DescribeCursor(token)
+	require.NoError(t, err)
+	require.Equal(t, CursorInfo{Fields: []CursorFieldInfo{
+		{Name: "name", Type: "string", Value: "test item"},
+		{Name: "createdAt", Type: "date", Value: "2024-02-29T12:30:45.123Z"},
+		{Name: "count", Type: "int", Value: "3"},
+		{Name: "score", Type: "double", Value: "0.5"},
+		{Name: "deleted", Type: "null", Value: "null"},
+		{Name: "_id", Type: "objectId", Value: "5addf533e81549de7696cb04"},
+		{Name: cursorIssuedKey, Type: "long", Value: "2024-02-29T12:30:45.123Z"},
+	}}, info)
+
+	_, err = DescribeCursor("not a cursor")
+	require.Error(t, err)
+	require.IsType(t, &CursorError{}, err)
+	_, err = DescribeCursor("AAAA")
+	require.Error(t, err)
+	require.IsType(t, &CursorError{}, err)
+}
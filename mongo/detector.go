@@ -0,0 +1,133 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type (
+	// MorePagesDetector detects whether more documents follow a page in the direction it was queried, which sets
+	// HasNext of the Cursor of a page queried with Next or without cursor, and HasPrevious of a page queried with
+	// Previous.
+	MorePagesDetector interface {
+		// FetchLimit returns the number of documents to fetch for a page of limit documents, at least limit
+		FetchLimit(limit int64) int64
+		// HasMore returns whether more documents follow the page, of which fetched documents were fetched
+		HasMore(ctx context.Context, q PageQuery, fetched int) (bool, error)
+	}
+
+	// PageQuery describes the find query of a page for a MorePagesDetector
+	PageQuery struct {
+		Collection Collection
+		// The params of the page, whose mandatory params are ensured
+		Params FindParams
+		// The filter and sort of the find query, which are reversed for a page queried with Previous
+		Filter bson.M
+		Sort   bson.D
+		// The last document of the page in the order it was queried, nil if the page is empty
+		Last interface{}
+	}
+
+	// LimitPlusOneDetector fetches an additional document to see if there's another page. It is the default
+	// MorePagesDetector.
+	LimitPlusOneDetector struct{}
+
+	// CountDetector counts whether a document follows the page with an additional count query, sparing fetching an
+	// additional document, e.g. when the documents are large.
+	CountDetector struct{}
+
+	// RangeProbeDetector probes for a document following the last one of the page with an additional find query
+	// of a single _id, sparing fetching an additional document, e.g. when the documents are large.
+	RangeProbeDetector struct{}
+
+	// FullPageDetector assumes that more documents follow the full pages without querying, so the last page may
+	// be followed by an empty one, e.g. for infinite feeds.
+	FullPageDetector struct{}
+)
+
+var (
+	_ MorePagesDetector = LimitPlusOneDetector{}
+	_ MorePagesDetector = CountDetector{}
+	_ MorePagesDetector = RangeProbeDetector{}
+	_ MorePagesDetector = FullPageDetector{}
+)
+
+// FetchLimit implements MorePagesDetector
+func (LimitPlusOneDetector) FetchLimit(limit int64) int64 {
+	return limit + 1
+}
+
+// HasMore implements MorePagesDetector
+func (LimitPlusOneDetector) HasMore(_ context.Context, q PageQuery, fetched int) (bool, error) {
+	return fetched > int(q.Params.Limit), nil
+}
+
+// FetchLimit implements MorePagesDetector
+func (CountDetector) FetchLimit(limit int64) int64 {
+	return limit
+}
+
+// HasMore implements MorePagesDetector
+func (CountDetector) HasMore(ctx context.Context, q PageQuery, fetched int) (bool, error) {
+	if fetched < int(q.Params.Limit) {
+		return false, nil
+	}
+	_, countOptions := buildCountQuery(q.Params)
+	countOptions.SetSkip(q.Params.Limit).SetLimit(1)
+	count, err := executeCountQuery(ctx, q.Collection, q.Filter, countOptions)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// FetchLimit implements MorePagesDetector
+func (RangeProbeDetector) FetchLimit(limit int64) int64 {
+	return limit
+}
+
+// HasMore implements MorePagesDetector
+func (RangeProbeDetector) HasMore(ctx context.Context, q PageQuery, fetched int) (bool, error) {
+	if fetched < int(q.Params.Limit) || q.Last == nil {
+		return false, nil
+	}
+	p := q.Params
+	lastCursor, err := generateCursor(q.Last, p)
+	if err != nil {
+		return false, fmt.Errorf("could not create a probe cursor: %s", err)
+	}
+	// The documents following the last one are the ones past its cursor in the direction of the page
+	if p.Previous != "" {
+		p.Previous = lastCursor
+	} else {
+		p.Next = lastCursor
+	}
+	p.IncludeAnchor = false
+	cursorQuery, sort, err := buildCursorQuery(p)
+	if err != nil {
+		return false, err
+	}
+
+	findOptions := newFindOptions(sort, 1, p.Collation, p.Hint, bson.M{"_id": 1}, p.Timeout)
+	mongoCursor, err := q.Collection.Find(ctx, MergeQueries([]bson.M{p.Query, cursorQuery}), findOptions)
+	if err != nil {
+		return false, err
+	}
+	defer mongoCursor.Close(ctx)
+	if mongoCursor.Next(ctx) {
+		return true, nil
+	}
+	return false, mongoCursor.Err()
+}
+
+// FetchLimit implements MorePagesDetector
+func (FullPageDetector) FetchLimit(limit int64) int64 {
+	return limit
+}
+
+// HasMore implements MorePagesDetector
+func (FullPageDetector) HasMore(_ context.Context, q PageQuery, fetched int) (bool, error) {
+	return fetched >= int(q.Params.Limit), nil
+}
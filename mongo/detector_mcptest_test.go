@@ -0,0 +1,57 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindMorePagesDetectors(t *testing.T) {
+	items, col := mcptest.NewItems(t)
+	detectors := []mongo.MorePagesDetector{
+		nil,
+		mongo.LimitPlusOneDetector{},
+		mongo.CountDetector{},
+		mongo.RangeProbeDetector{},
+		mongo.FullPageDetector{},
+	}
+	for _, detector := range detectors {
+		p := mongo.FindParams{Collection: col, Limit: 2, PaginatedField: "createdAt", MorePagesDetector: detector}
+
+		var results []mcptest.Item
+		cursor, err := mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []mcptest.Item{items[4], items[3]}, results)
+		require.True(t, cursor.HasNext)
+
+		p.Next = cursor.Next
+		cursor, err = mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []mcptest.Item{items[2], items[1]}, results)
+		require.True(t, cursor.HasNext)
+
+		p.Next = cursor.Next
+		cursor, err = mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []mcptest.Item{items[0]}, results)
+		require.False(t, cursor.HasNext)
+
+		p.Next = ""
+		p.Previous = cursor.Previous
+		cursor, err = mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []mcptest.Item{items[2], items[1]}, results)
+		require.True(t, cursor.HasPrevious)
+
+		// The full first page is followed by an empty one unless more pages are detected by querying
+		p.Previous = cursor.Previous
+		cursor, err = mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []mcptest.Item{items[4], items[3]}, results)
+		_, full := detector.(mongo.FullPageDetector)
+		require.Equal(t, full, cursor.HasPrevious)
+	}
+}
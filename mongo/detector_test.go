@@ -0,0 +1,29 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindFetchLimit(t *testing.T) {
+	var cases = []struct {
+		detector   MorePagesDetector
+		fetchLimit int64
+	}{
+		{nil, 3},
+		{LimitPlusOneDetector{}, 3},
+		{CountDetector{}, 2},
+		{RangeProbeDetector{}, 2},
+		{FullPageDetector{}, 2},
+	}
+	for _, tc := range cases {
+		col := &fakeCollection{}
+		var results []Item
+		cursor, err := Find(context.Background(), FindParams{Collection: col, Limit: 2, MorePagesDetector: tc.detector}, &results)
+		require.NoError(t, err)
+		require.Equal(t, tc.fetchLimit, *col.findOptions.Limit)
+		require.False(t, cursor.HasNext)
+	}
+}
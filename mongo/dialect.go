@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"strconv"
+	"strings"
+
+	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Dialect selects the shape of the cursor queries, for the mongo compatible servers that don't accept or plan the
+// default shape well
+type Dialect int
+
+const (
+	// DialectMongo generates cursor queries combining the paginated fields' ranges with $or
+	DialectMongo Dialect = iota
+	// DialectCosmos generates cursor queries without $or, which Azure Cosmos DB's API for MongoDB rejects in
+	// some shapes. As sorting across partitions is expensive on Cosmos DB, the Query should also target a single
+	// partition by matching on the partition key. The PredicateStrategy is ignored.
+	DialectCosmos
+)
+
+// PredicateStrategy selects how the cursor queries of DialectMongo compare the paginated fields to the cursor
+type PredicateStrategy int
+
+const (
+	// PredicateNestedOr compares each paginated field in a tree of $or and $and predicates, which mongo plans
+	// with the index bounds of the fields
+	PredicateNestedOr PredicateStrategy = iota
+	// PredicateRowValueExpr compares the array of the paginated fields to the array of the cursor values in a
+	// single $expr, avoiding large $or trees. It requires all the sort orders to be the same, including the _id's,
+	// and falls back to PredicateNestedOr otherwise.
+	PredicateRowValueExpr
+	// PredicateAuto uses PredicateRowValueExpr when paginating on multiple fields besides the _id in a single
+	// direction on a server known to support $expr, and PredicateNestedOr otherwise
+	PredicateAuto
+)
+
+// rowValueMinFields is the number of paginated fields, including the _id, from which PredicateAuto compares them
+// as a row value
+const rowValueMinFields = 3
+
+// generateCursorQuery returns the query matching the documents past the cursor values in the dialect of p
+func generateCursorQuery(p FindParams, comparisonOps []string, cursorValues []interface{}) (bson.M, error) {
+	switch {
+	case p.Dialect == DialectCosmos:
+		return mcpbson.GenerateOrFreeCursorQuery(p.PaginatedFields, comparisonOps, cursorValues, p.IncludeAnchor)
+	case usesRowValueExpr(p, comparisonOps):
+		return mcpbson.GenerateRowValueCursorQuery(p.PaginatedFields, comparisonOps, cursorValues, p.IncludeAnchor)
+	case p.IncludeAnchor:
+		return mcpbson.GenerateInclusiveCursorQuery(p.PaginatedFields, comparisonOps, cursorValues)
+	default:
+		return mcpbson.GenerateCursorQuery(p.PaginatedFields, comparisonOps, cursorValues)
+	}
+}
+
+// usesRowValueExpr returns whether the PredicateStrategy of p resolves to PredicateRowValueExpr
+func usesRowValueExpr(p FindParams, comparisonOps []string) bool {
+	switch p.PredicateStrategy {
+	case PredicateRowValueExpr:
+	case PredicateAuto:
+		if len(p.PaginatedFields) < rowValueMinFields || !supportsExpr(p.ServerVersion) {
+			return false
+		}
+	default:
+		return false
+	}
+	for _, op := range comparisonOps {
+		if op != comparisonOps[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// supportsExpr returns whether the server version, e.g. "4.2.1", supports $expr, which was added in 3.6
+func supportsExpr(version string) bool {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return false
+	}
+	return major > 3 || (major == 3 && minor >= 6)
+}
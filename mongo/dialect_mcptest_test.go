@@ -0,0 +1,32 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRowValuePredicateMatchesPaginate(t *testing.T) {
+	items, col := mcptest.NewItems(t)
+	r := mongo.PageRequest{Limit: 2, PaginatedFields: []string{"count", "name"}, SortOrders: []int{1, 1}}
+	p := mongo.FindParams{Collection: col, PredicateStrategy: mongo.PredicateRowValueExpr}
+
+	var pages [][]mcptest.Item
+	for {
+		var results []mcptest.Item
+		cursor, err := mongo.Find(context.Background(), r.Apply(p), &results)
+		require.NoError(t, err)
+		expected, _, err := mongo.Paginate(items, r)
+		require.NoError(t, err)
+		require.Equal(t, expected, results)
+		pages = append(pages, results)
+		if !cursor.HasNext {
+			break
+		}
+		r = r.WithToken(cursor.Next, mongo.Forward)
+	}
+	require.Equal(t, [][]mcptest.Item{{items[1], items[2]}, {items[4], items[0]}, {items[3]}}, pages)
+}
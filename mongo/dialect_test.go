@@ -0,0 +1,93 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPredicateStrategy(t *testing.T) {
+	id := primitive.NewObjectID()
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "name", Value: "b"}, {Key: "data", Value: "x"}, {Key: "_id", Value: id}})
+	require.NoError(t, err)
+	rowValueQuery := bson.M{"$expr": map[string]interface{}{"$gt": []interface{}{
+		[]interface{}{"$name", "$data", "$_id"},
+		[]interface{}{"b", "x", id},
+	}}}
+
+	var cases = []struct {
+		name           string
+		strategy       PredicateStrategy
+		serverVersion  string
+		sortOrders     []int
+		expectRowValue bool
+	}{
+		{
+			name:           "nested or by default",
+			strategy:       PredicateNestedOr,
+			sortOrders:     []int{1, 1},
+			expectRowValue: false,
+		},
+		{
+			name:           "row value when requested",
+			strategy:       PredicateRowValueExpr,
+			sortOrders:     []int{1, 1},
+			expectRowValue: true,
+		},
+		{
+			name:           "nested or when the sort orders differ",
+			strategy:       PredicateRowValueExpr,
+			sortOrders:     []int{1, -1},
+			expectRowValue: false,
+		},
+		{
+			name:           "auto uses row value on servers supporting $expr",
+			strategy:       PredicateAuto,
+			serverVersion:  "4.2.1",
+			sortOrders:     []int{1, 1},
+			expectRowValue: true,
+		},
+		{
+			name:           "auto uses nested or on older servers",
+			strategy:       PredicateAuto,
+			serverVersion:  "3.4.0",
+			sortOrders:     []int{1, 1},
+			expectRowValue: false,
+		},
+		{
+			name:           "auto uses nested or when the server version is unknown",
+			strategy:       PredicateAuto,
+			sortOrders:     []int{1, 1},
+			expectRowValue: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := FindParams{
+				Collection:        &fakeCollection{},
+				Limit:             2,
+				PaginatedFields:   []string{"name", "data"},
+				SortOrders:        tc.sortOrders,
+				Next:              next,
+				PredicateStrategy: tc.strategy,
+				ServerVersion:     tc.serverVersion,
+			}
+			queries, _, err := BuildQueries(context.Background(), p)
+			require.NoError(t, err)
+			if tc.expectRowValue {
+				require.Equal(t, rowValueQuery, queries[1])
+			} else {
+				require.Contains(t, queries[1], "$and")
+			}
+		})
+	}
+}
+
+func TestPredicateAutoKeepsNestedOrForSingleField(t *testing.T) {
+	p := FindParams{PaginatedField: "name", SortAscending: true, PredicateStrategy: PredicateAuto, ServerVersion: "6.0"}
+	p = ensureMandatoryParams(p)
+	require.False(t, usesRowValueExpr(p, generateComparisonOps(p)))
+}
@@ -0,0 +1,71 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// DiffPages executes a find mongo query for the documents between two boundary cursors of the same FindParams,
+// e.g. the cursor of the last document a client saw on its last visit and the one it sees now, sparing change
+// streams for "what's new since" features. The documents strictly past the first boundary in the sort order until
+// the second one, inclusive, are filled into the passed in result slice pointer, whichever order the cursors are
+// passed in. Only the documents that still exist are found: the documents removed since a boundary was issued
+// can't be told apart from the ones that never were, which requires keeping the _ids of the documents seen. At most
+// Limit documents are returned, in the sort order, truncated being set when more lie between the boundaries. The
+// boundaries are ordered as mongo compares their values, regardless of the Collation.
+func DiffPages(ctx context.Context, p FindParams, tokenA, tokenB string, results interface{}) (truncated bool, err error) {
+	if tokenA == "" || tokenB == "" {
+		return false, &CursorError{errors.New("diff boundary parse failed: empty cursor")}
+	}
+	bounded, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return false, err
+	}
+	bounded = ensureMandatoryParams(bounded)
+	err = bounded.encoding().validate(results, bounded.PaginatedFields)
+	if err != nil {
+		return false, err
+	}
+
+	valuesA, err := parseCursor(tokenA, len(bounded.PaginatedFields), bounded.cursorCodec())
+	if err != nil {
+		return false, &CursorError{fmt.Errorf("diff boundary parse failed: %w", err)}
+	}
+	valuesB, err := parseCursor(tokenB, len(bounded.PaginatedFields), bounded.cursorCodec())
+	if err != nil {
+		return false, &CursorError{fmt.Errorf("diff boundary parse failed: %w", err)}
+	}
+
+	from, until := tokenA, tokenB
+	c := compareKeys(valuesA, valuesB, bounded.SortOrders)
+	if c > 0 {
+		from, until = tokenB, tokenA
+	}
+	if c == 0 {
+		emptyResults(results)
+		return false, nil
+	}
+
+	bounded.Next = from
+	bounded.Previous = ""
+	bounded.IncludeAnchor = false
+	bounded.Query, err = chunkQuery(bounded, until)
+	if err != nil {
+		return false, err
+	}
+	bounded.Next = ""
+	bounded.CountTotal = false
+	cursor, err := Find(ctx, bounded, results)
+	if err != nil {
+		return false, err
+	}
+	return cursor.HasNext, nil
+}
+
+// emptyResults sets the results slice pointer to an empty slice
+func emptyResults(results interface{}) {
+	resultsVal := reflect.ValueOf(results).Elem()
+	resultsVal.Set(reflect.MakeSlice(resultsVal.Type(), 0, 0))
+}
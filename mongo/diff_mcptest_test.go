@@ -0,0 +1,45 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPages(t *testing.T) {
+	items, col := mcptest.NewItems(t)
+	p := mongo.FindParams{Collection: col, Limit: 5, PaginatedField: "createdAt"}
+	tokenA, err := mongo.GenerateCursor(items[3], p)
+	require.NoError(t, err)
+	tokenB, err := mongo.GenerateCursor(items[1], p)
+	require.NoError(t, err)
+
+	// The documents past tokenA until tokenB are found whichever order the boundaries are passed in
+	var results []mcptest.Item
+	truncated, err := mongo.DiffPages(context.Background(), p, tokenA, tokenB, &results)
+	require.NoError(t, err)
+	require.False(t, truncated)
+	require.Equal(t, []mcptest.Item{items[2], items[1]}, results)
+
+	truncated, err = mongo.DiffPages(context.Background(), p, tokenB, tokenA, &results)
+	require.NoError(t, err)
+	require.False(t, truncated)
+	require.Equal(t, []mcptest.Item{items[2], items[1]}, results)
+
+	p.Limit = 1
+	truncated, err = mongo.DiffPages(context.Background(), p, tokenA, tokenB, &results)
+	require.NoError(t, err)
+	require.True(t, truncated)
+	require.Equal(t, []mcptest.Item{items[2]}, results)
+
+	truncated, err = mongo.DiffPages(context.Background(), p, tokenA, tokenA, &results)
+	require.NoError(t, err)
+	require.False(t, truncated)
+	require.Empty(t, results)
+
+	_, err = mongo.DiffPages(context.Background(), p, "", tokenB, &results)
+	require.ErrorAs(t, err, new(*mongo.CursorError))
+}
@@ -0,0 +1,169 @@
+package mongo
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type (
+	// AttributeValue is a value of a DynamoDB LastEvaluatedKey in the DynamoDB JSON format, where exactly one
+	// member is set
+	AttributeValue struct {
+		S    *string `json:"S,omitempty"`
+		N    *string `json:"N,omitempty"`
+		B    []byte  `json:"B,omitempty"`
+		BOOL *bool   `json:"BOOL,omitempty"`
+	}
+
+	// LastEvaluatedKey is a cursor in the shape of the LastEvaluatedKey of a DynamoDB query, mapping the paginated
+	// fields to their values, for services presenting the same pagination contract over DynamoDB and mongo
+	// backed resources. ObjectIDs are strings of their hex representation and dates are numbers of milliseconds
+	// since the epoch.
+	LastEvaluatedKey map[string]AttributeValue
+)
+
+// ToLastEvaluatedKey converts a Next or Previous cursor of the provided FindParams to a LastEvaluatedKey
+func ToLastEvaluatedKey(cursor string, p FindParams) (LastEvaluatedKey, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return nil, err
+	}
+	p = ensureMandatoryParams(p)
+	cursorData, err := p.cursorCodec().Decode(cursor)
+	if err != nil {
+		return nil, &CursorError{fmt.Errorf("cursor parse failed: %w", err)}
+	}
+
+	key := make(LastEvaluatedKey, len(cursorData))
+	for _, e := range cursorData {
+		av, err := toAttributeValue(e.Value)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert the value of %s: %s", e.Key, err)
+		}
+		key[e.Key] = av
+	}
+	return key, nil
+}
+
+// FromLastEvaluatedKey converts a LastEvaluatedKey back to a cursor of the provided FindParams. The values are
+// converted to the bson types of the paginated fields of the results' struct, which results is a slice pointer of.
+func FromLastEvaluatedKey(key LastEvaluatedKey, p FindParams, results interface{}) (string, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return "", err
+	}
+	p = ensureMandatoryParams(p)
+	err = p.encoding().validate(results, p.PaginatedFields)
+	if err != nil {
+		return "", err
+	}
+	elem := reflect.TypeOf(results).Elem().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return "", NewErrInvalidResults("expected results' element to be a struct or struct pointer")
+	}
+	if len(key) != len(p.PaginatedFields) {
+		return "", &CursorError{fmt.Errorf("expecting a key with %d attributes", len(p.PaginatedFields))}
+	}
+
+	cursorData := make(bson.D, 0, len(p.PaginatedFields))
+	for _, paginatedField := range p.PaginatedFields {
+		av, ok := key[paginatedField]
+		if !ok {
+			return "", &CursorError{fmt.Errorf("attribute %s is missing", paginatedField)}
+		}
+		field, _ := p.encoding().findPaginatedField(elem, paginatedField)
+		expected, ok := p.encoding().zeroBSONValue(field.Type)
+		if !ok {
+			return "", fmt.Errorf("the bson type of %s can't be inferred from the results", paginatedField)
+		}
+		value, err := fromAttributeValue(av, expected)
+		if err != nil {
+			return "", &CursorError{fmt.Errorf("invalid attribute %s: %s", paginatedField, err)}
+		}
+		cursorData = append(cursorData, bson.E{Key: paginatedField, Value: value})
+	}
+	return p.cursorCodec().Encode(cursorData)
+}
+
+// toAttributeValue converts a cursor value to an AttributeValue
+func toAttributeValue(v interface{}) (AttributeValue, error) {
+	var s string
+	switch v := v.(type) {
+	case string:
+		return AttributeValue{S: &v}, nil
+	case primitive.ObjectID:
+		s = v.Hex()
+		return AttributeValue{S: &s}, nil
+	case int32:
+		s = strconv.FormatInt(int64(v), 10)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'g', -1, 64)
+	case primitive.Decimal128:
+		s = v.String()
+	case primitive.DateTime:
+		s = strconv.FormatInt(int64(v), 10)
+	case bool:
+		return AttributeValue{BOOL: &v}, nil
+	case primitive.Binary:
+		return AttributeValue{B: v.Data}, nil
+	default:
+		return AttributeValue{}, fmt.Errorf("unsupported type %s", bsonTypeName(v))
+	}
+	return AttributeValue{N: &s}, nil
+}
+
+// fromAttributeValue converts an AttributeValue to a cursor value of the type of expected
+func fromAttributeValue(av AttributeValue, expected interface{}) (interface{}, error) {
+	switch expected := expected.(type) {
+	case string:
+		if av.S != nil {
+			return *av.S, nil
+		}
+	case primitive.ObjectID:
+		if av.S != nil {
+			return primitive.ObjectIDFromHex(*av.S)
+		}
+	case int32:
+		if av.N != nil {
+			n, err := strconv.ParseInt(*av.N, 10, 32)
+			return int32(n), err
+		}
+	case int64:
+		if av.N != nil {
+			return strconv.ParseInt(*av.N, 10, 64)
+		}
+	case float64:
+		if av.N != nil {
+			return strconv.ParseFloat(*av.N, 64)
+		}
+	case primitive.Decimal128:
+		if av.N != nil {
+			return primitive.ParseDecimal128(*av.N)
+		}
+	case primitive.DateTime:
+		if av.N != nil {
+			n, err := strconv.ParseInt(*av.N, 10, 64)
+			return primitive.DateTime(n), err
+		}
+	case bool:
+		if av.BOOL != nil {
+			return *av.BOOL, nil
+		}
+	case primitive.Binary:
+		if av.B != nil {
+			return primitive.Binary{Subtype: expected.Subtype, Data: av.B}, nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %s", bsonTypeName(expected))
+	}
+	return nil, fmt.Errorf("expected a %s value", bsonTypeName(expected))
+}
@@ -0,0 +1,56 @@
+package mongo
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestLastEvaluatedKey(t *testing.T) {
+	type event struct {
+		ID        primitive.ObjectID   `bson:"_id"`
+		Kind      string               `bson:"kind"`
+		Seq       int64                `bson:"seq"`
+		Amount    primitive.Decimal128 `bson:"amount"`
+		CreatedAt time.Time            `bson:"createdAt"`
+	}
+	amount, err := primitive.ParseDecimal128("12.50")
+	require.NoError(t, err)
+	e := event{ID: primitive.NewObjectID(), Kind: "click", Seq: 42, Amount: amount, CreatedAt: time.UnixMilli(1700000000123).UTC()}
+	p := FindParams{PaginatedFields: []string{"kind", "seq", "amount", "createdAt"}, SortOrders: []int{1, 1, 1, 1}}
+	cursor, err := GenerateCursor(e, p)
+	require.NoError(t, err)
+
+	key, err := ToLastEvaluatedKey(cursor, p)
+	require.NoError(t, err)
+	data, err := json.Marshal(key)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"_id": {"S": "`+e.ID.Hex()+`"},
+		"kind": {"S": "click"},
+		"seq": {"N": "42"},
+		"amount": {"N": "12.50"},
+		"createdAt": {"N": "1700000000123"}
+	}`, string(data))
+
+	var decoded LastEvaluatedKey
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	roundTripped, err := FromLastEvaluatedKey(decoded, p, &[]event{})
+	require.NoError(t, err)
+	require.Equal(t, cursor, roundTripped)
+	p.Next = roundTripped
+	require.NoError(t, ValidateCursor(p, &[]event{}))
+
+	delete(decoded, "seq")
+	_, err = FromLastEvaluatedKey(decoded, p, &[]event{})
+	require.EqualError(t, err, "expecting a key with 5 attributes")
+	require.IsType(t, &CursorError{}, err)
+
+	kind := "click"
+	decoded["seq"] = AttributeValue{S: &kind}
+	_, err = FromLastEvaluatedKey(decoded, p, &[]event{})
+	require.EqualError(t, err, "invalid attribute seq: expected a 64-bit integer value")
+}
@@ -0,0 +1,85 @@
+package mongo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ToSearchAfter converts a Next or Previous cursor of the provided FindParams to the search_after array of an
+// Elasticsearch search sorted on the paginated fields, for services mirroring their mongo documents into
+// Elasticsearch behind the same pagination tokens. ObjectIDs are strings of their hex representation, dates are
+// numbers of milliseconds since the epoch and decimals are json.Number values to keep their precision.
+func ToSearchAfter(cursor string, p FindParams) ([]interface{}, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return nil, err
+	}
+	p = ensureMandatoryParams(p)
+	cursorData, err := p.cursorCodec().Decode(cursor)
+	if err != nil {
+		return nil, &CursorError{fmt.Errorf("cursor parse failed: %w", err)}
+	}
+
+	searchAfter := make([]interface{}, 0, len(cursorData))
+	for _, e := range cursorData {
+		av, err := toAttributeValue(e.Value)
+		if err != nil {
+			return nil, fmt.Errorf("could not convert the value of %s: %s", e.Key, err)
+		}
+		switch {
+		case av.S != nil:
+			searchAfter = append(searchAfter, *av.S)
+		case av.N != nil:
+			searchAfter = append(searchAfter, json.Number(*av.N))
+		case av.BOOL != nil:
+			searchAfter = append(searchAfter, *av.BOOL)
+		default:
+			return nil, fmt.Errorf("could not convert the value of %s: unsupported type %s", e.Key, bsonTypeName(e.Value))
+		}
+	}
+	return searchAfter, nil
+}
+
+// FromSearchAfter converts the search_after array of an Elasticsearch search back to a cursor of the provided
+// FindParams, e.g. as unmarshaled from JSON with json.Decoder.UseNumber so that large integers keep their
+// precision. The values are converted to the bson types of the paginated fields of the results' struct, which
+// results is a slice pointer of.
+func FromSearchAfter(searchAfter []interface{}, p FindParams, results interface{}) (string, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return "", err
+	}
+	p = ensureMandatoryParams(p)
+	if len(searchAfter) != len(p.PaginatedFields) {
+		return "", &CursorError{fmt.Errorf("expecting a search_after array with %d values", len(p.PaginatedFields))}
+	}
+
+	// The values are converted as the ones of the equivalent LastEvaluatedKey
+	key := make(LastEvaluatedKey, len(searchAfter))
+	for i, value := range searchAfter {
+		var av AttributeValue
+		switch value := value.(type) {
+		case string:
+			av.S = &value
+		case bool:
+			av.BOOL = &value
+		case json.Number:
+			n := value.String()
+			av.N = &n
+		case float64:
+			n := strconv.FormatFloat(value, 'f', -1, 64)
+			av.N = &n
+		case int:
+			n := strconv.Itoa(value)
+			av.N = &n
+		case int64:
+			n := strconv.FormatInt(value, 10)
+			av.N = &n
+		default:
+			return "", &CursorError{fmt.Errorf("invalid search_after value %v of %s", value, p.PaginatedFields[i])}
+		}
+		key[p.PaginatedFields[i]] = av
+	}
+	return FromLastEvaluatedKey(key, p, results)
+}
@@ -0,0 +1,48 @@
+package mongo
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSearchAfter(t *testing.T) {
+	type document struct {
+		ID        primitive.ObjectID `bson:"_id"`
+		Score     float64            `bson:"score"`
+		Published bool               `bson:"published"`
+		CreatedAt time.Time          `bson:"createdAt"`
+	}
+	d := document{ID: primitive.NewObjectID(), Score: 0.75, Published: true, CreatedAt: time.UnixMilli(1700000000123).UTC()}
+	p := FindParams{PaginatedFields: []string{"score", "published", "createdAt"}, SortOrders: []int{-1, 1, 1}}
+	cursor, err := GenerateCursor(d, p)
+	require.NoError(t, err)
+
+	searchAfter, err := ToSearchAfter(cursor, p)
+	require.NoError(t, err)
+	data, err := json.Marshal(searchAfter)
+	require.NoError(t, err)
+	require.Equal(t, `[0.75,true,1700000000123,"`+d.ID.Hex()+`"]`, string(data))
+
+	// The values are accepted whether the numbers were decoded as json.Number or float64
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var numbers []interface{}
+	require.NoError(t, decoder.Decode(&numbers))
+	var floats []interface{}
+	require.NoError(t, json.Unmarshal(data, &floats))
+	for _, values := range [][]interface{}{numbers, floats} {
+		roundTripped, err := FromSearchAfter(values, p, &[]document{})
+		require.NoError(t, err)
+		require.Equal(t, cursor, roundTripped)
+	}
+
+	_, err = FromSearchAfter(floats[:2], p, &[]document{})
+	require.EqualError(t, err, "expecting a search_after array with 4 values")
+	_, err = FromSearchAfter([]interface{}{"high", true, 1, d.ID.Hex()}, p, &[]document{})
+	require.EqualError(t, err, "invalid attribute score: expected a double value")
+}
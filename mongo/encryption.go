@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Encryption tells how a field is encrypted by client-side field level encryption (CSFLE) or queryable
+// encryption, which determines the queries the server can run on it
+type Encryption int
+
+const (
+	// EncryptionRandom is the CSFLE random encryption, whose fields can't be queried
+	EncryptionRandom Encryption = iota
+	// EncryptionDeterministic is the CSFLE deterministic encryption or the queryable encryption of equality
+	// queries, whose fields can only be matched by equality
+	EncryptionDeterministic
+	// EncryptionRange is the queryable encryption of range queries, whose fields can be compared with $gt and $lt
+	EncryptionRange
+)
+
+// equalityPinnedFields returns which paginated fields of p are encrypted and matched by an equality of its Query,
+// so that their value is the same for all the documents and their comparisons are left out of the cursor query
+// and sort. An ErrFieldNotRangeQueryable is returned for an encrypted paginated field that can't be compared with
+// range queries and isn't matched by an equality, or for the tiebreaker which must always be compared.
+func equalityPinnedFields(p FindParams) ([]bool, error) {
+	var pinned []bool
+	last := len(p.PaginatedFields) - 1
+	for i, paginatedField := range p.PaginatedFields {
+		encryption, encrypted := p.EncryptedFields[paginatedField]
+		if !encrypted || encryption == EncryptionRange {
+			continue
+		}
+		if i == last || !matchesEquality(p.Query[paginatedField]) {
+			return nil, NewErrFieldNotRangeQueryable(paginatedField, encryption)
+		}
+		if pinned == nil {
+			pinned = make([]bool, len(p.PaginatedFields))
+		}
+		pinned[i] = true
+	}
+	return pinned, nil
+}
+
+// matchesEquality returns whether the predicate of a field in a query only matches the documents equal to a value
+func matchesEquality(predicate interface{}) bool {
+	var operators []string
+	switch predicate := predicate.(type) {
+	case nil:
+		return false
+	case bson.M:
+		for key := range predicate {
+			operators = append(operators, key)
+		}
+	case map[string]interface{}:
+		for key := range predicate {
+			operators = append(operators, key)
+		}
+	case bson.D:
+		for _, e := range predicate {
+			operators = append(operators, e.Key)
+		}
+	default:
+		return true
+	}
+	// An embedded document is matched by equality, an operator other than $eq doesn't pin the field
+	for _, operator := range operators {
+		if strings.HasPrefix(operator, "$") && (operator != "$eq" || len(operators) > 1) {
+			return false
+		}
+	}
+	return true
+}
+
+// unpinned returns the elements of values which aren't pinned
+func unpinned[T any](values []T, pinned []bool) []T {
+	if pinned == nil {
+		return values
+	}
+	kept := make([]T, 0, len(values))
+	for i, value := range values {
+		if !pinned[i] {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
@@ -2,6 +2,9 @@ package mongo
 
 import (
 	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
 )
 
 type (
@@ -31,3 +34,181 @@ func NewErrPaginatedFieldNotFound(fieldName string) error {
 func (e *ErrPaginatedFieldNotFound) Error() string {
 	return fmt.Sprintf("paginated field %s not found", e.fieldName)
 }
+
+type (
+	ErrUnsortableField struct {
+		fieldName string
+	}
+)
+
+func NewErrUnsortableField(fieldName string) error {
+	return &ErrUnsortableField{fieldName: fieldName}
+}
+
+func (e *ErrUnsortableField) Error() string {
+	return fmt.Sprintf("field %s is not sortable", e.fieldName)
+}
+
+type (
+	ErrNonDeterministicExpression struct {
+		operator string
+	}
+)
+
+func NewErrNonDeterministicExpression(operator string) error {
+	return &ErrNonDeterministicExpression{operator: operator}
+}
+
+func (e *ErrNonDeterministicExpression) Error() string {
+	return fmt.Sprintf("expression uses %s which may evaluate differently between pages", e.operator)
+}
+
+type (
+	ErrNoSupportingIndex struct {
+		sort      interface{}
+		collation string
+	}
+)
+
+func NewErrNoSupportingIndex(sort interface{}, collation string) error {
+	return &ErrNoSupportingIndex{sort: sort, collation: collation}
+}
+
+func (e *ErrNoSupportingIndex) Error() string {
+	return fmt.Sprintf("no index supports the sort %v with the %s collation", e.sort, e.collation)
+}
+
+type (
+	ErrCursorTypeMismatch struct {
+		fieldName string
+		expected  string
+		actual    string
+	}
+)
+
+func NewErrCursorTypeMismatch(fieldName string, expected, actual string) error {
+	return &ErrCursorTypeMismatch{fieldName: fieldName, expected: expected, actual: actual}
+}
+
+func (e *ErrCursorTypeMismatch) Error() string {
+	return fmt.Sprintf("cursor value of paginated field %s is of type %s where %s is expected", e.fieldName, e.actual, e.expected)
+}
+
+type (
+	ErrCursorScopeMismatch struct{}
+)
+
+func NewErrCursorScopeMismatch() error {
+	return &ErrCursorScopeMismatch{}
+}
+
+func (e *ErrCursorScopeMismatch) Error() string {
+	return "cursor was issued for another query"
+}
+
+type (
+	ErrCursorExpired struct{}
+)
+
+func NewErrCursorExpired() error {
+	return &ErrCursorExpired{}
+}
+
+func (e *ErrCursorExpired) Error() string {
+	return "cursor has expired"
+}
+
+type (
+	ErrCursorIssuedInFuture struct {
+		ahead time.Duration
+	}
+)
+
+func NewErrCursorIssuedInFuture(ahead time.Duration) error {
+	return &ErrCursorIssuedInFuture{ahead: ahead}
+}
+
+func (e *ErrCursorIssuedInFuture) Error() string {
+	return fmt.Sprintf("cursor was issued %s in the future, beyond the tolerated clock skew", e.ahead)
+}
+
+type (
+	ErrDocumentDecode struct {
+		index int
+		raw   bson.Raw
+		err   error
+	}
+)
+
+func NewErrDocumentDecode(index int, raw bson.Raw, err error) error {
+	return &ErrDocumentDecode{index: index, raw: raw, err: err}
+}
+
+func (e *ErrDocumentDecode) Error() string {
+	return fmt.Sprintf("document %d of the page could not be decoded: %s", e.index, e.err)
+}
+
+func (e *ErrDocumentDecode) Unwrap() error {
+	return e.err
+}
+
+// Index returns the position of the document in the page
+func (e *ErrDocumentDecode) Index() int {
+	return e.index
+}
+
+// Raw returns the document that couldn't be decoded
+func (e *ErrDocumentDecode) Raw() bson.Raw {
+	return e.raw
+}
+
+type (
+	ErrUntargetableSort struct {
+		shardKey string
+		hashed   bool
+	}
+)
+
+func NewErrUntargetableSort(shardKey string, hashed bool) error {
+	return &ErrUntargetableSort{shardKey: shardKey, hashed: hashed}
+}
+
+func (e *ErrUntargetableSort) Error() string {
+	if e.hashed {
+		return fmt.Sprintf("the pages can't target the shards: the hashed shard key field %s must be matched by an equality of the Query", e.shardKey)
+	}
+	return fmt.Sprintf("the pages can't target the shards: the shard key field %s must be matched by the Query or be a paginated field", e.shardKey)
+}
+
+type (
+	ErrFieldNotRangeQueryable struct {
+		fieldName  string
+		encryption Encryption
+	}
+)
+
+func NewErrFieldNotRangeQueryable(fieldName string, encryption Encryption) error {
+	return &ErrFieldNotRangeQueryable{fieldName: fieldName, encryption: encryption}
+}
+
+func (e *ErrFieldNotRangeQueryable) Error() string {
+	encryption := "randomly"
+	if e.encryption == EncryptionDeterministic {
+		encryption = "deterministically"
+	}
+	return fmt.Sprintf("paginated field %s is %s encrypted and can't be compared to the cursors unless the Query matches it with an equality", e.fieldName, encryption)
+}
+
+type (
+	ErrProjectionExcludesField struct {
+		fieldName string
+	}
+)
+
+func NewErrProjectionExcludesField(fieldName string) error {
+	return &ErrProjectionExcludesField{fieldName: fieldName}
+}
+
+func (e *ErrProjectionExcludesField) Error() string {
+	return fmt.Sprintf("the Projection excludes the paginated field %s, which the cursors are generated from", e.fieldName)
+}
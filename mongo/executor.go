@@ -0,0 +1,86 @@
+package mongo
+
+import (
+	"context"
+	"sync"
+)
+
+// QueryKind tells which query of a page an Executor runs
+type QueryKind int
+
+const (
+	// QueryFind is the find query of the documents of a page
+	QueryFind QueryKind = iota
+	// QueryCount is the query counting the documents when CountTotal is set
+	QueryCount
+	// QueryAggregate is the aggregation of the documents of a page
+	QueryAggregate
+	// QueryAggregateCount is the aggregation counting the documents output by the pipeline when CountTotal is set
+	QueryAggregateCount
+)
+
+type (
+	// Executor runs the count, find and aggregate queries of the pages, e.g. to limit the concurrent paginated
+	// scans of a collection with a semaphore or to fail them fast with a circuit breaker. Each attempt of a query
+	// retried by a RetryPolicy is run by the Executor.
+	Executor interface {
+		// Execute runs the query, or returns an error without running it
+		Execute(ctx context.Context, q QueryInfo, query func(ctx context.Context) error) error
+	}
+
+	// QueryInfo describes a query run by an Executor
+	QueryInfo struct {
+		Kind QueryKind
+		// The CollectionName of the params of the query, empty if they don't set it
+		CollectionName string
+		// The QueryFingerprint of the params of the query, grouping the queries of the same logical query, e.g.
+		// to label the spans and metrics of the queries
+		Fingerprint string
+	}
+
+	// ConcurrencyLimiter is an Executor limiting the number of queries running concurrently on each collection,
+	// the queries beyond the limit waiting for a slot until their context is done
+	ConcurrencyLimiter struct {
+		max   int
+		mu    sync.Mutex
+		slots map[string]chan struct{}
+	}
+)
+
+var _ Executor = &ConcurrencyLimiter{}
+
+// NewConcurrencyLimiter returns a ConcurrencyLimiter running at most max queries concurrently on each collection,
+// identified by the CollectionName of the params of the queries
+func NewConcurrencyLimiter(max int) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{max: max, slots: map[string]chan struct{}{}}
+}
+
+// Execute implements Executor
+func (l *ConcurrencyLimiter) Execute(ctx context.Context, q QueryInfo, query func(ctx context.Context) error) error {
+	l.mu.Lock()
+	slots, ok := l.slots[q.CollectionName]
+	if !ok {
+		slots = make(chan struct{}, l.max)
+		l.slots[q.CollectionName] = slots
+	}
+	l.mu.Unlock()
+
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	defer func() { <-slots }()
+	return query(ctx)
+}
+
+// execute runs the query with the Executor of p, retrying it with its RetryPolicy
+func (p FindParams) execute(ctx context.Context, kind QueryKind, query func(ctx context.Context) error) error {
+	ctx = withServerSelectionTimeout(ctx, p)
+	return p.RetryPolicy.do(ctx, func() error {
+		if p.Executor == nil {
+			return query(ctx)
+		}
+		return p.Executor.Execute(ctx, QueryInfo{Kind: kind, CollectionName: p.CollectionName, Fingerprint: p.fingerprint}, query)
+	})
+}
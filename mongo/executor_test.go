@@ -0,0 +1,136 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recordingExecutor records the queries it runs, failing them with err when it is set
+type recordingExecutor struct {
+	queries []QueryInfo
+	err     error
+}
+
+func (e *recordingExecutor) Execute(ctx context.Context, q QueryInfo, query func(ctx context.Context) error) error {
+	e.queries = append(e.queries, q)
+	if e.err != nil {
+		return e.err
+	}
+	return query(ctx)
+}
+
+func TestExecutor(t *testing.T) {
+	col := &fakeCollection{docs: []interface{}{Item{ID: primitive.NewObjectID(), Name: "a"}}}
+	executor := &recordingExecutor{}
+	var results []Item
+	p := FindParams{Collection: col, CollectionName: "items", Limit: 2, CountTotal: true, Executor: executor}
+	_, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	fingerprint := QueryFingerprint(p)
+	require.Equal(t, []QueryInfo{{Kind: QueryCount, CollectionName: "items", Fingerprint: fingerprint}, {Kind: QueryFind, CollectionName: "items", Fingerprint: fingerprint}}, executor.queries)
+
+	executor = &recordingExecutor{}
+	_, err = Aggregate(context.Background(), AggregateParams{Collection: col, CollectionName: "items", Pipeline: []bson.M{}, Limit: 2, CountTotal: true, Executor: executor}, &results)
+	require.NoError(t, err)
+	require.Equal(t, []QueryInfo{{Kind: QueryAggregateCount, CollectionName: "items", Fingerprint: fingerprint}, {Kind: QueryAggregate, CollectionName: "items", Fingerprint: fingerprint}}, executor.queries)
+
+	// An open circuit fails the queries without running them
+	executor = &recordingExecutor{err: errors.New("circuit open")}
+	col.filter = nil
+	_, err = Find(context.Background(), FindParams{Collection: col, Limit: 2, Executor: executor}, &results)
+	require.EqualError(t, err, "circuit open")
+	require.Nil(t, col.filter)
+}
+
+func TestFindTimeSeriesExecutor(t *testing.T) {
+	col := &fakeCollection{docs: []interface{}{Item{ID: primitive.NewObjectID(), Name: "a", CreatedAt: time.Now()}}}
+	executor := &recordingExecutor{}
+	var results []Item
+	p := FindParams{Collection: col, CollectionName: "items", Limit: 1, PaginatedField: "createdAt", CountTotal: true, TimeBucket: time.Hour, Executor: executor}
+	_, err := FindTimeSeries(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	kinds := make([]QueryKind, len(executor.queries))
+	for i, q := range executor.queries {
+		kinds[i] = q.Kind
+	}
+	// The count, the lookup of the first bucket, its documents and the lookup of the following one
+	require.Equal(t, []QueryKind{QueryCount, QueryFind, QueryFind, QueryFind}, kinds)
+
+	// An open circuit fails the lookup of the first bucket without running it
+	executor = &recordingExecutor{err: errors.New("circuit open")}
+	col.filter = nil
+	p.CountTotal = false
+	p.Executor = executor
+	_, err = FindTimeSeries(context.Background(), p, &results)
+	require.EqualError(t, err, "circuit open")
+	require.Nil(t, col.filter)
+}
+
+func TestConcurrencyLimiter(t *testing.T) {
+	limiter := NewConcurrencyLimiter(2)
+	var mu sync.Mutex
+	running, maxRunning := map[string]int{}, map[string]int{}
+	query := func(collection string) func(context.Context) error {
+		return func(context.Context) error {
+			mu.Lock()
+			running[collection]++
+			maxRunning[collection] = max(maxRunning[collection], running[collection])
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			running[collection]--
+			mu.Unlock()
+			return nil
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, collection := range []string{"items", "users"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.NoError(t, limiter.Execute(context.Background(), QueryInfo{CollectionName: collection}, query(collection)))
+			}()
+		}
+	}
+	wg.Wait()
+	require.Equal(t, map[string]int{"items": 2, "users": 2}, maxRunning)
+
+	// The queries waiting for a slot give up when their context is done
+	release := make(chan struct{})
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = limiter.Execute(context.Background(), QueryInfo{}, func(context.Context) error {
+				<-release
+				return nil
+			})
+		}()
+	}
+	for {
+		limiter.mu.Lock()
+		busy := len(limiter.slots[""]) == 2
+		limiter.mu.Unlock()
+		if busy {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := limiter.Execute(ctx, QueryInfo{}, func(context.Context) error { return nil })
+	require.Equal(t, context.Canceled, err)
+	close(release)
+	wg.Wait()
+}
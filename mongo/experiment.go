@@ -0,0 +1,96 @@
+package mongo
+
+import (
+	"context"
+	"hash/fnv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type (
+	// PredicateExperiment routes a percentage of the pages of mongo.Find to an alternate PredicateStrategy and
+	// records the latency of their find queries along with the ones of the other pages, to choose the strategy of
+	// the queries with production evidence. Only the pages starting at a cursor on DialectMongo take part in the
+	// experiment, the first pages have no cursor predicate.
+	PredicateExperiment struct {
+		// The strategy of the pages routed to the variant
+		Alternate PredicateStrategy
+		// The percentage of the pages routed to the variant, from 0 to 100. The pages are routed by hashing the
+		// QueryFingerprint of their params and their cursor, so a page is always routed to the same arm and
+		// every logical query has pages in both arms
+		Percent float64
+		// Samples the documents the server examines for the find queries of the experiment with explain, when
+		// the Collection implements DocsExaminedEstimator. The explain is an additional query
+		ExplainDocsExamined bool
+		// Records the observation of each page of the experiment, e.g. as a metric labeled by its fingerprint and
+		// strategy, required
+		Record func(ctx context.Context, o PredicateObservation)
+	}
+
+	// PredicateObservation is the outcome of the find query of a page of a PredicateExperiment
+	PredicateObservation struct {
+		// The QueryFingerprint of the params of the page
+		Fingerprint string
+		// The strategy the page was queried with
+		Strategy PredicateStrategy
+		// true if the page was routed to the Alternate strategy of the experiment
+		Variant bool
+		// The duration of the find query, retries included
+		Latency time.Duration
+		// The number of documents fetched by the find query
+		Documents int
+		// The number of documents the server examined, -1 if not sampled
+		DocsExamined int64
+		// The error of the find query
+		Err error
+	}
+)
+
+// routesToVariant returns whether the page of the fingerprint starting at the cursor is routed to the variant
+func (e PredicateExperiment) routesToVariant(fingerprint string, cursor string) bool {
+	h := fnv.New64a()
+	writeString(h, fingerprint)
+	writeString(h, cursor)
+	return float64(h.Sum64()%10000) < e.Percent*100
+}
+
+// predicateExperiment returns p with the PredicateStrategy of the arm of its page when it takes part in its
+// PredicateExperiment, along with the observation of the page to record, nil if it doesn't take part
+func (p FindParams) predicateExperiment() (FindParams, *PredicateObservation) {
+	e := p.PredicateExperiment
+	if e == nil || e.Record == nil || p.Dialect != DialectMongo || e.Alternate == p.PredicateStrategy {
+		return p, nil
+	}
+	cursor := p.Next
+	if cursor == "" {
+		cursor = p.Previous
+	}
+	if cursor == "" {
+		return p, nil
+	}
+
+	o := &PredicateObservation{Fingerprint: p.fingerprint, Strategy: p.PredicateStrategy, DocsExamined: -1}
+	if e.routesToVariant(p.fingerprint, cursor) {
+		o.Strategy = e.Alternate
+		o.Variant = true
+	}
+	p.PredicateStrategy = o.Strategy
+	return p, o
+}
+
+// recordPredicateObservation completes the observation of the find query of a page with its outcome, sampling the
+// documents it examined if required, and records it
+func (p FindParams) recordPredicateObservation(ctx context.Context, o *PredicateObservation, queries []bson.M, sort bson.D, fetchLimit int64, started time.Time, fetched int, err error) {
+	o.Latency = now().Sub(started)
+	o.Documents = fetched
+	o.Err = err
+	if estimator, ok := p.Collection.(DocsExaminedEstimator); ok && err == nil && p.PredicateExperiment.ExplainDocsExamined {
+		options := newFindOptions(sort, fetchLimit, p.Collation, p.Hint, p.Projection, p.Timeout)
+		docsExamined, explainErr := estimator.EstimateDocsExamined(ctx, MergeQueries(queries), options)
+		if explainErr == nil {
+			o.DocsExamined = docsExamined
+		}
+	}
+	p.PredicateExperiment.Record(ctx, *o)
+}
@@ -0,0 +1,62 @@
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPredicateExperiment(t *testing.T) {
+	items := []Item{{ID: primitive.NewObjectID(), Name: "a", Data: "x"}}
+	col := &estimatingCollection{fakeCollection: fakeCollection{docs: []interface{}{items[0]}}, docsExamined: 7}
+	var observations []PredicateObservation
+	experiment := &PredicateExperiment{
+		Alternate:           PredicateRowValueExpr,
+		Percent:             30,
+		ExplainDocsExamined: true,
+		Record: func(_ context.Context, o PredicateObservation) {
+			observations = append(observations, o)
+		},
+	}
+	p := FindParams{Collection: col, CollectionName: "items", Limit: 2, PaginatedFields: []string{"name", "data"}, SortOrders: []int{1, 1}, PredicateExperiment: experiment}
+
+	// The first page has no cursor predicate
+	var results []Item
+	_, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Empty(t, observations)
+
+	variants := 0
+	for i := 0; i < 200; i++ {
+		p.Next, err = GenerateCursor(Item{ID: primitive.NewObjectID(), Name: fmt.Sprint(i), Data: "x"}, p)
+		require.NoError(t, err)
+		_, err = Find(context.Background(), p, &results)
+		require.NoError(t, err)
+
+		o := observations[len(observations)-1]
+		require.Equal(t, QueryFingerprint(p), o.Fingerprint)
+		require.Equal(t, 1, o.Documents)
+		require.Equal(t, int64(7), o.DocsExamined)
+		require.GreaterOrEqual(t, o.Latency, time.Duration(0))
+		usesExpr := strings.Contains(fmt.Sprint(col.filter), "$expr")
+		require.Equal(t, o.Variant, usesExpr)
+		if o.Variant {
+			require.Equal(t, PredicateRowValueExpr, o.Strategy)
+			variants++
+		} else {
+			require.Equal(t, PredicateNestedOr, o.Strategy)
+		}
+
+		// A page is always routed to the same arm
+		_, err = Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, o.Variant, observations[len(observations)-1].Variant)
+	}
+	require.Len(t, observations, 400)
+	require.InDelta(t, 60, variants, 25)
+}
@@ -0,0 +1,53 @@
+package mongo
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// cursorIssuedKey is the key of the cursor element holding the issue time of an expiring cursor, in milliseconds
+// since the epoch
+const cursorIssuedKey = "$iat"
+
+// now returns the current time, replaced by the tests
+var now = time.Now
+
+// expiringCursorCodec stamps the cursors of a CursorCodec with their issue time, rejecting the cursors older than
+// the ttl or issued later than now, both beyond the tolerated clock skew of the instances minting them
+type expiringCursorCodec struct {
+	codec     CursorCodec
+	ttl       time.Duration
+	clockSkew time.Duration
+}
+
+// Encode implements CursorCodec
+func (c expiringCursorCodec) Encode(cursorData bson.D) (string, error) {
+	stamped := make(bson.D, 0, len(cursorData)+1)
+	stamped = append(stamped, cursorData...)
+	return c.codec.Encode(append(stamped, bson.E{Key: cursorIssuedKey, Value: now().UnixMilli()}))
+}
+
+// Decode implements CursorCodec
+func (c expiringCursorCodec) Decode(cursor string) (bson.D, error) {
+	cursorData, err := c.codec.Decode(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if len(cursorData) == 0 || cursorData[len(cursorData)-1].Key != cursorIssuedKey {
+		return nil, NewErrCursorExpired()
+	}
+	issuedMillis, ok := cursorData[len(cursorData)-1].Value.(int64)
+	if !ok {
+		return nil, NewErrCursorExpired()
+	}
+	issued := time.UnixMilli(issuedMillis)
+	current := now()
+	if issued.After(current.Add(c.clockSkew)) {
+		return nil, NewErrCursorIssuedInFuture(issued.Sub(current))
+	}
+	if current.Sub(issued) > c.ttl+c.clockSkew {
+		return nil, NewErrCursorExpired()
+	}
+	return cursorData[:len(cursorData)-1], nil
+}
@@ -0,0 +1,92 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindExpiresCursors(t *testing.T) {
+	defer func() { now = time.Now }()
+	issued := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return issued }
+
+	items := []Item{{ID: primitive.NewObjectID(), Name: "a"}, {ID: primitive.NewObjectID(), Name: "b"}}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1]}}
+	p := FindParams{Collection: col, Limit: 1, CursorTTL: time.Minute, CursorClockSkew: 5 * time.Second}
+
+	var results []Item
+	cursor, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	p.Next = cursor.Next
+
+	for _, tc := range []struct {
+		name string
+		now  time.Time
+		err  interface{}
+	}{
+		{"within the ttl", issued.Add(time.Minute), nil},
+		{"within the tolerated skew past the ttl", issued.Add(time.Minute + 5*time.Second), nil},
+		{"expired", issued.Add(time.Minute + 6*time.Second), &ErrCursorExpired{}},
+		{"issued by a clock ahead within the tolerated skew", issued.Add(-5 * time.Second), nil},
+		{"issued in the future", issued.Add(-6 * time.Second), &ErrCursorIssuedInFuture{}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			now = func() time.Time { return tc.now }
+			_, err := Find(context.Background(), p, &results)
+			if tc.err == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.IsType(t, &CursorError{}, err)
+			switch tc.err.(type) {
+			case *ErrCursorExpired:
+				var expired *ErrCursorExpired
+				require.ErrorAs(t, err, &expired)
+			case *ErrCursorIssuedInFuture:
+				var future *ErrCursorIssuedInFuture
+				require.ErrorAs(t, err, &future)
+			}
+		})
+	}
+
+	// The cursors issued without a ttl are rejected
+	next, err := GenerateCursor(items[0], FindParams{})
+	require.NoError(t, err)
+	p.Next = next
+	_, err = Find(context.Background(), p, &results)
+	require.EqualError(t, err, "next cursor parse failed: cursor has expired")
+}
+
+func TestBase64CursorCodecLetsClientsRestampCursors(t *testing.T) {
+	defer func() { now = time.Now }()
+	issued := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return issued }
+
+	items := []Item{{ID: primitive.NewObjectID(), Name: "a"}, {ID: primitive.NewObjectID(), Name: "b"}}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1]}}
+	p := FindParams{Collection: col, Limit: 1, CursorTTL: time.Minute}
+
+	var results []Item
+	cursor, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+
+	// The expired cursor is renewed by rewriting its issue time, which only a signing CursorCodec prevents
+	now = func() time.Time { return issued.Add(time.Hour) }
+	p.Next = cursor.Next
+	_, err = Find(context.Background(), p, &results)
+	var expired *ErrCursorExpired
+	require.ErrorAs(t, err, &expired)
+
+	cursorData, err := Base64CursorCodec{}.Decode(cursor.Next)
+	require.NoError(t, err)
+	require.Equal(t, cursorIssuedKey, cursorData[len(cursorData)-1].Key)
+	cursorData[len(cursorData)-1].Value = now().UnixMilli()
+	p.Next, err = Base64CursorCodec{}.Encode(cursorData)
+	require.NoError(t, err)
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+}
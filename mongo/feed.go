@@ -0,0 +1,144 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// maxGapTokenSize bounds the size of a decoded gap token, which holds two base64 encoded cursors
+const maxGapTokenSize = 4 * mcpbson.DefaultMaxCursorSize
+
+type (
+	// FeedCursor holds the pagination data of a feed page, e.g. of a chat or timeline whose head keeps growing.
+	// The head of a feed is its first page in the sort order of the FindParams, e.g. the newest documents when
+	// sorting by descending creation time.
+	FeedCursor struct {
+		Cursor
+		// The cursor of the first document of the feed fetched so far, to pass as since to a later FindFeedHead
+		// call to get the documents added in the meantime
+		Head string
+		// The token to pass to FillGap to get the documents between the page and the ones fetched before it, when
+		// the page didn't reach them. This is set to the empty string if there is no gap.
+		GapToken string
+	}
+
+	gapToken struct {
+		From  string `bson:"f"`
+		Until string `bson:"u"`
+	}
+)
+
+// FindFeedHead executes a find mongo query for the head page of a feed, fills the passed in result slice pointer
+// and returns a FeedCursor. since is the Head of the FeedCursor of the last head fetched, the page then only holds
+// the documents added before it. When more documents than the limit were added, the page is the newest part of
+// them and the GapToken of the FeedCursor allows fetching the others with FillGap. An empty since fetches the head
+// of the feed, whose Next cursor pages through the older history with Find.
+func FindFeedHead(ctx context.Context, p FindParams, since string, results interface{}) (FeedCursor, error) {
+	p.Next = ""
+	p.Previous = ""
+	feedCursor, err := findUntil(ctx, p, since, results)
+	if err != nil {
+		return FeedCursor{}, err
+	}
+
+	feedCursor.Head = since
+	resultsVal := reflect.ValueOf(results).Elem()
+	if resultsVal.Len() > 0 {
+		p, err = applySortSpec(p, DefaultSortRegistry)
+		if err != nil {
+			return FeedCursor{}, err
+		}
+		p = ensureMandatoryParams(p)
+		feedCursor.Head, err = generateCursor(resultsVal.Index(0).Interface(), p)
+		if err != nil {
+			return FeedCursor{}, fmt.Errorf("could not create a head cursor: %s", err)
+		}
+	}
+	return feedCursor, nil
+}
+
+// FillGap executes a find mongo query for the page following the start of the gap encoded in the gap token of a
+// FeedCursor, without going past the documents fetched before the gap. The GapToken of the returned FeedCursor is
+// set if the gap still isn't filled.
+func FillGap(ctx context.Context, p FindParams, token string, results interface{}) (FeedCursor, error) {
+	gt, err := decodeGapToken(token)
+	if err != nil {
+		return FeedCursor{}, err
+	}
+	p.Next = gt.From
+	p.Previous = ""
+	return findUntil(ctx, p, gt.Until, results)
+}
+
+// findUntil executes a find mongo query for the page of p, excluding the documents from the until cursor on. A
+// gap token is returned if more documents remain before the until cursor.
+func findUntil(ctx context.Context, p FindParams, until string, results interface{}) (FeedCursor, error) {
+	if until == "" {
+		cursor, err := Find(ctx, p, results)
+		return FeedCursor{Cursor: cursor}, err
+	}
+
+	bounded, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return FeedCursor{}, err
+	}
+	bounded = ensureMandatoryParams(bounded)
+
+	// The documents preceding the until cursor are the ones of the pages before it
+	untilParams := bounded
+	untilParams.Next = ""
+	untilParams.Previous = until
+	untilParams.IncludeAnchor = false
+	untilQuery, _, err := buildCursorQuery(untilParams)
+	if err != nil {
+		return FeedCursor{}, &CursorError{fmt.Errorf("gap boundary parse failed: %s", err)}
+	}
+	bounded.Query = MergeQueries([]bson.M{bounded.Query, untilQuery})
+
+	cursor, err := Find(ctx, bounded, results)
+	if err != nil {
+		return FeedCursor{}, err
+	}
+
+	// The page reached the documents fetched before unless there are more
+	feedCursor := FeedCursor{Cursor: cursor}
+	if cursor.HasNext && cursor.Next != "" {
+		feedCursor.GapToken, err = encodeGapToken(gapToken{From: cursor.Next, Until: until})
+		if err != nil {
+			return FeedCursor{}, err
+		}
+	}
+	return feedCursor, nil
+}
+
+func encodeGapToken(gt gapToken) (string, error) {
+	data, err := bson.Marshal(gt)
+	if err != nil {
+		return "", err
+	}
+	return mcpbson.EncodeCursor(data), nil
+}
+
+func decodeGapToken(token string) (gapToken, error) {
+	var gt gapToken
+	if token == "" {
+		return gt, &CursorError{errors.New("gap token parse failed: empty token")}
+	}
+	data, err := mcpbson.DecodeCursor(token, maxGapTokenSize, mcpbson.DefaultMaxCursorDepth)
+	if err != nil {
+		return gt, &CursorError{fmt.Errorf("gap token parse failed: %s", err)}
+	}
+	err = bson.Unmarshal(data, &gt)
+	if err != nil {
+		return gt, &CursorError{fmt.Errorf("gap token parse failed: %s", err)}
+	}
+	if gt.From == "" || gt.Until == "" {
+		return gt, &CursorError{errors.New("gap token parse failed: missing boundary")}
+	}
+	return gt, nil
+}
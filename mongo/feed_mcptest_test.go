@@ -0,0 +1,48 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindFeedHeadFillsGap(t *testing.T) {
+	items, col := mcptest.NewItems(t)
+	p := mongo.FindParams{Collection: col, Limit: 2, PaginatedField: "createdAt"}
+
+	var results []mcptest.Item
+	feedCursor, err := mongo.FindFeedHead(context.Background(), p, "", &results)
+	require.NoError(t, err)
+	require.Equal(t, []mcptest.Item{items[4], items[3]}, results)
+
+	// More documents than the limit are added since the head
+	now := items[4].CreatedAt
+	added := []mcptest.Item{
+		{ID: primitive.NewObjectID(), Name: "new item 1", CreatedAt: now.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), Name: "new item 2", CreatedAt: now.Add(2 * time.Hour)},
+		{ID: primitive.NewObjectID(), Name: "new item 3", CreatedAt: now.Add(3 * time.Hour)},
+	}
+	for _, i := range added {
+		require.NoError(t, col.Insert(i))
+	}
+
+	feedCursor, err = mongo.FindFeedHead(context.Background(), p, feedCursor.Head, &results)
+	require.NoError(t, err)
+	require.Equal(t, []mcptest.Item{added[2], added[1]}, results)
+	require.NotEmpty(t, feedCursor.GapToken)
+
+	gapCursor, err := mongo.FillGap(context.Background(), p, feedCursor.GapToken, &results)
+	require.NoError(t, err)
+	require.Equal(t, []mcptest.Item{added[0]}, results)
+	require.Empty(t, gapCursor.GapToken)
+
+	feedCursor, err = mongo.FindFeedHead(context.Background(), p, feedCursor.Head, &results)
+	require.NoError(t, err)
+	require.Empty(t, results)
+	require.Empty(t, feedCursor.GapToken)
+}
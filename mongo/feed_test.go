@@ -0,0 +1,129 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestFindFeedHead(t *testing.T) {
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	col := &fakeCollection{docs: []interface{}{items[2], items[1]}}
+	p := FindParams{Collection: col, Limit: 1}
+
+	var results []Item
+	feedCursor, err := FindFeedHead(context.Background(), p, "", &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[2]}, results)
+	require.Empty(t, feedCursor.GapToken)
+	head, err := GenerateCursor(items[2], p)
+	require.NoError(t, err)
+	require.Equal(t, head, feedCursor.Head)
+
+	// The documents added since the head are queried, leaving a gap when they don't fit in the page
+	since, err := GenerateCursor(items[0], p)
+	require.NoError(t, err)
+	feedCursor, err = FindFeedHead(context.Background(), p, since, &results)
+	require.NoError(t, err)
+	require.Equal(t, head, feedCursor.Head)
+	require.NotEmpty(t, feedCursor.GapToken)
+	require.Equal(t, bson.M{"_id": map[string]interface{}{"$gt": items[0].ID}}, col.filter)
+
+	col.docs = []interface{}{items[1]}
+	feedCursor, err = FillGap(context.Background(), p, feedCursor.GapToken, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[1]}, results)
+	require.Empty(t, feedCursor.GapToken)
+	require.Equal(t, bson.M{"$and": []bson.M{
+		{"_id": map[string]interface{}{"$gt": items[0].ID}},
+		{"_id": map[string]interface{}{"$lt": items[2].ID}},
+	}}, col.filter)
+}
+
+func TestFillGapErrors(t *testing.T) {
+	var results []Item
+	_, err := FillGap(context.Background(), FindParams{Collection: &fakeCollection{}, Limit: 1}, "", &results)
+	require.EqualError(t, err, "gap token parse failed: empty token")
+	require.IsType(t, &CursorError{}, err)
+
+	_, err = FillGap(context.Background(), FindParams{Collection: &fakeCollection{}, Limit: 1}, "invalid", &results)
+	require.IsType(t, &CursorError{}, err)
+}
+
+// pollingCollection serves its docs once polled the specified number of times
+type pollingCollection struct {
+	*fakeCollection
+	docs  []interface{}
+	polls int
+	after int
+}
+
+func (c *pollingCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (MongoCursor, error) {
+	c.polls++
+	if c.polls > c.after {
+		c.fakeCollection.docs = c.docs
+	}
+	return c.fakeCollection.Find(ctx, filter, opts...)
+}
+
+func TestPollFeedHead(t *testing.T) {
+	item := Item{ID: primitive.NewObjectID(), Name: "a"}
+	since, err := GenerateCursor(Item{ID: primitive.NewObjectID()}, FindParams{})
+	require.NoError(t, err)
+	var delays []time.Duration
+	backoff := func(attempt int) time.Duration {
+		delay := ExponentialIdleBackoff(time.Millisecond, 4*time.Millisecond)(attempt)
+		delays = append(delays, delay)
+		return delay
+	}
+
+	// The feed is polled until documents are added
+	col := &pollingCollection{fakeCollection: &fakeCollection{}, docs: []interface{}{item}, after: 4}
+	var results []Item
+	feedCursor, err := PollFeedHead(context.Background(), FindParams{Collection: col, Limit: 1}, since, &results, LongPoll{MaxWait: time.Minute, Backoff: backoff})
+	require.NoError(t, err)
+	require.Equal(t, []Item{item}, results)
+	require.NotEqual(t, since, feedCursor.Head)
+	require.Equal(t, 5, col.polls)
+	require.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond, 4 * time.Millisecond, 4 * time.Millisecond}, delays)
+
+	// The empty page keeps the position once the wait elapsed
+	col = &pollingCollection{fakeCollection: &fakeCollection{}, after: 1000}
+	feedCursor, err = PollFeedHead(context.Background(), FindParams{Collection: col, Limit: 1}, since, &results, LongPoll{MaxWait: 20 * time.Millisecond, Backoff: backoff})
+	require.NoError(t, err)
+	require.Empty(t, results)
+	require.Equal(t, since, feedCursor.Head)
+	require.Greater(t, col.polls, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = PollFeedHead(ctx, FindParams{Collection: col, Limit: 1}, since, &results, LongPoll{MaxWait: time.Minute, Backoff: backoff})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func FuzzDecodeGapToken(f *testing.F) {
+	token, err := encodeGapToken(gapToken{From: "AQ", Until: "Ag"})
+	require.NoError(f, err)
+	f.Add(token)
+	f.Add(token[:len(token)/2])
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, token string) {
+		gt, err := decodeGapToken(token)
+		if err != nil {
+			require.IsType(t, &CursorError{}, err)
+			return
+		}
+		require.NotEmpty(t, gt.From)
+		require.NotEmpty(t, gt.Until)
+	})
+}
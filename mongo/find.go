@@ -1,26 +1,28 @@
-// Package mongocursorpagination eases the computation of pagination information of a find mongo query
-// by augmenting the base query with cursor information and returning a cursor.
+// Package mongo eases the computation of pagination information of a find mongo query using the mongo driver
+// (go.mongodb.org/mongo-driver) by augmenting
+// the base query with cursor information and returning a cursor.
 package mongo
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const (
-	defaultCursorTimeout = 45 * time.Second
-)
-
 type (
 	MongoCursor interface {
 		Close(context.Context) error
@@ -32,6 +34,8 @@ type (
 		All(context.Context, interface{}) error
 		RemainingBatchLength() int
 	}
+	// DecodeFunc decodes a raw document into a value of its concrete type
+	DecodeFunc func(bson.Raw) (interface{}, error)
 	Collection interface {
 		CountDocuments(context.Context, interface{}, ...*options.CountOptions) (int64, error)
 		Find(context.Context, interface{}, ...*options.FindOptions) (MongoCursor, error)
@@ -40,6 +44,11 @@ type (
 	// Cursor.
 	FindParams struct {
 		Collection Collection
+		// The mongo collection to query when Collection is nil, sparing the need to wrap it in a Collection
+		MongoCollection *mongodriver.Collection
+		// The name of the mongo collection, used to look up its SortSpec in the DefaultSortRegistry.
+		// The paginated fields aren't governed by a SortSpec when empty
+		CollectionName string
 
 		// The find query to augment with pagination
 		Query primitive.M
@@ -49,7 +58,7 @@ type (
 		SortAscending bool
 		// The name of the mongo collection field being paginated and sorted on. This field must:
 		// 1. Be orderable. We must sort by this value. If duplicate values for paginatedField field
-		//    exist, the results will be secondarily ordered by the _id
+		//    exist, the results will be secondarily ordered by the TiebreakerField
 		// 2. Be indexed. For large collections, this should be indexed for query performance
 		// 3. Be immutable. If the value changes between paged queries, it could appear twice
 		// 4. Match the bson field name the result struct. e.g.:
@@ -62,7 +71,10 @@ type (
 		//    }
 		//
 		PaginatedField string
-		// This parameter will also apply timeout of counting total results
+		// The collation to use for the sort ordering, the cursor query comparisons and for counting total results.
+		// This is ignored when paginating on the _id only. The index on the paginated fields must be created with
+		// the same collation to be used for the sort, see ValidateCollationIndex. Find resolves it with the
+		// CollationResolver of the Defaults if nil
 		Collation *options.Collation
 		// The value to start querying the page
 		Next string
@@ -75,17 +87,142 @@ type (
 		// as a document. The default value is nil, which means that no hint will be sent.
 		Hint interface{}
 		// A document describing which fields will be included in the documents returned by the operation. The default value
-		// is nil, which means all fields will be included.
-		// Example: bson.D{"_id":0, "name": 1}
+		// is nil, which means all fields will be included. The paginated fields, including the _id tiebreaker, must
+		// be returned or an ErrProjectionExcludesField is returned.
+		// Example: bson.D{{"name", 1}}
 		Projection interface{}
 		// This parameter will set the maxTimeMS option on the mongo find cursor, making sure we add a limit to the amount of time
-		// mongo can process this on the backend. Will default to 45 seconds, but should be set to an appropriate duration
+		// mongo can process this on the backend. Will default to the Timeout of the Defaults, but should be set to an appropriate duration
 		// This parameter will also apply timeout of counting total results
 		Timeout time.Duration
 		// The names of multiple fields being paginated and sorted on. Takes precedence over PaginatedField
 		PaginatedFields []string
 		// The sort orders corresponding to PaginatedFields. Each value must be either 1 or -1
 		SortOrders []int
+		// true, if the page starting at Next or Previous should include the anchor document the cursor points at,
+		// e.g. for sync protocols verifying that the boundary document wasn't missed
+		IncludeAnchor bool
+		// The number of pages of Limit documents skipped past the cursor, or from the start of the query without
+		// one, to jump several pages in a single query, e.g. for numbered paginators. The skipped documents are
+		// still scanned by the server, so it should stay small
+		SkipPages int
+		// The scan budget to enforce on the find query. When the budget is exceeded, the documents fetched so far
+		// are returned as a partial page and Cursor.BudgetExceeded is set instead of failing the query
+		ScanBudget *ScanBudget
+		// The shape of the cursor queries, defaults to DialectMongo
+		Dialect Dialect
+		// How the cursor queries compare the paginated fields, defaults to PredicateNestedOr
+		PredicateStrategy PredicateStrategy
+		// The version of the mongo server, e.g. "4.2.1", used by PredicateAuto. $expr isn't used when unknown
+		ServerVersion string
+		// Routes a percentage of the pages to an alternate PredicateStrategy and records the latency of both.
+		// Defaults to the PredicateExperiment of the Defaults
+		PredicateExperiment *PredicateExperiment
+		// true to bind the cursors to the collection, paginated fields, sort orders and query they were minted
+		// for, so that a cursor reused with other params is rejected with an ErrCursorScopeMismatch instead of
+		// producing confusing pages. This guards against mistakes, not tampering: the scope is only protected by
+		// the CursorCodec, the Base64CursorCodec lets clients rewrite it, so enforcing it against untrusted clients
+		// requires a CursorCodec signing or encrypting the cursors
+		BindCursorScope bool
+		// How long the cursors remain valid once issued, e.g. to bound the staleness of the pages clients resume.
+		// An expired cursor is rejected with an ErrCursorExpired. The cursors don't expire if it is 0. The issue
+		// time is only protected by the CursorCodec, the Base64CursorCodec lets clients re-stamp their cursors,
+		// so it should be combined with a CursorCodec signing or encrypting them
+		CursorTTL time.Duration
+		// The clock skew tolerated between the instances issuing and decoding the expiring cursors, extending their
+		// CursorTTL. A cursor issued later than now beyond it is rejected with an ErrCursorIssuedInFuture
+		CursorClockSkew time.Duration
+		// The number of documents at each end of a page whose tiebreaker values are carried by its cursors, so that
+		// the next or previous page excludes them when their paginated field changed between the requests and
+		// moved them past the cursor, instead of delivering them twice. The cursors grow with it, so it should be
+		// small. The pages of Find and Aggregate carry them. No document is excluded if it is 0
+		DedupWindow int
+		// Whether Find checks that an index supports the sort of the page, listing the indexes before each page,
+		// defaults to the IndexCheck of the Defaults. The indexes are left unchecked if IndexCheckOff
+		IndexCheck IndexCheck
+		// The indexes of the Collection checked by the IndexCheck, defaults to the indexes of the MongoCollection
+		Indexes IndexLister
+		// How the Cursor detects whether more documents follow the page, defaults to LimitPlusOneDetector
+		MorePagesDetector MorePagesDetector
+		// How exactly the HasPrevious and HasNext values of the Cursor reflect the documents on the other side of
+		// Next or Previous, defaults to ConsistencyFast
+		Consistency Consistency
+		// How the total count of documents is computed for the pages queried with Next or Previous when
+		// CountTotal is set, defaults to CountEveryPage
+		CountPolicy CountPolicy
+		// The number of documents counted at most when CountTotal is set, bounding the cost of the count of large
+		// result sets. The Count of the Cursor is the CountLimit and its CountRelation is CountRelationGte when
+		// more documents match. The documents are all counted if it is 0
+		CountLimit int64
+		// Adjusts the options of the find query of the page once the package has set them, e.g. to set driver
+		// options it doesn't expose. Changing the sort, limit or skip breaks the pagination. The options of the
+		// count and probe queries aren't adjusted.
+		RawFindOptions func(*options.FindOptions)
+		// The field breaking the ties of the paginated fields, appended to them and encoded in the cursors,
+		// defaults to _id. It must be unique across the documents matched by the Query, e.g. a uuid field with a
+		// unique index, or a page may skip or repeat the documents tied with its boundary. The paginated fields'
+		// index should end with it.
+		TiebreakerField string
+		// true if the last paginated field, PaginatedField unless PaginatedFields is set, is unique across the
+		// documents matched by the Query, e.g. an email with a unique index. It then breaks the ties itself rather
+		// than the TiebreakerField, which is neither sorted on nor encoded in the cursors, so that the index of the
+		// paginated fields may cover the query. A page may skip or repeat documents if it isn't unique.
+		PaginatedFieldIsUnique bool
+		// The shard key of a sharded collection, e.g. bson.D{{"tenant", 1}, {"createdAt", 1}}, whose values are 1
+		// or "hashed". The cursor queries then also bound the ranged shard key fields among the paginated fields
+		// with the cursor values they're implied to be past or tied with, so that the pages target the shards
+		// owning them instead of being broadcast to all the shards. An ErrUntargetableSort is returned when the
+		// first shard key field is neither matched by the Query nor a ranged paginated field.
+		ShardKey bson.D
+		// The granularity of the buckets FindTimeSeries fetches a page by, e.g. 24 * time.Hour, aligned on its
+		// multiples since the zero time in UTC
+		TimeBucket time.Duration
+		// The encryption of the fields encrypted with CSFLE or queryable encryption. A paginated field that isn't
+		// range encrypted can't be compared to the cursors, unless the Query matches it with an equality, e.g. to
+		// page the documents of an encrypted ssn sorted by date. It is then left out of the cursor queries and sort,
+		// and an ErrFieldNotRangeQueryable is returned otherwise.
+		EncryptedFields map[string]Encryption
+		// Retries the count and find queries of the page failing with a transient error, defaults to the
+		// RetryPolicy of the Defaults. The queries aren't retried if nil
+		RetryPolicy *RetryPolicy
+		// Runs the count and find queries of the page, e.g. to limit the concurrent scans of the collection,
+		// defaults to the Executor of the Defaults. The queries are run directly if nil
+		Executor Executor
+		// The maxAwaitTimeMS of the getMore commands of a tailable await cursor, e.g. one whose CursorType is set
+		// with RawFindOptions. It is ignored by the other cursors
+		MaxAwaitTime time.Duration
+		// Bounds the time the find and count queries of the page wait for a server to be selected, shorter than
+		// the client's, for the endpoints that must fail fast when no server is available. The driver has no per
+		// operation server selection timeout, so it also bounds the time until the first batch of each query
+		// returns, the following batches being bounded by the context only
+		ServerSelectionTimeout time.Duration
+		// Serves the pages already queried with identical params without querying, e.g. for read heavy endpoints.
+		// The pages are keyed by the collection, query, sort, cursor, limit, CountTotal, collation, Projection and
+		// Hint of the params, see PageCacheKey. They're cached for PageCacheTTL, or until the callers invalidate the
+		// collection after writing to it, as the package doesn't see the writes
+		PageCache PageCache
+		// How long the pages are cached in the PageCache, until they're evicted if 0
+		PageCacheTTL time.Duration
+		// true to set the PreviousValues and NextValues of the Cursor to the values its cursors hold, e.g. to log
+		// the boundaries of the pages while troubleshooting. The cursors are decoded after being generated
+		DebugCursors bool
+		// Decodes each document of the page into its concrete type, e.g. by the discriminator field of a
+		// polymorphic collection, in place of the results' element type. The results must then be a pointer to a
+		// slice of an interface type, e.g. []interface{}, that the decoded values implement. The cursors are
+		// generated from the raw documents
+		DecodeFunc DecodeFunc
+		// The Defaults snapshotted when the query started
+		defaults *Defaults
+		// The scope of the cursors when they're bound to their query
+		scope string
+		// The QueryFingerprint reported to the Executor
+		fingerprint string
+		// The count carried by the cursors when the CountPolicy is CountCarried
+		carriedCount *int
+		// The tiebreaker values carried by the cursors when the DedupWindow is set
+		seen bson.A
+		// true if the Collation was resolved by the CollationResolver of the Defaults and is carried by the cursors
+		resolvedCollation bool
 	}
 
 	// Cursor holds the pagination data about the find mongo query that was performed.
@@ -102,26 +239,63 @@ type (
 		HasNext bool
 		// Total count of documents matching filter - only computed if CountTotal is True
 		Count int
+		// Where Count comes from, see FindParams.CountPolicy
+		CountSource CountSource
+		// Whether Count is the exact count of documents, or the CountLimit of the FindParams when more documents
+		// match, e.g. for UIs to render "10,000+ results"
+		CountRelation CountRelation
+		// true if the FindParams' ScanBudget was exceeded and the results are a partial page
+		BudgetExceeded bool
+		// The values the Previous cursor holds, decoded for logs and support tooling when the FindParams set
+		// DebugCursors
+		PreviousValues bson.D
+		// The values the Next cursor holds, decoded for logs and support tooling when the FindParams set
+		// DebugCursors
+		NextValues bson.D
 	}
 
 	CursorError struct {
 		err error
 	}
+
+	// driverCollection implements Collection for FindParams.MongoCollection
+	driverCollection struct {
+		collection *mongodriver.Collection
+	}
 )
 
+func (c *driverCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	return c.collection.CountDocuments(ctx, filter, opts...)
+}
+
+func (c *driverCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (MongoCursor, error) {
+	return c.collection.Find(ctx, filter, opts...)
+}
+
+func (c *driverCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (MongoCursor, error) {
+	return c.collection.Aggregate(ctx, pipeline, opts...)
+}
+
+// EstimateDocsExamined implements DocsExaminedEstimator so scan budgets can enforce MaxDocsExamined
+func (c *driverCollection) EstimateDocsExamined(ctx context.Context, filter interface{}, opts *options.FindOptions) (int64, error) {
+	return ExplainDocsExamined(ctx, c.collection, filter, opts)
+}
+
 func (e *CursorError) Error() string {
 	return e.err.Error()
 }
 
+func (e *CursorError) Unwrap() error {
+	return e.err
+}
+
 // BuildQueries builds the queries without executing them
 func BuildQueries(ctx context.Context, p FindParams) (queries []bson.M, sort bson.D, err error) {
-	p = ensureMandatoryParams(p)
-	var numPaginatedFields int
-	if len(p.PaginatedFields) > 0 {
-		numPaginatedFields = len(p.PaginatedFields)
-	} else {
-		numPaginatedFields = 1
+	p, err = applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return []bson.M{}, nil, err
 	}
+	p = ensureMandatoryParams(p)
 
 	if p.Collection == nil {
 		return []bson.M{}, nil, errors.New("Collection can't be nil")
@@ -131,20 +305,58 @@ func BuildQueries(ctx context.Context, p FindParams) (queries []bson.M, sort bso
 		return []bson.M{}, nil, errors.New("a limit of at least 1 is required")
 	}
 
-	nextCursorValues, err := parseCursor(p.Next, numPaginatedFields)
+	if p.SkipPages < 0 {
+		return []bson.M{}, nil, errors.New("SkipPages can't be negative")
+	}
+
+	cursorQuery, sort, err := buildCursorQuery(p)
+	if err != nil {
+		return []bson.M{}, nil, err
+	}
+
+	// Augment the specified find query with cursor data
+	queries = []bson.M{p.Query}
+	if cursorQuery != nil {
+		queries = append(queries, cursorQuery)
+	}
+
+	return queries, sort, nil
+}
+
+// buildCursorQuery returns the query matching the documents past the Next or Previous cursor of p, or nil when
+// neither is set, and the sort to apply
+func buildCursorQuery(p FindParams) (cursorQuery bson.M, sort bson.D, err error) {
+	var numPaginatedFields int
+	if len(p.PaginatedFields) > 0 {
+		numPaginatedFields = len(p.PaginatedFields)
+	} else {
+		numPaginatedFields = 1
+	}
+
+	nextCursorValues, err := parseCursor(p.Next, numPaginatedFields, p.cursorCodec())
 	if err != nil {
-		return []bson.M{}, nil, &CursorError{fmt.Errorf("next cursor parse failed: %s", err)}
+		return nil, nil, &CursorError{fmt.Errorf("next cursor parse failed: %w", err)}
 	}
 
-	previousCursorValues, err := parseCursor(p.Previous, numPaginatedFields)
+	previousCursorValues, err := parseCursor(p.Previous, numPaginatedFields, p.cursorCodec())
 	if err != nil {
-		return []bson.M{}, nil, &CursorError{fmt.Errorf("previous cursor parse failed: %s", err)}
+		return nil, nil, &CursorError{fmt.Errorf("previous cursor parse failed: %w", err)}
 	}
 
+	// The sort orders are reversed in place for a previous page, don't share them with the caller
+	p.SortOrders = append([]int{}, p.SortOrders...)
 	comparisonOps := generateComparisonOps(p)
 
-	// Augment the specified find query with cursor data
-	queries = []bson.M{p.Query}
+	// The encrypted fields matched by an equality are neither compared nor sorted on
+	pinned, err := equalityPinnedFields(p)
+	if err != nil {
+		return nil, nil, err
+	}
+	if pinned != nil {
+		comparisonOps = unpinned(comparisonOps, pinned)
+		p.PaginatedFields = unpinned(p.PaginatedFields, pinned)
+		p.SortOrders = unpinned(p.SortOrders, pinned)
+	}
 
 	// Setup the pagination query
 	if p.Next != "" || p.Previous != "" {
@@ -154,12 +366,26 @@ func BuildQueries(ctx context.Context, p FindParams) (queries []bson.M, sort bso
 		} else if p.Previous != "" {
 			cursorValues = previousCursorValues
 		}
-		var cursorQuery bson.M
-		cursorQuery, err = mcpbson.GenerateCursorQuery(p.PaginatedFields, comparisonOps, cursorValues)
+		cursorValues = unpinned(cursorValues, pinned)
+		cursorQuery, err = generateCursorQuery(p, comparisonOps, cursorValues)
 		if err != nil {
-			return []bson.M{}, nil, err
+			return nil, nil, err
+		}
+		if len(p.ShardKey) > 0 {
+			bounds, err := shardKeyBounds(p, comparisonOps, cursorValues)
+			if err != nil {
+				return nil, nil, err
+			}
+			cursorQuery = MergeQueries([]bson.M{bounds, cursorQuery})
+		}
+		if seen := seenQuery(p); seen != nil {
+			cursorQuery = MergeQueries([]bson.M{cursorQuery, seen})
+		}
+	} else if len(p.ShardKey) > 0 {
+		_, err = shardKeyBounds(p, comparisonOps, nil)
+		if err != nil {
+			return nil, nil, err
 		}
-		queries = append(queries, cursorQuery)
 	}
 
 	// Setup the sort query
@@ -167,82 +393,242 @@ func BuildQueries(ctx context.Context, p FindParams) (queries []bson.M, sort bso
 		sort = append(sort, bson.E{Key: p.PaginatedFields[i], Value: p.SortOrders[i]})
 	}
 
-	return queries, sort, nil
+	return cursorQuery, sort, nil
 }
 
 // Find executes a find mongo query by using the provided FindParams, fills the passed in result
 // slice pointer and returns a Cursor.
 func Find(ctx context.Context, p FindParams, results interface{}) (Cursor, error) {
-	var err error
-	p = ensureMandatoryParams(p)
-	err = validate(results, p.PaginatedFields)
+	if p.DecodeFunc != nil {
+		return findDecoded(ctx, p, results)
+	}
+	if p.natural() {
+		return findNatural(ctx, p, results)
+	}
+	p, err := prepareFind(ctx, p, results)
 	if err != nil {
 		return Cursor{}, err
 	}
+	if p.PageCache != nil {
+		return findCachedPage(ctx, p, results)
+	}
+	return findPage(ctx, p, results)
+}
+
+// prepareFind returns the ensured FindParams of a Find query filling results, once checked
+func prepareFind(ctx context.Context, p FindParams, results interface{}) (FindParams, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return p, err
+	}
+	p = ensureMandatoryParams(resolveCollation(ctx, p))
+	err = p.encoding().validate(results, p.PaginatedFields)
+	if err != nil {
+		return p, err
+	}
+	err = validateCursorTypes(results, p)
+	if err != nil {
+		return p, err
+	}
+	err = validateProjection(p)
+	if err != nil {
+		return p, err
+	}
+	return p, checkIndex(ctx, p)
+}
 
+// findPage executes the find mongo query of the page of the ensured FindParams, filling results
+func findPage(ctx context.Context, p FindParams, results interface{}) (Cursor, error) {
 	// Compute total count of documents matching filter - only computed if CountTotal is True
-	var count int
-	if p.CountTotal {
-		count, err = executeCountQuery(ctx, p.Collection, []bson.M{p.Query}, p.Collation, p.Timeout)
+	count, countSource, err := countTotal(p, func() (count int, err error) {
+		filter, countOptions := buildCountQuery(p)
+		err = p.execute(ctx, QueryCount, func(ctx context.Context) error {
+			count, err = executeCountQuery(ctx, p.Collection, filter, countOptions)
+			return err
+		})
+		return count, err
+	})
+	if err != nil {
+		return Cursor{}, err
+	}
+	p = p.carryCount(count, countSource)
+
+	// Probe for documents on the other side of the cursor
+	hasBeyondCursor := true
+	if p.Consistency == ConsistencyExact && (p.Next != "" || p.Previous != "") {
+		hasBeyondCursor, err = probeBeyondCursor(ctx, p)
 		if err != nil {
 			return Cursor{}, err
 		}
 	}
 
+	p, observation := p.predicateExperiment()
 	queries, sort, err := BuildQueries(ctx, p)
 	if err != nil {
 		return Cursor{}, err
 	}
 
-	// Execute the augmented query, get an additional element to see if there's another page
-	err = executeCursorQuery(ctx, p.Collection, queries, sort, p.Limit, p.Collation, p.Hint, p.Projection, p.Timeout, results)
+	// Execute the augmented query, fetching the documents the detector needs to see if there's another page
+	detector := p.MorePagesDetector
+	if detector == nil {
+		detector = LimitPlusOneDetector{}
+	}
+	fetchLimit := max(detector.FetchLimit(p.Limit), p.Limit)
+	// A previous page is fetched in reverse order, its documents are decoded in the sort order of the page
+	reverse := p.Previous != ""
+	rawOptions := p.skipPagesOptions()
+	var budgetExceeded bool
+	started := now()
+	err = p.execute(ctx, QueryFind, func(ctx context.Context) (err error) {
+		if p.ScanBudget != nil {
+			budgetExceeded, err = executeBudgetedCursorQuery(ctx, p.Collection, queries, sort, fetchLimit, reverse, p.Collation, p.Hint, p.Projection, *p.ScanBudget, p.Timeout, rawOptions, results)
+			return err
+		}
+		return executeCursorQuery(ctx, p.Collection, queries, sort, fetchLimit, reverse, p.Collation, p.Hint, p.Projection, p.Timeout, rawOptions, results)
+	})
+	if observation != nil {
+		var fetched int
+		if err == nil {
+			fetched = reflect.ValueOf(results).Elem().Len()
+		}
+		p.recordPredicateObservation(ctx, observation, queries, sort, fetchLimit, started, fetched, err)
+	}
 	if err != nil {
 		return Cursor{}, err
 	}
 
-	// Get the results slice's pointer and value
-	resultsPtr := reflect.ValueOf(results)
-	resultsVal := resultsPtr.Elem()
+	// Get the results slice's value and the bounds of the page within the fetched documents, the additional
+	// documents fetched to see if there's another page come last in the order they were fetched
+	resultsVal := reflect.ValueOf(results).Elem()
+	fetched := resultsVal.Len()
+	start, end := 0, min(fetched, int(p.Limit))
+	if reverse {
+		start, end = fetched-end, fetched
+	}
+
+	var hasMore bool
+	if budgetExceeded {
+		// A partial page may be followed by documents that weren't scanned
+		hasMore = fetched > 0
+	} else {
+		q := PageQuery{Collection: p.Collection, Params: p, Filter: MergeQueries(queries), Sort: sort}
+		if fetched > 0 {
+			if reverse {
+				q.Last = resultsVal.Index(start).Interface()
+			} else {
+				q.Last = resultsVal.Index(end - 1).Interface()
+			}
+		}
+		hasMore, err = detector.HasMore(ctx, q, fetched)
+		if err != nil {
+			return Cursor{}, err
+		}
+	}
+
+	// Remove the additional documents fetched to see if there was another page
+	resultsVal.Set(resultsVal.Slice(start, end))
+	p, err = p.carrySeen(resultsVal)
+	if err != nil {
+		return Cursor{}, err
+	}
+	var firstResult, lastResult interface{}
+	if resultsVal.Len() > 0 {
+		firstResult = resultsVal.Index(0).Interface()
+		lastResult = resultsVal.Index(resultsVal.Len() - 1).Interface()
+	}
+	cursor, err := pageCursor(p, firstResult, lastResult, hasMore)
+	if err != nil {
+		return Cursor{}, err
+	}
+	cursor.Count, cursor.CountRelation = p.cappedCount(count)
+	cursor.CountSource = countSource
+	cursor.BudgetExceeded = budgetExceeded
+	if budgetExceeded && resultsVal.Len() == 0 {
+		// Nothing was scanned, the query resumes from the cursor it started at
+		if reverse {
+			cursor.HasPrevious, cursor.Previous = true, p.Previous
+		} else {
+			cursor.HasNext, cursor.Next = true, p.Next
+		}
+	}
+	if !hasBeyondCursor {
+		if p.Next != "" {
+			cursor.HasPrevious, cursor.Previous = false, ""
+		} else {
+			cursor.HasNext, cursor.Next = false, ""
+		}
+	}
+
+	return cursor, nil
+}
 
-	hasMore := resultsVal.Len() > int(p.Limit)
+// paginateResults removes the additional documents fetched to see if there's another page from resultsVal,
+// corrects the sort order of previous pages fetched by the caller and returns the page's results and Cursor.
+// hasMore tells whether more documents follow the page in the direction it was queried.
+func paginateResults(p FindParams, resultsVal reflect.Value, hasMore bool) (reflect.Value, Cursor, error) {
+	// Remove the additional documents fetched to see if there was another page
+	if resultsVal.Len() > int(p.Limit) {
+		resultsVal = resultsVal.Slice(0, int(p.Limit))
+	}
 
-	// Remove the extra element that we added to see if there was another page
-	if hasMore {
-		resultsVal = resultsVal.Slice(0, resultsVal.Len()-1)
+	// If we sorted reverse to get the previous page, correct the sort order
+	if p.Previous != "" {
+		mcpbson.ReverseResults(resultsVal)
 	}
 
-	hasPrevious := p.Next != "" || (p.Previous != "" && hasMore)
+	var firstResult, lastResult interface{}
+	if resultsVal.Len() > 0 {
+		firstResult = resultsVal.Index(0).Interface()
+		lastResult = resultsVal.Index(resultsVal.Len() - 1).Interface()
+	}
+	cursor, err := pageCursor(p, firstResult, lastResult, hasMore)
+	return resultsVal, cursor, err
+}
+
+// orderPage removes the additional documents fetched to see if there's another page from docs and corrects the
+// sort order of previous pages
+func orderPage[T any](p FindParams, docs []T) []T {
+	if len(docs) > int(p.Limit) {
+		docs = docs[:p.Limit]
+	}
+	if p.Previous != "" {
+		slices.Reverse(docs)
+	}
+	return docs
+}
+
+// firstAndLast returns the first and last documents of a page, which are nil when it is empty
+func firstAndLast[T any](docs []T) (first, last interface{}) {
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	return docs[0], docs[len(docs)-1]
+}
+
+// pageCursor returns the Cursor of a page whose first and last results are specified, which are nil when the page
+// is empty. hasMore tells whether more documents follow the page in the direction it was queried.
+func pageCursor(p FindParams, firstResult, lastResult interface{}, hasMore bool) (Cursor, error) {
+	var err error
+	// The pages skipped forward from the start of the query precede the page
+	hasPrevious := p.Next != "" || (p.Previous != "" && hasMore) || (p.SkipPages > 0 && p.Previous == "")
 	hasNext := p.Previous != "" || hasMore
 
 	var previousCursor string
 	var nextCursor string
 
-	if resultsVal.Len() > 0 {
-		// If we sorted reverse to get the previous page, correct the sort order
-		if p.Previous != "" {
-			for left, right := 0, resultsVal.Len()-1; left < right; left, right = left+1, right-1 {
-				leftValue := resultsVal.Index(left).Interface()
-				resultsVal.Index(left).Set(resultsVal.Index(right))
-				resultsVal.Index(right).Set(reflect.ValueOf(leftValue))
-			}
-		}
-
-		// Generate the previous cursor
-		if hasPrevious {
-			firstResult := resultsVal.Index(0).Interface()
-			previousCursor, err = generateCursor(firstResult, p.PaginatedFields)
-			if err != nil {
-				return Cursor{}, fmt.Errorf("could not create a previous cursor: %s", err)
-			}
+	// Generate the previous cursor
+	if firstResult != nil && hasPrevious {
+		previousCursor, err = generateCursor(firstResult, p)
+		if err != nil {
+			return Cursor{}, fmt.Errorf("could not create a previous cursor: %s", err)
 		}
+	}
 
-		// Generate the next cursor
-		if hasNext {
-			lastResult := resultsVal.Index(resultsVal.Len() - 1).Interface()
-			nextCursor, err = generateCursor(lastResult, p.PaginatedFields)
-			if err != nil {
-				return Cursor{}, fmt.Errorf("could not create a next cursor: %s", err)
-			}
+	// Generate the next cursor
+	if lastResult != nil && hasNext {
+		nextCursor, err = generateCursor(lastResult, p)
+		if err != nil {
+			return Cursor{}, fmt.Errorf("could not create a next cursor: %s", err)
 		}
 	}
 
@@ -252,73 +638,211 @@ func Find(ctx context.Context, p FindParams, results interface{}) (Cursor, error
 		HasPrevious: hasPrevious,
 		Next:        nextCursor,
 		HasNext:     hasNext,
-		Count:       count,
 	}
+	if p.DebugCursors {
+		cursor.PreviousValues, err = p.cursorValues(previousCursor)
+		if err != nil {
+			return Cursor{}, fmt.Errorf("could not decode the previous cursor: %s", err)
+		}
+		cursor.NextValues, err = p.cursorValues(nextCursor)
+		if err != nil {
+			return Cursor{}, fmt.Errorf("could not decode the next cursor: %s", err)
+		}
+	}
+	return cursor, nil
+}
 
-	// Save the modified result slice in the result pointer
-	resultsPtr.Elem().Set(resultsVal)
+// cursorValues returns the values the cursor of p holds, nil if it is empty
+func (p FindParams) cursorValues(cursor string) (bson.D, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	return p.cursorCodec().Decode(cursor)
+}
+
+// GenerateCursor returns the URL safe cursor pointing at the specified result for the paginated fields of
+// the provided FindParams. Passing it as Next or Previous in a Find call with the same FindParams returns the
+// page after or before that result.
+func GenerateCursor(result interface{}, p FindParams) (string, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return "", err
+	}
+	p = ensureMandatoryParams(p)
+	return generateCursor(result, p)
+}
+
+// EndCursor returns the cursor pointing past the last document of the pages of the provided FindParams, to set as
+// their Previous cursor to query their last page, e.g. for the last argument of a Relay connection without before.
+// It holds MaxKey for the paginated fields sorted in ascending order and MinKey for the others, which sort after and
+// before every value. No document follows it, but the Cursor of the last page has HasNext set unless the
+// Consistency is ConsistencyExact, as the Cursor of any previous page.
+func EndCursor(p FindParams) (string, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return "", err
+	}
+	p = ensureMandatoryParams(p)
+	if p.natural() {
+		return "", errors.New("the natural order has no end cursor")
+	}
+	end := make(bson.D, 0, len(p.PaginatedFields))
+	for i, field := range p.PaginatedFields {
+		var value interface{} = primitive.MaxKey{}
+		if p.SortOrders[i] == -1 {
+			value = primitive.MinKey{}
+		}
+		end = append(end, bson.E{Key: field, Value: value})
+	}
+	return generateCursor(end, p)
+}
+
+// GenerateCursors returns the cursors pointing at each of the specified results, a slice or a pointer to a slice of
+// documents, for the paginated fields of the provided FindParams, e.g. for the per item cursors of Relay edges or
+// to cache the positions of documents. The params are resolved once for the whole batch and the raw documents are
+// read in place.
+func GenerateCursors(results interface{}, p FindParams) ([]string, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return nil, err
+	}
+	p = ensureMandatoryParams(p)
+	encoding := p.encoding()
+	codec := p.cursorCodec()
+
+	if docs, ok := results.([]bson.Raw); ok {
+		cursors := make([]string, len(docs))
+		for i, doc := range docs {
+			cursors[i], err = encoding.cursor(doc, p.PaginatedFields, codec)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return cursors, nil
+	}
+
+	resultsVal := reflect.Indirect(reflect.ValueOf(results))
+	if resultsVal.Kind() != reflect.Slice && resultsVal.Kind() != reflect.Array {
+		return nil, NewErrInvalidResults("expected results to be a slice or a pointer to a slice")
+	}
+	cursors := make([]string, resultsVal.Len())
+	for i := range cursors {
+		cursors[i], err = encoding.cursor(resultsVal.Index(i).Interface(), p.PaginatedFields, codec)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cursors, nil
+}
 
+// MakeCursors returns the Cursor of a page whose documents were fetched by the caller, e.g. with a custom driver
+// or from a cache, using the queries and sort returned by BuildQueries for the same FindParams. results is a
+// pointer to the slice of the fetched documents, which is trimmed to the limit and, for a previous page, reversed
+// back to the sort order. hasMore tells whether more documents follow the page in the direction it was queried,
+// e.g. whether more documents than the limit were fetched.
+func MakeCursors(results interface{}, p FindParams, hasMore bool) (Cursor, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return Cursor{}, err
+	}
+	p = ensureMandatoryParams(p)
+	err = p.encoding().validate(results, p.PaginatedFields)
+	if err != nil {
+		return Cursor{}, err
+	}
+	if p.Limit <= 0 {
+		return Cursor{}, errors.New("a limit of at least 1 is required")
+	}
+
+	resultsPtr := reflect.ValueOf(results)
+	resultsVal, cursor, err := paginateResults(p, resultsPtr.Elem(), hasMore)
+	if err != nil {
+		return Cursor{}, err
+	}
+	resultsPtr.Elem().Set(resultsVal)
 	return cursor, nil
 }
 
+// ValidateCursor verifies that the Next or Previous cursor of the provided FindParams would be accepted by a Find
+// call filling results, without querying, e.g. to check that the cursors issued by a previous version of the
+// package are still accepted.
+func ValidateCursor(p FindParams, results interface{}) error {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return err
+	}
+	p = ensureMandatoryParams(p)
+	err = p.encoding().validate(results, p.PaginatedFields)
+	if err != nil {
+		return err
+	}
+	err = validateCursorTypes(results, p)
+	if err != nil {
+		return err
+	}
+	_, _, err = buildCursorQuery(p)
+	return err
+}
+
 func generateComparisonOps(p FindParams) []string {
-	comparisonOps := make([]string, 0, len(p.SortOrders))
-	for i := range p.SortOrders {
-		// Figure out the sort direction and comparison operator that will be used in the augmented query
-		sortAsc := (p.SortOrders[i] == -1 && p.Previous != "") || (p.SortOrders[i] == 1 && p.Previous == "")
-		if sortAsc {
-			comparisonOps = append(comparisonOps, "$gt")
-			p.SortOrders[i] = 1
-		} else {
-			comparisonOps = append(comparisonOps, "$lt")
-			p.SortOrders[i] = -1
+	return mcpbson.ComparisonOps(p.SortOrders, p.Previous != "")
+}
+
+// tiebreaker returns the unique field breaking the ties of the paginated fields of p
+func (p FindParams) tiebreaker() string {
+	// The position of a document in the natural order is unique
+	if p.natural() {
+		return NaturalField
+	}
+	if p.PaginatedFieldIsUnique {
+		if len(p.PaginatedFields) > 0 {
+			return p.PaginatedFields[len(p.PaginatedFields)-1]
 		}
+		if p.PaginatedField != "" {
+			return p.PaginatedField
+		}
+	}
+	if p.TiebreakerField != "" {
+		return p.TiebreakerField
 	}
-	return comparisonOps
+	return "_id"
 }
 
 func ensureMandatoryParams(p FindParams) FindParams {
-	if p.PaginatedField == "" {
-		p.PaginatedField = "_id"
-		p.Collation = nil
+	p = applyDefaults(p)
+	if p.Collection == nil && p.MongoCollection != nil {
+		p.Collection = &driverCollection{collection: p.MongoCollection}
 	}
-	if len(p.PaginatedFields) == 0 {
-		if p.PaginatedField == "_id" {
-			p.PaginatedFields = []string{"_id"}
-		} else {
-			p.PaginatedFields = []string{p.PaginatedField, "_id"}
+	tiebreaker := p.tiebreaker()
+	if p.PaginatedField == "" {
+		p.PaginatedField = tiebreaker
+		// The collation only matters when sorting on other fields than the _id
+		if len(p.PaginatedFields) == 0 && tiebreaker == "_id" {
+			p.Collation = nil
 		}
-	} else if p.PaginatedFields[len(p.PaginatedFields)-1] != "_id" {
-		p.PaginatedFields = append(p.PaginatedFields, "_id")
-		p.SortOrders = append(p.SortOrders, 1)
 	}
-	if len(p.SortOrders) == 0 {
-		p.SortOrders = []int{}
-		if p.SortAscending {
-			for i := 0; i < len(p.PaginatedFields); i++ {
-				p.SortOrders = append(p.SortOrders, 1)
-			}
-		} else {
-			for i := 0; i < len(p.PaginatedFields); i++ {
-				p.SortOrders = append(p.SortOrders, -1)
-			}
-		}
+	p.PaginatedFields, p.SortOrders = mcpbson.EnsurePaginatedFields(p.PaginatedField, p.PaginatedFields, p.SortOrders, p.SortAscending, tiebreaker)
+	// The scope is computed before the sort orders are reversed for a previous page
+	if p.BindCursorScope && p.scope == "" {
+		p.scope = computeCursorScope(p)
+	}
+	// The fingerprint is only reported to the Executor and the PredicateExperiment
+	if (p.Executor != nil || p.PredicateExperiment != nil) && p.fingerprint == "" {
+		p.fingerprint = computeQueryFingerprint(p, nil)
 	}
 	return p
 }
 
-var parseCursor = func(cursor string, numPaginatedFields int) ([]interface{}, error) {
+var parseCursor = func(cursor string, numPaginatedFields int, codec CursorCodec) ([]interface{}, error) {
 	cursorValues := make([]interface{}, 0, numPaginatedFields)
 	if cursor != "" {
-		parsedCursor, err := decodeCursor(cursor)
+		parsedCursor, err := codec.Decode(cursor)
 		if err != nil {
 			return nil, err
 		}
-		if len(parsedCursor) != numPaginatedFields {
-			if numPaginatedFields == 1 {
-				return nil, errors.New("expecting a cursor with a single element")
-			}
-			return nil, fmt.Errorf("expecting a cursor with %d elements", numPaginatedFields)
+		err = mcpbson.CheckCursorLength(len(parsedCursor), numPaginatedFields)
+		if err != nil {
+			return nil, err
 		}
 		for _, obj := range parsedCursor {
 			cursorValues = append(cursorValues, obj.Value)
@@ -328,39 +852,104 @@ var parseCursor = func(cursor string, numPaginatedFields int) ([]interface{}, er
 	return cursorValues, nil
 }
 
-// decodeCursor decodes cursor data that was previously encoded with createCursor
-func decodeCursor(cursor string) (bson.D, error) {
-	var cursorData bson.D
-	data, err := base64.RawURLEncoding.DecodeString(cursor)
+// BuildCountQuery builds the filter and options of the query counting the documents matching the FindParams'
+// query when CountTotal is set, without executing it. The cursors don't restrict the count. Running the query out
+// of band, e.g. to precompute totals, counts the documents Find would.
+func BuildCountQuery(p FindParams) (filter bson.M, opts *options.CountOptions, err error) {
+	p, err = applySortSpec(p, DefaultSortRegistry)
 	if err != nil {
-		return cursorData, err
+		return nil, nil, err
 	}
-
-	err = bson.Unmarshal(data, &cursorData)
-	return cursorData, err
+	p = ensureMandatoryParams(p)
+	filter, opts = buildCountQuery(p)
+	return filter, opts, nil
 }
 
-var executeCountQuery = func(ctx context.Context, c Collection, queries []bson.M, collation *options.Collation, timeout time.Duration) (int, error) {
+func buildCountQuery(p FindParams) (bson.M, *options.CountOptions) {
 	options := options.Count()
-	if collation != nil {
-		options.SetCollation(collation)
+	if p.Collation != nil {
+		options.SetCollation(p.Collation)
 	}
-	if timeout > time.Duration(0) {
-		options.SetMaxTime(timeout)
-	} else {
-		options.SetMaxTime(defaultCursorTimeout)
+	if p.Timeout > time.Duration(0) {
+		options.SetMaxTime(p.Timeout)
+	}
+	// One more document than the limit is counted to tell a truncated count apart
+	if p.CountLimit > 0 {
+		options.SetLimit(p.CountLimit + 1)
 	}
-	count, err := c.CountDocuments(ctx, bson.M{"$and": queries}, options)
+	return MergeQueries([]bson.M{p.Query}), options
+}
+
+// MergeQueries returns the filter matching the documents matched by all the queries, e.g. the ones returned by
+// BuildQueries, as Find does. Rather than wrapping them in an $and, which defeats some query planner
+// optimizations, the queries are merged into a single document when none of their keys conflict, and empty
+// queries are dropped.
+func MergeQueries(queries []bson.M) bson.M {
+	return mcpbson.MergeQueries(queries)
+}
+
+var executeCountQuery = func(ctx context.Context, c Collection, filter bson.M, opts *options.CountOptions) (int, error) {
+	ctx, cancel := serverSelectionContext(ctx)
+	defer cancel()
+	count, err := c.CountDocuments(ctx, filter, opts)
 	if err != nil {
 		return 0, err
 	}
 	return int(count), nil
 }
 
-func executeCursorQuery(ctx context.Context, c Collection, query []bson.M, sort bson.D, limit int64, collation *options.Collation, hint interface{}, projection interface{}, timeout time.Duration, results interface{}) error {
+func executeCursorQuery(ctx context.Context, c Collection, query []bson.M, sort bson.D, fetchLimit int64, reverse bool, collation *options.Collation, hint interface{}, projection interface{}, timeout time.Duration, rawOptions func(*options.FindOptions), results interface{}) error {
+	options := newFindOptions(sort, fetchLimit, collation, hint, projection, timeout)
+	if rawOptions != nil {
+		rawOptions(options)
+	}
+	findCtx, cancel := serverSelectionContext(ctx)
+	cursor, err := c.Find(findCtx, MergeQueries(query), options)
+	cancel()
+	if err != nil {
+		return err
+	}
+	if reverse {
+		defer cursor.Close(ctx)
+		return decodeReversed(ctx, cursor, fetchLimit, results)
+	}
+	err = cursor.All(ctx, results)
+
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// decodeReversed decodes at most fetchLimit documents of the cursor into the slice results points at, from the end
+// of the slice so that the documents fetched in reverse order end up in order without being swapped. The documents
+// decoded before an error are kept.
+func decodeReversed(ctx context.Context, cursor MongoCursor, fetchLimit int64, results interface{}) error {
+	resultsVal := reflect.ValueOf(results).Elem()
+	decoded := reflect.MakeSlice(resultsVal.Type(), int(fetchLimit), int(fetchLimit))
+	i := decoded.Len()
+	var err error
+	for i > 0 && cursor.Next(ctx) {
+		err = cursor.Decode(decoded.Index(i - 1).Addr().Interface())
+		if err != nil {
+			break
+		}
+		i--
+	}
+	if err == nil {
+		err = cursor.Err()
+	}
+	resultsVal.Set(decoded.Slice(i, decoded.Len()))
+	return err
+}
+
+// newFindOptions returns the options of a find query fetching fetchLimit documents, 0 meaning no limit
+func newFindOptions(sort bson.D, fetchLimit int64, collation *options.Collation, hint interface{}, projection interface{}, timeout time.Duration) *options.FindOptions {
 	options := options.Find()
 	options.SetSort(sort)
-	options.SetLimit(limit + 1)
+	if fetchLimit > 0 {
+		options.SetLimit(fetchLimit)
+	}
 
 	if collation != nil {
 		options.SetCollation(collation)
@@ -373,59 +962,53 @@ func executeCursorQuery(ctx context.Context, c Collection, query []bson.M, sort
 	}
 	if timeout > time.Duration(0) {
 		options.SetMaxTime(timeout)
-	} else {
-		options.SetMaxTime(defaultCursorTimeout)
 	}
-	cursor, err := c.Find(ctx, bson.M{"$and": query}, options)
-	if err != nil {
-		return err
-	}
-	err = cursor.All(ctx, results)
+	return options
+}
 
-	if err != nil {
-		return err
-	}
-	return nil
+// generateCursor returns the cursor of the paginated fields of result, encoded by the CursorCodec of p
+func generateCursor(result interface{}, p FindParams) (string, error) {
+	return p.encoding().cursor(result, p.PaginatedFields, p.cursorCodec())
 }
 
-func generateCursor(result interface{}, paginatedFields []string) (string, error) {
+// cursor returns the cursor of the paginated fields of result, encoded by the codec
+func (e bsonEncoding) cursor(result interface{}, paginatedFields []string, codec CursorCodec) (string, error) {
 	if result == nil {
 		return "", fmt.Errorf("the specified result must be a non nil value")
 	}
-	// Handle pointer values and reduce number of times reflection is done on the same type.
-	val := reflect.ValueOf(result)
-	if val.Kind() == reflect.Ptr {
-		_ = reflect.Indirect(val)
-	}
 
-	var recordAsBytes []byte
+	// Raw documents are read in place rather than marshaled into a copy
+	var record bson.Raw
 	var err error
-
 	switch v := result.(type) {
+	case bson.Raw:
+		record = v
 	case []byte:
-		recordAsBytes = v
+		record = v
+	case *bson.Raw:
+		record = *v
 	default:
-		recordAsBytes, err = bson.Marshal(result)
+		record, err = e.marshalDocument(result)
 		if err != nil {
 			return "", err
 		}
 	}
 
-	var recordAsMap map[string]interface{}
-	err = bson.Unmarshal(recordAsBytes, &recordAsMap)
-	if err != nil {
-		return "", err
-	}
-	// Set the cursor data
+	// Set the cursor data, keeping the raw values so that their bson type (e.g. decimal128, timestamp or binary
+	// subtype) and the key order of embedded documents are preserved
 	cursorData := make(bson.D, 0, len(paginatedFields))
 	for i := range paginatedFields {
-		paginatedFieldValue := recordAsMap[paginatedFields[i]]
-		if paginatedFieldValue != nil {
-			cursorData = append(cursorData, bson.E{Key: paginatedFields[i], Value: paginatedFieldValue})
+		t, value, found, err := mcpbson.LookupField(record, paginatedFields[i])
+		if err != nil {
+			return "", err
 		}
+		if !found || bsontype.Type(t) == bson.TypeNull {
+			continue
+		}
+		cursorData = append(cursorData, bson.E{Key: paginatedFields[i], Value: bson.RawValue{Type: bsontype.Type(t), Value: value}})
 	}
 	// Encode the cursor data into a url safe string
-	cursor, err := encodeCursor(cursorData)
+	cursor, err := codec.Encode(cursorData)
 	if err != nil {
 		return "", fmt.Errorf("failed to encode cursor using %v: %s", cursorData, err)
 	}
@@ -433,15 +1016,9 @@ func generateCursor(result interface{}, paginatedFields []string) (string, error
 	return cursor, nil
 }
 
-// encodeCursor encodes and returns cursor data that is url safe
-func encodeCursor(cursorData bson.D) (string, error) {
-	data, err := bson.Marshal(cursorData)
-	return base64.RawURLEncoding.EncodeToString(data), err
-}
-
-// validate verifies that the results array is of a supported type and that its underlying struct has a bson tag that
-// matches each paginated field
-func validate(results interface{}, paginatedFields []string) error {
+// validate verifies that the results array is of a supported type and that its underlying struct has a field whose
+// bson key matches each paginated field
+func (e bsonEncoding) validate(results interface{}, paginatedFields []string) error {
 	if results == nil {
 		return NewErrInvalidResults("expected results to be non nil")
 	}
@@ -468,6 +1045,11 @@ func validate(results interface{}, paginatedFields []string) error {
 		return nil
 	}
 
+	// Nor maps such as bson.M, whose cursor values are looked up by key
+	if elem.Kind() == reflect.Map && elem.Key().Kind() == reflect.String {
+		return nil
+	}
+
 	// If the slice contains pointers to structs, dereference to get the struct type
 	if elem.Kind() == reflect.Ptr {
 		elem = elem.Elem()
@@ -475,50 +1057,239 @@ func validate(results interface{}, paginatedFields []string) error {
 
 	// Ensure that elem is now a struct
 	if elem.Kind() != reflect.Struct {
-		return NewErrInvalidResults("expected results' element to be a struct or struct pointer")
+		return NewErrInvalidResults("expected results' element to be a struct, struct pointer or string keyed map")
 	}
 
 	for _, paginatedField := range paginatedFields {
-		paginatedFieldFound := false
-		for i := 0; i < elem.NumField(); i++ {
-			field := elem.Field(i)
-			tag := field.Tag.Get("bson")
+		field, found := e.findPaginatedField(elem, paginatedField)
+		if !found {
+			return NewErrPaginatedFieldNotFound(paginatedField)
+		}
+		if !roundTrips(field.Type) {
+			return NewErrInvalidResults(fmt.Sprintf("paginated field %s implements bson.ValueMarshaler but not bson.ValueUnmarshaler", paginatedField))
+		}
+	}
+	return nil
+}
 
-			tagParts := strings.Split(tag, ",")
-			fieldName := strings.TrimSpace(tagParts[0])
+var (
+	valueMarshalerType   = reflect.TypeOf((*bson.ValueMarshaler)(nil)).Elem()
+	valueUnmarshalerType = reflect.TypeOf((*bson.ValueUnmarshaler)(nil)).Elem()
+)
 
-			if fieldName == paginatedField {
-				paginatedFieldFound = true
-				break
+// roundTrips returns whether the values of type t decoded from bson are marshaled back to the same bson value, as
+// the cursors are generated from the decoded results. A type marshaled by its MarshalBSONValue method must be
+// unmarshaled by an UnmarshalBSONValue method, which must restore the marshaled value.
+func roundTrips(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	ptr := reflect.PointerTo(t)
+	if !t.Implements(valueMarshalerType) && !ptr.Implements(valueMarshalerType) {
+		return true
+	}
+	return ptr.Implements(valueUnmarshalerType)
+}
+
+// findPaginatedField returns the field of the struct type elem whose bson key matches the paginated field,
+// looking into the inlined structs and struct pointers at any depth. As with the driver, the key of an untagged
+// field is its lowercased name and a field shadows the ones of the same key inlined deeper, while the fields of
+// the same key inlined at the same depth are ambiguous and none of them is found.
+func (e bsonEncoding) findPaginatedField(elem reflect.Type, paginatedField string) (reflect.StructField, bool) {
+	level := []reflect.Type{elem}
+	var visited map[reflect.Type]bool
+	for len(level) > 0 {
+		var inlined []reflect.Type
+		var match reflect.StructField
+		matches := 0
+		for _, t := range level {
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				if !field.IsExported() {
+					continue
+				}
+				fieldName, inline, ok := e.fieldKey(field)
+				if !ok {
+					continue
+				}
+				if inline {
+					inlineType := field.Type
+					if inlineType.Kind() == reflect.Ptr {
+						inlineType = inlineType.Elem()
+					}
+					if inlineType.Kind() == reflect.Struct {
+						inlined = append(inlined, inlineType)
+					}
+					continue
+				}
+				if fieldName == paginatedField || fieldName == "" && strings.ToLower(field.Name) == paginatedField {
+					match = field
+					matches++
+				}
 			}
+		}
+		if matches > 0 {
+			return match, matches == 1
+		}
 
-			if len(tagParts) > 1 && strings.ToLower(strings.TrimSpace(tagParts[1])) == "inline" && validateInlineFields(field, paginatedField) {
-				paginatedFieldFound = true
-				break
+		// Inlined structs are only looked into once, as a struct pointer may inline its own type
+		level = level[:0]
+		for _, t := range inlined {
+			if visited == nil {
+				visited = map[reflect.Type]bool{elem: true}
+			}
+			if !visited[t] {
+				visited[t] = true
+				level = append(level, t)
 			}
 		}
-		if !paginatedFieldFound {
-			return NewErrPaginatedFieldNotFound(paginatedField)
+	}
+	return reflect.StructField{}, false
+}
+
+// marshalDocument marshals the document with the MarshalBSONValue methods of the types of its fields, including
+// the ones with pointer receivers, which only apply to addressable fields. A struct passed by value is copied to
+// be addressable so that the cursor values are the marshaled values of the fields whether the documents are
+// passed by value or by pointer.
+func (e bsonEncoding) marshalDocument(doc interface{}) (bson.Raw, error) {
+	val := reflect.ValueOf(doc)
+	switch {
+	case val.Kind() == reflect.Map || val.Kind() == reflect.Slice:
+		// The maps of documents such as bson.M or bson.D are marshaled in their random iteration order, which
+		// would vary the cursors of their values
+		doc, _ = canonicalValue(doc)
+	case val.Kind() == reflect.Struct && hasPointerMarshalers(val.Type()):
+		ptr := reflect.New(val.Type())
+		ptr.Elem().Set(val)
+		doc = ptr.Interface()
+	case val.Kind() == reflect.Ptr && !val.IsNil() && val.Elem().Kind() == reflect.Struct && !hasPointerMarshalers(val.Elem().Type()):
+		// Marshaling the struct a pointer points at spares the allocations of the pointer encoder
+		doc = val.Elem().Interface()
+	}
+	if e.registry == nil {
+		return bson.Marshal(doc)
+	}
+	buf := &bytes.Buffer{}
+	vw, err := bsonrw.NewBSONValueWriter(buf)
+	if err != nil {
+		return nil, err
+	}
+	encoder, err := bson.NewEncoder(vw)
+	if err != nil {
+		return nil, err
+	}
+	err = encoder.SetRegistry(e.registry)
+	if err != nil {
+		return nil, err
+	}
+	err = encoder.Encode(doc)
+	return buf.Bytes(), err
+}
+
+// pointerMarshalers caches whether struct types have fields only marshaled by their MarshalBSONValue method when
+// addressable
+var pointerMarshalers sync.Map
+
+// hasPointerMarshalers returns whether the struct type t has fields, or fields of its struct fields, whose
+// MarshalBSONValue method has a pointer receiver
+func hasPointerMarshalers(t reflect.Type) bool {
+	if cached, ok := pointerMarshalers.Load(t); ok {
+		return cached.(bool)
+	}
+	found := false
+	for i := 0; i < t.NumField() && !found; i++ {
+		fieldType := t.Field(i).Type
+		switch {
+		case !fieldType.Implements(valueMarshalerType) && reflect.PointerTo(fieldType).Implements(valueMarshalerType):
+			found = true
+		case fieldType.Kind() == reflect.Struct && !fieldType.Implements(valueMarshalerType):
+			found = hasPointerMarshalers(fieldType)
 		}
 	}
-	return nil
+	pointerMarshalers.Store(t, found)
+	return found
 }
 
+// fieldKey returns the bson key of a struct field, empty if it's the lowercased field name, whether the field is
+// inlined and whether it is encoded at all
+func (e bsonEncoding) fieldKey(field reflect.StructField) (key string, inline bool, ok bool) {
+	if e.resolve != nil {
+		key, inline = e.resolve(field)
+		return key, inline, key != "" || inline
+	}
+	key, inline = mcpbson.ParseBSONTag(field.Tag.Get("bson"))
+	return key, inline, key != "-"
+}
 
-func validateInlineFields(field reflect.StructField ,  paginatedField string) bool {
-	if field.Type.Kind() == reflect.Struct {
-		// Iterate over fields of the embedded struct
-		for j := 0; j < field.Type.NumField(); j++ {
-			inlineField := field.Type.Field(j)
-			inlineTag := inlineField.Tag.Get("bson")
-			inlineTagParts := strings.Split(inlineTag, ",")
-			inlineFieldName := strings.TrimSpace(inlineTagParts[0])
+// validateCursorTypes verifies that the values of the Next or Previous cursor of p have bson types comparable to
+// the ones of the paginated fields of the results' struct, so that a stale cursor or one of another endpoint is
+// rejected instead of matching no documents. The results must have been validated already.
+func validateCursorTypes(results interface{}, p FindParams) error {
+	cursor := p.Next
+	if cursor == "" {
+		cursor = p.Previous
+	}
+	if cursor == "" || !p.settings().StrictCursorTypes {
+		return nil
+	}
+	// A cursor that can't be decoded is reported when building the cursor query
+	cursorData, err := p.cursorCodec().Decode(cursor)
+	if err != nil {
+		return nil
+	}
 
-			// Check if the embedded struct contains the paginated field
-			if inlineFieldName == paginatedField {
-				return true
-			}
+	elem := reflect.TypeOf(results).Elem().Elem()
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return nil
+	}
+
+	// The cursor values are compared to the paginated fields in order, whatever their keys
+	encoding := p.encoding()
+	for i, e := range cursorData {
+		if i >= len(p.PaginatedFields) {
+			break
 		}
+		field, found := encoding.findPaginatedField(elem, p.PaginatedFields[i])
+		if !found {
+			continue
+		}
+		expected, ok := encoding.zeroBSONValue(field.Type)
+		if ok && !ComparableValues(expected, e.Value) {
+			return &CursorError{NewErrCursorTypeMismatch(p.PaginatedFields[i], bsonTypeName(expected), bsonTypeName(e.Value))}
+		}
+	}
+	return nil
+}
+
+// zeroBSONValue returns the zero value of the type t as decoded from bson, to know the bson type t is encoded to.
+// ok is false if it can't be known, e.g. for interfaces.
+func (e bsonEncoding) zeroBSONValue(t reflect.Type) (value interface{}, ok bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// The zero value is marshaled from a pointer so that the MarshalBSONValue methods with pointer receivers apply
+	doc, err := e.marshalDocument(bson.D{{Key: "v", Value: reflect.New(t).Interface()}})
+	if err != nil {
+		return nil, false
+	}
+	raw := doc.Lookup("v")
+	if raw.Type == bson.TypeNull || raw.Type == bson.TypeUndefined {
+		return nil, false
+	}
+	err = raw.Unmarshal(&value)
+	if err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+func bsonTypeName(v interface{}) string {
+	bsonType, _, err := bson.MarshalValue(v)
+	if err != nil {
+		return fmt.Sprintf("%T", v)
 	}
-	return false
+	return bsonType.String()
 }
@@ -0,0 +1,93 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindTiebreakerField(t *testing.T) {
+	type event struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		UUID string             `bson:"uuid"`
+		Kind string             `bson:"kind"`
+	}
+	// The _id order differs from the uuid order, which breaks the ties on the kind
+	events := []event{
+		{ID: primitive.NewObjectID(), UUID: "e", Kind: "click"},
+		{ID: primitive.NewObjectID(), UUID: "b", Kind: "click"},
+		{ID: primitive.NewObjectID(), UUID: "d", Kind: "click"},
+		{ID: primitive.NewObjectID(), UUID: "a", Kind: "view"},
+		{ID: primitive.NewObjectID(), UUID: "c", Kind: "click"},
+	}
+	docs := make([]interface{}, 0, len(events))
+	for _, e := range events {
+		docs = append(docs, e)
+	}
+	col, err := mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+
+	for _, dialect := range []mongo.Dialect{mongo.DialectMongo, mongo.DialectCosmos} {
+		p := mongo.FindParams{Collection: col, Limit: 2, PaginatedField: "kind", SortAscending: true, TiebreakerField: "uuid", Dialect: dialect}
+		var pages [][]string
+		var results []event
+		for {
+			cursor, err := mongo.Find(context.Background(), p, &results)
+			require.NoError(t, err)
+			var uuids []string
+			for _, e := range results {
+				uuids = append(uuids, e.UUID)
+			}
+			pages = append(pages, uuids)
+			if !cursor.HasNext {
+				break
+			}
+			p.Next = cursor.Next
+		}
+		require.Equal(t, [][]string{{"b", "c"}, {"d", "e"}, {"a"}}, pages)
+
+		p.Next = ""
+		p.Previous, err = mongo.GenerateCursor(events[3], p)
+		require.NoError(t, err)
+		_, err = mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, []event{events[2], events[0]}, results)
+	}
+}
+
+func TestFindDebugCursorsDecodesTheBoundaries(t *testing.T) {
+	var docs []interface{}
+	for i := 0; i < 5; i++ {
+		docs = append(docs, mcptest.Item{ID: primitive.NewObjectID(), Name: "item", Count: i})
+	}
+	col, err := mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+	p := mongo.FindParams{Collection: col, Limit: 2, PaginatedField: "count", SortAscending: true}
+
+	// The values are only decoded when asked for
+	var results []mcptest.Item
+	cursor, err := mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Nil(t, cursor.NextValues)
+
+	p.DebugCursors = true
+	p.Next = cursor.Next
+	cursor, err = mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, bson.D{{Key: "count", Value: int32(2)}, {Key: "_id", Value: results[0].ID}}, cursor.PreviousValues)
+	require.Equal(t, bson.D{{Key: "count", Value: int32(3)}, {Key: "_id", Value: results[1].ID}}, cursor.NextValues)
+
+	// The values of an empty cursor are nil
+	p.Next = cursor.Next
+	cursor, err = mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.False(t, cursor.HasNext)
+	require.NotNil(t, cursor.PreviousValues)
+	require.Nil(t, cursor.NextValues)
+}
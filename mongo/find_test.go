@@ -1,12 +1,18 @@
 package mongo
 
 import (
+	"context"
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type (
@@ -22,8 +28,60 @@ type (
 		Example string             `bson:"example,omitempty"`
 		Item    Item               `bson:",inline"`
 	}
+
+	// fakeCollection serves the canned docs, failing with err once they are iterated, and records the filter
+	// and options of the last find and aggregate queries
+	fakeCollection struct {
+		docs             []interface{}
+		err              error
+		filter           interface{}
+		findOptions      *options.FindOptions
+		pipeline         interface{}
+		aggregateOptions *options.AggregateOptions
+	}
+
+	fakeCursor struct {
+		*mongodriver.Cursor
+		err error
+	}
 )
 
+func (c *fakeCursor) Err() error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.Cursor.Err()
+}
+
+func (c *fakeCursor) All(ctx context.Context, results interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.Cursor.All(ctx, results)
+}
+
+func (c *fakeCollection) CountDocuments(context.Context, interface{}, ...*options.CountOptions) (int64, error) {
+	return int64(len(c.docs)), nil
+}
+
+func (c *fakeCollection) Aggregate(_ context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (MongoCursor, error) {
+	c.pipeline = pipeline
+	if len(opts) > 0 {
+		c.aggregateOptions = opts[0]
+	}
+	return c.Find(context.Background(), nil, options.Find())
+}
+
+func (c *fakeCollection) Find(_ context.Context, filter interface{}, opts ...*options.FindOptions) (MongoCursor, error) {
+	c.filter = filter
+	c.findOptions = opts[0]
+	cursor, err := mongodriver.NewCursorFromDocuments(c.docs, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &fakeCursor{Cursor: cursor, err: c.err}, nil
+}
+
 func TestValidate(t *testing.T) {
 	var cases = []struct {
 		name            string
@@ -65,7 +123,25 @@ func TestValidate(t *testing.T) {
 			name:            "errors when results' element type is not a struct",
 			results:         &[]*bool{},
 			paginatedFields: nil,
-			expectedErr:     NewErrInvalidResults("expected results' element to be a struct or struct pointer"),
+			expectedErr:     NewErrInvalidResults("expected results' element to be a struct, struct pointer or string keyed map"),
+		},
+		{
+			name:            "passes validation when results' element type is a bson.M",
+			results:         &[]bson.M{},
+			paginatedFields: []string{"_id", "name"},
+			expectedErr:     nil,
+		},
+		{
+			name:            "passes validation when results' element type is a map",
+			results:         &[]map[string]interface{}{},
+			paginatedFields: []string{"_id", "name"},
+			expectedErr:     nil,
+		},
+		{
+			name:            "errors when results' element type is a map without string keys",
+			results:         &[]map[int]interface{}{},
+			paginatedFields: nil,
+			expectedErr:     NewErrInvalidResults("expected results' element to be a struct, struct pointer or string keyed map"),
 		},
 		{
 			name:            "passes validation when results is of a supported type and all paginatedFields are found",
@@ -106,8 +182,708 @@ func TestValidate(t *testing.T) {
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			err := validate(tc.results, tc.paginatedFields)
+			err := bsonEncoding{}.validate(tc.results, tc.paginatedFields)
 			require.Equal(t, tc.expectedErr, err)
 		})
 	}
 }
+
+func TestFindPaginatedFieldInlinedDeeply(t *testing.T) {
+	type (
+		audit struct {
+			UpdatedAt time.Time `bson:"updatedAt"`
+			Version   int64     `bson:"version"`
+		}
+		meta struct {
+			Audit  *audit `bson:",omitempty,inline"`
+			Owner  string `bson:"owner"`
+			secret string
+		}
+		tags struct {
+			Owner string `bson:"owner"`
+		}
+		model struct {
+			ID      primitive.ObjectID `bson:"_id"`
+			Meta    meta               `bson:",inline"`
+			Version string             `bson:"version"`
+			Ignored int64              `bson:"-"`
+			Title   string
+		}
+		ambiguous struct {
+			Meta meta `bson:",inline"`
+			Tags tags `bson:",inline"`
+		}
+	)
+	elem := reflect.TypeOf(model{})
+
+	field, found := bsonEncoding{}.findPaginatedField(elem, "updatedAt")
+	require.True(t, found)
+	require.Equal(t, "UpdatedAt", field.Name)
+
+	// The field of the outer struct shadows the one inlined deeper
+	field, found = bsonEncoding{}.findPaginatedField(elem, "version")
+	require.True(t, found)
+	require.Equal(t, reflect.TypeOf(""), field.Type)
+
+	// Untagged fields are keyed by their lowercased name
+	field, found = bsonEncoding{}.findPaginatedField(elem, "title")
+	require.True(t, found)
+	require.Equal(t, "Title", field.Name)
+
+	// Fields of the same key inlined at the same depth are ambiguous, the driver rejects them when marshaling
+	_, found = bsonEncoding{}.findPaginatedField(reflect.TypeOf(ambiguous{}), "owner")
+	require.False(t, found)
+
+	for _, paginatedField := range []string{"ignored", "Ignored", "-", "secret"} {
+		_, found = bsonEncoding{}.findPaginatedField(elem, paginatedField)
+		require.False(t, found, paginatedField)
+	}
+
+	require.NoError(t, bsonEncoding{}.validate(&[]model{}, []string{"_id", "updatedAt"}))
+	item := model{ID: primitive.NewObjectID(), Meta: meta{Audit: &audit{UpdatedAt: time.UnixMilli(1700000000000)}}}
+	cursor, err := GenerateCursor(item, FindParams{PaginatedFields: []string{"updatedAt"}})
+	require.NoError(t, err)
+	cursorData, err := Base64CursorCodec{}.Decode(cursor)
+	require.NoError(t, err)
+	require.Equal(t, primitive.NewDateTimeFromTime(item.Meta.Audit.UpdatedAt), cursorData.Map()["updatedAt"])
+}
+
+func TestEnsureMandatoryParamsWrapsMongoCollection(t *testing.T) {
+	collection := &mongodriver.Collection{}
+	p := ensureMandatoryParams(FindParams{MongoCollection: collection})
+	require.Equal(t, &driverCollection{collection: collection}, p.Collection)
+
+	fc := &fakeCollection{}
+	p = ensureMandatoryParams(FindParams{Collection: fc, MongoCollection: collection})
+	require.Equal(t, fc, p.Collection)
+}
+
+func TestBuildQueriesIncludeAnchor(t *testing.T) {
+	id := primitive.NewObjectID()
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "_id", Value: id}})
+	require.NoError(t, err)
+
+	queries, _, err := BuildQueries(context.Background(), FindParams{Collection: &fakeCollection{}, Limit: 2, Next: next, IncludeAnchor: true})
+	require.NoError(t, err)
+	require.Equal(t, []bson.M{nil, {"_id": map[string]interface{}{"$lte": id}}}, queries)
+
+	queries, _, err = BuildQueries(context.Background(), FindParams{Collection: &fakeCollection{}, Limit: 2, Next: next})
+	require.NoError(t, err)
+	require.Equal(t, []bson.M{nil, {"_id": map[string]interface{}{"$lt": id}}}, queries)
+}
+
+func TestBuildQueriesCosmosDialect(t *testing.T) {
+	id := primitive.NewObjectID()
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "name", Value: "b"}, {Key: "_id", Value: id}})
+	require.NoError(t, err)
+
+	p := FindParams{Collection: &fakeCollection{}, Limit: 2, PaginatedField: "name", SortAscending: true, Next: next, Dialect: DialectCosmos}
+	queries, sort, err := BuildQueries(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, []bson.M{nil, {"$and": []map[string]interface{}{
+		{"name": map[string]interface{}{"$gte": "b"}},
+		{"$nor": []map[string]interface{}{{"name": "b", "_id": map[string]interface{}{"$lte": id}}}},
+	}}}, queries)
+	require.Equal(t, bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}, sort)
+}
+
+func TestBuildQueriesTiebreakerField(t *testing.T) {
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "name", Value: "b"}, {Key: "uuid", Value: "u2"}})
+	require.NoError(t, err)
+
+	p := FindParams{Collection: &fakeCollection{}, Limit: 2, PaginatedField: "name", TiebreakerField: "uuid", Next: next}
+	queries, sort, err := BuildQueries(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, []bson.M{nil, {"$or": []map[string]interface{}{
+		{"name": map[string]interface{}{"$lt": "b"}},
+		{"$and": []map[string]interface{}{
+			{"name": map[string]interface{}{"$lte": "b"}},
+			{"uuid": map[string]interface{}{"$lt": "u2"}},
+		}},
+	}}}, queries)
+	require.Equal(t, bson.D{{Key: "name", Value: -1}, {Key: "uuid", Value: -1}}, sort)
+
+	// The tiebreaker is appended to the paginated fields, or paginated on alone
+	p = ensureMandatoryParams(FindParams{PaginatedFields: []string{"name"}, SortOrders: []int{1}, TiebreakerField: "uuid"})
+	require.Equal(t, []string{"name", "uuid"}, p.PaginatedFields)
+	require.Equal(t, []int{1, 1}, p.SortOrders)
+	p = ensureMandatoryParams(FindParams{TiebreakerField: "uuid"})
+	require.Equal(t, []string{"uuid"}, p.PaginatedFields)
+
+	// The tiebreaker is sortable whatever the SortSpec of the collection
+	registry := NewSortRegistry()
+	registry.Register("items", SortSpec{SortableFields: []string{"name"}})
+	_, err = applySortSpec(FindParams{CollectionName: "items", PaginatedFields: []string{"name", "uuid"}, TiebreakerField: "uuid"}, registry)
+	require.NoError(t, err)
+}
+
+func TestBuildQueriesPaginatedFieldIsUnique(t *testing.T) {
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "email", Value: "b@example.com"}})
+	require.NoError(t, err)
+
+	p := FindParams{Collection: &fakeCollection{}, Limit: 2, PaginatedField: "email", SortAscending: true, PaginatedFieldIsUnique: true, Next: next}
+	queries, sort, err := BuildQueries(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, []bson.M{nil, {"email": map[string]interface{}{"$gt": "b@example.com"}}}, queries)
+	require.Equal(t, bson.D{{Key: "email", Value: 1}}, sort)
+
+	// The last of the paginated fields breaks the ties, even when a TiebreakerField is set
+	p = ensureMandatoryParams(FindParams{PaginatedFields: []string{"tenant", "email"}, SortOrders: []int{1, -1}, TiebreakerField: "uuid", PaginatedFieldIsUnique: true})
+	require.Equal(t, []string{"tenant", "email"}, p.PaginatedFields)
+	require.Equal(t, []int{1, -1}, p.SortOrders)
+	p = ensureMandatoryParams(FindParams{PaginatedFieldIsUnique: true})
+	require.Equal(t, []string{"_id"}, p.PaginatedFields)
+}
+
+func TestBuildQueriesShardKey(t *testing.T) {
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "createdAt", Value: int64(5)}, {Key: "_id", Value: "x"}})
+	require.NoError(t, err)
+
+	// The ranged shard key field is bounded by its cursor value
+	p := FindParams{Collection: &fakeCollection{}, Limit: 2, PaginatedField: "createdAt", SortAscending: true, Next: next,
+		ShardKey: bson.D{{Key: "createdAt", Value: 1}, {Key: "_id", Value: 1}}}
+	queries, _, err := BuildQueries(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, bson.M{
+		"createdAt": bson.M{"$gte": int64(5)},
+		"$or": []map[string]interface{}{
+			{"createdAt": map[string]interface{}{"$gt": int64(5)}},
+			{"$and": []map[string]interface{}{
+				{"createdAt": map[string]interface{}{"$gte": int64(5)}},
+				{"_id": map[string]interface{}{"$gt": "x"}},
+			}},
+		},
+	}, queries[1])
+
+	// A previous page is bounded the other way
+	p.Next = ""
+	p.Previous = next
+	queries, _, err = BuildQueries(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, bson.M{"$lte": int64(5)}, queries[1]["createdAt"])
+
+	// The first shard key field may be matched by the query instead, a hashed one is never bounded
+	p.Query = bson.M{"tenant": "t"}
+	p.ShardKey = bson.D{{Key: "tenant", Value: "hashed"}, {Key: "createdAt", Value: "hashed"}}
+	queries, _, err = BuildQueries(context.Background(), p)
+	require.NoError(t, err)
+	require.NotContains(t, queries[1], "createdAt")
+
+	// Pages that can't target the shards are rejected, even without cursor
+	p.Query = nil
+	p.Previous = ""
+	_, _, err = BuildQueries(context.Background(), p)
+	require.ErrorAs(t, err, new(*ErrUntargetableSort))
+	p.ShardKey = bson.D{{Key: "tenant", Value: 1}}
+	_, _, err = BuildQueries(context.Background(), p)
+	require.EqualError(t, err, "the pages can't target the shards: the shard key field tenant must be matched by the Query or be a paginated field")
+	p.ShardKey = bson.D{{Key: "createdAt", Value: -1}}
+	_, _, err = BuildQueries(context.Background(), p)
+	require.EqualError(t, err, "invalid order -1 of the shard key field createdAt, must be 1 or \"hashed\"")
+}
+
+func TestBuildQueriesEncryptedFields(t *testing.T) {
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "ssn", Value: "123"}, {Key: "date", Value: int64(5)}, {Key: "_id", Value: "x"}})
+	require.NoError(t, err)
+
+	// The deterministically encrypted field matched by an equality isn't compared nor sorted on
+	p := FindParams{Collection: &fakeCollection{}, Limit: 2, PaginatedFields: []string{"ssn", "date"}, SortOrders: []int{1, -1}, Next: next,
+		Query: bson.M{"ssn": "123"}, EncryptedFields: map[string]Encryption{"ssn": EncryptionDeterministic}}
+	queries, sort, err := BuildQueries(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, []bson.M{{"ssn": "123"}, {"$or": []map[string]interface{}{
+		{"date": map[string]interface{}{"$lt": int64(5)}},
+		{"$and": []map[string]interface{}{
+			{"date": map[string]interface{}{"$lte": int64(5)}},
+			{"_id": map[string]interface{}{"$lt": "x"}},
+		}},
+	}}}, queries)
+	require.Equal(t, bson.D{{Key: "date", Value: -1}, {Key: "_id", Value: 1}}, sort)
+
+	p.Query = bson.M{"ssn": bson.M{"$eq": "123"}}
+	_, _, err = BuildQueries(context.Background(), p)
+	require.NoError(t, err)
+
+	// A range encrypted field is compared as any other
+	p.Query = nil
+	p.EncryptedFields = map[string]Encryption{"ssn": EncryptionRange}
+	_, sort, err = BuildQueries(context.Background(), p)
+	require.NoError(t, err)
+	require.Len(t, sort, 3)
+
+	// The other encrypted fields can't be compared
+	p.EncryptedFields = map[string]Encryption{"ssn": EncryptionDeterministic}
+	p.Query = bson.M{"ssn": bson.M{"$in": []string{"123"}}}
+	_, _, err = BuildQueries(context.Background(), p)
+	require.ErrorAs(t, err, new(*ErrFieldNotRangeQueryable))
+	p.EncryptedFields = map[string]Encryption{"_id": EncryptionRandom}
+	p.Query = bson.M{"_id": "x"}
+	_, _, err = BuildQueries(context.Background(), p)
+	require.EqualError(t, err, "paginated field _id is randomly encrypted and can't be compared to the cursors unless the Query matches it with an equality")
+}
+
+func TestRawOptions(t *testing.T) {
+	item := Item{ID: primitive.NewObjectID(), Name: "a"}
+	col := &fakeCollection{docs: []interface{}{item}}
+	rawFindOptions := func(opts *options.FindOptions) {
+		opts.SetReturnKey(true)
+		opts.SetComment("page")
+	}
+
+	var results []Item
+	_, err := Find(context.Background(), FindParams{Collection: col, Limit: 2, RawFindOptions: rawFindOptions}, &results)
+	require.NoError(t, err)
+	require.True(t, *col.findOptions.ReturnKey)
+	require.Equal(t, "page", *col.findOptions.Comment)
+	require.Equal(t, int64(3), *col.findOptions.Limit)
+
+	budget := &ScanBudget{MaxTime: time.Second}
+	_, err = Find(context.Background(), FindParams{Collection: col, Limit: 2, ScanBudget: budget, RawFindOptions: rawFindOptions}, &results)
+	require.NoError(t, err)
+	require.True(t, *col.findOptions.ReturnKey)
+
+	_, err = StreamRaw(context.Background(), FindParams{Collection: col, Limit: 2, RawFindOptions: rawFindOptions}, func(bson.Raw) error { return nil })
+	require.NoError(t, err)
+	require.True(t, *col.findOptions.ReturnKey)
+
+	rawAggregateOptions := func(opts *options.AggregateOptions) { opts.SetAllowDiskUse(true) }
+	_, err = Aggregate(context.Background(), AggregateParams{Collection: col, Limit: 2, RawAggregateOptions: rawAggregateOptions}, &results)
+	require.NoError(t, err)
+	require.True(t, *col.aggregateOptions.AllowDiskUse)
+}
+
+func TestGenerateCursorPreservesBSONTypes(t *testing.T) {
+	amount, err := primitive.ParseDecimal128("1.0000000000000000000000000001")
+	require.NoError(t, err)
+	uuid := primitive.Binary{Subtype: 0x04, Data: []byte("0123456789abcdef")}
+	doc := bson.D{
+		{Key: "_id", Value: primitive.NewObjectID()},
+		{Key: "amount", Value: amount},
+		{Key: "ts", Value: primitive.Timestamp{T: 1700000000, I: 3}},
+		{Key: "uuid", Value: uuid},
+		{Key: "version", Value: bson.D{{Key: "major", Value: int32(2)}, {Key: "minor", Value: int32(1)}}},
+		{Key: "deleted", Value: nil},
+	}
+	p := FindParams{PaginatedFields: []string{"amount", "ts", "uuid", "version", "deleted", "_id"}}
+
+	cursor, err := generateCursor(doc, p)
+	require.NoError(t, err)
+	values, err := Base64CursorCodec{}.Decode(cursor)
+	require.NoError(t, err)
+	require.Equal(t, bson.D{
+		{Key: "amount", Value: amount},
+		{Key: "ts", Value: primitive.Timestamp{T: 1700000000, I: 3}},
+		{Key: "uuid", Value: uuid},
+		{Key: "version", Value: bson.D{{Key: "major", Value: int32(2)}, {Key: "minor", Value: int32(1)}}},
+		{Key: "_id", Value: doc[0].Value},
+	}, values)
+
+	// Raw documents are supported as well
+	raw, err := bson.Marshal(doc)
+	require.NoError(t, err)
+	rawCursor, err := generateCursor(raw, p)
+	require.NoError(t, err)
+	require.Equal(t, cursor, rawCursor)
+
+	_, err = generateCursor([]byte{1, 2, 3}, p)
+	require.Error(t, err)
+}
+
+func TestFindRejectsCursorTypeMismatch(t *testing.T) {
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "name", Value: "b"}, {Key: "_id", Value: "5f1a2b3c4d5e6f7a8b9c0d1e"}})
+	require.NoError(t, err)
+	col := &fakeCollection{}
+	p := FindParams{Collection: col, Limit: 2, PaginatedField: "name", Next: next}
+
+	var results []Item
+	_, err = Find(context.Background(), p, &results)
+	require.EqualError(t, err, "cursor value of paginated field _id is of type string where objectID is expected")
+	require.IsType(t, &CursorError{}, err)
+	var mismatch *ErrCursorTypeMismatch
+	require.ErrorAs(t, err, &mismatch)
+	require.Nil(t, col.filter)
+
+	// A cursor whose values have the expected types is accepted
+	previous, err := Base64CursorCodec{}.Encode(bson.D{{Key: "createdAt", Value: primitive.NewDateTimeFromTime(time.Now())}, {Key: "_id", Value: primitive.NewObjectID()}})
+	require.NoError(t, err)
+	p = FindParams{Collection: col, Limit: 2, PaginatedField: "createdAt", Previous: previous}
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+}
+
+func TestBuildCountQuery(t *testing.T) {
+	collation := &options.Collation{Locale: "en", Strength: 2}
+	query := bson.M{"name": bson.M{"$exists": true}}
+	next, err := Base64CursorCodec{}.Encode(bson.D{{Key: "name", Value: "a"}, {Key: "_id", Value: primitive.NewObjectID()}})
+	require.NoError(t, err)
+
+	filter, opts, err := BuildCountQuery(FindParams{Query: query, Limit: 2, PaginatedField: "name", Collation: collation, Timeout: time.Second, Next: next})
+	require.NoError(t, err)
+	require.Equal(t, query, filter)
+	require.Equal(t, collation, opts.Collation)
+	require.Equal(t, time.Second, *opts.MaxTime)
+
+	// The collation doesn't apply when paginating on the _id
+	_, opts, err = BuildCountQuery(FindParams{Query: query, Limit: 2, Collation: collation})
+	require.NoError(t, err)
+	require.Nil(t, opts.Collation)
+	require.Equal(t, CurrentDefaults().Timeout, *opts.MaxTime)
+
+	RegisterSort("count_test_items", SortSpec{SortableFields: []string{"name"}})
+	_, _, err = BuildCountQuery(FindParams{CollectionName: "count_test_items", PaginatedField: "data"})
+	require.Equal(t, NewErrUnsortableField("data"), err)
+}
+
+func TestValidateCursor(t *testing.T) {
+	item := Item{ID: primitive.NewObjectID(), Name: "a"}
+	sortOrders := []int{1, 1}
+	p := FindParams{Limit: 2, PaginatedFields: []string{"name", "_id"}, SortOrders: sortOrders}
+	previous, err := GenerateCursor(item, p)
+	require.NoError(t, err)
+
+	p.Previous = previous
+	require.NoError(t, ValidateCursor(p, &[]Item{}))
+	require.Equal(t, []int{1, 1}, sortOrders)
+
+	p.PaginatedFields = []string{"createdAt", "_id"}
+	var mismatch *ErrCursorTypeMismatch
+	require.ErrorAs(t, ValidateCursor(p, &[]Item{}), &mismatch)
+
+	p.PaginatedFields = []string{"_id"}
+	require.IsType(t, &CursorError{}, ValidateCursor(p, &[]Item{}))
+}
+
+func TestGenerateCursors(t *testing.T) {
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+	}
+	p := FindParams{PaginatedField: "name"}
+	var expected []string
+	var raws []bson.Raw
+	for _, item := range items {
+		cursor, err := GenerateCursor(item, p)
+		require.NoError(t, err)
+		expected = append(expected, cursor)
+		raw, err := bson.Marshal(item)
+		require.NoError(t, err)
+		raws = append(raws, raw)
+	}
+
+	for _, results := range []interface{}{items, &items, []*Item{&items[0], &items[1]}, raws} {
+		cursors, err := GenerateCursors(results, p)
+		require.NoError(t, err)
+		require.Equal(t, expected, cursors)
+	}
+
+	cursors, err := GenerateCursors([]Item{}, p)
+	require.NoError(t, err)
+	require.Empty(t, cursors)
+	_, err = GenerateCursors(items[0], p)
+	require.Equal(t, NewErrInvalidResults("expected results to be a slice or a pointer to a slice"), err)
+}
+
+func TestEndCursor(t *testing.T) {
+	for _, tc := range []struct {
+		p              FindParams
+		expectedValues []interface{}
+	}{
+		{FindParams{PaginatedField: "name", SortAscending: true}, []interface{}{primitive.MaxKey{}, primitive.MaxKey{}}},
+		{FindParams{PaginatedFields: []string{"name", "_id"}, SortOrders: []int{-1, 1}}, []interface{}{primitive.MinKey{}, primitive.MaxKey{}}},
+	} {
+		end, err := EndCursor(tc.p)
+		require.NoError(t, err)
+		p := ensureMandatoryParams(tc.p)
+		values, err := parseCursor(end, len(p.PaginatedFields), p.cursorCodec())
+		require.NoError(t, err)
+		require.Equal(t, tc.expectedValues, values)
+
+		// The end cursor is accepted as the previous cursor of documents of any type
+		var results []Item
+		p.Previous = end
+		require.NoError(t, ValidateCursor(p, &results))
+	}
+
+	_, err := EndCursor(FindParams{PaginatedField: NaturalField})
+	require.EqualError(t, err, "the natural order has no end cursor")
+}
+
+func TestMakeCursors(t *testing.T) {
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	p := FindParams{Limit: 2, PaginatedField: "name", SortAscending: true}
+	aCursor, err := GenerateCursor(items[0], p)
+	require.NoError(t, err)
+	bCursor, err := GenerateCursor(items[1], p)
+	require.NoError(t, err)
+
+	// The documents fetched beyond the limit are removed
+	results := []Item{items[0], items[1], items[2]}
+	cursor, err := MakeCursors(&results, p, true)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[0], items[1]}, results)
+	require.Equal(t, Cursor{Next: bCursor, HasNext: true}, cursor)
+
+	// Previous pages are fetched in reverse order
+	p.Previous, err = GenerateCursor(items[2], p)
+	require.NoError(t, err)
+	results = []Item{items[1], items[0]}
+	cursor, err = MakeCursors(&results, p, false)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[0], items[1]}, results)
+	require.Equal(t, Cursor{Next: bCursor, HasNext: true}, cursor)
+
+	p.Previous = ""
+	p.Next = aCursor
+	results = []Item{items[1]}
+	cursor, err = MakeCursors(&results, p, false)
+	require.NoError(t, err)
+	require.Equal(t, Cursor{Previous: bCursor, HasPrevious: true}, cursor)
+
+	_, err = MakeCursors(results, p, false)
+	require.Equal(t, NewErrInvalidResults("expected results to be a slice pointer"), err)
+	_, err = MakeCursors(&results, FindParams{}, false)
+	require.EqualError(t, err, "a limit of at least 1 is required")
+}
+
+func TestMergeQueries(t *testing.T) {
+	id := primitive.NewObjectID()
+	cursorQuery := bson.M{"_id": bson.M{"$lt": id}}
+
+	require.Equal(t, bson.M{}, MergeQueries([]bson.M{nil, {}}))
+	require.Equal(t, cursorQuery, MergeQueries([]bson.M{nil, cursorQuery}))
+	require.Equal(t, bson.M{"name": "a", "_id": bson.M{"$lt": id}}, MergeQueries([]bson.M{{"name": "a"}, cursorQuery}))
+
+	// Conflicting keys can't be merged
+	query := bson.M{"_id": bson.M{"$in": bson.A{id}}}
+	require.Equal(t, bson.M{"$and": []bson.M{query, cursorQuery}}, MergeQueries([]bson.M{query, {}, cursorQuery}))
+	orQuery := bson.M{"$or": bson.A{bson.M{"name": "a"}, bson.M{"name": "b"}}}
+	require.Equal(t, bson.M{"$and": []bson.M{orQuery, orQuery}}, MergeQueries([]bson.M{orQuery, orQuery}))
+}
+
+func TestFindPreviousPageInOrder(t *testing.T) {
+	items := []*Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	p := FindParams{Limit: 2, PaginatedField: "name", SortAscending: true}
+	previous, err := GenerateCursor(items[2], p)
+	require.NoError(t, err)
+
+	// The previous page is fetched in reverse order, along with the additional document
+	p.Collection = &fakeCollection{docs: []interface{}{items[1], items[0], items[2]}}
+	p.Previous = previous
+	var results []*Item
+	cursor, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []*Item{items[0], items[1]}, results)
+	require.True(t, cursor.HasPrevious)
+	require.True(t, cursor.HasNext)
+	expectedPrevious, err := GenerateCursor(items[0], p)
+	require.NoError(t, err)
+	require.Equal(t, expectedPrevious, cursor.Previous)
+}
+
+func BenchmarkFind(b *testing.B) {
+	for _, pageSize := range []int{10, 100, 1000} {
+		docs := make([]interface{}, 0, pageSize+1)
+		for i := 0; i <= pageSize; i++ {
+			docs = append(docs, Item{ID: primitive.NewObjectID(), Name: fmt.Sprintf("item %04d", i), CreatedAt: time.Now()})
+		}
+		col := &fakeCollection{docs: docs}
+		cursor, err := GenerateCursor(docs[0], FindParams{PaginatedField: "name"})
+		require.NoError(b, err)
+
+		for _, direction := range []struct {
+			name string
+			p    FindParams
+		}{
+			{name: "Next", p: FindParams{Collection: col, Limit: int64(pageSize), PaginatedField: "name", Next: cursor}},
+			{name: "Previous", p: FindParams{Collection: col, Limit: int64(pageSize), PaginatedField: "name", Previous: cursor}},
+		} {
+			for _, results := range []struct {
+				name    string
+				results func() interface{}
+			}{
+				{name: "Struct", results: func() interface{} { return &[]Item{} }},
+				{name: "Pointer", results: func() interface{} { return &[]*Item{} }},
+				{name: "Raw", results: func() interface{} { return &[]bson.Raw{} }},
+			} {
+				b.Run(fmt.Sprintf("%s/%s/%d", direction.name, results.name, pageSize), func(b *testing.B) {
+					b.ReportAllocs()
+					for i := 0; i < b.N; i++ {
+						_, err := Find(context.Background(), direction.p, results.results())
+						require.NoError(b, err)
+					}
+				})
+			}
+		}
+	}
+}
+
+func BenchmarkGenerateCursor(b *testing.B) {
+	item := Item{ID: primitive.NewObjectID(), Name: "item", CreatedAt: time.Now()}
+	raw, err := bson.Marshal(item)
+	require.NoError(b, err)
+	p := FindParams{PaginatedFields: []string{"createdAt", "_id"}, SortOrders: []int{1, 1}}
+	for _, result := range []struct {
+		name   string
+		result interface{}
+	}{
+		{name: "Struct", result: item},
+		{name: "Pointer", result: &item},
+		{name: "Raw", result: bson.Raw(raw)},
+	} {
+		b.Run(result.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, err := GenerateCursor(result.result, p)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		err := bsonEncoding{}.validate(&[]Item{}, []string{"createdAt", "_id"})
+		require.NoError(b, err)
+	}
+}
+
+func TestHotPathAllocationBudget(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates on its own")
+	}
+	item := Item{ID: primitive.NewObjectID(), Name: "item", CreatedAt: time.Now()}
+	raw, err := bson.Marshal(item)
+	require.NoError(t, err)
+	paginatedFields := []string{"createdAt", "_id"}
+	p := applyDefaults(FindParams{PaginatedFields: paginatedFields})
+	var cases = []struct {
+		name   string
+		budget float64
+		run    func()
+	}{
+		{name: "validate", budget: 0, run: func() { _ = bsonEncoding{}.validate(&[]Item{}, paginatedFields) }},
+		{name: "generateCursor of a raw document", budget: 8, run: func() { _, _ = generateCursor(bson.Raw(raw), p) }},
+		{name: "generateCursor of a struct", budget: 9, run: func() { _, _ = generateCursor(item, p) }},
+		{name: "generateCursor of a struct pointer", budget: 10, run: func() { _, _ = generateCursor(&item, p) }},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.LessOrEqual(t, testing.AllocsPerRun(100, tc.run), tc.budget)
+		})
+	}
+}
+
+func TestFindMapResults(t *testing.T) {
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	p := FindParams{Collection: &fakeCollection{docs: []interface{}{items[0], items[1], items[2]}}, Limit: 2, PaginatedField: "name", SortAscending: true}
+
+	var results []bson.M
+	cursor, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, items[1].ID, results[1]["_id"])
+	expectedNext, err := GenerateCursor(items[1], p)
+	require.NoError(t, err)
+	require.Equal(t, expectedNext, cursor.Next)
+
+	// The cursor values are looked up by key
+	next, err := GenerateCursor(results[1], p)
+	require.NoError(t, err)
+	require.Equal(t, expectedNext, next)
+
+	p.Collection = &fakeCollection{docs: []interface{}{items[2]}}
+	p.Next = cursor.Next
+	var maps []map[string]interface{}
+	cursor, err = Find(context.Background(), p, &maps)
+	require.NoError(t, err)
+	require.Len(t, maps, 1)
+	require.Equal(t, "c", maps[0]["name"])
+	require.False(t, cursor.HasNext)
+	require.True(t, cursor.HasPrevious)
+}
+
+// version is marshaled as a "major.minor" string by methods with pointer receivers
+type version struct {
+	Major, Minor int
+}
+
+func (v *version) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(fmt.Sprintf("%d.%d", v.Major, v.Minor))
+}
+
+func (v *version) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	var s string
+	err := bson.RawValue{Type: t, Value: data}.Unmarshal(&s)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Sscanf(s, "%d.%d", &v.Major, &v.Minor)
+	return err
+}
+
+// label is marshaled by a MarshalBSONValue method but can't be unmarshaled back
+type label string
+
+func (l label) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue("label:" + string(l))
+}
+
+func TestCursorValuesOfValueMarshalers(t *testing.T) {
+	type release struct {
+		ID      primitive.ObjectID `bson:"_id"`
+		Version version            `bson:"version"`
+	}
+	releases := []release{
+		{ID: primitive.NewObjectID(), Version: version{Major: 1, Minor: 2}},
+		{ID: primitive.NewObjectID(), Version: version{Major: 1, Minor: 10}},
+	}
+	p := FindParams{Limit: 1, PaginatedField: "version", SortAscending: true}
+
+	// The cursor holds the marshaled value whether the document is passed by value or by pointer
+	cursor, err := GenerateCursor(releases[0], p)
+	require.NoError(t, err)
+	pointerCursor, err := GenerateCursor(&releases[0], p)
+	require.NoError(t, err)
+	require.Equal(t, cursor, pointerCursor)
+	cursorData, err := Base64CursorCodec{}.Decode(cursor)
+	require.NoError(t, err)
+	require.Equal(t, bson.D{{Key: "version", Value: "1.2"}, {Key: "_id", Value: releases[0].ID}}, cursorData)
+
+	// The cursor values have the type of the marshaled values
+	p.Collection = &fakeCollection{docs: []interface{}{&releases[1]}}
+	p.Next = cursor
+	var results []release
+	next, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, releases[1:], results)
+	require.Equal(t, bson.M{"$or": []map[string]interface{}{
+		{"version": map[string]interface{}{"$gt": "1.2"}},
+		{"$and": []map[string]interface{}{
+			{"version": map[string]interface{}{"$gte": "1.2"}},
+			{"_id": map[string]interface{}{"$gt": releases[0].ID}},
+		}},
+	}}, p.Collection.(*fakeCollection).filter)
+	expectedPrevious, err := GenerateCursor(releases[1], p)
+	require.NoError(t, err)
+	require.Equal(t, expectedPrevious, next.Previous)
+
+	type labeled struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Label label              `bson:"label"`
+	}
+	err = bsonEncoding{}.validate(&[]labeled{}, []string{"label", "_id"})
+	require.Equal(t, NewErrInvalidResults("paginated field label implements bson.ValueMarshaler but not bson.ValueUnmarshaler"), err)
+}
@@ -0,0 +1,99 @@
+package mongo
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// QueryFingerprint returns a stable fingerprint of the logical query of p, hashing its collection, paginated fields,
+// sort orders and the shape of its Query, i.e. its keys and operators and the bson types of its values, rather than
+// the values themselves. Pages of the same query with other filter values or cursors share the fingerprint, so it
+// has a low cardinality and groups the performance of the queries, e.g. as a span attribute or a metric label. The
+// fingerprint is 16 hex characters, safe in OpenTelemetry baggage and in metric labels.
+func QueryFingerprint(p FindParams) string {
+	return computeQueryFingerprint(ensureMandatoryParams(p), nil)
+}
+
+// computeQueryFingerprint hashes the collection, paginated fields, sort orders and query shape of the ensured p,
+// followed by the shapes of the stages of the pipeline of an aggregation, in order
+func computeQueryFingerprint(p FindParams, pipeline []bson.M) string {
+	h := sha256.New()
+	collectionName := p.CollectionName
+	if collectionName == "" && p.MongoCollection != nil {
+		collectionName = p.MongoCollection.Name()
+	}
+	writeString(h, collectionName)
+	for i := range p.PaginatedFields {
+		writeString(h, p.PaginatedFields[i])
+		_ = binary.Write(h, binary.BigEndian, int8(p.SortOrders[i]))
+	}
+	// The query is wrapped as marshaling a nil query fails
+	query, err := bson.Marshal(bson.M{"q": p.Query})
+	if err == nil {
+		writeShape(h, bson.Raw(query).Lookup("q"))
+	}
+	for _, stage := range pipeline {
+		raw, err := bson.Marshal(stage)
+		if err == nil {
+			writeShape(h, bson.RawValue{Type: bson.TypeEmbeddedDocument, Value: raw})
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// shapeType returns the type of a value in the shape of a query, the numeric types being the same as a filter
+// matches them alike
+func shapeType(t bsontype.Type) bsontype.Type {
+	switch t {
+	case bson.TypeInt32, bson.TypeInt64, bson.TypeDecimal128:
+		return bson.TypeDouble
+	default:
+		return t
+	}
+}
+
+// writeShape writes the shape of the value to the hash: the sorted keys of its documents and the bson types of its
+// other values. The elements of an array are written once per distinct shape, so that an $in of any length or an
+// $or of repeated clauses has the same shape.
+func writeShape(h hash.Hash, v bson.RawValue) {
+	h.Write([]byte{byte(shapeType(v.Type))})
+	switch v.Type {
+	case bson.TypeEmbeddedDocument:
+		elements, err := v.Document().Elements()
+		if err != nil {
+			return
+		}
+		sort.Slice(elements, func(i, j int) bool { return elements[i].Key() < elements[j].Key() })
+		_ = binary.Write(h, binary.BigEndian, int32(len(elements)))
+		for _, e := range elements {
+			writeString(h, e.Key())
+			writeShape(h, e.Value())
+		}
+	case bson.TypeArray:
+		values, err := v.Array().Values()
+		if err != nil {
+			return
+		}
+		shapes := map[string]bool{}
+		for _, value := range values {
+			elementHash := sha256.New()
+			writeShape(elementHash, value)
+			shapes[string(elementHash.Sum(nil))] = true
+		}
+		distinct := make([]string, 0, len(shapes))
+		for shape := range shapes {
+			distinct = append(distinct, shape)
+		}
+		sort.Strings(distinct)
+		_ = binary.Write(h, binary.BigEndian, int32(len(distinct)))
+		for _, shape := range distinct {
+			h.Write([]byte(shape))
+		}
+	}
+}
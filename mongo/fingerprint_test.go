@@ -0,0 +1,37 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestQueryFingerprint(t *testing.T) {
+	fingerprint := func(query bson.M, paginatedField string) string {
+		return QueryFingerprint(FindParams{CollectionName: "items", Query: query, PaginatedField: paginatedField})
+	}
+	base := fingerprint(bson.M{"owner": "a", "tags": bson.M{"$in": bson.A{"x", "y"}}, "count": bson.M{"$gt": 1}}, "name")
+	require.Len(t, base, 16)
+	require.Regexp(t, "^[0-9a-f]+$", base)
+
+	// The values of the filter, the length of the arrays and the numeric types don't change the fingerprint
+	require.Equal(t, base, fingerprint(bson.M{"owner": "b", "tags": bson.M{"$in": bson.A{"z"}}, "count": bson.M{"$gt": 2.5}}, "name"))
+	require.Equal(t, base, fingerprint(bson.M{"count": bson.M{"$gt": int64(3)}, "tags": bson.M{"$in": bson.A{"y", "x", "x"}}, "owner": "c"}, "name"))
+
+	// The keys, operators, value types, sort and collection do
+	require.NotEqual(t, base, fingerprint(bson.M{"owner": "a", "tags": bson.M{"$nin": bson.A{"x"}}, "count": bson.M{"$gt": 1}}, "name"))
+	require.NotEqual(t, base, fingerprint(bson.M{"owner": "a", "tags": bson.M{"$in": bson.A{1}}, "count": bson.M{"$gt": 1}}, "name"))
+	require.NotEqual(t, base, fingerprint(bson.M{"owner": "a", "count": bson.M{"$gt": 1}}, "name"))
+	require.NotEqual(t, base, fingerprint(bson.M{"owner": "a", "tags": bson.M{"$in": bson.A{"x"}}, "count": bson.M{"$gt": 1}}, "createdAt"))
+	require.NotEqual(t, base, QueryFingerprint(FindParams{CollectionName: "items", PaginatedField: "name", SortAscending: true,
+		Query: bson.M{"owner": "a", "tags": bson.M{"$in": bson.A{"x"}}, "count": bson.M{"$gt": 1}}}))
+	require.NotEqual(t, base, QueryFingerprint(FindParams{CollectionName: "orders", PaginatedField: "name",
+		Query: bson.M{"owner": "a", "tags": bson.M{"$in": bson.A{"x"}}, "count": bson.M{"$gt": 1}}}))
+
+	// The stages of a pipeline are fingerprinted in order
+	p := ensureMandatoryParams(FindParams{CollectionName: "items"})
+	match, unwind := bson.M{"$match": bson.M{"owner": "a"}}, bson.M{"$unwind": "$tags"}
+	require.Equal(t, computeQueryFingerprint(p, []bson.M{match, unwind}), computeQueryFingerprint(p, []bson.M{{"$match": bson.M{"owner": "b"}}, unwind}))
+	require.NotEqual(t, computeQueryFingerprint(p, []bson.M{match, unwind}), computeQueryFingerprint(p, []bson.M{unwind, match}))
+}
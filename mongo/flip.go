@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+)
+
+// FlipSort returns p sorted in the inverted order, every paginated field including the tiebreaker being sorted in
+// the opposite direction, e.g. when a user toggles between the newest and the oldest documents first
+func (p FindParams) FlipSort() FindParams {
+	ensured := ensureMandatoryParams(p)
+	p.PaginatedFields = append([]string{}, ensured.PaginatedFields...)
+	p.SortOrders = make([]int, len(ensured.SortOrders))
+	for i, order := range ensured.SortOrders {
+		p.SortOrders[i] = -order
+	}
+	p.SortAscending = !p.SortAscending
+	p.scope = ""
+	return p
+}
+
+// FlipDirection rewrites a single page token generated for the find params p so that it queries the params
+// returned by p.FlipSort() while keeping the user's place: the token of the next page becomes the token of the
+// previous page in the inverted order, returning the same documents in the inverted order, and vice versa. The
+// scope of a cursor bound to its query is recomputed for the inverted sort, while its issue time is kept so that
+// flipping doesn't extend the life of an expiring cursor.
+func FlipDirection(p FindParams, token string) (string, error) {
+	if token == "" {
+		return "", nil
+	}
+	pt, err := decodePageToken(token)
+	if err != nil {
+		return "", err
+	}
+	switch pt.Direction {
+	case tokenDirectionNext:
+		pt.Direction = tokenDirectionPrevious
+	case tokenDirectionPrevious:
+		pt.Direction = tokenDirectionNext
+	default:
+		return "", &CursorError{errors.New("page token parse failed: unknown direction")}
+	}
+
+	// The cursor is validated against p before being rewritten, a stale cursor isn't made valid by flipping it
+	_, err = p.baseCursorCodec().Decode(pt.Cursor)
+	if err != nil {
+		return "", &CursorError{fmt.Errorf("page token parse failed: %w", err)}
+	}
+	if p.BindCursorScope {
+		codec := p.settings().CursorCodec
+		cursorData, err := codec.Decode(pt.Cursor)
+		if err != nil {
+			return "", &CursorError{fmt.Errorf("page token parse failed: %w", err)}
+		}
+		flippedScope := p.FlipSort().cursorScope()
+		for i := range cursorData {
+			if cursorData[i].Key == cursorScopeKey {
+				cursorData[i].Value = flippedScope
+			}
+		}
+		pt.Cursor, err = codec.Encode(cursorData)
+		if err != nil {
+			return "", err
+		}
+	}
+	return encodePageToken(pt)
+}
@@ -0,0 +1,45 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFlipDirectionKeepsPlace(t *testing.T) {
+	var docs []interface{}
+	for i := 0; i < 10; i++ {
+		docs = append(docs, mcptest.Item{ID: primitive.NewObjectID(), Name: "item", Count: i})
+	}
+	col, err := mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+	p := mongo.FindParams{Collection: col, Limit: 3, PaginatedField: "count", SortAscending: true, BindCursorScope: true}
+
+	var results []mcptest.Item
+	cursor, err := mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	token, err := cursor.NextToken(0)
+	require.NoError(t, err)
+
+	flipped, err := mongo.FlipDirection(p, token)
+	require.NoError(t, err)
+	// The token isn't valid for the inverted sort until flipped
+	unflipped, err := mongo.ApplyToken(p.FlipSort(), token)
+	require.NoError(t, err)
+	_, err = mongo.Find(context.Background(), unflipped, &results)
+	require.Error(t, err)
+
+	fp, err := mongo.ApplyToken(p.FlipSort(), flipped)
+	require.NoError(t, err)
+	require.Empty(t, fp.Next)
+	_, err = mongo.Find(context.Background(), fp, &results)
+	require.NoError(t, err)
+	require.Equal(t, []int{5, 4, 3}, []int{results[0].Count, results[1].Count, results[2].Count})
+
+	_, err = mongo.FlipDirection(mongo.FindParams{Collection: col, Limit: 3, PaginatedField: "name", BindCursorScope: true}, token)
+	require.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// InsertedPageToken returns the single page token positioned immediately before a document that was just
+// inserted, so that a client navigates to the page of the provided FindParams starting with its new document.
+// The _id of the document is taken from the result of its insert when the document doesn't hold it, e.g. when
+// the driver generated it. The page must be read in a causally consistent session of the insert to be sure to
+// hold the document, see NewCausalContext.
+func InsertedPageToken(p FindParams, inserted *mongodriver.InsertOneResult, doc interface{}) (string, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return "", err
+	}
+	p = ensureMandatoryParams(p)
+
+	raw, err := p.encoding().marshalDocument(doc)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal the inserted document: %s", err)
+	}
+	var fields bson.D
+	err = bson.Unmarshal(raw, &fields)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal the inserted document: %s", err)
+	}
+	if _, err := raw.LookupErr("_id"); err != nil {
+		if inserted == nil || inserted.InsertedID == nil {
+			return "", errors.New("the inserted document has no _id")
+		}
+		fields = append(fields, bson.E{Key: "_id", Value: inserted.InsertedID})
+	}
+
+	cursor, err := generateCursor(fields, p)
+	if err != nil {
+		return "", fmt.Errorf("could not create a cursor: %s", err)
+	}
+	return encodePageToken(pageToken{Direction: tokenDirectionNext, Cursor: cursor, Anchor: true})
+}
+
+// NewCausalContext starts a causally consistent session of the client and returns the context binding the
+// operations to it, along with the function ending the session. Inserting a document and finding its page with
+// the context, e.g. with the token of InsertedPageToken, reads the insert even from a secondary, provided the
+// insert is acknowledged by the majority and the find reads with the majority read concern.
+func NewCausalContext(ctx context.Context, client *mongodriver.Client) (context.Context, func(), error) {
+	session, err := client.StartSession(options.Session().SetCausalConsistency(true))
+	if err != nil {
+		return nil, nil, err
+	}
+	return mongodriver.NewSessionContext(ctx, session), func() { session.EndSession(ctx) }, nil
+}
@@ -0,0 +1,97 @@
+package mongo
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// cursorCollationKey is the key of the cursor element holding the collation resolved for the first page of a
+// pagination session, see Defaults.CollationResolver
+const cursorCollationKey = "$collation"
+
+type (
+	// CollationResolver returns the collation of the pages queried with the context, e.g. from the language of
+	// the user of the request, nil for the simple binary comparison
+	CollationResolver func(ctx context.Context) *options.Collation
+
+	// collationCursorCodec carries the resolved collation of a page in the cursors of a CursorCodec, stripping it
+	// from the decoded cursors
+	collationCursorCodec struct {
+		codec     CursorCodec
+		collation *options.Collation
+	}
+
+	carriedCollationData struct {
+		Locale   string `bson:"l"`
+		Strength int    `bson:"s,omitempty"`
+	}
+)
+
+// Encode implements CursorCodec
+func (c collationCursorCodec) Encode(cursorData bson.D) (string, error) {
+	if c.collation == nil {
+		return c.codec.Encode(cursorData)
+	}
+	collated := make(bson.D, 0, len(cursorData)+1)
+	collated = append(collated, cursorData...)
+	carried := carriedCollationData{Locale: c.collation.Locale, Strength: c.collation.Strength}
+	return c.codec.Encode(append(collated, bson.E{Key: cursorCollationKey, Value: carried}))
+}
+
+// Decode implements CursorCodec
+func (c collationCursorCodec) Decode(cursor string) (bson.D, error) {
+	cursorData, err := c.codec.Decode(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := carriedCollation(cursorData); ok {
+		return cursorData[:len(cursorData)-1], nil
+	}
+	return cursorData, nil
+}
+
+// carriedCollation returns the collation carried by the decoded cursor data, if any
+func carriedCollation(cursorData bson.D) (*options.Collation, bool) {
+	if len(cursorData) == 0 || cursorData[len(cursorData)-1].Key != cursorCollationKey {
+		return nil, false
+	}
+	raw, err := bson.Marshal(bson.M{"c": cursorData[len(cursorData)-1].Value})
+	if err != nil {
+		return nil, false
+	}
+	var carried struct {
+		C carriedCollationData `bson:"c"`
+	}
+	if bson.Unmarshal(raw, &carried) != nil || carried.C.Locale == "" {
+		return nil, false
+	}
+	return &options.Collation{Locale: carried.C.Locale, Strength: carried.C.Strength}, true
+}
+
+// resolveCollation returns p with the collation of the pagination session when p doesn't set one and the Defaults
+// have a CollationResolver: the one carried by the Next or Previous cursor, so that the following pages keep the
+// order of the first one, or else the one resolved from the context
+func resolveCollation(ctx context.Context, p FindParams) FindParams {
+	resolve := p.settings().CollationResolver
+	if p.Collation != nil || resolve == nil {
+		return p
+	}
+	cursor := p.Next
+	if cursor == "" {
+		cursor = p.Previous
+	}
+	if cursor != "" {
+		// An invalid cursor is rejected when building the queries
+		cursorData, err := p.cursorCodec().Decode(cursor)
+		if err == nil {
+			p.Collation, _ = carriedCollation(cursorData)
+		}
+	}
+	if p.Collation == nil {
+		p.Collation = resolve(ctx)
+	}
+	p.resolvedCollation = p.Collation != nil
+	return p
+}
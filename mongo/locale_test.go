@@ -0,0 +1,57 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type languageKey struct{}
+
+func TestFindResolvesCollation(t *testing.T) {
+	d := NewDefaults()
+	d.CollationResolver = func(ctx context.Context) *options.Collation {
+		if language, ok := ctx.Value(languageKey{}).(string); ok {
+			return &options.Collation{Locale: language, Strength: 2}
+		}
+		return nil
+	}
+	setDefaults(t, d)
+
+	items := []Item{{ID: primitive.NewObjectID(), Name: "a"}, {ID: primitive.NewObjectID(), Name: "b"}}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1]}}
+	p := FindParams{Collection: col, Limit: 1, PaginatedField: "name"}
+
+	// The collation is resolved from the context of the first page
+	var results []Item
+	cursor, err := Find(context.WithValue(context.Background(), languageKey{}, "fr"), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, &options.Collation{Locale: "fr", Strength: 2}, col.findOptions.Collation)
+
+	// And carried by the cursors, whatever the context of the following pages
+	p.Next = cursor.Next
+	cursor, err = Find(context.WithValue(context.Background(), languageKey{}, "de"), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, &options.Collation{Locale: "fr", Strength: 2}, col.findOptions.Collation)
+	p.Next = ""
+	p.Previous = cursor.Previous
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, &options.Collation{Locale: "fr", Strength: 2}, col.findOptions.Collation)
+
+	// The collation of the params takes precedence
+	p.Previous = ""
+	p.Collation = &options.Collation{Locale: "en"}
+	_, err = Find(context.WithValue(context.Background(), languageKey{}, "fr"), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, &options.Collation{Locale: "en"}, col.findOptions.Collation)
+
+	// No collation is resolved without language
+	p.Collation = nil
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Nil(t, col.findOptions.Collation)
+}
@@ -0,0 +1,165 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	// ProgressStore persists the progress token of a materialization job, e.g. in a document of a jobs collection,
+	// so that an interrupted job resumes after the last chunk it completed
+	ProgressStore interface {
+		// LoadProgress returns the saved progress token, the empty string when the job hasn't started
+		LoadProgress(ctx context.Context) (string, error)
+		// SaveProgress saves the progress token once a chunk is materialized
+		SaveProgress(ctx context.Context, token string) error
+	}
+
+	// MaterializeParams holds the parameters of a resumable materialization job, running a pipeline ending with a
+	// $merge or $out stage over the source documents in chunks
+	MaterializeParams struct {
+		// Source selects the documents to materialize and the order they're chunked in, its Limit being the
+		// number of documents of a chunk. Its Next and Previous cursors are ignored, the job starts after the
+		// saved progress token.
+		Source FindParams
+		// The collection running the pipeline of each chunk, which must be the source collection. Defaults to the
+		// Source's Collection when it's an AggregateCollection, e.g. its MongoCollection.
+		Collection AggregateCollection
+		// The stages applied to the documents of each chunk, ending with a $merge or $out stage. As each chunk
+		// would replace the collection output by $out, it is run as a $merge replacing and inserting the
+		// documents instead, which doesn't drop the documents already in the output collection.
+		Pipeline []bson.M
+		// Where the progress token is persisted, required
+		Progress ProgressStore
+	}
+)
+
+// Materialize runs the pipeline of the provided MaterializeParams over the source documents in chunks in their
+// cursor order, from the chunk following the saved progress token, and returns the number of chunks it
+// materialized. Each chunk is bounded by the range predicates of its first and last documents and its progress
+// token, the cursor of its last document, is saved once its pipeline succeeded. The pipeline must be idempotent
+// for a chunk to be materialized again when the job is interrupted before saving its progress.
+func Materialize(ctx context.Context, p MaterializeParams) (chunks int, err error) {
+	if p.Progress == nil {
+		return 0, errors.New("Progress can't be nil")
+	}
+	output, err := outputStage(p.Pipeline)
+	if err != nil {
+		return 0, err
+	}
+
+	source, err := applySortSpec(p.Source, DefaultSortRegistry)
+	if err != nil {
+		return 0, err
+	}
+	source = ensureMandatoryParams(source)
+	collection := p.Collection
+	if collection == nil {
+		collection, _ = source.Collection.(AggregateCollection)
+	}
+	if collection == nil {
+		return 0, errors.New("Collection can't be nil")
+	}
+
+	// Only the paginated fields of the documents are fetched to bound the chunks
+	source.Next = ""
+	source.Previous = ""
+	source.CountTotal = false
+	projection := make(bson.M, len(source.PaginatedFields))
+	for _, paginatedField := range source.PaginatedFields {
+		projection[paginatedField] = 1
+	}
+	source.Projection = projection
+
+	token, err := p.Progress.LoadProgress(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("could not load the progress: %w", err)
+	}
+	for {
+		chunk := source
+		chunk.Next = token
+		var keys []bson.Raw
+		cursor, err := Find(ctx, chunk, &keys)
+		if err != nil {
+			return chunks, err
+		}
+		if len(keys) == 0 {
+			return chunks, nil
+		}
+		last, err := generateCursor(keys[len(keys)-1], chunk)
+		if err != nil {
+			return chunks, fmt.Errorf("could not create a progress token: %s", err)
+		}
+
+		match, err := chunkQuery(chunk, last)
+		if err != nil {
+			return chunks, err
+		}
+		pipeline := make([]bson.M, 0, len(p.Pipeline)+1)
+		pipeline = append(pipeline, bson.M{"$match": match})
+		pipeline = append(pipeline, p.Pipeline[:len(p.Pipeline)-1]...)
+		pipeline = append(pipeline, output)
+		err = executeMaterializeQuery(ctx, collection, pipeline, source.Collation)
+		if err != nil {
+			return chunks, err
+		}
+
+		err = p.Progress.SaveProgress(ctx, last)
+		if err != nil {
+			return chunks, fmt.Errorf("could not save the progress: %w", err)
+		}
+		chunks++
+		token = last
+		if !cursor.HasNext {
+			return chunks, nil
+		}
+	}
+}
+
+// chunkQuery returns the query matching the documents of the chunk from the Next cursor of p, exclusive, until
+// the last cursor, inclusive
+func chunkQuery(p FindParams, last string) (bson.M, error) {
+	fromQuery, _, err := buildCursorQuery(p)
+	if err != nil {
+		return nil, err
+	}
+	p.Next = ""
+	p.Previous = last
+	p.IncludeAnchor = true
+	untilQuery, _, err := buildCursorQuery(p)
+	if err != nil {
+		return nil, err
+	}
+	return MergeQueries([]bson.M{p.Query, fromQuery, untilQuery}), nil
+}
+
+// outputStage returns the last stage of the pipeline, a $merge stage or the $merge equivalent of a $out stage
+func outputStage(pipeline []bson.M) (bson.M, error) {
+	if len(pipeline) == 0 {
+		return nil, errors.New("the pipeline must end with a $merge or $out stage")
+	}
+	stage := pipeline[len(pipeline)-1]
+	if _, ok := stage["$merge"]; ok && len(stage) == 1 {
+		return stage, nil
+	}
+	if out, ok := stage["$out"]; ok && len(stage) == 1 {
+		return bson.M{"$merge": bson.M{"into": out, "whenMatched": "replace", "whenNotMatched": "insert"}}, nil
+	}
+	return nil, errors.New("the pipeline must end with a $merge or $out stage")
+}
+
+var executeMaterializeQuery = func(ctx context.Context, c AggregateCollection, pipeline []bson.M, collation *options.Collation) error {
+	opts := options.Aggregate()
+	if collation != nil {
+		opts.SetCollation(collation)
+	}
+	cursor, err := c.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return err
+	}
+	return cursor.Close(ctx)
+}
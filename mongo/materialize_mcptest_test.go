@@ -0,0 +1,100 @@
+package mongo_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// mergingCollection runs the pipelines of Materialize without their $merge stage, recording the names of the
+// documents of each chunk
+type mergingCollection struct {
+	*mcptest.Collection
+	chunks [][]string
+}
+
+func (c *mergingCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (mongo.MongoCursor, error) {
+	stages := pipeline.([]bson.M)
+	cursor, err := c.Collection.Aggregate(ctx, stages[:len(stages)-1], opts...)
+	if err != nil {
+		return nil, err
+	}
+	var docs []mcptest.Item
+	err = cursor.All(ctx, &docs)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, doc := range docs {
+		names = append(names, doc.Name)
+	}
+	c.chunks = append(c.chunks, names)
+	return mcptest.NewCursor()
+}
+
+// progressStore keeps the progress token in memory, failing to save it once saves reaches failAt
+type progressStore struct {
+	token  string
+	saves  int
+	failAt int
+}
+
+func (s *progressStore) LoadProgress(context.Context) (string, error) {
+	return s.token, nil
+}
+
+func (s *progressStore) SaveProgress(_ context.Context, token string) error {
+	s.saves++
+	if s.saves == s.failAt {
+		return errors.New("interrupted")
+	}
+	s.token = token
+	return nil
+}
+
+func TestMaterializeResumesAfterProgress(t *testing.T) {
+	_, col := mcptest.NewItems(t)
+	merging := &mergingCollection{Collection: col}
+	progress := &progressStore{failAt: 2}
+	p := mongo.MaterializeParams{
+		Source: mongo.FindParams{
+			Collection:      col,
+			Query:           bson.M{"name": primitive.Regex{Pattern: "^test"}},
+			Limit:           2,
+			PaginatedFields: []string{"name"},
+			SortOrders:      []int{1},
+		},
+		Collection: merging,
+		Pipeline:   []bson.M{{"$out": "names"}},
+		Progress:   progress,
+	}
+
+	// The chunk whose progress wasn't saved is materialized again when resuming
+	chunks, err := mongo.Materialize(context.Background(), p)
+	require.EqualError(t, err, "could not save the progress: interrupted")
+	require.Equal(t, 1, chunks)
+	chunks, err = mongo.Materialize(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, 1, chunks)
+	require.Equal(t, [][]string{
+		{"test item 1", "test item 2"},
+		{"test item 3", "test item 4"},
+		{"test item 3", "test item 4"},
+	}, merging.chunks)
+
+	// A completed job has nothing left to materialize
+	chunks, err = mongo.Materialize(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, 0, chunks)
+
+	p.Pipeline = []bson.M{{"$match": bson.M{}}}
+	_, err = mongo.Materialize(context.Background(), p)
+	require.EqualError(t, err, "the pipeline must end with a $merge or $out stage")
+}
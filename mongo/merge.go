@@ -0,0 +1,195 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+type (
+	// MergeSource is one of the collections queried by MergeFind, e.g. the collection of a tenant database
+	MergeSource struct {
+		// The name of the source, set as the CollectionName of its queries
+		Name       string
+		Collection Collection
+		// The mongo collection to query when Collection is nil
+		MongoCollection *mongodriver.Collection
+	}
+
+	// mergeToken is the composite cursor of MergeFind, holding the position of each source in their order
+	mergeToken struct {
+		Positions []mergePosition `bson:"p"`
+	}
+
+	// mergePosition is the position of a source in a merged listing
+	mergePosition struct {
+		// The Next cursor of the last document of the source delivered, none if none was
+		Cursor string `bson:"c,omitempty"`
+		// true once every document of the source was delivered
+		Done bool `bson:"d,omitempty"`
+	}
+)
+
+// MergeFind executes the paginated find query of p on every source concurrently, e.g. the same collection of the
+// databases of several tenants, and merges their documents in the sort order of p into the passed in result slice
+// pointer. The Next cursor of the returned Cursor is a composite cursor holding the position of each source, to
+// pass as the Next of p along with the same sources in the same order. The documents tied on the paginated fields
+// across sources are ordered by their source. Each source fetches up to the Limit documents per page, and only
+// forward pagination is supported: the Previous cursor of p must be empty. The Collection of p and CountTotal are
+// ignored.
+func MergeFind(ctx context.Context, p FindParams, sources []MergeSource, results interface{}) (Cursor, error) {
+	if len(sources) == 0 {
+		return Cursor{}, errors.New("at least one source is required")
+	}
+	if p.Previous != "" {
+		return Cursor{}, errors.New("merged listings can't be paged backwards")
+	}
+	token, err := decodeMergeToken(p.Next, len(sources))
+	if err != nil {
+		return Cursor{}, err
+	}
+	p, err = applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return Cursor{}, err
+	}
+	p = ensureMandatoryParams(p)
+	p.CountTotal = false
+	err = p.encoding().validate(results, p.PaginatedFields)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	// Every source that has more documents is queried for a full page as any of them may fill the merged page
+	pages := make([][]bson.Raw, len(sources))
+	hasNext := make([]bool, len(sources))
+	errs := make([]error, len(sources))
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		if token.Positions[i].Done {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, source MergeSource) {
+			defer wg.Done()
+			sp := p
+			sp.Collection = source.Collection
+			sp.MongoCollection = source.MongoCollection
+			if source.Name != "" {
+				sp.CollectionName = source.Name
+			}
+			sp.Next = token.Positions[i].Cursor
+			cursor, err := Find(ctx, sp, &pages[i])
+			if err != nil {
+				errs[i] = fmt.Errorf("could not find the page of source %d: %w", i, err)
+				return
+			}
+			hasNext[i] = cursor.HasNext
+		}(i, source)
+	}
+	wg.Wait()
+	err = errors.Join(errs...)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	merged, consumed, err := mergePages(p, pages)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	// Each source resumes after its last document delivered
+	more := false
+	for i, page := range pages {
+		position := &token.Positions[i]
+		if consumed[i] > 0 {
+			position.Cursor, err = generateCursor(page[consumed[i]-1], p)
+			if err != nil {
+				return Cursor{}, fmt.Errorf("could not create a next cursor: %s", err)
+			}
+		}
+		position.Done = position.Done || (consumed[i] == len(page) && !hasNext[i])
+		more = more || !position.Done
+	}
+
+	cursor := Cursor{HasPrevious: p.Next != "", HasNext: more}
+	if more {
+		cursor.Next, err = encodeMergeToken(token)
+		if err != nil {
+			return Cursor{}, err
+		}
+	}
+	err = decodeResults(merged, p.settings().Registry, results)
+	if err != nil {
+		return Cursor{}, err
+	}
+	return cursor, nil
+}
+
+// mergePages merges the sorted pages of the sources into the page of p, returning it along with the number of
+// documents of each source it holds
+func mergePages(p FindParams, pages [][]bson.Raw) ([]bson.Raw, []int, error) {
+	keys := make([][][]interface{}, len(pages))
+	for i, page := range pages {
+		keys[i] = make([][]interface{}, 0, len(page))
+		for _, doc := range page {
+			key, err := paginatedValues(doc, p)
+			if err != nil {
+				return nil, nil, err
+			}
+			keys[i] = append(keys[i], key)
+		}
+	}
+
+	consumed := make([]int, len(pages))
+	merged := make([]bson.Raw, 0, p.Limit)
+	for int64(len(merged)) < p.Limit {
+		next := -1
+		for i := range pages {
+			if consumed[i] == len(pages[i]) {
+				continue
+			}
+			if next < 0 || compareKeys(keys[i][consumed[i]], keys[next][consumed[next]], p.SortOrders) < 0 {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		merged = append(merged, pages[next][consumed[next]])
+		consumed[next]++
+	}
+	return merged, consumed, nil
+}
+
+func encodeMergeToken(mt mergeToken) (string, error) {
+	data, err := bson.Marshal(mt)
+	if err != nil {
+		return "", err
+	}
+	return mcpbson.EncodeCursor(data), nil
+}
+
+// decodeMergeToken decodes the composite cursor of a merged listing of the number of sources, the empty cursor
+// being the start of every source
+func decodeMergeToken(token string, sources int) (mergeToken, error) {
+	mt := mergeToken{Positions: make([]mergePosition, sources)}
+	if token == "" {
+		return mt, nil
+	}
+	data, err := mcpbson.DecodeCursor(token, sources*mcpbson.DefaultMaxCursorSize, mcpbson.DefaultMaxCursorDepth)
+	if err == nil {
+		err = bson.Unmarshal(data, &mt)
+	}
+	if err != nil {
+		return mt, &CursorError{fmt.Errorf("merge cursor parse failed: %s", err)}
+	}
+	if len(mt.Positions) != sources {
+		return mt, &CursorError{fmt.Errorf("merge cursor parse failed: expecting the positions of %d sources", sources)}
+	}
+	return mt, nil
+}
@@ -0,0 +1,73 @@
+package mongo_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestMergeFindMergesTheSources(t *testing.T) {
+	type sourced struct {
+		mcptest.Item
+		source int
+	}
+	// The tenants share some _ids and counts
+	shared := primitive.NewObjectID()
+	var all []sourced
+	sources := make([]mongo.MergeSource, 3)
+	for s := range sources {
+		docs := []interface{}{mcptest.Item{ID: shared, Name: "shared", Count: 2}}
+		all = append(all, sourced{Item: docs[0].(mcptest.Item), source: s})
+		for i := 0; i < 3+s; i++ {
+			doc := mcptest.Item{ID: primitive.NewObjectID(), Name: "item", Count: (i*5 + s) % 4}
+			docs = append(docs, doc)
+			all = append(all, sourced{Item: doc, source: s})
+		}
+		col, err := mcptest.NewCollection(docs...)
+		require.NoError(t, err)
+		sources[s] = mongo.MergeSource{Name: "items", Collection: col}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Count != all[j].Count {
+			return all[i].Count < all[j].Count
+		}
+		if all[i].ID != all[j].ID {
+			return all[i].ID.Hex() < all[j].ID.Hex()
+		}
+		return all[i].source < all[j].source
+	})
+	var expected []mcptest.Item
+	for _, s := range all {
+		expected = append(expected, s.Item)
+	}
+
+	p := mongo.FindParams{Limit: 3, PaginatedField: "count", SortAscending: true}
+	var merged, results []mcptest.Item
+	pages := 0
+	for cursor := (mongo.Cursor{HasNext: true}); cursor.HasNext; p.Next = cursor.Next {
+		var err error
+		cursor, err = mongo.MergeFind(context.Background(), p, sources, &results)
+		require.NoError(t, err)
+		require.Equal(t, p.Next != "", cursor.HasPrevious)
+		merged = append(merged, results...)
+		pages++
+	}
+	require.Equal(t, expected, merged)
+	require.Equal(t, (len(all)+2)/3, pages)
+
+	// The cursor holds the positions of the sources it was issued for
+	p.Next = ""
+	cursor, err := mongo.MergeFind(context.Background(), p, sources, &results)
+	require.NoError(t, err)
+	p.Next = cursor.Next
+	_, err = mongo.MergeFind(context.Background(), p, sources[:2], &results)
+	require.EqualError(t, err, "merge cursor parse failed: expecting the positions of 2 sources")
+	p.Previous, p.Next = cursor.Next, ""
+	_, err = mongo.MergeFind(context.Background(), p, sources, &results)
+	require.EqualError(t, err, "merged listings can't be paged backwards")
+}
@@ -0,0 +1,24 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func FuzzDecodeMergeToken(f *testing.F) {
+	token, err := encodeMergeToken(mergeToken{Positions: []mergePosition{{Cursor: "AQ"}, {Done: true}}})
+	require.NoError(f, err)
+	f.Add(token)
+	f.Add(token[:len(token)/2])
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, token string) {
+		mt, err := decodeMergeToken(token, 2)
+		if err != nil {
+			require.IsType(t, &CursorError{}, err)
+			return
+		}
+		require.Len(t, mt.Positions, 2)
+	})
+}
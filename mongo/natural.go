@@ -0,0 +1,187 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NaturalField is the paginated field paginating the documents in their natural order, e.g. the insertion order
+// of a capped or log style collection, see findNatural
+const NaturalField = "$natural"
+
+// natural returns true if p paginates the documents in their natural order
+func (p FindParams) natural() bool {
+	if len(p.PaginatedFields) > 0 {
+		return p.PaginatedFields[len(p.PaginatedFields)-1] == NaturalField
+	}
+	return p.PaginatedField == NaturalField
+}
+
+// findNatural executes the find query of the page of p in the ascending natural order of the documents matched by
+// its Query, filling results. The documents have no field to compare with a cursor: the cursors hold the position
+// of the documents they point at in that order, and the pages are skipped to. The server walks the skipped
+// documents, so a page costs O(n) in its position. The positions are only stable as long as documents are appended
+// at the end of the order, as in an insert only collection: removing documents, e.g. as a capped collection does,
+// shifts the following ones and makes the pages skip or repeat documents. As each insert would shift the positions
+// of the descending order, it is rejected. The options bounding or checking the cursor queries, e.g. the
+// ScanBudget, the Consistency or the PageCache, are ignored.
+func findNatural(ctx context.Context, p FindParams, results interface{}) (Cursor, error) {
+	p = ensureMandatoryParams(p)
+	if len(p.PaginatedFields) != 1 {
+		return Cursor{}, errors.New("the natural order can't be paginated along with other fields")
+	}
+	if p.SortOrders[0] != 1 {
+		return Cursor{}, errors.New("the natural order can only be paginated in ascending order, set SortAscending")
+	}
+	if p.Collection == nil {
+		return Cursor{}, errors.New("Collection can't be nil")
+	}
+	if p.Limit <= 0 {
+		return Cursor{}, errors.New("a limit of at least 1 is required")
+	}
+	if p.SkipPages < 0 {
+		return Cursor{}, errors.New("SkipPages can't be negative")
+	}
+	err := p.encoding().validate(results, nil)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	count, countSource, err := countTotal(p, func() (count int, err error) {
+		filter, countOptions := buildCountQuery(p)
+		err = p.execute(ctx, QueryCount, func(ctx context.Context) error {
+			count, err = executeCountQuery(ctx, p.Collection, filter, countOptions)
+			return err
+		})
+		return count, err
+	})
+	if err != nil {
+		return Cursor{}, err
+	}
+	p = p.carryCount(count, countSource)
+
+	// The page is fetched forward from its start, with one more document to see if another page follows it
+	skipped := int64(p.SkipPages) * p.Limit
+	start, fetchLimit, err := naturalRange(p, skipped)
+	if err != nil {
+		return Cursor{}, err
+	}
+	rawOptions := p.rawFindOptions()
+	resultsVal := reflect.ValueOf(results).Elem()
+	if fetchLimit > 0 {
+		err = p.execute(ctx, QueryFind, func(ctx context.Context) error {
+			return executeCursorQuery(ctx, p.Collection, []bson.M{p.Query}, bson.D{{Key: NaturalField, Value: p.SortOrders[0]}}, fetchLimit, false, p.Collation, p.Hint, p.Projection, p.Timeout, func(o *options.FindOptions) {
+				o.SetSkip(start)
+				if rawOptions != nil {
+					rawOptions(o)
+				}
+			}, results)
+		})
+		if err != nil {
+			return Cursor{}, err
+		}
+	} else {
+		// No document precedes the cursor of the previous page
+		resultsVal.Set(reflect.MakeSlice(resultsVal.Type(), 0, 0))
+	}
+
+	// Remove the additional document fetched to see if there was another page
+	hasMore := int64(resultsVal.Len()) > p.Limit
+	if hasMore {
+		resultsVal.Set(resultsVal.Slice(0, int(p.Limit)))
+	}
+	end := start + int64(resultsVal.Len())
+	cursor := Cursor{HasPrevious: start > 0, HasNext: p.Previous != "" || hasMore}
+	if end > start {
+		if cursor.HasPrevious {
+			cursor.Previous, err = encodeNaturalCursor(p, start)
+			if err != nil {
+				return Cursor{}, fmt.Errorf("could not create a previous cursor: %s", err)
+			}
+		}
+		if cursor.HasNext {
+			cursor.Next, err = encodeNaturalCursor(p, end-1)
+			if err != nil {
+				return Cursor{}, fmt.Errorf("could not create a next cursor: %s", err)
+			}
+		}
+	}
+	if p.DebugCursors {
+		cursor.PreviousValues, err = p.cursorValues(cursor.Previous)
+		if err != nil {
+			return Cursor{}, fmt.Errorf("could not decode the previous cursor: %s", err)
+		}
+		cursor.NextValues, err = p.cursorValues(cursor.Next)
+		if err != nil {
+			return Cursor{}, fmt.Errorf("could not decode the next cursor: %s", err)
+		}
+	}
+	cursor.Count, cursor.CountRelation = p.cappedCount(count)
+	cursor.CountSource = countSource
+	return cursor, nil
+}
+
+// naturalRange returns the position of the first document of the page of p in the natural order and the number of
+// documents to fetch from it, skipping the skipped documents past its cursor
+func naturalRange(p FindParams, skipped int64) (start int64, fetchLimit int64, err error) {
+	switch {
+	case p.Next != "":
+		position, err := decodeNaturalCursor(p, p.Next)
+		if err != nil {
+			return 0, 0, &CursorError{fmt.Errorf("next cursor parse failed: %w", err)}
+		}
+		start = position + 1 + skipped
+		if p.IncludeAnchor {
+			start--
+		}
+	case p.Previous != "":
+		position, err := decodeNaturalCursor(p, p.Previous)
+		if err != nil {
+			return 0, 0, &CursorError{fmt.Errorf("previous cursor parse failed: %w", err)}
+		}
+		end := position - skipped
+		if p.IncludeAnchor {
+			end++
+		}
+		start = max(end-p.Limit, 0)
+		// The documents of a previous page are the ones preceding its end, there's no need to fetch another one
+		return start, max(end-start, 0), nil
+	default:
+		start = skipped
+	}
+	return start, p.Limit + 1, nil
+}
+
+// encodeNaturalCursor returns the cursor of p pointing at the document of the position in the natural order
+func encodeNaturalCursor(p FindParams, position int64) (string, error) {
+	return p.cursorCodec().Encode(bson.D{{Key: NaturalField, Value: position}})
+}
+
+// decodeNaturalCursor returns the position in the natural order of the document the cursor of p points at
+func decodeNaturalCursor(p FindParams, cursor string) (int64, error) {
+	cursorData, err := p.cursorCodec().Decode(cursor)
+	if err != nil {
+		return 0, err
+	}
+	err = mcpbson.CheckCursorLength(len(cursorData), 1)
+	if err != nil {
+		return 0, err
+	}
+	if cursorData[0].Key != NaturalField {
+		return 0, fmt.Errorf("cursor element 0 is %s where %s is expected", cursorData[0].Key, NaturalField)
+	}
+	position, ok := cursorData[0].Value.(int64)
+	if !ok {
+		return 0, NewErrCursorTypeMismatch(NaturalField, bson.TypeInt64.String(), bsonTypeName(cursorData[0].Value))
+	}
+	if position < 0 {
+		return 0, errors.New("the position of the cursor can't be negative")
+	}
+	return position, nil
+}
@@ -0,0 +1,70 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindNaturalOrder(t *testing.T) {
+	// The counts are out of insertion order, which the pages follow
+	var docs []interface{}
+	for _, count := range []int{3, 1, 4, 0, 2} {
+		docs = append(docs, mcptest.Item{ID: primitive.NewObjectID(), Name: "item", Count: count})
+	}
+	col, err := mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+	p := mongo.FindParams{Collection: col, Limit: 2, PaginatedField: mongo.NaturalField, SortAscending: true, CountTotal: true}
+
+	var counts [][]int
+	var cursors []mongo.Cursor
+	for cursor := (mongo.Cursor{HasNext: true}); cursor.HasNext; p.Next = cursor.Next {
+		var page []mcptest.Item
+		cursor, err = mongo.Find(context.Background(), p, &page)
+		require.NoError(t, err)
+		require.Equal(t, 5, cursor.Count)
+		var pageCounts []int
+		for _, result := range page {
+			pageCounts = append(pageCounts, result.Count)
+		}
+		counts = append(counts, pageCounts)
+		cursors = append(cursors, cursor)
+	}
+	require.Equal(t, [][]int{{3, 1}, {4, 0}, {2}}, counts)
+	require.False(t, cursors[0].HasPrevious)
+	require.Empty(t, cursors[2].Next)
+
+	// Paging back from the last page returns the previous ones
+	var results []mcptest.Item
+	p.Next = ""
+	p.Previous = cursors[2].Previous
+	cursor, err := mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, 4, results[0].Count)
+	require.True(t, cursor.HasPrevious)
+	require.True(t, cursor.HasNext)
+	require.Equal(t, cursors[1].Previous, cursor.Previous)
+
+	p.Previous = cursor.Previous
+	cursor, err = mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.Equal(t, 3, results[0].Count)
+	require.False(t, cursor.HasPrevious)
+
+	// The positions of the descending natural order would shift with each insert
+	p = mongo.FindParams{Collection: col, Limit: 2, PaginatedField: mongo.NaturalField}
+	_, err = mongo.Find(context.Background(), p, &results)
+	require.EqualError(t, err, "the natural order can only be paginated in ascending order, set SortAscending")
+
+	// The natural order has no field to break ties with
+	p.SortAscending = true
+	p.PaginatedFields = []string{"count", mongo.NaturalField}
+	_, err = mongo.Find(context.Background(), p, &results)
+	require.EqualError(t, err, "the natural order can't be paginated along with other fields")
+}
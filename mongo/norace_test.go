@@ -0,0 +1,6 @@
+//go:build !race
+
+package mongo
+
+// raceEnabled is true when the tests run with the race detector, which instruments the allocations
+const raceEnabled = false
@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TotalPages returns the number of pages of limit documents holding the Count of the Cursor, for the API layers
+// rendering numbered paginators. It is 0 when there are no documents, when the limit isn't positive or when the
+// documents weren't counted, and a lower bound when the Count was truncated to a CountLimit.
+func (c Cursor) TotalPages(limit int64) int {
+	if limit <= 0 || c.Count <= 0 {
+		return 0
+	}
+	return int((int64(c.Count) + limit - 1) / limit)
+}
+
+// skipPagesOptions returns the adjustment of the options of the find query of the page skipping its SkipPages,
+// followed by its raw options
+func (p FindParams) skipPagesOptions() func(*options.FindOptions) {
+	rawOptions := p.rawFindOptions()
+	if p.SkipPages <= 0 {
+		return rawOptions
+	}
+	return func(o *options.FindOptions) {
+		o.SetSkip(int64(p.SkipPages) * p.Limit)
+		if rawOptions != nil {
+			rawOptions(o)
+		}
+	}
+}
+
+// ApproxPageIndex returns the 0-based index of the page of the provided FindParams among the pages of their Limit,
+// counting the documents preceding its Next or Previous cursor. The index is approximate as the pages reached
+// with cursors don't start at multiples of the limit once documents were added or removed, or when paging back.
+func ApproxPageIndex(ctx context.Context, p FindParams) (int, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return 0, err
+	}
+	p = ensureMandatoryParams(p)
+	if p.Collection == nil {
+		return 0, errors.New("Collection can't be nil")
+	}
+	if p.Limit <= 0 {
+		return 0, errors.New("a limit of at least 1 is required")
+	}
+	if p.Next == "" && p.Previous == "" {
+		return 0, nil
+	}
+
+	// The documents preceding a next page are the ones up to its cursor, included unless it is the anchor
+	preceding := p
+	preceding.Previous = p.Next + p.Previous
+	preceding.Next = ""
+	preceding.IncludeAnchor = p.Next != "" && !p.IncludeAnchor
+	precedingQuery, _, err := buildCursorQuery(preceding)
+	if err != nil {
+		return 0, err
+	}
+	counted := p
+	counted.CountLimit = 0
+	counted.Query = MergeQueries([]bson.M{p.Query, precedingQuery})
+	filter, countOptions := buildCountQuery(counted)
+	var count int
+	err = p.execute(ctx, QueryCount, func(ctx context.Context) error {
+		count, err = executeCountQuery(ctx, p.Collection, filter, countOptions)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if p.Next != "" {
+		return int(int64(count) / p.Limit), nil
+	}
+	// A previous page ends before its cursor, or with it when it is the anchor
+	if p.IncludeAnchor {
+		count++
+	}
+	if count == 0 {
+		return 0, nil
+	}
+	return int(int64(count-1) / p.Limit), nil
+}
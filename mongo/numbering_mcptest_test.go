@@ -0,0 +1,106 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestApproxPageIndex(t *testing.T) {
+	var docs []interface{}
+	for i := 0; i < 10; i++ {
+		docs = append(docs, mcptest.Item{ID: primitive.NewObjectID(), Name: "item", Count: i})
+	}
+	col, err := mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+	p := mongo.FindParams{Collection: col, Limit: 3, PaginatedField: "count", SortAscending: true, CountTotal: true}
+
+	var results []mcptest.Item
+	var cursor mongo.Cursor
+	for index := 0; index < 4; index++ {
+		if index > 0 {
+			p.Next = cursor.Next
+		}
+		cursor, err = mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.Equal(t, 4, cursor.TotalPages(p.Limit))
+		approx, err := mongo.ApproxPageIndex(context.Background(), p)
+		require.NoError(t, err)
+		require.Equal(t, index, approx)
+	}
+
+	// Paging back from the last page
+	p.Next = ""
+	p.Previous = cursor.Previous
+	approx, err := mongo.ApproxPageIndex(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, 2, approx)
+
+	require.Equal(t, 0, mongo.Cursor{}.TotalPages(3))
+	require.Equal(t, 0, mongo.Cursor{Count: 3}.TotalPages(0))
+	require.Equal(t, 1, mongo.Cursor{Count: 3}.TotalPages(3))
+}
+
+func TestFindSkipPagesJumpsAhead(t *testing.T) {
+	var docs []interface{}
+	for i := 0; i < 10; i++ {
+		docs = append(docs, mcptest.Item{ID: primitive.NewObjectID(), Name: "item", Count: i})
+	}
+	col, err := mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+	p := mongo.FindParams{Collection: col, Limit: 2, PaginatedField: "count", SortAscending: true}
+
+	// Page through the documents one page at a time
+	var pages [][]mcptest.Item
+	var cursors []mongo.Cursor
+	for cursor := (mongo.Cursor{HasNext: true}); cursor.HasNext; p.Next = cursor.Next {
+		var page []mcptest.Item
+		cursor, err = mongo.Find(context.Background(), p, &page)
+		require.NoError(t, err)
+		pages = append(pages, page)
+		cursors = append(cursors, cursor)
+	}
+	require.Len(t, pages, 5)
+
+	// Skipping pages from the start, past a next cursor or before a previous cursor lands on the same pages
+	var results []mcptest.Item
+	p.Next = ""
+	p.SkipPages = 2
+	cursor, err := mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, pages[2], results)
+	require.Equal(t, cursors[2].Next, cursor.Next)
+	require.True(t, cursor.HasPrevious)
+	require.True(t, cursor.HasNext)
+
+	p.Next = cursors[0].Next
+	cursor, err = mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, pages[3], results)
+	require.Equal(t, cursors[3].Next, cursor.Next)
+	require.True(t, cursor.HasNext)
+
+	p.Next = ""
+	p.Previous = cursors[4].Previous
+	cursor, err = mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, pages[1], results)
+	require.True(t, cursor.HasPrevious)
+	require.True(t, cursor.HasNext)
+
+	// Skipping past the last page returns an empty page
+	p.Previous = ""
+	p.SkipPages = 5
+	cursor, err = mongo.Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Empty(t, results)
+	require.False(t, cursor.HasNext)
+
+	p.SkipPages = -1
+	_, err = mongo.Find(context.Background(), p, &results)
+	require.EqualError(t, err, "SkipPages can't be negative")
+}
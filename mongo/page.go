@@ -0,0 +1,107 @@
+package mongo
+
+// Direction is the direction of the page requested relative to the cursor of a PageRequest
+type Direction int
+
+const (
+	// Forward requests the page following the cursor
+	Forward Direction = iota
+	// Backward requests the page preceding the cursor
+	Backward
+)
+
+// PageRequest holds the pagination parameters of a page, independently of where the documents are queried from.
+// The Cursor returned for a page holds the cursors to request the adjacent pages with.
+type PageRequest struct {
+	// The number of results to fetch, should be > 0
+	Limit int64
+	// true, if the results should be sort ascending, false otherwise
+	SortAscending bool
+	// The name of the field being paginated and sorted on, see FindParams.PaginatedField
+	PaginatedField string
+	// The names of multiple fields being paginated and sorted on. Takes precedence over PaginatedField
+	PaginatedFields []string
+	// The sort orders corresponding to PaginatedFields. Each value must be either 1 or -1
+	SortOrders []int
+	// The value to start querying the page
+	Next string
+	// The value to start querying previous page
+	Previous string
+	// true, if the page starting at Next or Previous should include the anchor document the cursor points at
+	IncludeAnchor bool
+}
+
+// NewPageRequest returns a PageRequest for the first page of limit results, sorted by descending _id
+func NewPageRequest(limit int64) PageRequest {
+	return PageRequest{Limit: limit}
+}
+
+// WithToken returns a copy of r requesting the page in the specified direction from the cursor token, e.g. the
+// Next or Previous value of a Cursor. An empty token requests the first page.
+func (r PageRequest) WithToken(token string, d Direction) PageRequest {
+	r.Next, r.Previous = "", ""
+	if d == Backward {
+		r.Previous = token
+	} else {
+		r.Next = token
+	}
+	return r
+}
+
+// Token returns the cursor token of r and the direction of the requested page from it
+func (r PageRequest) Token() (string, Direction) {
+	if r.Previous != "" {
+		return r.Previous, Backward
+	}
+	return r.Next, Forward
+}
+
+// WithSortField returns a copy of r sorted on the single paginated field, then by _id
+func (r PageRequest) WithSortField(paginatedField string, ascending bool) PageRequest {
+	r.PaginatedField = paginatedField
+	r.SortAscending = ascending
+	r.PaginatedFields = nil
+	r.SortOrders = nil
+	return r
+}
+
+// WithSort returns a copy of r sorted on the paginated fields in their corresponding sort orders, then by _id
+func (r PageRequest) WithSort(paginatedFields []string, sortOrders []int) PageRequest {
+	r.PaginatedField = ""
+	r.PaginatedFields = append([]string{}, paginatedFields...)
+	r.SortOrders = append([]int{}, sortOrders...)
+	return r
+}
+
+// Apply returns p with its pagination parameters set from r
+func (r PageRequest) Apply(p FindParams) FindParams {
+	p.Limit = r.Limit
+	p.SortAscending = r.SortAscending
+	p.PaginatedField = r.PaginatedField
+	p.PaginatedFields = nil
+	if r.PaginatedFields != nil {
+		p.PaginatedFields = append([]string{}, r.PaginatedFields...)
+	}
+	p.SortOrders = nil
+	if r.SortOrders != nil {
+		p.SortOrders = append([]int{}, r.SortOrders...)
+	}
+	p.Next = r.Next
+	p.Previous = r.Previous
+	p.IncludeAnchor = r.IncludeAnchor
+	return p
+}
+
+// PageRequest returns the pagination parameters of p
+func (p FindParams) PageRequest() PageRequest {
+	return PageRequest{
+		Limit:           p.Limit,
+		SortAscending:   p.SortAscending,
+		PaginatedField:  p.PaginatedField,
+		PaginatedFields: p.PaginatedFields,
+		SortOrders:      p.SortOrders,
+		Next:            p.Next,
+		Previous:        p.Previous,
+		IncludeAnchor:   p.IncludeAnchor,
+	}
+}
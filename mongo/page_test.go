@@ -0,0 +1,39 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageRequestBuilders(t *testing.T) {
+	r := NewPageRequest(10).WithSortField("name", true).WithToken("abc", Backward)
+	require.Equal(t, PageRequest{Limit: 10, SortAscending: true, PaginatedField: "name", Previous: "abc"}, r)
+	token, direction := r.Token()
+	require.Equal(t, "abc", token)
+	require.Equal(t, Backward, direction)
+
+	r = r.WithToken("def", Forward).WithSort([]string{"name", "createdAt"}, []int{1, -1})
+	require.Equal(t, PageRequest{
+		Limit:           10,
+		SortAscending:   true,
+		PaginatedFields: []string{"name", "createdAt"},
+		SortOrders:      []int{1, -1},
+		Next:            "def",
+	}, r)
+
+	p := r.Apply(FindParams{CountTotal: true, PaginatedField: "data", Previous: "xyz"})
+	require.Equal(t, FindParams{
+		CountTotal:      true,
+		Limit:           10,
+		SortAscending:   true,
+		PaginatedFields: []string{"name", "createdAt"},
+		SortOrders:      []int{1, -1},
+		Next:            "def",
+	}, p)
+	require.Equal(t, r, p.PageRequest())
+
+	// Applying a PageRequest doesn't share its slices with the FindParams
+	p.SortOrders[0] = -1
+	require.Equal(t, []int{1, -1}, r.SortOrders)
+}
@@ -0,0 +1,254 @@
+package mongo
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+type (
+	// PageCache caches the pages of the FindParams setting it, see FindParams.PageCache
+	PageCache interface {
+		// Get returns the page cached for the key, false if there is none or it expired
+		Get(key PageCacheKey) (CachedPage, bool)
+		// Set caches the page for the key, until ttl elapses unless it is 0
+		Set(key PageCacheKey, page CachedPage, ttl time.Duration)
+		// Invalidate evicts the pages of a collection, e.g. once documents were written to it. The package never
+		// writes to the collections, the callers invalidate them after their writes
+		Invalidate(collectionName string)
+	}
+
+	// PageCacheKey identifies the pages of the same params
+	PageCacheKey struct {
+		CollectionName string
+		// The SHA-256 of the query and sort of the page
+		QueryHash  string
+		Next       string
+		Previous   string
+		Limit      int64
+		SkipPages  int
+		CountTotal bool
+		// The locale and strength of the collation of the page, e.g. resolved from the language of the user
+		Collation string
+		// The SHA-256 of the projection of the page, whatever the order of its fields, empty without projection
+		Projection string
+		// The SHA-256 of the index hint of the page, empty without hint
+		Hint string
+	}
+
+	// CachedPage holds the documents and Cursor of a cached page
+	CachedPage struct {
+		Documents []bson.Raw
+		Cursor    Cursor
+	}
+
+	// LRUPageCache is an in-memory PageCache evicting the least recently used pages beyond its capacity
+	LRUPageCache struct {
+		capacity int
+		mu       sync.Mutex
+		entries  *list.List
+		keys     map[PageCacheKey]*list.Element
+	}
+
+	lruPageCacheEntry struct {
+		key     PageCacheKey
+		page    CachedPage
+		expires time.Time
+	}
+)
+
+var _ PageCache = &LRUPageCache{}
+
+// NewLRUPageCache returns an LRUPageCache holding at most capacity pages
+func NewLRUPageCache(capacity int) *LRUPageCache {
+	return &LRUPageCache{capacity: capacity, entries: list.New(), keys: map[PageCacheKey]*list.Element{}}
+}
+
+// Get implements PageCache
+func (c *LRUPageCache) Get(key PageCacheKey) (CachedPage, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.keys[key]
+	if !ok {
+		return CachedPage{}, false
+	}
+	entry := element.Value.(*lruPageCacheEntry)
+	if !entry.expires.IsZero() && !now().Before(entry.expires) {
+		c.remove(element)
+		return CachedPage{}, false
+	}
+	c.entries.MoveToFront(element)
+	return entry.page, true
+}
+
+// Set implements PageCache
+func (c *LRUPageCache) Set(key PageCacheKey, page CachedPage, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := &lruPageCacheEntry{key: key, page: page}
+	if ttl > time.Duration(0) {
+		entry.expires = now().Add(ttl)
+	}
+	if element, ok := c.keys[key]; ok {
+		element.Value = entry
+		c.entries.MoveToFront(element)
+		return
+	}
+	c.keys[key] = c.entries.PushFront(entry)
+	for c.entries.Len() > c.capacity {
+		c.remove(c.entries.Back())
+	}
+}
+
+// Invalidate implements PageCache
+func (c *LRUPageCache) Invalidate(collectionName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for element := c.entries.Front(); element != nil; {
+		next := element.Next()
+		if element.Value.(*lruPageCacheEntry).key.CollectionName == collectionName {
+			c.remove(element)
+		}
+		element = next
+	}
+}
+
+func (c *LRUPageCache) remove(element *list.Element) {
+	c.entries.Remove(element)
+	delete(c.keys, element.Value.(*lruPageCacheEntry).key)
+}
+
+// pageCacheKey returns the key of the page of the ensured FindParams
+func pageCacheKey(p FindParams) PageCacheKey {
+	collectionName := p.CollectionName
+	if collectionName == "" && p.MongoCollection != nil {
+		collectionName = p.MongoCollection.Name()
+	}
+	var collation string
+	if p.Collation != nil {
+		collation = fmt.Sprintf("%s strength %d", p.Collation.Locale, collationStrength(p.Collation.Strength))
+	}
+	return PageCacheKey{
+		Collation:      collation,
+		CollectionName: collectionName,
+		QueryHash:      pageCacheQueryHash(p),
+		Next:           p.Next,
+		Previous:       p.Previous,
+		Limit:          p.Limit,
+		SkipPages:      p.SkipPages,
+		CountTotal:     p.CountTotal,
+		Projection:     pageCacheHash(p.Projection, true),
+		Hint:           pageCacheHash(p.Hint, false),
+	}
+}
+
+// pageCacheQueryHash returns the SHA-256 of the collection, paginated fields, sort orders and query of p. Unlike
+// the cursor scope, the whole hash is kept as a collision would serve the page of another query.
+func pageCacheQueryHash(p FindParams) string {
+	h := sha256.New()
+	writeQuery(h, p)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// pageCacheHash returns the SHA-256 of the value, a projection or a hint, or the empty string if it's nil. The keys
+// of its documents are sorted if unordered, the order of the keys of an index hint telling the indexes apart.
+func pageCacheHash(v interface{}, unordered bool) string {
+	if v == nil {
+		return ""
+	}
+	// The value is wrapped as only documents can be marshaled
+	data, err := bson.Marshal(bson.M{"v": v})
+	if err != nil {
+		return ""
+	}
+	h := sha256.New()
+	value := bson.Raw(data).Lookup("v")
+	if unordered {
+		writeFingerprint(h, value)
+	} else {
+		h.Write([]byte{byte(value.Type)})
+		h.Write(value.Value)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// findCachedPage fills results with the page of the ensured FindParams cached in their PageCache, querying and
+// caching it when it isn't
+func findCachedPage(ctx context.Context, p FindParams, results interface{}) (Cursor, error) {
+	key := pageCacheKey(p)
+	if page, ok := p.PageCache.Get(key); ok {
+		documents := make([]interface{}, len(page.Documents))
+		for i, document := range page.Documents {
+			documents[i] = document
+		}
+		cursor, err := mongodriver.NewCursorFromDocuments(documents, nil, p.encoding().registry)
+		if err != nil {
+			return Cursor{}, err
+		}
+		err = cursor.All(ctx, results)
+		if err != nil {
+			return Cursor{}, fmt.Errorf("could not decode the cached page: %s", err)
+		}
+		return page.Cursor, nil
+	}
+
+	return cachePage(ctx, p, key, results)
+}
+
+// cachePage fills results with the page of the ensured FindParams and caches it in their PageCache for the key
+func cachePage(ctx context.Context, p FindParams, key PageCacheKey, results interface{}) (Cursor, error) {
+	cursor, err := findPage(ctx, p, results)
+	if err != nil {
+		return Cursor{}, err
+	}
+	// A partial page isn't cached as the next query may complete it
+	if cursor.BudgetExceeded {
+		return cursor, nil
+	}
+	resultsVal := reflect.ValueOf(results).Elem()
+	page := CachedPage{Documents: make([]bson.Raw, resultsVal.Len()), Cursor: cursor}
+	for i := range page.Documents {
+		page.Documents[i], err = p.encoding().marshalDocument(resultsVal.Index(i).Interface())
+		if err != nil {
+			return Cursor{}, fmt.Errorf("could not cache the page: %s", err)
+		}
+	}
+	p.PageCache.Set(key, page, p.PageCacheTTL)
+	return cursor, nil
+}
+
+// WarmFirstPages queries the first page of each of the FindParams, e.g. of the hot listings of dashboards on
+// startup or from a cron job, and stores it in their PageCache, replacing the page cached for the same key if any,
+// so that the first requests don't wait for the queries. Their count is cached along with the pages of the
+// FindParams setting CountTotal. The Next and Previous cursors of the FindParams are ignored and their
+// CollationResolver is resolved from ctx. The pages that can't be warmed don't stop the others from being warmed,
+// their errors are returned joined.
+func WarmFirstPages(ctx context.Context, params []FindParams) error {
+	var errs []error
+	for i, p := range params {
+		if p.PageCache == nil {
+			errs = append(errs, fmt.Errorf("could not warm the first page of params %d: PageCache can't be nil", i))
+			continue
+		}
+		p.Next = ""
+		p.Previous = ""
+		var documents []bson.Raw
+		p, err := prepareFind(ctx, p, &documents)
+		if err == nil {
+			_, err = cachePage(ctx, p, pageCacheKey(p), &documents)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not warm the first page of params %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
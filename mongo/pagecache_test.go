@@ -0,0 +1,129 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindServesCachedPages(t *testing.T) {
+	items := []Item{{ID: primitive.NewObjectID(), Name: "a"}, {ID: primitive.NewObjectID(), Name: "b"}}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1]}}
+	cache := NewLRUPageCache(10)
+	p := FindParams{Collection: col, CollectionName: "items", Limit: 1, PageCache: cache}
+
+	var results []Item
+	cursor, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, items[:1], results)
+
+	// The page is served from the cache once the collection changed
+	col.docs = []interface{}{items[1]}
+	var cached []Item
+	cachedCursor, err := Find(context.Background(), p, &cached)
+	require.NoError(t, err)
+	require.Equal(t, items[:1], cached)
+	require.Equal(t, cursor, cachedCursor)
+
+	// But not for another limit
+	p.Limit = 2
+	_, err = Find(context.Background(), p, &cached)
+	require.NoError(t, err)
+	require.Equal(t, items[1:], cached)
+
+	// Nor once the collection was invalidated
+	p.Limit = 1
+	cache.Invalidate("items")
+	_, err = Find(context.Background(), p, &cached)
+	require.NoError(t, err)
+	require.Equal(t, items[1:], cached)
+}
+
+func TestWarmFirstPages(t *testing.T) {
+	items := []Item{{ID: primitive.NewObjectID(), Name: "a"}, {ID: primitive.NewObjectID(), Name: "b"}}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1]}}
+	cache := NewLRUPageCache(10)
+	p := FindParams{Collection: col, CollectionName: "items", Limit: 1, PageCache: cache}
+
+	// The warmed pages are the first ones, whatever the cursor of the params
+	warmed := p
+	warmed.Next = "ignored"
+	err := WarmFirstPages(context.Background(), []FindParams{warmed, {Collection: col, Limit: 1}})
+	require.EqualError(t, err, "could not warm the first page of params 1: PageCache can't be nil")
+
+	col.docs = []interface{}{items[1]}
+	var results []Item
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, items[:1], results)
+
+	// Warming again refreshes the cached page
+	require.NoError(t, WarmFirstPages(context.Background(), []FindParams{p}))
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, items[1:], results)
+}
+
+func TestLRUPageCache(t *testing.T) {
+	defer func() { now = time.Now }()
+	current := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return current }
+
+	cache := NewLRUPageCache(2)
+	a := PageCacheKey{CollectionName: "items", Limit: 1}
+	b := PageCacheKey{CollectionName: "items", Limit: 2}
+	c := PageCacheKey{CollectionName: "others", Limit: 1}
+	cache.Set(a, CachedPage{Cursor: Cursor{Next: "a"}}, 0)
+	cache.Set(b, CachedPage{Cursor: Cursor{Next: "b"}}, time.Minute)
+
+	// The least recently used page is evicted beyond the capacity
+	_, ok := cache.Get(a)
+	require.True(t, ok)
+	cache.Set(c, CachedPage{Cursor: Cursor{Next: "c"}}, 0)
+	_, ok = cache.Get(b)
+	require.False(t, ok)
+	page, ok := cache.Get(a)
+	require.True(t, ok)
+	require.Equal(t, "a", page.Cursor.Next)
+
+	// The pages expire after their ttl
+	cache.Set(b, CachedPage{Cursor: Cursor{Next: "b"}}, time.Minute)
+	current = current.Add(time.Minute)
+	_, ok = cache.Get(b)
+	require.False(t, ok)
+
+	// The invalidated collection's pages are evicted
+	cache.Set(c, CachedPage{Cursor: Cursor{Next: "c"}}, 0)
+	cache.Invalidate("items")
+	_, ok = cache.Get(a)
+	require.False(t, ok)
+	_, ok = cache.Get(c)
+	require.True(t, ok)
+}
+
+func TestPageCacheKey(t *testing.T) {
+	p := ensureMandatoryParams(FindParams{CollectionName: "items", Query: bson.M{"name": "a"}, Limit: 1})
+	key := pageCacheKey(p)
+	require.Len(t, key.QueryHash, 64)
+	require.Empty(t, key.Projection)
+	require.Empty(t, key.Hint)
+
+	// The pages of other projections or hints have other keys, whatever the order of the fields of a projection
+	projected := p
+	projected.Projection = bson.D{{Key: "name", Value: 1}, {Key: "count", Value: 1}}
+	reordered := p
+	reordered.Projection = bson.D{{Key: "count", Value: 1}, {Key: "name", Value: 1}}
+	require.NotEqual(t, key, pageCacheKey(projected))
+	require.Equal(t, pageCacheKey(projected), pageCacheKey(reordered))
+
+	hinted := p
+	hinted.Hint = bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}
+	otherIndex := p
+	otherIndex.Hint = bson.D{{Key: "_id", Value: 1}, {Key: "name", Value: 1}}
+	require.NotEqual(t, key, pageCacheKey(hinted))
+	require.NotEqual(t, pageCacheKey(hinted), pageCacheKey(otherIndex))
+}
@@ -0,0 +1,120 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Paginate returns the page of docs requested by r and its Cursor, applying the cursor semantics of Find to a slice.
+// The cursors are interchangeable with the ones of a Find call with the same pagination parameters, so Paginate
+// can serve as a test oracle for Find or page through small cached datasets. The docs don't need to be sorted.
+// Documents are ordered on the tuple of their paginated fields' values, as mongo sorts them.
+// Cursor.Count is set to the total number of docs.
+func Paginate[T any](docs []T, r PageRequest) ([]T, Cursor, error) {
+	p := ensureMandatoryParams(r.Apply(FindParams{}))
+	err := p.encoding().validate(&docs, p.PaginatedFields)
+	if err != nil {
+		return nil, Cursor{}, err
+	}
+	err = validateCursorTypes(&docs, p)
+	if err != nil {
+		return nil, Cursor{}, err
+	}
+
+	if p.Limit <= 0 {
+		return nil, Cursor{}, errors.New("a limit of at least 1 is required")
+	}
+
+	cursorValues, err := parseCursor(p.Next, len(p.PaginatedFields), p.cursorCodec())
+	if err != nil {
+		return nil, Cursor{}, &CursorError{fmt.Errorf("next cursor parse failed: %w", err)}
+	}
+	if p.Next == "" {
+		cursorValues, err = parseCursor(p.Previous, len(p.PaginatedFields), p.cursorCodec())
+		if err != nil {
+			return nil, Cursor{}, &CursorError{fmt.Errorf("previous cursor parse failed: %w", err)}
+		}
+	}
+
+	// Previous pages are fetched in reverse order, as in the mongo query
+	generateComparisonOps(p)
+
+	type keyedDoc struct {
+		doc T
+		key []interface{}
+	}
+	keyed := make([]keyedDoc, 0, len(docs))
+	for _, doc := range docs {
+		key, err := paginatedValues(doc, p)
+		if err != nil {
+			return nil, Cursor{}, err
+		}
+		if len(cursorValues) > 0 {
+			c := compareKeys(key, cursorValues, p.SortOrders)
+			if c < 0 || (c == 0 && !p.IncludeAnchor) {
+				continue
+			}
+		}
+		keyed = append(keyed, keyedDoc{doc: doc, key: key})
+	}
+	sort.SliceStable(keyed, func(i, j int) bool {
+		return compareKeys(keyed[i].key, keyed[j].key, p.SortOrders) < 0
+	})
+
+	// Get an additional element to see if there's another page
+	if int64(len(keyed)) > p.Limit+1 {
+		keyed = keyed[:p.Limit+1]
+	}
+	page := make([]T, 0, len(keyed))
+	for _, k := range keyed {
+		page = append(page, k.doc)
+	}
+
+	hasMore := len(page) > int(p.Limit)
+	page = orderPage(p, page)
+	first, last := firstAndLast(page)
+	cursor, err := pageCursor(p, first, last, hasMore)
+	if err != nil {
+		return nil, Cursor{}, err
+	}
+	cursor.Count = len(docs)
+	return page, cursor, nil
+}
+
+// paginatedValues returns the values of the paginated fields of doc, as they would be decoded from a cursor
+func paginatedValues(doc interface{}, p FindParams) ([]interface{}, error) {
+	data, err := p.encoding().marshalDocument(doc)
+	if err != nil {
+		return nil, err
+	}
+	var fields bson.D
+	err = bson.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(p.PaginatedFields))
+	for i, field := range p.PaginatedFields {
+		for _, e := range fields {
+			if e.Key == field {
+				values[i] = e.Value
+				break
+			}
+		}
+	}
+	return values, nil
+}
+
+// compareKeys compares the paginated field values a and b in the specified sort orders
+func compareKeys(a, b []interface{}, sortOrders []int) int {
+	for i := range a {
+		c := CompareValues(a[i], b[i])
+		if c != 0 {
+			return c * sortOrders[i]
+		}
+	}
+	return 0
+}
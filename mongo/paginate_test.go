@@ -0,0 +1,169 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPaginate(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "b", CreatedAt: now},
+		{ID: primitive.NewObjectID(), Name: "a", CreatedAt: now.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), Name: "c", CreatedAt: now.Add(2 * time.Hour)},
+		{ID: primitive.NewObjectID(), Name: "b", CreatedAt: now.Add(3 * time.Hour)},
+		{ID: primitive.NewObjectID(), Name: "d", CreatedAt: now.Add(4 * time.Hour)},
+	}
+	r := PageRequest{Limit: 2, PaginatedField: "name", SortAscending: true}
+
+	page, cursor, err := Paginate(items, r)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[1], items[0]}, page)
+	require.Equal(t, 5, cursor.Count)
+	require.True(t, cursor.HasNext)
+	require.False(t, cursor.HasPrevious)
+
+	// The cursors are interchangeable with Find's
+	expectedNext, err := GenerateCursor(items[0], FindParams{PaginatedField: "name", SortAscending: true})
+	require.NoError(t, err)
+	require.Equal(t, expectedNext, cursor.Next)
+
+	r.Next = cursor.Next
+	page, cursor, err = Paginate(items, r)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[3], items[2]}, page)
+	require.True(t, cursor.HasNext)
+	require.True(t, cursor.HasPrevious)
+
+	r.Next = ""
+	r.Previous = cursor.Previous
+	page, cursor, err = Paginate(items, r)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[1], items[0]}, page)
+	require.True(t, cursor.HasNext)
+	require.False(t, cursor.HasPrevious)
+
+	r.Previous = ""
+	r.Next = cursor.Next
+	r.IncludeAnchor = true
+	page, _, err = Paginate(items, r)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[0], items[3]}, page)
+}
+
+func TestPaginateMultipleFieldsDescending(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a", CreatedAt: now},
+		{ID: primitive.NewObjectID(), Name: "a", CreatedAt: now.Add(time.Hour)},
+		{ID: primitive.NewObjectID(), Name: "b", CreatedAt: now},
+	}
+	r := PageRequest{Limit: 2, PaginatedFields: []string{"name", "createdAt"}, SortOrders: []int{1, -1}}
+
+	page, cursor, err := Paginate(items, r)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[1], items[0]}, page)
+	require.True(t, cursor.HasNext)
+
+	r.Next = cursor.Next
+	page, cursor, err = Paginate(items, r)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[2]}, page)
+	require.False(t, cursor.HasNext)
+	require.True(t, cursor.HasPrevious)
+}
+
+func TestPaginateErrors(t *testing.T) {
+	items := []Item{{ID: primitive.NewObjectID(), Name: "a"}}
+
+	_, _, err := Paginate(items, PageRequest{Limit: 0})
+	require.EqualError(t, err, "a limit of at least 1 is required")
+
+	_, _, err = Paginate(items, PageRequest{Limit: 1, PaginatedField: "invalid"})
+	require.Equal(t, NewErrPaginatedFieldNotFound("invalid"), err)
+
+	_, _, err = Paginate(items, PageRequest{Limit: 1, Next: "invalid"})
+	require.IsType(t, &CursorError{}, err)
+
+	next, err := GenerateCursor(struct {
+		ID int `bson:"_id"`
+	}{ID: 1}, FindParams{})
+	require.NoError(t, err)
+	_, _, err = Paginate(items, PageRequest{Limit: 1, Next: next})
+	var mismatch *ErrCursorTypeMismatch
+	require.ErrorAs(t, err, &mismatch)
+}
+
+func TestCompareValues(t *testing.T) {
+	id := primitive.NewObjectID()
+	require.Equal(t, -1, CompareValues(int32(1), 1.5))
+	require.Equal(t, 0, CompareValues(int64(2), 2.0))
+	require.Equal(t, 1, CompareValues("b", "a"))
+	require.Equal(t, -1, CompareValues(nil, "a"))
+	require.Equal(t, -1, CompareValues(int64(10), "1"))
+	require.Equal(t, 0, CompareValues(id, id))
+	require.Equal(t, 1, CompareValues(primitive.DateTime(2), primitive.DateTime(1)))
+	small, _ := primitive.ParseDecimal128("1.0000000000000000000000000001")
+	large, _ := primitive.ParseDecimal128("1.0000000000000000000000000002")
+	require.Equal(t, -1, CompareValues(small, large))
+	require.Equal(t, 1, CompareValues(small, int32(1)))
+	ten, _ := primitive.ParseDecimal128("1.0E1")
+	require.Equal(t, 0, CompareValues(ten, int64(10)))
+	require.Equal(t, -1, CompareValues(ten, 10.5))
+	require.Equal(t, -1, CompareValues(primitive.Timestamp{T: 1, I: 2}, primitive.Timestamp{T: 2, I: 1}))
+	require.Equal(t, -1, CompareValues(bson.D{{Key: "b", Value: int32(1)}}, bson.D{{Key: "a", Value: "x"}}))
+	require.Equal(t, 1, CompareValues(bson.D{{Key: "b", Value: int32(1)}}, bson.D{{Key: "a", Value: int64(2)}}))
+	require.Equal(t, -1, CompareValues(bson.D{{Key: "a", Value: int32(1)}}, bson.D{{Key: "a", Value: 1.5}}))
+	require.True(t, ComparableValues(int32(1), 2.0))
+	require.False(t, ComparableValues(int32(1), "1"))
+	require.True(t, ComparableValues("1", primitive.MaxKey{}))
+	require.True(t, ComparableValues(primitive.MinKey{}, int32(1)))
+}
+
+func TestPaginateCustomBSONTypes(t *testing.T) {
+	type payment struct {
+		ID     primitive.ObjectID   `bson:"_id"`
+		Amount primitive.Decimal128 `bson:"amount"`
+		TS     primitive.Timestamp  `bson:"ts"`
+		UUID   primitive.Binary     `bson:"uuid"`
+	}
+	var payments []payment
+	for i, amount := range []string{"1.0000000000000000000000000003", "1.0000000000000000000000000001", "1.0000000000000000000000000002"} {
+		d, err := primitive.ParseDecimal128(amount)
+		require.NoError(t, err)
+		payments = append(payments, payment{
+			ID:     primitive.NewObjectID(),
+			Amount: d,
+			TS:     primitive.Timestamp{T: 1700000000, I: uint32(3 - i)},
+			UUID:   primitive.Binary{Subtype: 0x04, Data: []byte{15: byte(i)}},
+		})
+	}
+
+	for _, tc := range []struct {
+		field    string
+		expected []payment
+	}{
+		{field: "amount", expected: []payment{payments[1], payments[2], payments[0]}},
+		{field: "ts", expected: []payment{payments[2], payments[1], payments[0]}},
+		{field: "uuid", expected: []payment{payments[0], payments[1], payments[2]}},
+	} {
+		t.Run(tc.field, func(t *testing.T) {
+			r := PageRequest{Limit: 1, PaginatedField: tc.field, SortAscending: true}
+			var pages []payment
+			for {
+				page, cursor, err := Paginate(payments, r)
+				require.NoError(t, err)
+				pages = append(pages, page...)
+				if !cursor.HasNext {
+					break
+				}
+				r.Next = cursor.Next
+			}
+			require.Equal(t, tc.expected, pages)
+		})
+	}
+}
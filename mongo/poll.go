@@ -0,0 +1,64 @@
+package mongo
+
+import (
+	"context"
+	"reflect"
+	"time"
+)
+
+type (
+	// IdleBackoff returns the delay before polling a feed again after the attempt-th empty poll, attempt starting
+	// at 1
+	IdleBackoff func(attempt int) time.Duration
+
+	// LongPoll holds how PollFeedHead waits for the documents added to the head of a feed
+	LongPoll struct {
+		// How long the feed is polled before returning an empty page, e.g. a bit less than the request timeout of
+		// a long-poll endpoint. The feed is polled once if it is 0
+		MaxWait time.Duration
+		// The delay between the polls, ExponentialIdleBackoff(100*time.Millisecond, 5*time.Second) if nil
+		Backoff IdleBackoff
+	}
+)
+
+// ExponentialIdleBackoff returns an IdleBackoff doubling the delay between the polls from initial up to max, so
+// that idle feeds are polled less and less often
+func ExponentialIdleBackoff(initial, max time.Duration) IdleBackoff {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		return min(delay, max)
+	}
+}
+
+// PollFeedHead long-polls the head of a feed for the documents added since the Head of the last FeedCursor, as
+// FindFeedHead does, polling it again after the delays of the IdleBackoff while it is empty. The empty page found
+// once the MaxWait elapsed is returned with the same Head, for the client to poll again from the same position.
+// The context bounds the polls as well, its error being returned once it is done.
+func PollFeedHead(ctx context.Context, p FindParams, since string, results interface{}, poll LongPoll) (FeedCursor, error) {
+	backoff := poll.Backoff
+	if backoff == nil {
+		backoff = ExponentialIdleBackoff(100*time.Millisecond, 5*time.Second)
+	}
+	deadline := now().Add(poll.MaxWait)
+	for attempt := 1; ; attempt++ {
+		feedCursor, err := FindFeedHead(ctx, p, since, results)
+		if err != nil {
+			return FeedCursor{}, err
+		}
+		remaining := deadline.Sub(now())
+		if reflect.ValueOf(results).Elem().Len() > 0 || remaining <= 0 {
+			return feedCursor, nil
+		}
+
+		timer := time.NewTimer(min(backoff(attempt), remaining))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return FeedCursor{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package mongo
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// validateProjection returns an ErrProjectionExcludesField if the Projection of the ensured p leaves out a
+// paginated field of the returned documents, the cursors of the page then holding its zero value. A projection that
+// can't be marshaled is left to the driver to report.
+func validateProjection(p FindParams) error {
+	if p.Projection == nil {
+		return nil
+	}
+	data, err := bson.Marshal(p.Projection)
+	if err != nil {
+		return nil
+	}
+	elements, err := bson.Raw(data).Elements()
+	if err != nil {
+		return nil
+	}
+
+	// An inclusion projection only returns the fields it lists along with the _id
+	inclusion := false
+	excluded := make(map[string]bool, len(elements))
+	listed := make(map[string]bool, len(elements))
+	for _, element := range elements {
+		key, value := element.Key(), element.Value()
+		include, isFlag := projectionFlag(value)
+		switch {
+		case isFlag && !include:
+			excluded[key] = true
+		case key != "_id" && (isFlag || value.Type != bson.TypeEmbeddedDocument):
+			inclusion = true
+			listed[key] = true
+		default:
+			listed[key] = true
+		}
+	}
+
+	for _, field := range p.PaginatedFields {
+		if field == "_id" {
+			if excluded[field] {
+				return NewErrProjectionExcludesField(field)
+			}
+			continue
+		}
+		if projectionHasPath(excluded, field) || (inclusion && !projectionHasPath(listed, field)) {
+			return NewErrProjectionExcludesField(field)
+		}
+	}
+	return nil
+}
+
+// projectionFlag returns whether the projection value includes its field, and false if it's an expression rather
+// than a boolean or a number
+func projectionFlag(value bson.RawValue) (include bool, isFlag bool) {
+	switch value.Type {
+	case bson.TypeBoolean:
+		return value.Boolean(), true
+	case bson.TypeInt32:
+		return value.Int32() != 0, true
+	case bson.TypeInt64:
+		return value.Int64() != 0, true
+	case bson.TypeDouble:
+		return value.Double() != 0, true
+	}
+	return false, false
+}
+
+// projectionHasPath returns true if the field or one of its parents is one of the paths
+func projectionHasPath(paths map[string]bool, field string) bool {
+	for {
+		if paths[field] {
+			return true
+		}
+		i := strings.LastIndexByte(field, '.')
+		if i < 0 {
+			return false
+		}
+		field = field[:i]
+	}
+}
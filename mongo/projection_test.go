@@ -0,0 +1,47 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestValidateProjection(t *testing.T) {
+	cases := []struct {
+		name            string
+		paginatedFields []string
+		projection      interface{}
+		excluded        string
+	}{
+		{"no projection", []string{"name", "_id"}, nil, ""},
+		{"inclusion", []string{"name", "_id"}, bson.D{{Key: "name", Value: 1}}, ""},
+		{"inclusion of a parent", []string{"meta.createdAt", "_id"}, bson.M{"meta": true}, ""},
+		{"inclusion without the paginated field", []string{"name", "_id"}, bson.D{{Key: "count", Value: 1}}, "name"},
+		{"inclusion of a computed field", []string{"name", "_id"}, bson.M{"name": bson.M{"$toLower": "$title"}}, ""},
+		{"_id excluded", []string{"name", "_id"}, bson.D{{Key: "_id", Value: 0}, {Key: "name", Value: 1}}, "_id"},
+		{"_id excluded with a unique field", []string{"email"}, bson.D{{Key: "_id", Value: 0}, {Key: "email", Value: 1}}, ""},
+		{"exclusion", []string{"name", "_id"}, bson.M{"count": 0}, ""},
+		{"exclusion of the paginated field", []string{"name", "_id"}, bson.M{"name": false}, "name"},
+		{"exclusion of a parent", []string{"meta.createdAt", "_id"}, bson.M{"meta": 0.0}, "meta.createdAt"},
+		{"slice", []string{"name", "_id"}, bson.M{"tags": bson.M{"$slice": 2}}, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := validateProjection(FindParams{PaginatedFields: c.paginatedFields, Projection: c.projection})
+			if c.excluded == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, NewErrProjectionExcludesField(c.excluded), err)
+		})
+	}
+
+	// Find fails before querying
+	collection := &fakeCollection{}
+	var results []Item
+	_, err := Find(context.Background(), FindParams{Collection: collection, Limit: 2, PaginatedField: "name", Projection: bson.M{"_id": 0}}, &results)
+	require.Equal(t, NewErrProjectionExcludesField("_id"), err)
+	require.Nil(t, collection.filter)
+}
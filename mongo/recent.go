@@ -0,0 +1,33 @@
+package mongo
+
+import (
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// RecentFirstTimeField is the time field of the FindParams returned by RecentFirst when none is given
+	RecentFirstTimeField = "createdAt"
+	// RecentFirstLimit is the Limit of the FindParams returned by RecentFirst
+	RecentFirstLimit = 20
+)
+
+// RecentFirst returns the FindParams paginating the documents of the collection from the most recent one, by
+// descending timeField, RecentFirstTimeField if empty, then descending _id, RecentFirstLimit documents at a time.
+// The _id breaks the ties of the documents created at the same time in the same direction, so that the collection
+// only needs a {timeField: -1, _id: -1} index. Set the Query, the Next or Previous cursor of the page or another
+// Limit before passing them to Find.
+func RecentFirst(col *mongodriver.Collection, timeField string) FindParams {
+	if timeField == "" {
+		timeField = RecentFirstTimeField
+	}
+	p := FindParams{
+		MongoCollection: col,
+		Limit:           RecentFirstLimit,
+		PaginatedFields: []string{timeField, "_id"},
+		SortOrders:      []int{-1, -1},
+	}
+	if col != nil {
+		p.CollectionName = col.Name()
+	}
+	return p
+}
@@ -0,0 +1,26 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestRecentFirst(t *testing.T) {
+	collection := &mongodriver.Collection{}
+	p := RecentFirst(collection, "")
+	require.Equal(t, collection, p.MongoCollection)
+	require.Equal(t, int64(RecentFirstLimit), p.Limit)
+	require.Equal(t, []string{"createdAt", "_id"}, p.PaginatedFields)
+	require.Equal(t, []int{-1, -1}, p.SortOrders)
+
+	// The page is sorted from the most recent document, the _id breaking the ties alike
+	p = RecentFirst(nil, "updatedAt")
+	p.Collection = &fakeCollection{}
+	_, sort, err := BuildQueries(context.Background(), p)
+	require.NoError(t, err)
+	require.Equal(t, bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}}, sort)
+}
@@ -0,0 +1,98 @@
+package mongo
+
+import (
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type (
+	// SortSpec declares how the documents of a collection may be sorted.
+	SortSpec struct {
+		// The fields clients may paginate and sort on. _id is always sortable
+		SortableFields []string
+		// The fields paginated and sorted on when FindParams specifies neither PaginatedField nor PaginatedFields
+		DefaultPaginatedFields []string
+		// The sort orders corresponding to DefaultPaginatedFields. Each value must be either 1 or -1
+		DefaultSortOrders []int
+		// The index keys required to efficiently sort on the sortable fields, e.g. to create them at startup
+		RequiredIndexes []bson.D
+	}
+
+	// SortRegistry holds the SortSpec of each collection, by collection name.
+	SortRegistry struct {
+		mu    sync.RWMutex
+		specs map[string]SortSpec
+	}
+)
+
+// DefaultSortRegistry is the SortRegistry consulted by Find for the FindParams' CollectionName
+var DefaultSortRegistry = NewSortRegistry()
+
+// NewSortRegistry returns an empty SortRegistry
+func NewSortRegistry() *SortRegistry {
+	return &SortRegistry{specs: map[string]SortSpec{}}
+}
+
+// RegisterSort declares the SortSpec of the specified collection in the DefaultSortRegistry
+func RegisterSort(collectionName string, spec SortSpec) {
+	DefaultSortRegistry.Register(collectionName, spec)
+}
+
+// Register declares the SortSpec of the specified collection, replacing any previous declaration
+func (r *SortRegistry) Register(collectionName string, spec SortSpec) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.specs[collectionName] = spec
+}
+
+// Lookup returns the SortSpec of the specified collection and true if one was declared
+func (r *SortRegistry) Lookup(collectionName string) (SortSpec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	spec, ok := r.specs[collectionName]
+	return spec, ok
+}
+
+// applySortSpec applies the default sort of the collection's SortSpec when p doesn't specify one and otherwise
+// verifies that all the paginated fields are sortable
+func applySortSpec(p FindParams, r *SortRegistry) (FindParams, error) {
+	if p.CollectionName == "" {
+		return p, nil
+	}
+	spec, ok := r.Lookup(p.CollectionName)
+	if !ok {
+		return p, nil
+	}
+
+	if p.PaginatedField == "" && len(p.PaginatedFields) == 0 {
+		if len(spec.DefaultPaginatedFields) > 0 {
+			p.PaginatedFields = append([]string{}, spec.DefaultPaginatedFields...)
+			p.SortOrders = append([]int{}, spec.DefaultSortOrders...)
+		}
+		return p, nil
+	}
+
+	paginatedFields := p.PaginatedFields
+	if len(paginatedFields) == 0 {
+		paginatedFields = []string{p.PaginatedField}
+	}
+	for _, paginatedField := range paginatedFields {
+		if !isSortable(spec, paginatedField, p.tiebreaker()) {
+			return p, NewErrUnsortableField(paginatedField)
+		}
+	}
+	return p, nil
+}
+
+func isSortable(spec SortSpec, field string, tiebreaker string) bool {
+	if field == "_id" || field == tiebreaker {
+		return true
+	}
+	for _, sortableField := range spec.SortableFields {
+		if sortableField == field {
+			return true
+		}
+	}
+	return false
+}
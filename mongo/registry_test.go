@@ -0,0 +1,83 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestApplySortSpec(t *testing.T) {
+	registry := NewSortRegistry()
+	registry.Register("items", SortSpec{
+		SortableFields:         []string{"name", "createdAt"},
+		DefaultPaginatedFields: []string{"createdAt"},
+		DefaultSortOrders:      []int{-1},
+	})
+
+	var cases = []struct {
+		name               string
+		findParams         FindParams
+		expectedFindParams FindParams
+		expectedErr        error
+	}{
+		{
+			name:               "ignores find params without a collection name",
+			findParams:         FindParams{PaginatedField: "invalid"},
+			expectedFindParams: FindParams{PaginatedField: "invalid"},
+			expectedErr:        nil,
+		},
+		{
+			name:               "ignores collections without a sort spec",
+			findParams:         FindParams{CollectionName: "others", PaginatedField: "invalid"},
+			expectedFindParams: FindParams{CollectionName: "others", PaginatedField: "invalid"},
+			expectedErr:        nil,
+		},
+		{
+			name:               "applies the default sort when none is specified",
+			findParams:         FindParams{CollectionName: "items"},
+			expectedFindParams: FindParams{CollectionName: "items", PaginatedFields: []string{"createdAt"}, SortOrders: []int{-1}},
+			expectedErr:        nil,
+		},
+		{
+			name:               "accepts sortable paginated fields",
+			findParams:         FindParams{CollectionName: "items", PaginatedFields: []string{"name", "_id"}},
+			expectedFindParams: FindParams{CollectionName: "items", PaginatedFields: []string{"name", "_id"}},
+			expectedErr:        nil,
+		},
+		{
+			name:               "errors when the paginated field is not sortable",
+			findParams:         FindParams{CollectionName: "items", PaginatedField: "data"},
+			expectedFindParams: FindParams{CollectionName: "items", PaginatedField: "data"},
+			expectedErr:        NewErrUnsortableField("data"),
+		},
+		{
+			name:               "errors when one of the paginated fields is not sortable",
+			findParams:         FindParams{CollectionName: "items", PaginatedFields: []string{"name", "data"}},
+			expectedFindParams: FindParams{CollectionName: "items", PaginatedFields: []string{"name", "data"}},
+			expectedErr:        NewErrUnsortableField("data"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := applySortSpec(tc.findParams, registry)
+			require.Equal(t, tc.expectedFindParams, p)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestFindConsultsDefaultSortRegistry(t *testing.T) {
+	RegisterSort("registry_test_items", SortSpec{SortableFields: []string{"name"}})
+	defer delete(DefaultSortRegistry.specs, "registry_test_items")
+
+	col := &fakeCollection{}
+	var results []Item
+	_, err := Find(context.Background(), FindParams{Collection: col, CollectionName: "registry_test_items", Limit: 2, PaginatedField: "createdAt"}, &results)
+	require.Equal(t, NewErrUnsortableField("createdAt"), err)
+
+	_, err = Find(context.Background(), FindParams{Collection: col, CollectionName: "registry_test_items", Limit: 2, PaginatedField: "name"}, &results)
+	require.NoError(t, err)
+	require.Equal(t, bson.D{{Key: "name", Value: -1}, {Key: "_id", Value: -1}}, col.findOptions.Sort)
+}
@@ -0,0 +1,63 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+// RetryPolicy retries the count and find queries of a page failing with a transient error, so that callers don't
+// each wrap Find with their own retry logic
+type RetryPolicy struct {
+	// The number of attempts of a query, including the first one. A query is attempted once if it is less than 2
+	MaxAttempts int
+	// The delay before the first retry of a query, doubled for each of the following retries
+	Backoff time.Duration
+	// Whether the error of a query is retryable, defaults to IsRetryableError
+	IsRetryable func(err error) bool
+}
+
+// IsRetryableError returns whether the error of a query is transient, i.e. a network error, a timeout or a server
+// error labeled as retryable. The cursor errors and the errors of a done context aren't retryable.
+func IsRetryableError(err error) bool {
+	var cursorErr *CursorError
+	if errors.As(err, &cursorErr) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if mongodriver.IsNetworkError(err) || mongodriver.IsTimeout(err) {
+		return true
+	}
+	var serverErr mongodriver.ServerError
+	if errors.As(err, &serverErr) {
+		return serverErr.HasErrorLabel("RetryableReadError") || serverErr.HasErrorLabel("TransientTransactionError")
+	}
+	return false
+}
+
+// do runs the query until it succeeds, fails with an error that isn't retryable or runs out of attempts. The
+// query runs once with a nil policy.
+func (r *RetryPolicy) do(ctx context.Context, query func() error) error {
+	err := query()
+	if r == nil {
+		return err
+	}
+	isRetryable := r.IsRetryable
+	if isRetryable == nil {
+		isRetryable = IsRetryableError
+	}
+	backoff := r.Backoff
+	for attempt := 1; err != nil && attempt < r.MaxAttempts && isRetryable(err); attempt++ {
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+		err = query()
+	}
+	return err
+}
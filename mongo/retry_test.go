@@ -0,0 +1,94 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// flakyCollection fails its first find and count queries
+type flakyCollection struct {
+	fakeCollection
+	errs   []error
+	finds  int
+	counts int
+}
+
+func (c *flakyCollection) fail() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	err := c.errs[0]
+	c.errs = c.errs[1:]
+	return err
+}
+
+func (c *flakyCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	c.counts++
+	if err := c.fail(); err != nil {
+		return 0, err
+	}
+	return c.fakeCollection.CountDocuments(ctx, filter, opts...)
+}
+
+func (c *flakyCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (MongoCursor, error) {
+	c.finds++
+	if err := c.fail(); err != nil {
+		return nil, err
+	}
+	return c.fakeCollection.Find(ctx, filter, opts...)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	transient := mongodriver.CommandError{Labels: []string{"RetryableReadError"}}
+	items := []interface{}{Item{ID: primitive.NewObjectID(), Name: "a"}}
+	policy := &RetryPolicy{MaxAttempts: 3}
+
+	// The count and find queries are retried on transient errors
+	col := &flakyCollection{fakeCollection: fakeCollection{docs: items}, errs: []error{transient, transient, nil, transient}}
+	var results []Item
+	cursor, err := Find(context.Background(), FindParams{Collection: col, Limit: 2, CountTotal: true, RetryPolicy: policy}, &results)
+	require.NoError(t, err)
+	require.Equal(t, 1, cursor.Count)
+	require.Len(t, results, 1)
+	require.Equal(t, 3, col.counts)
+	require.Equal(t, 2, col.finds)
+
+	// The attempts are limited
+	col = &flakyCollection{fakeCollection: fakeCollection{docs: items}, errs: []error{transient, transient, transient}}
+	_, err = Find(context.Background(), FindParams{Collection: col, Limit: 2, RetryPolicy: policy}, &results)
+	require.Equal(t, transient, err)
+	require.Equal(t, 3, col.finds)
+
+	// The errors that aren't transient aren't retried, nor are the queries without policy
+	col = &flakyCollection{fakeCollection: fakeCollection{docs: items}, errs: []error{errors.New("unauthorized")}}
+	_, err = Find(context.Background(), FindParams{Collection: col, Limit: 2, RetryPolicy: policy}, &results)
+	require.EqualError(t, err, "unauthorized")
+	require.Equal(t, 1, col.finds)
+	col = &flakyCollection{fakeCollection: fakeCollection{docs: items}, errs: []error{transient}}
+	_, err = Find(context.Background(), FindParams{Collection: col, Limit: 2}, &results)
+	require.Equal(t, transient, err)
+
+	// The policy of the Defaults applies to the queries that don't set one
+	d := NewDefaults()
+	d.RetryPolicy = policy
+	SetDefaults(d)
+	defer SetDefaults(NewDefaults())
+	col = &flakyCollection{fakeCollection: fakeCollection{docs: items}, errs: []error{transient}}
+	_, err = Find(context.Background(), FindParams{Collection: col, Limit: 2}, &results)
+	require.NoError(t, err)
+	require.Equal(t, 2, col.finds)
+}
+
+func TestIsRetryableError(t *testing.T) {
+	require.True(t, IsRetryableError(mongodriver.CommandError{Labels: []string{"RetryableReadError"}}))
+	require.True(t, IsRetryableError(mongodriver.CommandError{Labels: []string{"NetworkError"}}))
+	require.False(t, IsRetryableError(mongodriver.CommandError{Code: 13}))
+	require.False(t, IsRetryableError(&CursorError{errors.New("bad cursor")}))
+	require.False(t, IsRetryableError(context.DeadlineExceeded))
+}
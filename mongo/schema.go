@@ -0,0 +1,88 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+type (
+	// CursorSchema describes the cursors of a query in the default wire format, base64 URL encoded BSON
+	// documents without padding holding the values of the paginated fields in order, so that the implementations
+	// in other languages can verify that they read and write the same cursors, see ValidateToken.
+	CursorSchema struct {
+		// The paginated fields, in order, including the tiebreaker
+		Fields []CursorSchemaField `json:"fields"`
+	}
+
+	// CursorSchemaField describes the value of a paginated field in a cursor
+	CursorSchemaField struct {
+		// The key of the value, the name of the paginated field
+		Name string `json:"name"`
+		// The accepted BSON types of the value, by their $type alias, e.g. "string", "objectId" or "date". Any
+		// type is accepted if empty
+		Types []string `json:"types,omitempty"`
+	}
+)
+
+// bsonTypeAliases are the $type aliases of the BSON types
+var bsonTypeAliases = map[bsontype.Type]string{
+	bson.TypeDouble:           "double",
+	bson.TypeString:           "string",
+	bson.TypeEmbeddedDocument: "object",
+	bson.TypeArray:            "array",
+	bson.TypeBinary:           "binData",
+	bson.TypeUndefined:        "undefined",
+	bson.TypeObjectID:         "objectId",
+	bson.TypeBoolean:          "bool",
+	bson.TypeDateTime:         "date",
+	bson.TypeNull:             "null",
+	bson.TypeRegex:            "regex",
+	bson.TypeDBPointer:        "dbPointer",
+	bson.TypeJavaScript:       "javascript",
+	bson.TypeSymbol:           "symbol",
+	bson.TypeCodeWithScope:    "javascriptWithScope",
+	bson.TypeInt32:            "int",
+	bson.TypeTimestamp:        "timestamp",
+	bson.TypeInt64:            "long",
+	bson.TypeDecimal128:       "decimal",
+	bson.TypeMinKey:           "minKey",
+	bson.TypeMaxKey:           "maxKey",
+}
+
+// ValidateToken verifies that the cursor is a cursor of the schema in the default wire format: a base64 URL
+// encoded BSON document without padding, holding one element per field of the schema, in order, keyed by the
+// field's name and of one of its types. Cursors of a custom CursorCodec can't be validated.
+func ValidateToken(token string, schema CursorSchema) error {
+	data, err := mcpbson.DecodeCursor(token, mcpbson.DefaultMaxCursorSize, mcpbson.DefaultMaxCursorDepth)
+	if err != nil {
+		return &CursorError{fmt.Errorf("cursor parse failed: %s", err)}
+	}
+	elements, err := bson.Raw(data).Elements()
+	if err != nil {
+		return &CursorError{fmt.Errorf("cursor parse failed: %s", err)}
+	}
+	if len(elements) != len(schema.Fields) {
+		if len(schema.Fields) == 1 {
+			return &CursorError{errors.New("expecting a cursor with a single element")}
+		}
+		return &CursorError{fmt.Errorf("expecting a cursor with %d elements", len(schema.Fields))}
+	}
+
+	for i, field := range schema.Fields {
+		key, value := elements[i].Key(), elements[i].Value()
+		if key != field.Name {
+			return &CursorError{fmt.Errorf("cursor element %d is %s where %s is expected", i, key, field.Name)}
+		}
+		alias := bsonTypeAliases[value.Type]
+		if len(field.Types) > 0 && !slices.Contains(field.Types, alias) {
+			return &CursorError{NewErrCursorTypeMismatch(field.Name, strings.Join(field.Types, " or "), alias)}
+		}
+	}
+	return nil
+}
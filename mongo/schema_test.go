@@ -0,0 +1,144 @@
+package mongo
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var updateVectors = flag.Bool("update-vectors", false, "regenerate testdata/cursor_vectors.json")
+
+const cursorVectorsFile = "cursor_vectors.json"
+
+type (
+	// cursorVector is a test vector of the cross-language cursor corpus
+	cursorVector struct {
+		Name   string       `json:"name"`
+		Token  string       `json:"token"`
+		Schema CursorSchema `json:"schema"`
+		// The canonical extended JSON of the cursor document, absent if the token isn't a BSON document
+		Document json.RawMessage `json:"document,omitempty"`
+		Valid    bool            `json:"valid"`
+		// The error of ValidateToken for the invalid tokens
+		Error string `json:"error,omitempty"`
+	}
+)
+
+// generateCursorVectors returns the vectors of the corpus, their cursors being generated by the package
+func generateCursorVectors(t *testing.T) []cursorVector {
+	objectID, err := primitive.ObjectIDFromHex("5addf533e81549de7696cb04")
+	require.NoError(t, err)
+	date := time.Date(2024, 2, 29, 12, 30, 45, 123000000, time.UTC)
+	decimal, err := primitive.ParseDecimal128("1234.5678")
+	require.NoError(t, err)
+	uuid := primitive.Binary{Subtype: 4, Data: []byte{0x6f, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e, 0x4f, 0x60, 0x81, 0x92, 0xa3, 0xb4, 0xc5, 0xd6, 0xe7, 0xf8}}
+
+	field := func(name string, types ...string) CursorSchemaField {
+		return CursorSchemaField{Name: name, Types: types}
+	}
+	valid := func(name string, doc bson.D, fields ...CursorSchemaField) cursorVector {
+		paginatedFields := make([]string, 0, len(doc))
+		for _, e := range doc {
+			paginatedFields = append(paginatedFields, e.Key)
+		}
+		token, err := GenerateCursor(doc, FindParams{PaginatedFields: paginatedFields})
+		require.NoError(t, err)
+		return cursorVector{Name: name, Token: token, Schema: CursorSchema{Fields: fields}, Valid: true}
+	}
+	invalid := func(name string, token string, fields ...CursorSchemaField) cursorVector {
+		return cursorVector{Name: name, Token: token, Schema: CursorSchema{Fields: fields}}
+	}
+	nameAndID := valid("string and objectId", bson.D{{Key: "name", Value: "test item 1"}, {Key: "_id", Value: objectID}},
+		field("name", "string"), field("_id", "objectId"))
+
+	vectors := []cursorVector{
+		valid("objectId", bson.D{{Key: "_id", Value: objectID}}, field("_id", "objectId")),
+		nameAndID,
+		valid("empty string", bson.D{{Key: "name", Value: ""}, {Key: "_id", Value: objectID}}, field("name", "string"), field("_id", "objectId")),
+		valid("unicode string", bson.D{{Key: "name", Value: "Ünïcødé 名前 🚀"}, {Key: "_id", Value: objectID}}, field("name", "string"), field("_id", "objectId")),
+		valid("int", bson.D{{Key: "count", Value: int32(-42)}, {Key: "_id", Value: objectID}}, field("count", "int"), field("_id", "objectId")),
+		valid("long bounds", bson.D{{Key: "min", Value: int64(math.MinInt64)}, {Key: "max", Value: int64(math.MaxInt64)}, {Key: "_id", Value: objectID}},
+			field("min", "long"), field("max", "long"), field("_id", "objectId")),
+		valid("double", bson.D{{Key: "score", Value: 0.1}, {Key: "_id", Value: objectID}}, field("score", "double", "int", "long"), field("_id", "objectId")),
+		valid("double infinity", bson.D{{Key: "score", Value: math.Inf(-1)}, {Key: "_id", Value: objectID}}, field("score", "double"), field("_id", "objectId")),
+		valid("decimal", bson.D{{Key: "price", Value: decimal}, {Key: "_id", Value: objectID}}, field("price", "decimal"), field("_id", "objectId")),
+		valid("date", bson.D{{Key: "createdAt", Value: primitive.NewDateTimeFromTime(date)}, {Key: "_id", Value: objectID}}, field("createdAt", "date"), field("_id", "objectId")),
+		valid("timestamp", bson.D{{Key: "ts", Value: primitive.Timestamp{T: 1709209845, I: 7}}, {Key: "_id", Value: objectID}}, field("ts", "timestamp"), field("_id", "objectId")),
+		valid("bool", bson.D{{Key: "done", Value: true}, {Key: "_id", Value: objectID}}, field("done", "bool"), field("_id", "objectId")),
+		valid("uuid tiebreaker", bson.D{{Key: "name", Value: "a"}, {Key: "uid", Value: uuid}}, field("name", "string"), field("uid", "binData")),
+		valid("embedded document", bson.D{{Key: "owner", Value: bson.D{{Key: "z", Value: "1"}, {Key: "a", Value: int32(2)}}}, {Key: "_id", Value: objectID}},
+			field("owner", "object"), field("_id", "objectId")),
+		valid("array", bson.D{{Key: "tags", Value: bson.A{"x", int32(1)}}, {Key: "_id", Value: objectID}}, field("tags", "array"), field("_id", "objectId")),
+		valid("any type", bson.D{{Key: "name", Value: int64(7)}, {Key: "_id", Value: "string id"}}, field("name"), field("_id")),
+
+		invalid("type mismatch", nameAndID.Token, field("name", "int", "long"), field("_id", "objectId")),
+		invalid("missing element", nameAndID.Token, field("name", "string"), field("createdAt", "date"), field("_id", "objectId")),
+		invalid("extra element", nameAndID.Token, field("_id", "objectId")),
+		invalid("key mismatch", nameAndID.Token, field("_id", "objectId"), field("name", "string")),
+		invalid("padded base64", nameAndID.Token+"=", field("name", "string"), field("_id", "objectId")),
+		invalid("standard base64", "ab+/", field("name", "string")),
+		invalid("truncated document", nameAndID.Token[:len(nameAndID.Token)-8], field("name", "string"), field("_id", "objectId")),
+	}
+
+	for i := range vectors {
+		data, err := base64.RawURLEncoding.DecodeString(vectors[i].Token)
+		if err == nil && bson.Raw(data).Validate() == nil {
+			document, err := bson.MarshalExtJSON(bson.Raw(data), true, false)
+			require.NoError(t, err)
+			vectors[i].Document = document
+		}
+		err = ValidateToken(vectors[i].Token, vectors[i].Schema)
+		require.Equal(t, vectors[i].Valid, err == nil, vectors[i].Name)
+		if err != nil {
+			vectors[i].Error = err.Error()
+		}
+	}
+	return vectors
+}
+
+func TestCursorVectors(t *testing.T) {
+	generated, err := json.MarshalIndent(generateCursorVectors(t), "", "  ")
+	require.NoError(t, err)
+	generated = append(generated, '\n')
+	path := filepath.Join("testdata", cursorVectorsFile)
+	if *updateVectors {
+		require.NoError(t, os.WriteFile(path, generated, 0o644))
+	}
+
+	// The corpus is what the other implementations verify, it must not drift from the cursors of the package
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, string(generated), string(data), "run go test -run TestCursorVectors -update-vectors to regenerate the corpus")
+	var corpus []cursorVector
+	require.NoError(t, json.Unmarshal(data, &corpus))
+
+	for _, v := range corpus {
+		t.Run(v.Name, func(t *testing.T) {
+			err := ValidateToken(v.Token, v.Schema)
+			if !v.Valid {
+				require.EqualError(t, err, v.Error)
+				return
+			}
+			require.NoError(t, err)
+
+			// The token round trips through the extended JSON document, as the other implementations read it
+			var doc bson.Raw
+			require.NoError(t, bson.UnmarshalExtJSON(v.Document, true, &doc))
+			require.Equal(t, v.Token, base64.RawURLEncoding.EncodeToString(doc))
+
+			// and decodes into the cursor values of the package
+			cursorValues, err := parseCursor(v.Token, len(v.Schema.Fields), Base64CursorCodec{})
+			require.NoError(t, err)
+			require.Len(t, cursorValues, len(v.Schema.Fields))
+		})
+	}
+}
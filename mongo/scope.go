@@ -0,0 +1,113 @@
+package mongo
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// cursorScopeKey is the key of the cursor element holding the scope of a cursor bound to its query
+const cursorScopeKey = "$scope"
+
+// scopedCursorCodec binds the cursors of a CursorCodec to a scope, rejecting the cursors of other scopes. The scope
+// is only as tamper proof as the wrapped CursorCodec
+type scopedCursorCodec struct {
+	codec CursorCodec
+	scope string
+}
+
+// Encode implements CursorCodec
+func (c scopedCursorCodec) Encode(cursorData bson.D) (string, error) {
+	scoped := make(bson.D, 0, len(cursorData)+1)
+	scoped = append(scoped, cursorData...)
+	return c.codec.Encode(append(scoped, bson.E{Key: cursorScopeKey, Value: c.scope}))
+}
+
+// Decode implements CursorCodec
+func (c scopedCursorCodec) Decode(cursor string) (bson.D, error) {
+	cursorData, err := c.codec.Decode(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if len(cursorData) == 0 {
+		return nil, NewErrCursorScopeMismatch()
+	}
+	last := cursorData[len(cursorData)-1]
+	if last.Key != cursorScopeKey || last.Value != c.scope {
+		return nil, NewErrCursorScopeMismatch()
+	}
+	return cursorData[:len(cursorData)-1], nil
+}
+
+// cursorScope returns the scope of the cursors of p, computed when the mandatory params are ensured
+func (p FindParams) cursorScope() string {
+	if p.scope == "" {
+		return ensureMandatoryParams(p).scope
+	}
+	return p.scope
+}
+
+// computeCursorScope hashes the collection, paginated fields, sort orders and query of p
+func computeCursorScope(p FindParams) string {
+	h := sha256.New()
+	writeQuery(h, p)
+	return hex.EncodeToString(h.Sum(nil)[:8])
+}
+
+// writeQuery writes the collection, paginated fields, sort orders and the fingerprint of the query of p to the hash
+func writeQuery(h hash.Hash, p FindParams) {
+	collectionName := p.CollectionName
+	if collectionName == "" && p.MongoCollection != nil {
+		collectionName = p.MongoCollection.Name()
+	}
+	writeString(h, collectionName)
+	for i := range p.PaginatedFields {
+		writeString(h, p.PaginatedFields[i])
+		_ = binary.Write(h, binary.BigEndian, int8(p.SortOrders[i]))
+	}
+	// The query is wrapped as marshaling a nil query fails
+	query, err := bson.Marshal(bson.M{"q": p.Query})
+	if err == nil {
+		writeFingerprint(h, bson.Raw(query).Lookup("q"))
+	}
+}
+
+// writeFingerprint writes a canonical form of the value to the hash, where the keys of the documents are sorted
+// so that the fingerprint doesn't depend on the iteration order of maps
+func writeFingerprint(h hash.Hash, v bson.RawValue) {
+	h.Write([]byte{byte(v.Type)})
+	switch v.Type {
+	case bson.TypeEmbeddedDocument:
+		elements, err := v.Document().Elements()
+		if err != nil {
+			return
+		}
+		sort.Slice(elements, func(i, j int) bool { return elements[i].Key() < elements[j].Key() })
+		_ = binary.Write(h, binary.BigEndian, int32(len(elements)))
+		for _, e := range elements {
+			writeString(h, e.Key())
+			writeFingerprint(h, e.Value())
+		}
+	case bson.TypeArray:
+		values, err := v.Array().Values()
+		if err != nil {
+			return
+		}
+		_ = binary.Write(h, binary.BigEndian, int32(len(values)))
+		for _, value := range values {
+			writeFingerprint(h, value)
+		}
+	default:
+		h.Write(v.Value)
+	}
+}
+
+// writeString writes the length prefixed string to the hash
+func writeString(h hash.Hash, s string) {
+	_ = binary.Write(h, binary.BigEndian, int32(len(s)))
+	h.Write([]byte(s))
+}
@@ -0,0 +1,64 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindBindsCursorScope(t *testing.T) {
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+	}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1]}}
+	query := bson.M{"name": bson.M{"$in": bson.A{"a", "b"}}, "data": bson.M{"$exists": false}}
+	p := FindParams{Collection: col, CollectionName: "items", Query: query, Limit: 1, PaginatedField: "name", BindCursorScope: true}
+
+	var results []Item
+	cursor, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+
+	// The cursor can be reused with the same params
+	p.Next = cursor.Next
+	cursor, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	p.Next = ""
+	p.Previous = cursor.Previous
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+
+	// It is rejected by another query
+	for _, other := range []FindParams{
+		{Collection: col, CollectionName: "items", Query: bson.M{"name": "a"}, Limit: 1, PaginatedField: "name", BindCursorScope: true},
+		{Collection: col, CollectionName: "others", Query: query, Limit: 1, PaginatedField: "name", BindCursorScope: true},
+		{Collection: col, CollectionName: "items", Query: query, Limit: 1, PaginatedField: "name", SortAscending: true, BindCursorScope: true},
+	} {
+		other.Previous = cursor.Previous
+		_, err = Find(context.Background(), other, &results)
+		require.IsType(t, &CursorError{}, err)
+		var mismatch *ErrCursorScopeMismatch
+		require.ErrorAs(t, err, &mismatch)
+	}
+
+	// Unbound cursors are rejected as well
+	next, err := GenerateCursor(items[0], FindParams{PaginatedField: "name"})
+	require.NoError(t, err)
+	p.Previous = ""
+	p.Next = next
+	_, err = Find(context.Background(), p, &results)
+	require.EqualError(t, err, "next cursor parse failed: cursor was issued for another query")
+}
+
+func TestCursorScopeIgnoresKeyOrder(t *testing.T) {
+	p := ensureMandatoryParams(FindParams{Query: bson.M{"a": 1, "b": bson.M{"c": 2, "d": 3, "e": 4}, "f": 5}})
+	scope := computeCursorScope(p)
+	for i := 0; i < 10; i++ {
+		require.Equal(t, scope, computeCursorScope(p))
+	}
+	p.Query = bson.M{"a": 1, "b": bson.M{"c": 2, "d": 3, "e": 5}, "f": 5}
+	require.NotEqual(t, scope, computeCursorScope(p))
+}
@@ -0,0 +1,118 @@
+package mongo
+
+import (
+	"errors"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ScoreField is the name of the field added by ScoreStage to hold the computed score of each document
+const ScoreField = "_score"
+
+// nonDeterministicOperators are the aggregation operators and variables that may evaluate differently between
+// pages, which would make documents skip pages or appear twice
+var nonDeterministicOperators = map[string]bool{
+	"$rand":          true,
+	"$sampleRate":    true,
+	"$function":      true,
+	"$accumulator":   true,
+	"$$NOW":          true,
+	"$$CLUSTER_TIME": true,
+}
+
+// ScoreWeight is the weight of a numeric field in a weighted score
+type ScoreWeight struct {
+	Field  string
+	Weight float64
+}
+
+// WeightedScore returns the aggregation expression summing the weighted values of the fields. Missing fields
+// count as 0.
+func WeightedScore(weights ...ScoreWeight) (bson.M, error) {
+	if len(weights) == 0 {
+		return nil, errors.New("at least one weight is required")
+	}
+	terms := make(bson.A, 0, len(weights))
+	for _, w := range weights {
+		if w.Field == "" {
+			return nil, errors.New("a weighted field name can't be empty")
+		}
+		terms = append(terms, bson.M{"$multiply": bson.A{bson.M{"$ifNull": bson.A{"$" + w.Field, 0}}, w.Weight}})
+	}
+	return bson.M{"$add": terms}, nil
+}
+
+// ScoreStage returns an $addFields stage setting ScoreField to the score expression. The expression must be
+// deterministic so the score of a document is the same on every page: it must only depend on fields that don't
+// change while paginating, and can't use $rand, $sampleRate, $function, $accumulator, $$NOW or $$CLUSTER_TIME.
+func ScoreStage(expression interface{}) (bson.M, error) {
+	err := validateDeterministic(reflect.ValueOf(expression))
+	if err != nil {
+		return nil, err
+	}
+	return bson.M{"$addFields": bson.M{ScoreField: expression}}, nil
+}
+
+// Score returns p set up to paginate the documents output by its pipeline by descending score, computed by the
+// expression, e.g. one returned by WeightedScore, then by descending TiebreakerField, the _id by default. The score
+// and tiebreaker of the boundary documents are encoded in the cursors.
+func Score(p AggregateParams, expression interface{}) (AggregateParams, error) {
+	stage, err := ScoreStage(expression)
+	if err != nil {
+		return p, err
+	}
+	pipeline := make([]bson.M, 0, len(p.Pipeline)+1)
+	pipeline = append(pipeline, p.Pipeline...)
+	p.Pipeline = append(pipeline, stage)
+	p.PaginatedField = ""
+	// The tie-breaker is compared in the score's direction by the cursor query, so sort it alike
+	p.PaginatedFields = []string{ScoreField, p.findParams().tiebreaker()}
+	p.SortOrders = []int{-1, -1}
+	return p, nil
+}
+
+// validateDeterministic walks the expression looking for non deterministic operators
+func validateDeterministic(v reflect.Value) error {
+	if !v.IsValid() {
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return validateDeterministic(v.Elem())
+	case reflect.String:
+		if nonDeterministicOperators[v.String()] {
+			return NewErrNonDeterministicExpression(v.String())
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			if iter.Key().Kind() == reflect.String && nonDeterministicOperators[iter.Key().String()] {
+				return NewErrNonDeterministicExpression(iter.Key().String())
+			}
+			err := validateDeterministic(iter.Value())
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := validateDeterministic(v.Index(i))
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		// The elements of a bson.D
+		if e, ok := v.Interface().(bson.E); ok {
+			if nonDeterministicOperators[e.Key] {
+				return NewErrNonDeterministicExpression(e.Key)
+			}
+			return validateDeterministic(reflect.ValueOf(e.Value))
+		}
+	}
+	return nil
+}
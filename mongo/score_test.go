@@ -0,0 +1,89 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestScore(t *testing.T) {
+	type scoredItem struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Name  string             `bson:"name"`
+		Score float64            `bson:"_score"`
+	}
+	items := []scoredItem{
+		{ID: primitive.NewObjectID(), Name: "a", Score: 9.5},
+		{ID: primitive.NewObjectID(), Name: "b", Score: 4},
+		{ID: primitive.NewObjectID(), Name: "c", Score: 1},
+	}
+	expression, err := WeightedScore(ScoreWeight{Field: "likes", Weight: 2}, ScoreWeight{Field: "views", Weight: 0.5})
+	require.NoError(t, err)
+	require.Equal(t, bson.M{"$add": bson.A{
+		bson.M{"$multiply": bson.A{bson.M{"$ifNull": bson.A{"$likes", 0}}, 2.0}},
+		bson.M{"$multiply": bson.A{bson.M{"$ifNull": bson.A{"$views", 0}}, 0.5}},
+	}}, expression)
+
+	col := &fakeCollection{docs: []interface{}{items[0], items[1], items[2]}}
+	p, err := Score(AggregateParams{Collection: col, Limit: 2}, expression)
+	require.NoError(t, err)
+
+	var results []Item
+	cursor, err := Aggregate(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{{ID: items[0].ID, Name: "a"}, {ID: items[1].ID, Name: "b"}}, results)
+	require.True(t, cursor.HasNext)
+
+	col.docs = col.docs[2:]
+	p.Next = cursor.Next
+	_, err = Aggregate(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []bson.M{
+		{"$addFields": bson.M{ScoreField: expression}},
+		{"$match": bson.M{"$or": []map[string]interface{}{
+			{"_score": map[string]interface{}{"$lt": 4.0}},
+			{"$and": []map[string]interface{}{
+				{"_score": map[string]interface{}{"$lte": 4.0}},
+				{"_id": map[string]interface{}{"$lt": items[1].ID}},
+			}},
+		}}},
+		{"$sort": bson.D{{Key: "_score", Value: -1}, {Key: "_id", Value: -1}}},
+		{"$limit": int64(3)},
+	}, col.pipeline)
+}
+
+func TestScoreStageRejectsNonDeterministicExpressions(t *testing.T) {
+	var cases = []struct {
+		name       string
+		expression interface{}
+		operator   string
+	}{
+		{
+			name:       "$rand operator",
+			expression: bson.M{"$multiply": bson.A{"$likes", bson.M{"$rand": bson.M{}}}},
+			operator:   "$rand",
+		},
+		{
+			name:       "$$NOW variable",
+			expression: bson.D{{Key: "$subtract", Value: bson.A{"$$NOW", "$createdAt"}}},
+			operator:   "$$NOW",
+		},
+		{
+			name:       "$function operator",
+			expression: bson.M{"$function": bson.M{"body": "function() { return 1 }", "args": bson.A{}, "lang": "js"}},
+			operator:   "$function",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ScoreStage(tc.expression)
+			require.Equal(t, NewErrNonDeterministicExpression(tc.operator), err)
+		})
+	}
+
+	_, err := WeightedScore()
+	require.EqualError(t, err, "at least one weight is required")
+}
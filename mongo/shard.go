@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// shardKeyBounds returns the range predicates of the shard key fields implied by the cursor query, so that mongos
+// targets the shards owning the chunks past the cursor instead of broadcasting the query. Every paginated field
+// but the tiebreaker is past or tied with its cursor value, the fields of a hashed shard key can't be bounded.
+// An ErrUntargetableSort is returned when neither the Query nor the bounds constrain the first shard key field.
+func shardKeyBounds(p FindParams, comparisonOps []string, cursorValues []interface{}) (bson.M, error) {
+	hashed := make(map[string]bool, len(p.ShardKey))
+	for _, e := range p.ShardKey {
+		switch e.Value {
+		case 1, int32(1), int64(1), float64(1):
+		case "hashed":
+			hashed[e.Key] = true
+		default:
+			return nil, fmt.Errorf("invalid order %v of the shard key field %s, must be 1 or \"hashed\"", e.Value, e.Key)
+		}
+	}
+
+	first := p.ShardKey[0].Key
+	_, matched := p.Query[first]
+	if !matched && (hashed[first] || !isPaginatedField(p, first)) {
+		return nil, NewErrUntargetableSort(first, hashed[first])
+	}
+
+	if cursorValues == nil {
+		return nil, nil
+	}
+	var bounds bson.M
+	last := len(p.PaginatedFields) - 1
+	for i := 0; i < last; i++ {
+		paginatedField := p.PaginatedFields[i]
+		if !inShardKey(p.ShardKey, paginatedField) || hashed[paginatedField] {
+			continue
+		}
+		if bounds == nil {
+			bounds = bson.M{}
+		}
+		bounds[paginatedField] = bson.M{comparisonOps[i] + "e": cursorValues[i]}
+	}
+	return bounds, nil
+}
+
+// inShardKey returns whether the field is part of the shard key
+func inShardKey(shardKey bson.D, field string) bool {
+	for _, e := range shardKey {
+		if e.Key == field {
+			return true
+		}
+	}
+	return false
+}
+
+// isPaginatedField returns whether the field is one of the paginated fields of p
+func isPaginatedField(p FindParams, field string) bool {
+	for _, paginatedField := range p.PaginatedFields {
+		if paginatedField == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,29 @@
+package mongo
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ShuffleField is the name of the field added by ShuffleStage to hold the seeded hash of each document
+const ShuffleField = "_shuffle"
+
+// ShuffleStage returns an $addFields stage setting ShuffleField to the hash of the seed and the document's _id.
+// Sorting on it gives a random order that is stable for a given seed. This requires a mongo server supporting the
+// $toHashedIndexKey aggregation operator.
+func ShuffleStage(seed string) bson.M {
+	return bson.M{"$addFields": bson.M{
+		ShuffleField: bson.M{"$toHashedIndexKey": bson.M{"$concat": bson.A{seed, bson.M{"$toString": "$_id"}}}},
+	}}
+}
+
+// Shuffle returns p set up to paginate the documents output by its pipeline in a random order that remains
+// consistent across pages as long as the same seed is used, e.g. a per session seed token.
+func Shuffle(p AggregateParams, seed string) AggregateParams {
+	pipeline := make([]bson.M, 0, len(p.Pipeline)+1)
+	pipeline = append(pipeline, p.Pipeline...)
+	p.Pipeline = append(pipeline, ShuffleStage(seed))
+	p.PaginatedField = ""
+	p.PaginatedFields = []string{ShuffleField}
+	p.SortOrders = []int{1}
+	return p
+}
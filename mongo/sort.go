@@ -0,0 +1,49 @@
+package mongo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseSort returns the PaginatedFields and SortOrders of the sort of an API request, a comma separated list of
+// fields each prefixed with - to sort in descending order or optionally + to sort in ascending order, e.g.
+// "-createdAt,+name". The fields must be among the sortable fields, e.g. the SortableFields of the SortSpec of the
+// collection, the _id always being sortable, or an ErrUnsortableField is returned. The whitespace around the
+// fields is trimmed, the + of a query string being decoded as a space. The _id being unique, it must be the last
+// field as the following ones would never be compared, and the _id would be appended again as the tiebreaker. An
+// empty sort returns no fields, so that the default sort applies.
+func ParseSort(sort string, sortableFields ...string) ([]string, []int, error) {
+	if strings.TrimSpace(sort) == "" {
+		return nil, nil, nil
+	}
+	spec := SortSpec{SortableFields: sortableFields}
+	terms := strings.Split(sort, ",")
+	paginatedFields := make([]string, 0, len(terms))
+	sortOrders := make([]int, 0, len(terms))
+	for i, term := range terms {
+		field := strings.TrimSpace(term)
+		order := 1
+		if strings.HasPrefix(field, "-") {
+			field, order = field[1:], -1
+		} else if strings.HasPrefix(field, "+") {
+			field = field[1:]
+		}
+		if field == "" {
+			return nil, nil, fmt.Errorf("sort field %d is empty", i)
+		}
+		if !isSortable(spec, field, "_id") {
+			return nil, nil, NewErrUnsortableField(field)
+		}
+		for _, paginatedField := range paginatedFields {
+			if paginatedField == field {
+				return nil, nil, fmt.Errorf("field %s is sorted on twice", field)
+			}
+			if paginatedField == "_id" {
+				return nil, nil, fmt.Errorf("field _id must be sorted on last, it is followed by %s", field)
+			}
+		}
+		paginatedFields = append(paginatedFields, field)
+		sortOrders = append(sortOrders, order)
+	}
+	return paginatedFields, sortOrders, nil
+}
@@ -0,0 +1,41 @@
+package mongo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort(t *testing.T) {
+	cases := []struct {
+		sort            string
+		paginatedFields []string
+		sortOrders      []int
+		err             string
+	}{
+		{"", nil, nil, ""},
+		{"  ", nil, nil, ""},
+		{"-createdAt,+name", []string{"createdAt", "name"}, []int{-1, 1}, ""},
+		{"name, -createdAt", []string{"name", "createdAt"}, []int{1, -1}, ""},
+		{" name,-_id", []string{"name", "_id"}, []int{1, -1}, ""},
+		{"-createdAt,,name", nil, nil, "sort field 1 is empty"},
+		{"-", nil, nil, "sort field 0 is empty"},
+		{"-secret", nil, nil, "field secret is not sortable"},
+		{"--createdAt", nil, nil, "field -createdAt is not sortable"},
+		{"name,-name", nil, nil, "field name is sorted on twice"},
+		{"-_id,name", nil, nil, "field _id must be sorted on last, it is followed by name"},
+		{"_id,-_id", nil, nil, "field _id is sorted on twice"},
+	}
+	for _, c := range cases {
+		t.Run(c.sort, func(t *testing.T) {
+			paginatedFields, sortOrders, err := ParseSort(c.sort, "createdAt", "name")
+			if c.err != "" {
+				require.EqualError(t, err, c.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, c.paginatedFields, paginatedFields)
+			require.Equal(t, c.sortOrders, sortOrders)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// splitSamplesPerPartition is the number of documents sampled per partition to bound the ranges of SplitFind
+const splitSamplesPerPartition = 32
+
+// SplitFind splits the documents matched by the query of the provided FindParams into at most partitions
+// non-overlapping ranges of their sort order and returns the FindParams of each range, in order, e.g. for the
+// workers of a large export to consume them in parallel. The ranges are bounded by the paginated fields of a
+// $sample of the documents, so they hold about the same number of documents, and fewer ranges are returned when
+// the collection has too few distinct documents. Each FindParams pages its range with Find as any other, its
+// cursors resuming a worker. The Collection must also be an AggregateCollection.
+func SplitFind(ctx context.Context, p FindParams, partitions int) ([]FindParams, error) {
+	if partitions < 1 {
+		return nil, errors.New("at least 1 partition is required")
+	}
+	ensured, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return nil, err
+	}
+	ensured = ensureMandatoryParams(ensured)
+	ensured.Next = ""
+	ensured.Previous = ""
+	ensured.IncludeAnchor = false
+	p.Next = ""
+	p.Previous = ""
+	if partitions == 1 {
+		return []FindParams{p}, nil
+	}
+	collection, ok := ensured.Collection.(AggregateCollection)
+	if !ok {
+		return nil, errors.New("Collection must be an AggregateCollection to be split")
+	}
+
+	boundaries, err := sampleBoundaries(ctx, collection, ensured, partitions)
+	if err != nil {
+		return nil, err
+	}
+
+	// The range of a partition goes from the boundary of the previous one, exclusive, until its own, inclusive
+	split := make([]FindParams, 0, len(boundaries)+1)
+	from := bson.M{}
+	for _, boundary := range append(boundaries, "") {
+		var until bson.M
+		if boundary != "" {
+			untilParams := ensured
+			untilParams.Previous = boundary
+			untilParams.IncludeAnchor = true
+			until, _, err = buildCursorQuery(untilParams)
+			if err != nil {
+				return nil, err
+			}
+		}
+		partition := p
+		partition.Query = MergeQueries([]bson.M{p.Query, from, until})
+		split = append(split, partition)
+
+		fromParams := ensured
+		fromParams.Next = boundary
+		from, _, err = buildCursorQuery(fromParams)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return split, nil
+}
+
+// sampleBoundaries returns the cursors of the distinct sampled documents bounding the ranges of the partitions,
+// in the sort order of p
+func sampleBoundaries(ctx context.Context, c AggregateCollection, p FindParams, partitions int) ([]string, error) {
+	pipeline := []bson.M{{"$sample": bson.M{"size": partitions * splitSamplesPerPartition}}}
+	if len(p.Query) > 0 {
+		pipeline = append([]bson.M{{"$match": p.Query}}, pipeline...)
+	}
+	var samples []bson.Raw
+	err := executeAggregateQuery(ctx, c, pipeline, p.Collation, p.Hint, p.Timeout, nil, &samples)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([][]interface{}, len(samples))
+	for i, sample := range samples {
+		keys[i], err = paginatedValues(sample, p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	order := make([]int, len(samples))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return compareKeys(keys[order[i]], keys[order[j]], p.SortOrders) < 0
+	})
+
+	var boundaries []string
+	var last []interface{}
+	for i := 1; i < partitions; i++ {
+		if len(order) == 0 {
+			break
+		}
+		sample := order[i*len(order)/partitions]
+		if last != nil && compareKeys(keys[sample], last, p.SortOrders) == 0 {
+			continue
+		}
+		boundary, err := generateCursor(samples[sample], p)
+		if err != nil {
+			return nil, fmt.Errorf("could not create a partition boundary: %s", err)
+		}
+		boundaries = append(boundaries, boundary)
+		last = keys[sample]
+	}
+	return boundaries, nil
+}
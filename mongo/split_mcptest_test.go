@@ -0,0 +1,56 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSplitFindPartitionsTheDocuments(t *testing.T) {
+	var docs []interface{}
+	for i := 0; i < 40; i++ {
+		docs = append(docs, mcptest.Item{ID: primitive.NewObjectID(), Name: "item", Count: i % 7})
+	}
+	col, err := mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+
+	p := mongo.FindParams{Collection: col, Limit: 4, PaginatedField: "count", Query: bson.M{"name": "item"}}
+	split, err := mongo.SplitFind(context.Background(), p, 3)
+	require.NoError(t, err)
+	require.Len(t, split, 3)
+
+	// Paging the partitions one after the other yields the documents of the query in order, once
+	var expected, actual []primitive.ObjectID
+	var results []mcptest.Item
+	for cursor := (mongo.Cursor{HasNext: true}); cursor.HasNext; p.Next = cursor.Next {
+		cursor, err = mongo.Find(context.Background(), p, &results)
+		require.NoError(t, err)
+		for _, i := range results {
+			expected = append(expected, i.ID)
+		}
+	}
+	for _, partition := range split {
+		var partitionIDs []primitive.ObjectID
+		for cursor := (mongo.Cursor{HasNext: true}); cursor.HasNext; partition.Next = cursor.Next {
+			cursor, err = mongo.Find(context.Background(), partition, &results)
+			require.NoError(t, err)
+			for _, i := range results {
+				partitionIDs = append(partitionIDs, i.ID)
+			}
+		}
+		require.NotEmpty(t, partitionIDs)
+		actual = append(actual, partitionIDs...)
+	}
+	require.Equal(t, expected, actual)
+
+	split, err = mongo.SplitFind(context.Background(), p, 1)
+	require.NoError(t, err)
+	require.Equal(t, []mongo.FindParams{p}, split)
+	_, err = mongo.SplitFind(context.Background(), p, 0)
+	require.EqualError(t, err, "at least 1 partition is required")
+}
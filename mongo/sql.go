@@ -0,0 +1,180 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SQLDialect selects the placeholders and identifier quoting of a SQLKeyset
+type SQLDialect int
+
+const (
+	// SQLPostgres numbers the placeholders, e.g. $1, and quotes the identifiers with double quotes
+	SQLPostgres SQLDialect = iota
+	// SQLMySQL uses ? placeholders and quotes the identifiers with backticks
+	SQLMySQL
+)
+
+type (
+	// SQLParams holds the parameters to render a cursor to SQL
+	SQLParams struct {
+		Dialect SQLDialect
+		// Columns maps the paginated fields, including _id, to the columns of the SQL table. A field that isn't mapped
+		// is its own column.
+		Columns map[string]string
+		// ArgOffset is the number of arguments of the query preceding the keyset predicate, so that the Postgres
+		// placeholders are numbered after them
+		ArgOffset int
+	}
+
+	// SQLKeyset is the keyset predicate and sort of a page in SQL, matching the same rows of a SQL replica as the
+	// cursor query and sort of FindParams match documents, to combine with the WHERE clause and LIMIT of a query,
+	// e.g. "SELECT * FROM items WHERE tenant = $1 AND " + keyset.Where + " ORDER BY " + keyset.OrderBy
+	SQLKeyset struct {
+		// Where is the predicate matching the rows past the cursor, empty without cursor
+		Where string
+		// Args are the arguments of the placeholders of Where
+		Args []interface{}
+		// OrderBy is the sort of the page. As with Find, a previous page is sorted in reverse and its rows must be
+		// reversed to be in order.
+		OrderBy string
+	}
+)
+
+// ToSQLKeyset renders the Next or Previous cursor of the provided FindParams to a parameterized SQL keyset
+// predicate, for hybrid systems paging a mongo collection and a SQL replica with the same pagination tokens.
+// ObjectIDs are strings of their hex representation, dates are time.Time values and decimals are strings.
+func ToSQLKeyset(p FindParams, sp SQLParams) (SQLKeyset, error) {
+	p, err := applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return SQLKeyset{}, err
+	}
+	p = ensureMandatoryParams(p)
+
+	cursor := p.Next
+	if cursor == "" {
+		cursor = p.Previous
+	}
+	cursorValues, err := parseCursor(cursor, len(p.PaginatedFields), p.cursorCodec())
+	if err != nil {
+		return SQLKeyset{}, &CursorError{fmt.Errorf("cursor parse failed: %w", err)}
+	}
+
+	// The sort orders are reversed in place for a previous page, don't share them with the caller
+	p.SortOrders = append([]int{}, p.SortOrders...)
+	comparisonOps := generateComparisonOps(p)
+
+	r := sqlRenderer{params: sp}
+	orderBy := make([]string, len(p.PaginatedFields))
+	for i, paginatedField := range p.PaginatedFields {
+		direction := "ASC"
+		if p.SortOrders[i] == -1 {
+			direction = "DESC"
+		}
+		orderBy[i] = r.column(paginatedField) + " " + direction
+	}
+	keyset := SQLKeyset{OrderBy: strings.Join(orderBy, ", ")}
+	if cursor == "" {
+		return keyset, nil
+	}
+
+	for i, value := range cursorValues {
+		cursorValues[i], err = toSQLArg(value)
+		if err != nil {
+			return SQLKeyset{}, fmt.Errorf("could not convert the value of %s: %s", p.PaginatedFields[i], err)
+		}
+	}
+	keyset.Where = r.predicate(p.PaginatedFields, comparisonOps, cursorValues, p.IncludeAnchor)
+	keyset.Args = r.args
+	return keyset, nil
+}
+
+// sqlRenderer renders the keyset predicate of a SQLDialect, collecting its arguments
+type sqlRenderer struct {
+	params SQLParams
+	args   []interface{}
+}
+
+// predicate renders the same predicate as the cursor query of the paginated fields: a field past its cursor
+// value, or tied with it and the _id past the cursor's, for each field besides the _id
+func (r *sqlRenderer) predicate(paginatedFields []string, comparisonOps []string, cursorValues []interface{}, inclusive bool) string {
+	last := len(paginatedFields) - 1
+	idOp := sqlOperator(comparisonOps[last])
+	if inclusive {
+		idOp += "="
+	}
+	if last == 0 {
+		return r.column(paginatedFields[0]) + " " + idOp + " " + r.arg(cursorValues[0])
+	}
+
+	conditions := make([]string, last)
+	for i := 0; i < last; i++ {
+		op := sqlOperator(comparisonOps[i])
+		column := r.column(paginatedFields[i])
+		fieldIDOp := op
+		if inclusive {
+			fieldIDOp += "="
+		}
+		conditions[i] = fmt.Sprintf("(%s %s %s OR (%s %s= %s AND %s %s %s))",
+			column, op, r.arg(cursorValues[i]),
+			column, op, r.arg(cursorValues[i]),
+			r.column(paginatedFields[last]), fieldIDOp, r.arg(cursorValues[last]))
+	}
+	if len(conditions) == 1 {
+		return conditions[0]
+	}
+	return "(" + strings.Join(conditions, " AND ") + ")"
+}
+
+// column returns the quoted column of the paginated field
+func (r *sqlRenderer) column(paginatedField string) string {
+	column, ok := r.params.Columns[paginatedField]
+	if !ok {
+		column = paginatedField
+	}
+	if r.params.Dialect == SQLMySQL {
+		return "`" + strings.ReplaceAll(column, "`", "``") + "`"
+	}
+	return `"` + strings.ReplaceAll(column, `"`, `""`) + `"`
+}
+
+// arg adds an argument, returning its placeholder
+func (r *sqlRenderer) arg(value interface{}) string {
+	r.args = append(r.args, value)
+	if r.params.Dialect == SQLMySQL {
+		return "?"
+	}
+	return "$" + strconv.Itoa(r.params.ArgOffset+len(r.args))
+}
+
+// sqlOperator returns the SQL operator of a $gt or $lt comparison operator
+func sqlOperator(comparisonOp string) string {
+	if comparisonOp == "$gt" {
+		return ">"
+	}
+	return "<"
+}
+
+// toSQLArg converts a cursor value to a SQL argument
+func toSQLArg(v interface{}) (interface{}, error) {
+	switch v := v.(type) {
+	case string, int32, int64, float64, bool:
+		return v, nil
+	case primitive.ObjectID:
+		return v.Hex(), nil
+	case primitive.DateTime:
+		return v.Time().UTC(), nil
+	case primitive.Decimal128:
+		return v.String(), nil
+	case primitive.Binary:
+		return v.Data, nil
+	case nil:
+		return nil, errors.New("null values can't be compared in SQL")
+	default:
+		return nil, fmt.Errorf("unsupported type %s", bsonTypeName(v))
+	}
+}
@@ -0,0 +1,48 @@
+package mongo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestToSQLKeyset(t *testing.T) {
+	item := Item{ID: primitive.NewObjectID(), Name: "b", CreatedAt: time.UnixMilli(1700000000123).UTC()}
+	p := FindParams{PaginatedFields: []string{"createdAt"}, SortOrders: []int{-1}}
+	cursor, err := GenerateCursor(item, p)
+	require.NoError(t, err)
+	columns := map[string]string{"_id": "id", "createdAt": "created_at"}
+
+	keyset, err := ToSQLKeyset(p, SQLParams{Columns: columns})
+	require.NoError(t, err)
+	require.Equal(t, SQLKeyset{OrderBy: `"created_at" DESC, "id" ASC`}, keyset)
+
+	p.Next = cursor
+	keyset, err = ToSQLKeyset(p, SQLParams{Columns: columns, ArgOffset: 1})
+	require.NoError(t, err)
+	require.Equal(t, `("created_at" < $2 OR ("created_at" <= $3 AND "id" < $4))`, keyset.Where)
+	require.Equal(t, []interface{}{item.CreatedAt, item.CreatedAt, item.ID.Hex()}, keyset.Args)
+	require.Equal(t, `"created_at" DESC, "id" ASC`, keyset.OrderBy)
+
+	// A previous page is sorted in reverse
+	p.Next = ""
+	p.Previous = cursor
+	p.IncludeAnchor = true
+	keyset, err = ToSQLKeyset(p, SQLParams{Dialect: SQLMySQL, Columns: columns})
+	require.NoError(t, err)
+	require.Equal(t, "(`created_at` > ? OR (`created_at` >= ? AND `id` >= ?))", keyset.Where)
+	require.Equal(t, "`created_at` ASC, `id` DESC", keyset.OrderBy)
+
+	p = FindParams{PaginatedFields: []string{"name", "createdAt"}, SortOrders: []int{1, -1}}
+	p.Next, err = GenerateCursor(item, p)
+	require.NoError(t, err)
+	keyset, err = ToSQLKeyset(p, SQLParams{})
+	require.NoError(t, err)
+	require.Equal(t, `(("name" > $1 OR ("name" >= $2 AND "_id" > $3)) AND ("createdAt" < $4 OR ("createdAt" <= $5 AND "_id" < $6)))`, keyset.Where)
+	require.Equal(t, []interface{}{"b", "b", item.ID.Hex(), item.CreatedAt, item.CreatedAt, item.ID.Hex()}, keyset.Args)
+
+	_, err = ToSQLKeyset(FindParams{Next: "invalid"}, SQLParams{})
+	require.IsType(t, &CursorError{}, err)
+}
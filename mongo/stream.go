@@ -0,0 +1,163 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+// StreamRaw executes a find mongo query like Find, but forwards the raw documents of the page to fn as they are
+// read from the mongo cursor's batches instead of decoding them, e.g. to relay them downstream byte for byte. The
+// documents are only valid during the call to fn and must be copied to be retained. The documents of a previous
+// page are read in reverse order, so they are buffered to be forwarded in order. An error returned by fn aborts
+// the query and is returned. The ScanBudget of p isn't supported.
+func StreamRaw(ctx context.Context, p FindParams, fn func(bson.Raw) error) (Cursor, error) {
+	var err error
+	p, err = applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return Cursor{}, err
+	}
+	p = ensureMandatoryParams(p)
+	if p.ScanBudget != nil {
+		return Cursor{}, errors.New("a ScanBudget can't be enforced when streaming")
+	}
+	queryCtx := withServerSelectionTimeout(ctx, p)
+
+	// Compute total count of documents matching filter - only computed if CountTotal is True
+	count, countSource, err := countTotal(p, func() (int, error) {
+		filter, countOptions := buildCountQuery(p)
+		return executeCountQuery(queryCtx, p.Collection, filter, countOptions)
+	})
+	if err != nil {
+		return Cursor{}, err
+	}
+	p = p.carryCount(count, countSource)
+
+	queries, sort, err := BuildQueries(ctx, p)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	options := newFindOptions(sort, p.Limit+1, p.Collation, p.Hint, p.Projection, p.Timeout)
+	if rawOptions := p.rawFindOptions(); rawOptions != nil {
+		rawOptions(options)
+	}
+	findCtx, cancel := serverSelectionContext(queryCtx)
+	mongoCursor, err := p.Collection.Find(findCtx, MergeQueries(queries), options)
+	cancel()
+	if err != nil {
+		return Cursor{}, err
+	}
+	defer mongoCursor.Close(ctx)
+
+	var firstResult, lastResult bson.Raw
+	var buffered []bson.Raw
+	var n int64
+	hasMore := false
+	for mongoCursor.Next(ctx) {
+		// Don't forward the additional element fetched to see if there's another page
+		if n == p.Limit {
+			hasMore = true
+			break
+		}
+		n++
+
+		doc, err := currentRaw(mongoCursor)
+		if err != nil {
+			return Cursor{}, err
+		}
+		if p.Previous != "" {
+			buffered = append(buffered, copyRaw(doc))
+			continue
+		}
+		if firstResult == nil {
+			firstResult = copyRaw(doc)
+		}
+		// The document may not outlive the next call to Next, keep it in a reused buffer
+		lastResult = append(lastResult[:0], doc...)
+		err = fn(doc)
+		if err != nil {
+			return Cursor{}, err
+		}
+	}
+	err = mongoCursor.Err()
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	// Forward the previous page in order
+	if len(buffered) > 0 {
+		firstResult, lastResult = buffered[len(buffered)-1], buffered[0]
+	}
+	for i := len(buffered) - 1; i >= 0; i-- {
+		err = fn(buffered[i])
+		if err != nil {
+			return Cursor{}, err
+		}
+	}
+
+	var first, last interface{}
+	if firstResult != nil {
+		first, last = firstResult, lastResult
+	}
+	cursor, err := pageCursor(p, first, last, hasMore)
+	if err != nil {
+		return Cursor{}, err
+	}
+	cursor.Count, cursor.CountRelation = p.cappedCount(count)
+	cursor.CountSource = countSource
+	return cursor, nil
+}
+
+// StreamRawTo executes StreamRaw, sending copies of the raw documents of the page to ch. It returns the context's
+// error if it is done while waiting on ch. ch isn't closed.
+func StreamRawTo(ctx context.Context, p FindParams, ch chan<- bson.Raw) (Cursor, error) {
+	return StreamRaw(ctx, p, func(doc bson.Raw) error {
+		return send(ctx, ch, copyRaw(doc))
+	})
+}
+
+// StreamTo executes StreamRaw, decoding the documents of the page into T and sending them to docs in order. A
+// document that can't be decoded doesn't abort the query, an *ErrDocumentDecode holding a copy of it is sent to
+// errs instead, so that the decoded documents can be processed as they arrive while the others are logged. The
+// cursors are computed from the raw documents, so they don't depend on whether they could be decoded. It returns
+// the context's error if it is done while waiting on a channel. The channels aren't closed.
+func StreamTo[T any](ctx context.Context, p FindParams, docs chan<- T, errs chan<- error) (Cursor, error) {
+	index := 0
+	return StreamRaw(ctx, p, func(raw bson.Raw) error {
+		i := index
+		index++
+		var doc T
+		err := bson.Unmarshal(raw, &doc)
+		if err != nil {
+			return send(ctx, errs, NewErrDocumentDecode(i, copyRaw(raw), err))
+		}
+		return send(ctx, docs, doc)
+	})
+}
+
+// send sends v to ch, returning the context's error if it is done first
+func send[T any](ctx context.Context, ch chan<- T, v T) error {
+	select {
+	case ch <- v:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// currentRaw returns the raw document the cursor points at, without copying it when it is a driver cursor
+func currentRaw(c MongoCursor) (bson.Raw, error) {
+	if driverCursor, ok := c.(*mongodriver.Cursor); ok {
+		return driverCursor.Current, nil
+	}
+	var doc bson.Raw
+	err := c.Decode(&doc)
+	return doc, err
+}
+
+func copyRaw(doc bson.Raw) bson.Raw {
+	return append(bson.Raw(nil), doc...)
+}
@@ -0,0 +1,110 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestStreamRaw(t *testing.T) {
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1], items[2]}}
+	p := FindParams{Collection: col, Limit: 2, PaginatedField: "name", SortAscending: true, CountTotal: true}
+
+	var names []string
+	cursor, err := StreamRaw(context.Background(), p, func(doc bson.Raw) error {
+		names = append(names, doc.Lookup("name").StringValue())
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, names)
+	require.True(t, cursor.HasNext)
+	require.False(t, cursor.HasPrevious)
+	require.Equal(t, 3, cursor.Count)
+	expectedNext, err := GenerateCursor(items[1], p)
+	require.NoError(t, err)
+	require.Equal(t, expectedNext, cursor.Next)
+
+	// Previous pages are read in reverse order but forwarded in order
+	col.docs = []interface{}{items[1], items[0]}
+	p.Previous, err = GenerateCursor(items[2], p)
+	require.NoError(t, err)
+	ch := make(chan bson.Raw, 2)
+	cursor, err = StreamRawTo(context.Background(), p, ch)
+	require.NoError(t, err)
+	close(ch)
+	names = names[:0]
+	for doc := range ch {
+		names = append(names, doc.Lookup("name").StringValue())
+	}
+	require.Equal(t, []string{"a", "b"}, names)
+	require.False(t, cursor.HasPrevious)
+	require.True(t, cursor.HasNext)
+	require.Equal(t, expectedNext, cursor.Next)
+}
+
+func TestStreamRawAbortsOnCallbackError(t *testing.T) {
+	col := &fakeCollection{docs: []interface{}{Item{ID: primitive.NewObjectID()}, Item{ID: primitive.NewObjectID()}}}
+	expected := errors.New("downstream closed")
+	calls := 0
+	_, err := StreamRaw(context.Background(), FindParams{Collection: col, Limit: 5}, func(bson.Raw) error {
+		calls++
+		return expected
+	})
+	require.Equal(t, expected, err)
+	require.Equal(t, 1, calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = StreamRawTo(ctx, FindParams{Collection: col, Limit: 5}, make(chan bson.Raw))
+	require.Equal(t, context.Canceled, err)
+}
+
+func TestStreamToSendsDecodeErrors(t *testing.T) {
+	type badItem struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Name int                `bson:"name"`
+	}
+	items := []Item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID()},
+	}
+	bad := badItem{ID: primitive.NewObjectID(), Name: 1}
+	col := &fakeCollection{docs: []interface{}{items[0], bad, items[1]}}
+	p := FindParams{Collection: col, Limit: 2}
+
+	docs := make(chan Item, 2)
+	errs := make(chan error, 2)
+	cursor, err := StreamTo(context.Background(), p, docs, errs)
+	require.NoError(t, err)
+	close(docs)
+	close(errs)
+	var results []Item
+	for doc := range docs {
+		results = append(results, doc)
+	}
+	require.Equal(t, items[:1], results)
+	var decodeErrs []error
+	for err := range errs {
+		decodeErrs = append(decodeErrs, err)
+	}
+	require.Len(t, decodeErrs, 1)
+	var decodeErr *ErrDocumentDecode
+	require.ErrorAs(t, decodeErrs[0], &decodeErr)
+	require.Equal(t, 1, decodeErr.Index())
+	require.Equal(t, bad.ID, decodeErr.Raw().Lookup("_id").ObjectID())
+
+	// The cursors are computed from the raw documents
+	expectedNext, err := GenerateCursor(bad, p)
+	require.NoError(t, err)
+	require.True(t, cursor.HasNext)
+	require.Equal(t, expectedNext, cursor.Next)
+}
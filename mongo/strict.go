@@ -0,0 +1,46 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+)
+
+// IndexCheck is whether Find checks that an index of the collection supports the sort of the pages, so that the
+// misconfigured pagination scanning the collection is caught, e.g. in staging
+type IndexCheck int
+
+const (
+	// IndexCheckOff doesn't check the indexes
+	IndexCheckOff IndexCheck = iota
+	// IndexCheckWarn reports the sorts no index supports to the IndexWarning hook of the Defaults, e.g. a logger,
+	// and queries the page anyway
+	IndexCheckWarn
+	// IndexCheckStrict fails with an ErrNoSupportingIndex when no index supports the sort
+	IndexCheckStrict
+)
+
+// checkIndex checks that an index supports the sort of the ensured FindParams according to their IndexCheck
+func checkIndex(ctx context.Context, p FindParams) error {
+	if p.IndexCheck == IndexCheckOff {
+		return nil
+	}
+	indexes := p.Indexes
+	if indexes == nil {
+		if c, ok := p.Collection.(*driverCollection); ok {
+			indexes = c.collection.Indexes()
+		}
+	}
+	if indexes == nil {
+		return errors.New("an IndexCheck requires the Indexes of the Collection")
+	}
+
+	err := validateSupportingIndex(ctx, indexes, p)
+	var noIndex *ErrNoSupportingIndex
+	if p.IndexCheck == IndexCheckWarn && errors.As(err, &noIndex) {
+		if warn := p.settings().IndexWarning; warn != nil {
+			warn(ctx, err)
+		}
+		return nil
+	}
+	return err
+}
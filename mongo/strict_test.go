@@ -0,0 +1,46 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindIndexCheck(t *testing.T) {
+	tenantIndex := bson.M{"name": "tenant_1_name_1__id_1", "key": bson.D{
+		{Key: "tenant", Value: int32(1)}, {Key: "name", Value: int32(1)}, {Key: "_id", Value: int32(1)},
+	}}
+	indexes := &fakeIndexLister{specs: []interface{}{bson.M{"name": "_id_", "key": bson.D{{Key: "_id", Value: int32(1)}}}, tenantIndex}}
+	col := &fakeCollection{docs: []interface{}{Item{ID: primitive.NewObjectID(), Name: "a"}}}
+	p := FindParams{Collection: col, Limit: 2, PaginatedField: "name", IndexCheck: IndexCheckStrict, Indexes: indexes}
+
+	// The index supports the sort once the query matches its leading fields by equality
+	var results []Item
+	_, err := Find(context.Background(), p, &results)
+	require.Equal(t, NewErrNoSupportingIndex(bson.D{{Key: "name", Value: -1}, {Key: "_id", Value: -1}}, "simple"), err)
+	p.Query = bson.M{"tenant": bson.M{"$in": bson.A{"a", "b"}}}
+	_, err = Find(context.Background(), p, &results)
+	require.Error(t, err)
+	p.Query = bson.M{"tenant": "a"}
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+
+	// The sorts no index supports are reported to the IndexWarning hook of the Defaults
+	var warnings []error
+	d := NewDefaults()
+	d.IndexCheck = IndexCheckWarn
+	d.IndexWarning = func(_ context.Context, err error) { warnings = append(warnings, err) }
+	setDefaults(t, d)
+	p = FindParams{Collection: col, Limit: 2, PaginatedField: "data", Indexes: indexes}
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, []error{NewErrNoSupportingIndex(bson.D{{Key: "data", Value: -1}, {Key: "_id", Value: -1}}, "simple")}, warnings)
+
+	p.Indexes = nil
+	_, err = Find(context.Background(), p, &results)
+	require.EqualError(t, err, "an IndexCheck requires the Indexes of the Collection")
+}
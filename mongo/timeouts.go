@@ -0,0 +1,45 @@
+package mongo
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// serverSelectionTimeoutKey is the context key of the ServerSelectionTimeout of the queries of a page
+type serverSelectionTimeoutKey struct{}
+
+// withServerSelectionTimeout returns the context of the queries of a page, carrying the ServerSelectionTimeout
+// of p if set
+func withServerSelectionTimeout(ctx context.Context, p FindParams) context.Context {
+	if p.ServerSelectionTimeout <= time.Duration(0) {
+		return ctx
+	}
+	return context.WithValue(ctx, serverSelectionTimeoutKey{}, p.ServerSelectionTimeout)
+}
+
+// serverSelectionContext returns the context sending a query, bounded by the ServerSelectionTimeout carried by
+// ctx if any, along with the function releasing it once the query returned its first batch. The driver has no
+// per operation server selection timeout, so the deadline bounds both the server selection and the first batch.
+func serverSelectionContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	timeout, ok := ctx.Value(serverSelectionTimeoutKey{}).(time.Duration)
+	if !ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// rawFindOptions returns the function adjusting the options of the find query of a page, setting the
+// MaxAwaitTime of p before applying its RawFindOptions
+func (p FindParams) rawFindOptions() func(*options.FindOptions) {
+	if p.MaxAwaitTime <= time.Duration(0) {
+		return p.RawFindOptions
+	}
+	return func(o *options.FindOptions) {
+		o.SetMaxAwaitTime(p.MaxAwaitTime)
+		if p.RawFindOptions != nil {
+			p.RawFindOptions(o)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// deadlineCollection records the deadlines of the contexts of its find and count queries
+type deadlineCollection struct {
+	fakeCollection
+	findDeadline, countDeadline time.Time
+}
+
+func (c *deadlineCollection) Find(ctx context.Context, filter interface{}, opts ...*options.FindOptions) (MongoCursor, error) {
+	c.findDeadline, _ = ctx.Deadline()
+	return c.fakeCollection.Find(ctx, filter, opts...)
+}
+
+func (c *deadlineCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...*options.CountOptions) (int64, error) {
+	c.countDeadline, _ = ctx.Deadline()
+	return c.fakeCollection.CountDocuments(ctx, filter, opts...)
+}
+
+func TestServerSelectionTimeout(t *testing.T) {
+	col := &deadlineCollection{fakeCollection: fakeCollection{docs: []interface{}{Item{ID: primitive.NewObjectID()}}}}
+	p := FindParams{Collection: col, Limit: 2, CountTotal: true, ServerSelectionTimeout: time.Second}
+
+	var results []Item
+	start := time.Now()
+	_, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.WithinDuration(t, start.Add(time.Second), col.findDeadline, 500*time.Millisecond)
+	require.WithinDuration(t, start.Add(time.Second), col.countDeadline, 500*time.Millisecond)
+
+	col.findDeadline = time.Time{}
+	_, err = StreamRaw(context.Background(), p, func(bson.Raw) error { return nil })
+	require.NoError(t, err)
+	require.False(t, col.findDeadline.IsZero())
+
+	p.ServerSelectionTimeout = 0
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.True(t, col.findDeadline.IsZero())
+	require.True(t, col.countDeadline.IsZero())
+}
+
+func TestMaxAwaitTime(t *testing.T) {
+	col := &fakeCollection{docs: []interface{}{Item{ID: primitive.NewObjectID()}}}
+	rawFindOptions := func(opts *options.FindOptions) { opts.SetCursorType(options.TailableAwait) }
+
+	var results []Item
+	_, err := Find(context.Background(), FindParams{Collection: col, Limit: 2, MaxAwaitTime: time.Second, RawFindOptions: rawFindOptions}, &results)
+	require.NoError(t, err)
+	require.Equal(t, time.Second, *col.findOptions.MaxAwaitTime)
+	require.Equal(t, options.TailableAwait, *col.findOptions.CursorType)
+
+	_, err = Find(context.Background(), FindParams{Collection: col, Limit: 2}, &results)
+	require.NoError(t, err)
+	require.Nil(t, col.findOptions.MaxAwaitTime)
+}
@@ -0,0 +1,158 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FindTimeSeries executes find mongo queries for a page of a time-series or metrics collection paginated on its
+// time field, the first paginated field, fills the passed in result slice pointer and returns a Cursor. Rather
+// than a single query ranging over all the documents past the cursor, the page is fetched bucket by bucket of
+// TimeBucket, each query being bounded by the range of its bucket so that mongo prunes the other buckets and
+// sorts the documents of a single bucket. The empty buckets are skipped. The pages are sorted by descending time
+// unless SortAscending or SortOrders is set, and their cursors are the ones of Find. A page costs about two round
+// trips per bucket it reaches, one finding the time of its first document and one fetching its documents, so a page
+// of sparse documents spread over many buckets costs more than the single query of Find.
+func FindTimeSeries(ctx context.Context, p FindParams, results interface{}) (Cursor, error) {
+	if p.TimeBucket <= 0 {
+		return Cursor{}, errors.New("a TimeBucket of at least 1ns is required")
+	}
+	var err error
+	p, err = applySortSpec(p, DefaultSortRegistry)
+	if err != nil {
+		return Cursor{}, err
+	}
+	p = ensureMandatoryParams(p)
+	if p.Collection == nil {
+		return Cursor{}, errors.New("Collection can't be nil")
+	}
+	if p.Limit <= 0 {
+		return Cursor{}, errors.New("a limit of at least 1 is required")
+	}
+	err = p.encoding().validate(results, p.PaginatedFields)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	count, countSource, err := countTotal(p, func() (count int, err error) {
+		filter, countOptions := buildCountQuery(p)
+		err = p.execute(ctx, QueryCount, func(ctx context.Context) error {
+			count, err = executeCountQuery(ctx, p.Collection, filter, countOptions)
+			return err
+		})
+		return count, err
+	})
+	if err != nil {
+		return Cursor{}, err
+	}
+	p = p.carryCount(count, countSource)
+
+	timeField := p.PaginatedFields[0]
+	resultsVal := reflect.ValueOf(results).Elem()
+	page := reflect.MakeSlice(resultsVal.Type(), 0, int(p.Limit))
+	// A previous page walks the buckets backwards, the documents of the buckets it reaches come first
+	reverse := p.Previous != ""
+
+	t, ok, err := nextBucketTime(ctx, p, nil)
+	if err != nil {
+		return Cursor{}, err
+	}
+	var hasMore bool
+	for ok {
+		start := t.Truncate(p.TimeBucket)
+		end := start.Add(p.TimeBucket)
+		bucket := p
+		bucket.Query = MergeQueries([]bson.M{p.Query, {timeField: bson.M{"$gte": start, "$lt": end}}})
+		bucket.Limit = p.Limit - int64(page.Len())
+		bucket.CountTotal = false
+		bucketResults := reflect.New(resultsVal.Type())
+		cursor, err := Find(ctx, bucket, bucketResults.Interface())
+		if err != nil {
+			return Cursor{}, err
+		}
+		if reverse {
+			page = reflect.AppendSlice(bucketResults.Elem(), page)
+		} else {
+			page = reflect.AppendSlice(page, bucketResults.Elem())
+		}
+
+		// The following buckets hold the documents past the bucket in the direction of the page
+		beyond := bson.M{timeField: bson.M{"$gte": end}}
+		if (p.SortOrders[0] == 1) == reverse {
+			beyond = bson.M{timeField: bson.M{"$lt": start}}
+		}
+		if page.Len() == int(p.Limit) {
+			hasMore = (reverse && cursor.HasPrevious) || (!reverse && cursor.HasNext)
+			if !hasMore {
+				_, hasMore, err = nextBucketTime(ctx, p, beyond)
+				if err != nil {
+					return Cursor{}, err
+				}
+			}
+			break
+		}
+		t, ok, err = nextBucketTime(ctx, p, beyond)
+		if err != nil {
+			return Cursor{}, err
+		}
+	}
+
+	resultsVal.Set(page)
+	var firstResult, lastResult interface{}
+	if page.Len() > 0 {
+		firstResult = page.Index(0).Interface()
+		lastResult = page.Index(page.Len() - 1).Interface()
+	}
+	cursor, err := pageCursor(p, firstResult, lastResult, hasMore)
+	if err != nil {
+		return Cursor{}, err
+	}
+	cursor.Count, cursor.CountRelation = p.cappedCount(count)
+	cursor.CountSource = countSource
+	return cursor, nil
+}
+
+// nextBucketTime returns the time of the first document past the cursor of p, in the direction of its page,
+// also matched by the bound, and false when there is none
+func nextBucketTime(ctx context.Context, p FindParams, bound bson.M) (time.Time, bool, error) {
+	cursorQuery, sort, err := buildCursorQuery(p)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	timeField := p.PaginatedFields[0]
+	filter := MergeQueries([]bson.M{p.Query, cursorQuery, bound})
+	findOptions := newFindOptions(sort, 1, p.Collation, p.Hint, bson.M{timeField: 1}, p.Timeout)
+	var doc bson.Raw
+	err = p.execute(ctx, QueryFind, func(ctx context.Context) error {
+		doc = nil
+		findCtx, cancel := serverSelectionContext(ctx)
+		mongoCursor, err := p.Collection.Find(findCtx, filter, findOptions)
+		cancel()
+		if err != nil {
+			return err
+		}
+		defer mongoCursor.Close(ctx)
+		if !mongoCursor.Next(ctx) {
+			return mongoCursor.Err()
+		}
+		return mongoCursor.Decode(&doc)
+	})
+	if err != nil || doc == nil {
+		return time.Time{}, false, err
+	}
+	value, err := doc.LookupErr(timeField)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("the time field %s is missing: %s", timeField, err)
+	}
+	dateTime, ok := value.DateTimeOK()
+	if !ok {
+		return time.Time{}, false, fmt.Errorf("the time field %s holds a %s instead of a date", timeField, value.Type)
+	}
+	return primitive.DateTime(dateTime).Time().UTC(), true, nil
+}
@@ -0,0 +1,76 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindTimeSeriesPagesAsFind(t *testing.T) {
+	type metric struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Time  time.Time          `bson:"time"`
+		Value int                `bson:"value"`
+	}
+	// Two metrics per day, with an empty day and a tie on the time
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	var docs []interface{}
+	for i, d := range []int{0, 0, 1, 1, 3, 3, 4, 4} {
+		at := day.AddDate(0, 0, d).Add(time.Duration(i%2) * time.Hour)
+		if i == 7 {
+			at = day.AddDate(0, 0, d)
+		}
+		docs = append(docs, metric{ID: primitive.NewObjectID(), Time: at, Value: i})
+	}
+	col, err := mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+
+	pages := func(find func(context.Context, mongo.FindParams, interface{}) (mongo.Cursor, error), p mongo.FindParams) ([][]int, mongo.Cursor) {
+		var values [][]int
+		var results []metric
+		var cursor mongo.Cursor
+		for {
+			cursor, err = find(context.Background(), p, &results)
+			require.NoError(t, err)
+			page := []int{}
+			for _, m := range results {
+				page = append(page, m.Value)
+			}
+			values = append(values, page)
+			if !cursor.HasNext {
+				return values, cursor
+			}
+			p.Next = cursor.Next
+		}
+	}
+
+	for _, ascending := range []bool{false, true} {
+		p := mongo.FindParams{Collection: col, Limit: 3, PaginatedField: "time", SortAscending: ascending, TimeBucket: 24 * time.Hour}
+		expected, _ := pages(mongo.Find, p)
+		actual, last := pages(mongo.FindTimeSeries, p)
+		require.Equal(t, expected, actual)
+		require.Len(t, actual, 3)
+
+		// Paging back from the last page
+		var results []metric
+		p.Previous = last.Previous
+		cursor, err := mongo.FindTimeSeries(context.Background(), p, &results)
+		require.NoError(t, err)
+		require.True(t, cursor.HasPrevious)
+		var values []int
+		for _, m := range results {
+			values = append(values, m.Value)
+		}
+		require.Equal(t, expected[1], values)
+	}
+
+	_, err = mongo.FindTimeSeries(context.Background(), mongo.FindParams{Collection: col, Limit: 3, PaginatedField: "time"}, &[]metric{})
+	require.EqualError(t, err, "a TimeBucket of at least 1ns is required")
+	_, err = mongo.FindTimeSeries(context.Background(), mongo.FindParams{Collection: col, Limit: 3, PaginatedField: "value", TimeBucket: time.Hour}, &[]metric{})
+	require.EqualError(t, err, "the time field value holds a 32-bit integer instead of a date")
+}
@@ -0,0 +1,121 @@
+package mongo
+
+import (
+	"errors"
+	"fmt"
+
+	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	tokenDirectionNext     = "n"
+	tokenDirectionPrevious = "p"
+
+	// maxPageTokenSize bounds the size of a decoded page token, which holds a base64 encoded cursor
+	maxPageTokenSize = 2 * mcpbson.DefaultMaxCursorSize
+)
+
+type (
+	// TokenPatch holds the navigation direction, cursor and limit encoded in a single page token, for APIs that
+	// only expose one token parameter.
+	TokenPatch struct {
+		// The cursor to start querying the next page
+		Next string
+		// The cursor to start querying the previous page
+		Previous string
+		// The number of results to fetch, 0 to keep the FindParams' limit
+		Limit int64
+		// true if the page includes the document the cursor points at, e.g. for the token of InsertedPageToken
+		IncludeAnchor bool
+	}
+
+	pageToken struct {
+		Direction string `bson:"d"`
+		Limit     int64  `bson:"l,omitempty"`
+		Cursor    string `bson:"c"`
+		Anchor    bool   `bson:"a,omitempty"`
+	}
+)
+
+// NextToken returns the single page token navigating to the next page, or the empty string if there is no next page
+func (c Cursor) NextToken(limit int64) (string, error) {
+	if !c.HasNext || c.Next == "" {
+		return "", nil
+	}
+	return encodePageToken(pageToken{Direction: tokenDirectionNext, Limit: limit, Cursor: c.Next})
+}
+
+// PreviousToken returns the single page token navigating to the previous page, or the empty string if there is no
+// previous page
+func (c Cursor) PreviousToken(limit int64) (string, error) {
+	if !c.HasPrevious || c.Previous == "" {
+		return "", nil
+	}
+	return encodePageToken(pageToken{Direction: tokenDirectionPrevious, Limit: limit, Cursor: c.Previous})
+}
+
+// FromToken decodes a single page token generated by Cursor.NextToken or Cursor.PreviousToken. An empty token
+// returns an empty patch querying the first page.
+func FromToken(token string) (TokenPatch, error) {
+	if token == "" {
+		return TokenPatch{}, nil
+	}
+	pt, err := decodePageToken(token)
+	if err != nil {
+		return TokenPatch{}, err
+	}
+
+	switch pt.Direction {
+	case tokenDirectionNext:
+		return TokenPatch{Next: pt.Cursor, Limit: pt.Limit, IncludeAnchor: pt.Anchor}, nil
+	case tokenDirectionPrevious:
+		return TokenPatch{Previous: pt.Cursor, Limit: pt.Limit, IncludeAnchor: pt.Anchor}, nil
+	default:
+		return TokenPatch{}, &CursorError{errors.New("page token parse failed: unknown direction")}
+	}
+}
+
+// ApplyToken returns p with the navigation direction, cursor and limit encoded in the specified single page token
+func ApplyToken(p FindParams, token string) (FindParams, error) {
+	patch, err := FromToken(token)
+	if err != nil {
+		return p, err
+	}
+	return patch.Apply(p), nil
+}
+
+// Apply returns p with the patch's cursors and limit, including the anchor document when the patch does. As page
+// tokens are client supplied, the limit of the patch can only lower the Limit of p
+func (t TokenPatch) Apply(p FindParams) FindParams {
+	p.Next = t.Next
+	p.Previous = t.Previous
+	if t.Limit > 0 && (p.Limit == 0 || t.Limit < p.Limit) {
+		p.Limit = t.Limit
+	}
+	if t.IncludeAnchor {
+		p.IncludeAnchor = true
+	}
+	return p
+}
+
+func encodePageToken(pt pageToken) (string, error) {
+	data, err := bson.Marshal(pt)
+	if err != nil {
+		return "", err
+	}
+	return mcpbson.EncodeCursor(data), nil
+}
+
+func decodePageToken(token string) (pageToken, error) {
+	data, err := mcpbson.DecodeCursor(token, maxPageTokenSize, mcpbson.DefaultMaxCursorDepth)
+	if err != nil {
+		return pageToken{}, &CursorError{fmt.Errorf("page token parse failed: %s", err)}
+	}
+	var pt pageToken
+	err = bson.Unmarshal(data, &pt)
+	if err != nil {
+		return pageToken{}, &CursorError{fmt.Errorf("page token parse failed: %s", err)}
+	}
+	return pt, nil
+}
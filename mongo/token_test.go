@@ -0,0 +1,118 @@
+package mongo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestPageTokens(t *testing.T) {
+	cursor := Cursor{Next: "next", HasNext: true, Previous: "previous", HasPrevious: true}
+
+	nextToken, err := cursor.NextToken(5)
+	require.NoError(t, err)
+	p, err := ApplyToken(FindParams{Limit: 10, Previous: "stale"}, nextToken)
+	require.NoError(t, err)
+	require.Equal(t, FindParams{Limit: 5, Next: "next"}, p)
+
+	previousToken, err := cursor.PreviousToken(0)
+	require.NoError(t, err)
+	p, err = ApplyToken(FindParams{Limit: 10}, previousToken)
+	require.NoError(t, err)
+	require.Equal(t, FindParams{Limit: 10, Previous: "previous"}, p)
+
+	p, err = ApplyToken(FindParams{Limit: 10, Next: "stale"}, "")
+	require.NoError(t, err)
+	require.Equal(t, FindParams{Limit: 10}, p)
+
+	// A forged token can't raise the limit
+	forgedToken, err := encodePageToken(pageToken{Direction: tokenDirectionNext, Cursor: "next", Limit: 1000000})
+	require.NoError(t, err)
+	p, err = ApplyToken(FindParams{Limit: 10}, forgedToken)
+	require.NoError(t, err)
+	require.Equal(t, FindParams{Limit: 10, Next: "next"}, p)
+
+	token, err := Cursor{}.NextToken(5)
+	require.NoError(t, err)
+	require.Empty(t, token)
+	token, err = Cursor{}.PreviousToken(5)
+	require.NoError(t, err)
+	require.Empty(t, token)
+}
+
+func TestFromToken(t *testing.T) {
+	unknownDirection, err := encodePageToken(pageToken{Direction: "x", Cursor: "abc"})
+	require.NoError(t, err)
+
+	var cases = []struct {
+		name          string
+		token         string
+		expectedPatch TokenPatch
+		expectedErr   error
+	}{
+		{
+			name:          "errors when token is not base64",
+			token:         "XXXXXaGVsbG8=",
+			expectedPatch: TokenPatch{},
+			expectedErr:   &CursorError{errors.New("page token parse failed: illegal base64 data at input byte 12")},
+		},
+		{
+			name:          "errors when token direction is unknown",
+			token:         unknownDirection,
+			expectedPatch: TokenPatch{},
+			expectedErr:   &CursorError{errors.New("page token parse failed: unknown direction")},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			patch, err := FromToken(tc.token)
+			require.Equal(t, tc.expectedPatch, patch)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestInsertedPageToken(t *testing.T) {
+	item := Item{Name: "new"}
+	inserted := &mongodriver.InsertOneResult{InsertedID: primitive.NewObjectID()}
+	p := FindParams{Collection: &fakeCollection{}, Limit: 2, PaginatedField: "name"}
+
+	// The token navigates to the page starting with the inserted document, whose _id was generated by the driver
+	token, err := InsertedPageToken(p, inserted, struct {
+		Name string `bson:"name"`
+	}{Name: item.Name})
+	require.NoError(t, err)
+	p, err = ApplyToken(p, token)
+	require.NoError(t, err)
+	require.True(t, p.IncludeAnchor)
+	item.ID = inserted.InsertedID.(primitive.ObjectID)
+	cursor, err := GenerateCursor(item, p)
+	require.NoError(t, err)
+	require.Equal(t, cursor, p.Next)
+
+	_, err = InsertedPageToken(p, &mongodriver.InsertOneResult{}, bson.M{"name": "new"})
+	require.EqualError(t, err, "the inserted document has no _id")
+}
+
+func FuzzDecodePageToken(f *testing.F) {
+	cursor := Cursor{Next: "AQ", HasNext: true}
+	token, err := cursor.NextToken(5)
+	require.NoError(f, err)
+	f.Add(token)
+	f.Add(token[:len(token)/2])
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, token string) {
+		pt, err := decodePageToken(token)
+		if err != nil {
+			require.IsType(t, &CursorError{}, err)
+			return
+		}
+		_, err = encodePageToken(pt)
+		require.NoError(t, err)
+	})
+}
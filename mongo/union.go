@@ -0,0 +1,59 @@
+package mongo
+
+import (
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// SourceField is the name of the field added by SourceStage and UnionWithStage to hold the source of each
+	// document
+	SourceField = "_source"
+	// UnionTiebreakerField is the name of the field added by UnionTiebreakerStage to hold the synthetic
+	// tiebreaker of each document
+	UnionTiebreakerField = "_tiebreaker"
+)
+
+// SourceStage returns an $addFields stage setting SourceField to the source, e.g. the name of the collection the
+// pipeline aggregates, telling its documents apart from the ones of the collections it $unionWith.
+func SourceStage(source string) bson.M {
+	return bson.M{"$addFields": bson.M{SourceField: source}}
+}
+
+// UnionWithStage returns a $unionWith stage adding the documents of the collection output by the pipeline, their
+// SourceField being set to the name of the collection.
+func UnionWithStage(collection string, pipeline ...bson.M) bson.M {
+	stages := make([]bson.M, 0, len(pipeline)+1)
+	stages = append(stages, SourceStage(collection))
+	stages = append(stages, pipeline...)
+	return bson.M{"$unionWith": bson.M{"coll": collection, "pipeline": stages}}
+}
+
+// UnionTiebreakerStage returns an $addFields stage setting UnionTiebreakerField to a document holding the values
+// of the fields, in order. The documents being compared field by field, it sorts as the fields do and is unique
+// as long as the fields are together, e.g. the source and the _id of the documents of several collections. The
+// dots of the fields' paths are replaced by underscores in the keys of the document.
+func UnionTiebreakerStage(fields ...string) bson.M {
+	tiebreaker := make(bson.D, 0, len(fields))
+	for _, field := range fields {
+		tiebreaker = append(tiebreaker, bson.E{Key: strings.ReplaceAll(field, ".", "_"), Value: "$" + field})
+	}
+	return bson.M{"$addFields": bson.M{UnionTiebreakerField: tiebreaker}}
+}
+
+// UnionTiebreaker returns p set up to paginate the documents output by its pipeline $unionWith other collections,
+// whose _id may collide across the collections, breaking the ties of its paginated fields with the synthetic
+// tiebreaker of the fields, SourceField and _id if none, rather than with the _id. The documents of every
+// collection must hold the fields, e.g. by starting the pipeline with the SourceStage of the aggregated collection
+// and adding the other ones with UnionWithStage. The documents output by the pipeline hold the tiebreaker.
+func UnionTiebreaker(p AggregateParams, fields ...string) AggregateParams {
+	if len(fields) == 0 {
+		fields = []string{SourceField, "_id"}
+	}
+	pipeline := make([]bson.M, 0, len(p.Pipeline)+1)
+	pipeline = append(pipeline, p.Pipeline...)
+	p.Pipeline = append(pipeline, UnionTiebreakerStage(fields...))
+	p.TiebreakerField = UnionTiebreakerField
+	return p
+}
@@ -0,0 +1,79 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUnionTiebreaker(t *testing.T) {
+	// The _id collides across the collections
+	id := primitive.NewObjectID()
+	doc := func(source string, name string) bson.D {
+		return bson.D{
+			{Key: "_id", Value: id},
+			{Key: "name", Value: name},
+			{Key: SourceField, Value: source},
+			{Key: UnionTiebreakerField, Value: bson.D{{Key: SourceField, Value: source}, {Key: "_id", Value: id}}},
+		}
+	}
+	col := &fakeCollection{docs: []interface{}{doc("items", "a"), doc("archived", "a")}}
+	union := UnionWithStage("archived", bson.M{"$match": bson.M{"deleted": false}})
+	p := UnionTiebreaker(AggregateParams{
+		Collection:      col,
+		Pipeline:        []bson.M{SourceStage("items"), union},
+		Limit:           1,
+		PaginatedFields: []string{"name"},
+		SortOrders:      []int{1},
+	})
+
+	var results []Item
+	cursor, err := Aggregate(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{{ID: id, Name: "a"}}, results)
+	require.Equal(t, []bson.M{
+		{"$addFields": bson.M{SourceField: "items"}},
+		{"$unionWith": bson.M{"coll": "archived", "pipeline": []bson.M{
+			{"$addFields": bson.M{SourceField: "archived"}},
+			{"$match": bson.M{"deleted": false}},
+		}}},
+		{"$addFields": bson.M{UnionTiebreakerField: bson.D{{Key: SourceField, Value: "$" + SourceField}, {Key: "_id", Value: "$_id"}}}},
+		{"$sort": bson.D{{Key: "name", Value: 1}, {Key: UnionTiebreakerField, Value: 1}}},
+		{"$limit": int64(2)},
+	}, col.pipeline)
+
+	// The next page starts after the document of the first collection, not after every document of its _id
+	cursorData, err := Base64CursorCodec{}.Decode(cursor.Next)
+	require.NoError(t, err)
+	require.Equal(t, bson.D{
+		{Key: "name", Value: "a"},
+		{Key: UnionTiebreakerField, Value: bson.D{{Key: SourceField, Value: "items"}, {Key: "_id", Value: id}}},
+	}, cursorData)
+
+	col.docs = col.docs[1:]
+	p.Next = cursor.Next
+	_, err = Aggregate(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{{ID: id, Name: "a"}}, results)
+	match := col.pipeline.([]bson.M)[3]["$match"]
+	require.Contains(t, match.(bson.M)["$or"], map[string]interface{}{"$and": []map[string]interface{}{
+		{"name": map[string]interface{}{"$gte": "a"}},
+		{UnionTiebreakerField: map[string]interface{}{"$gt": bson.D{{Key: SourceField, Value: "items"}, {Key: "_id", Value: id}}}},
+	}})
+}
+
+func TestUnionTiebreakerStage(t *testing.T) {
+	require.Equal(t, bson.M{"$addFields": bson.M{
+		UnionTiebreakerField: bson.D{{Key: "tenant_id", Value: "$tenant.id"}, {Key: "_id", Value: "$_id"}},
+	}}, UnionTiebreakerStage("tenant.id", "_id"))
+
+	// The pipeline of the params isn't modified
+	pipeline := make([]bson.M, 1, 2)
+	pipeline[0] = SourceStage("items")
+	p := UnionTiebreaker(AggregateParams{Pipeline: pipeline})
+	require.Equal(t, []bson.M{SourceStage("items")}, pipeline)
+	require.Equal(t, UnionTiebreakerField, p.TiebreakerField)
+}
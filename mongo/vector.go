@@ -0,0 +1,121 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	// defaultCandidatesPerResult is the numCandidates per result of a vector search window by default
+	defaultCandidatesPerResult = 10
+	// defaultMaxVectorSearchWindow is the largest limit of a vector search window by default
+	defaultMaxVectorSearchWindow = 1000
+	// maxNumCandidates is the largest numCandidates of a $vectorSearch stage
+	maxNumCandidates = 10000
+)
+
+// VectorSearch describes an Atlas $vectorSearch stage whose results are paginated by FindVectorSearch
+type VectorSearch struct {
+	// The name of the Atlas Vector Search index
+	Index string
+	// The indexed vector field
+	Path string
+	// The vector of the query, e.g. the embedding of a question
+	QueryVector interface{}
+	// Pre-filters the documents on the indexed filter fields, none if nil
+	Filter bson.M
+	// The numCandidates of the search per document of its window, 10 by default
+	CandidatesPerResult int
+	// The largest limit of the search window, 1000 by default. Only the documents of the window are paginated
+	MaxWindow int
+}
+
+// FindVectorSearch paginates the documents returned by an Atlas $vectorSearch by descending similarity score,
+// then by descending TiebreakerField, the _id by default, filling the results slice pointer. The score is added to
+// the documents as ScoreField and encoded in the cursors along with the tiebreaker, so that the pages are navigated
+// with the same tokens as the rest of the API. The Pipeline of p holds the stages following the search, e.g. a
+// $project, and its Query filters the documents returned by the search, which may leave fewer documents to
+// paginate than the Filter of the VectorSearch does.
+//
+// $vectorSearch only returns its limit most similar documents, so the search is re-issued with a doubled window,
+// its limit and numCandidates, until it reaches past the cursor to fill the page, the window ends or the search
+// runs out of documents. The results are approximate: a larger window may rank other neighbors, so a document
+// may be missing from the pages or appear on two of them, and the documents beyond the MaxWindow most similar
+// ones are never reached. The pages deep in the results cost several searches, so they should be kept shallow.
+func FindVectorSearch(ctx context.Context, p AggregateParams, search VectorSearch, results interface{}) (Cursor, error) {
+	if p.CountTotal {
+		return Cursor{}, errors.New("the documents of a vector search can't be counted")
+	}
+	ensured, fp, err := ensureAggregateParams(p)
+	if err != nil {
+		return Cursor{}, err
+	}
+	maxWindow := search.MaxWindow
+	if maxWindow <= 0 {
+		maxWindow = defaultMaxVectorSearchWindow
+	}
+	p.PaginatedField = ""
+	p.PaginatedFields = []string{ScoreField, fp.tiebreaker()}
+	p.SortOrders = []int{-1, -1}
+	// $vectorSearch must be the first stage, so the Query is matched once it returned
+	pipeline := p.Pipeline
+	if len(p.Query) > 0 {
+		pipeline = append([]bson.M{{"$match": p.Query}}, pipeline...)
+		p.Query = nil
+	}
+
+	window := min(int(p.Limit)+1, maxWindow)
+	for {
+		stages := vectorSearchStages(search, window)
+		p.Pipeline = append(stages, pipeline...)
+		cursor, err := Aggregate(ctx, p, results)
+		if err != nil {
+			return Cursor{}, err
+		}
+		filled := cursor.HasNext
+		if p.Previous != "" {
+			filled = cursor.HasPrevious
+		}
+		if filled || window >= maxWindow {
+			return cursor, nil
+		}
+
+		// The page can't be filled by a larger window once the search returned fewer documents than its limit
+		var returned int
+		err = fp.execute(ctx, QueryAggregateCount, func(ctx context.Context) error {
+			returned, err = executeAggregateCountQuery(ctx, ensured.Collection, stages, 0, p.Collation, fp.Timeout)
+			return err
+		})
+		if err != nil {
+			return Cursor{}, err
+		}
+		if returned < window {
+			return cursor, nil
+		}
+		window = min(2*window, maxWindow)
+	}
+}
+
+// vectorSearchStages returns the $vectorSearch stage of a window of documents and the stage adding their score
+func vectorSearchStages(search VectorSearch, window int) []bson.M {
+	candidatesPerResult := search.CandidatesPerResult
+	if candidatesPerResult <= 0 {
+		candidatesPerResult = defaultCandidatesPerResult
+	}
+	stage := bson.M{
+		"index":         search.Index,
+		"path":          search.Path,
+		"queryVector":   search.QueryVector,
+		"numCandidates": max(min(window*candidatesPerResult, maxNumCandidates), window),
+		"limit":         window,
+	}
+	if search.Filter != nil {
+		stage["filter"] = search.Filter
+	}
+	return []bson.M{
+		{"$vectorSearch": stage},
+		{"$addFields": bson.M{ScoreField: bson.M{"$meta": "vectorSearchScore"}}},
+	}
+}
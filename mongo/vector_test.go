@@ -0,0 +1,84 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// vectorCollection returns its docs to the pages and the window's limit to the counts of the search, as if it
+// held more similar documents than any window, recording the windows of the searches
+type vectorCollection struct {
+	fakeCollection
+	windows []int
+}
+
+func (c *vectorCollection) Aggregate(ctx context.Context, pipeline interface{}, opts ...*options.AggregateOptions) (MongoCursor, error) {
+	stages := pipeline.([]bson.M)
+	window := stages[0]["$vectorSearch"].(bson.M)["limit"].(int)
+	if _, ok := stages[len(stages)-1]["$count"]; ok {
+		return (&fakeCollection{docs: []interface{}{bson.M{"count": window}}}).Find(ctx, nil, options.Find())
+	}
+	c.windows = append(c.windows, window)
+	return c.fakeCollection.Aggregate(ctx, pipeline, opts...)
+}
+
+func TestFindVectorSearch(t *testing.T) {
+	type scoredItem struct {
+		ID    primitive.ObjectID `bson:"_id"`
+		Name  string             `bson:"name"`
+		Score float64            `bson:"_score"`
+	}
+	items := []scoredItem{
+		{ID: primitive.NewObjectID(), Name: "a", Score: 0.9},
+		{ID: primitive.NewObjectID(), Name: "b", Score: 0.8},
+		{ID: primitive.NewObjectID(), Name: "c", Score: 0.7},
+	}
+	search := VectorSearch{Index: "embeddings", Path: "embedding", QueryVector: []float64{0.1, 0.2}, Filter: bson.M{"lang": "en"}, MaxWindow: 10}
+	project := bson.M{"$project": bson.M{"embedding": 0}}
+	col := &vectorCollection{fakeCollection: fakeCollection{docs: []interface{}{items[0], items[1], items[2]}}}
+	p := AggregateParams{Collection: col, Pipeline: []bson.M{project}, Limit: 2}
+
+	// A full page is fetched with a single search
+	var results []Item
+	cursor, err := FindVectorSearch(context.Background(), p, search, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{{ID: items[0].ID, Name: "a"}, {ID: items[1].ID, Name: "b"}}, results)
+	require.True(t, cursor.HasNext)
+	require.Equal(t, []int{3}, col.windows)
+	require.Equal(t, []bson.M{
+		{"$vectorSearch": bson.M{
+			"index": "embeddings", "path": "embedding", "queryVector": []float64{0.1, 0.2},
+			"numCandidates": 30, "limit": 3, "filter": bson.M{"lang": "en"},
+		}},
+		{"$addFields": bson.M{ScoreField: bson.M{"$meta": "vectorSearchScore"}}},
+		project,
+		{"$sort": bson.D{{Key: ScoreField, Value: -1}, {Key: "_id", Value: -1}}},
+		{"$limit": int64(3)},
+	}, col.pipeline)
+
+	// The window is doubled until the page is filled or the window ends
+	col.docs = col.docs[2:]
+	col.windows = nil
+	p.Next = cursor.Next
+	cursor, err = FindVectorSearch(context.Background(), p, search, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{{ID: items[2].ID, Name: "c"}}, results)
+	require.False(t, cursor.HasNext)
+	require.Equal(t, []int{3, 6, 10}, col.windows)
+	require.Equal(t, bson.M{"$match": bson.M{"$or": []map[string]interface{}{
+		{ScoreField: map[string]interface{}{"$lt": 0.8}},
+		{"$and": []map[string]interface{}{
+			{ScoreField: map[string]interface{}{"$lte": 0.8}},
+			{"_id": map[string]interface{}{"$lt": items[1].ID}},
+		}},
+	}}}, col.pipeline.([]bson.M)[3])
+
+	p.CountTotal = true
+	_, err = FindVectorSearch(context.Background(), p, search, &results)
+	require.EqualError(t, err, "the documents of a vector search can't be counted")
+}
@@ -0,0 +1,151 @@
+package mongo
+
+import (
+	"context"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Window holds the pagination data of a page along with the documents on either side of it, e.g. for the UIs
+// rendering the previous and next items around a page
+type Window struct {
+	Cursor
+	// The document preceding the first document of the page in the sort order, nil if there is none
+	Before bson.Raw
+	// The document following the last document of the page in the sort order, nil if there is none
+	After bson.Raw
+}
+
+// FindWindow executes the query of the page of the provided FindParams along with the query of the document on the
+// other side of its cursor, fills the passed in result slice pointer with the page and returns its Window. Both
+// queries are indexed find queries of the paginated fields' sort, the page fetching one more document to get the
+// one beyond it. The Previous or Next cursor of the Window is empty when no document is on the other side of the
+// Next or Previous cursor, as with ConsistencyExact. Skipped pages are honored, the document preceding the page in
+// the order it is queried then being the last one skipped.
+func FindWindow(ctx context.Context, p FindParams, results interface{}) (Window, error) {
+	validated := results
+	if p.DecodeFunc != nil {
+		err := validateDecodedResults(results)
+		if err != nil {
+			return Window{}, err
+		}
+		validated = &[]bson.Raw{}
+	}
+	p, err := prepareFind(ctx, p, validated)
+	if err != nil {
+		return Window{}, err
+	}
+
+	count, countSource, err := countTotal(p, func() (count int, err error) {
+		filter, countOptions := buildCountQuery(p)
+		err = p.execute(ctx, QueryCount, func(ctx context.Context) error {
+			count, err = executeCountQuery(ctx, p.Collection, filter, countOptions)
+			return err
+		})
+		return count, err
+	})
+	if err != nil {
+		return Window{}, err
+	}
+	p = p.carryCount(count, countSource)
+
+	queries, sort, err := BuildQueries(ctx, p)
+	if err != nil {
+		return Window{}, err
+	}
+	var page []bson.Raw
+	err = p.execute(ctx, QueryFind, func(ctx context.Context) error {
+		return executeCursorQuery(ctx, p.Collection, queries, sort, p.Limit+1, false, p.Collation, p.Hint, p.Projection, p.Timeout, p.skipPagesOptions(), &page)
+	})
+	if err != nil {
+		return Window{}, err
+	}
+	beyondCursor, err := findBeyondCursor(ctx, p, queries, sort)
+	if err != nil {
+		return Window{}, err
+	}
+
+	// The document beyond the page precedes it when it is queried with Previous, as the cursor's anchor follows it
+	var window Window
+	hasMore := len(page) > int(p.Limit)
+	var beyondPage bson.Raw
+	if hasMore {
+		beyondPage = page[p.Limit]
+	}
+	if p.Previous != "" {
+		window.Before, window.After = beyondPage, beyondCursor
+	} else {
+		window.Before, window.After = beyondCursor, beyondPage
+	}
+
+	page = orderPage(p, page)
+	p, err = p.carrySeen(reflect.ValueOf(page))
+	if err != nil {
+		return Window{}, err
+	}
+	first, last := firstAndLast(page)
+	window.Cursor, err = pageCursor(p, first, last, hasMore)
+	if err != nil {
+		return Window{}, err
+	}
+	window.Count, window.CountRelation = p.cappedCount(count)
+	window.CountSource = countSource
+	if beyondCursor == nil && p.SkipPages == 0 {
+		if p.Next != "" {
+			window.HasPrevious, window.Previous = false, ""
+		} else if p.Previous != "" {
+			window.HasNext, window.Next = false, ""
+		}
+	}
+
+	if p.DecodeFunc != nil {
+		err = decodeResultsWith(page, p.DecodeFunc, results)
+	} else {
+		err = decodeResults(page, p.settings().Registry, results)
+	}
+	if err != nil {
+		return Window{}, err
+	}
+	return window, nil
+}
+
+// findBeyondCursor returns the document preceding the page of the ensured p in the order it is queried, whose
+// queries and sort are passed: the last document skipped when pages are skipped, otherwise the document on the
+// other side of its cursor, which is the anchor unless the page includes it. It is nil if there is none.
+func findBeyondCursor(ctx context.Context, p FindParams, queries []bson.M, sort bson.D) (bson.Raw, error) {
+	var rawOptions func(*options.FindOptions)
+	switch {
+	case p.SkipPages > 0:
+		skip := int64(p.SkipPages)*p.Limit - 1
+		pageOptions := p.rawFindOptions()
+		rawOptions = func(o *options.FindOptions) {
+			o.SetSkip(skip)
+			if pageOptions != nil {
+				pageOptions(o)
+			}
+		}
+	case p.Next != "" || p.Previous != "":
+		beyond := p
+		beyond.Next, beyond.Previous = p.Previous, p.Next
+		beyond.IncludeAnchor = !p.IncludeAnchor
+		var err error
+		queries, sort, err = BuildQueries(ctx, beyond)
+		if err != nil {
+			return nil, err
+		}
+		rawOptions = p.rawFindOptions()
+	default:
+		return nil, nil
+	}
+
+	var docs []bson.Raw
+	err := p.execute(ctx, QueryFind, func(ctx context.Context) error {
+		return executeCursorQuery(ctx, p.Collection, queries, sort, 1, false, p.Collation, p.Hint, p.Projection, p.Timeout, rawOptions, &docs)
+	})
+	if err != nil || len(docs) == 0 {
+		return nil, err
+	}
+	return docs[0], nil
+}
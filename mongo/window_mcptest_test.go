@@ -0,0 +1,102 @@
+package mongo_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/qlik-oss/mongocursorpagination/mcptest"
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFindWindowFetchesTheNeighbors(t *testing.T) {
+	var docs []interface{}
+	for i := 0; i < 7; i++ {
+		docs = append(docs, mcptest.Item{ID: primitive.NewObjectID(), Name: "item", Count: i})
+	}
+	col, err := mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+	p := mongo.FindParams{Collection: col, Limit: 2, PaginatedField: "count", SortAscending: true, CountTotal: true}
+	count := func(raw bson.Raw) int {
+		if raw == nil {
+			return -1
+		}
+		return int(raw.Lookup("count").AsInt64())
+	}
+	counts := func(items []mcptest.Item) []int {
+		var counts []int
+		for _, i := range items {
+			counts = append(counts, i.Count)
+		}
+		return counts
+	}
+
+	var results []mcptest.Item
+	window, err := mongo.FindWindow(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1}, counts(results))
+	require.Equal(t, -1, count(window.Before))
+	require.Equal(t, 2, count(window.After))
+	require.Equal(t, 7, window.Count)
+	require.False(t, window.HasPrevious)
+
+	p.Next = window.Next
+	window, err = mongo.FindWindow(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3}, counts(results))
+	require.Equal(t, 1, count(window.Before))
+	require.Equal(t, 4, count(window.After))
+	require.True(t, window.HasPrevious)
+	require.True(t, window.HasNext)
+
+	// The window is the same when paging back to it
+	next := window.Next
+	p.Next = next
+	window, err = mongo.FindWindow(context.Background(), p, &results)
+	require.NoError(t, err)
+	p.Next, p.Previous = "", window.Previous
+	window, err = mongo.FindWindow(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []int{2, 3}, counts(results))
+	require.Equal(t, 1, count(window.Before))
+	require.Equal(t, 4, count(window.After))
+	require.Equal(t, next, window.Next)
+
+	// The last page has no document after it
+	p.Previous = ""
+	p.Next = next
+	for window.HasNext {
+		window, err = mongo.FindWindow(context.Background(), p, &results)
+		require.NoError(t, err)
+		p.Next = window.Next
+	}
+	require.Equal(t, []int{6}, counts(results))
+	require.Equal(t, 5, count(window.Before))
+	require.Equal(t, -1, count(window.After))
+
+	// Nor a previous page once the documents before it were deleted
+	p.Next = next
+	col, err = mcptest.NewCollection(docs[4:]...)
+	require.NoError(t, err)
+	p.Collection = col
+	window, err = mongo.FindWindow(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 5}, counts(results))
+	require.Equal(t, -1, count(window.Before))
+	require.False(t, window.HasPrevious)
+	require.Empty(t, window.Previous)
+
+	// The document before a page reached by skipping pages is the last one skipped
+	p.Collection, err = mcptest.NewCollection(docs...)
+	require.NoError(t, err)
+	p.Next = ""
+	p.SkipPages = 2
+	window, err = mongo.FindWindow(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []int{4, 5}, counts(results))
+	require.Equal(t, 3, count(window.Before))
+	require.Equal(t, 6, count(window.After))
+	require.True(t, window.HasPrevious)
+}
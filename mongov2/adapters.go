@@ -0,0 +1,25 @@
+package mongov2
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type mongoCollection struct {
+	collection *mongo.Collection
+}
+
+// WrapCollection returns the Collection to use in FindParams for the specified v2 mongo collection
+func WrapCollection(c *mongo.Collection) Collection {
+	return &mongoCollection{collection: c}
+}
+
+func (c *mongoCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error) {
+	return c.collection.CountDocuments(ctx, filter, opts...)
+}
+
+func (c *mongoCollection) Find(ctx context.Context, filter interface{}, opts ...options.Lister[options.FindOptions]) (MongoCursor, error) {
+	return c.collection.Find(ctx, filter, opts...)
+}
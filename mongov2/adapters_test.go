@@ -0,0 +1,27 @@
+package mongov2
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestWrapCollection(t *testing.T) {
+	client, err := mongo.Connect(options.Client().ApplyURI("mongodb://localhost:1"))
+	require.NoError(t, err)
+	collection := client.Database("test_db").Collection("items")
+
+	c := WrapCollection(collection)
+	require.Equal(t, &mongoCollection{collection: collection}, c)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.Find(ctx, bson.M{})
+	require.ErrorIs(t, err, context.Canceled)
+	_, err = c.CountDocuments(ctx, bson.M{})
+	require.ErrorIs(t, err, context.Canceled)
+}
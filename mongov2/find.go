@@ -0,0 +1,440 @@
+// Package mongov2 eases the computation of pagination information of a find mongo query using the v2 mongo
+// driver (go.mongodb.org/mongo-driver/v2) by augmenting the base query with cursor information and returning
+// a cursor.
+package mongov2
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	mcpbson "github.com/qlik-oss/mongocursorpagination/bson"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+const (
+	defaultCursorTimeout = 45 * time.Second
+)
+
+type (
+	MongoCursor interface {
+		Close(context.Context) error
+		Decode(interface{}) error
+		ID() int64
+		Next(context.Context) bool
+		TryNext(context.Context) bool
+		Err() error
+		All(context.Context, interface{}) error
+		RemainingBatchLength() int
+	}
+	Collection interface {
+		CountDocuments(context.Context, interface{}, ...options.Lister[options.CountOptions]) (int64, error)
+		Find(context.Context, interface{}, ...options.Lister[options.FindOptions]) (MongoCursor, error)
+	}
+	// FindParams holds the parameters to be used in a paginated find mongo query that will return a
+	// Cursor.
+	FindParams struct {
+		Collection Collection
+
+		// The find query to augment with pagination
+		Query bson.M
+		// The number of results to fetch, should be > 0
+		Limit int64
+		// true, if the results should be sort ascending, false otherwise
+		SortAscending bool
+		// The name of the mongo collection field being paginated and sorted on. This field must:
+		// 1. Be orderable. We must sort by this value. If duplicate values for paginatedField field
+		//    exist, the results will be secondarily ordered by the _id
+		// 2. Be indexed. For large collections, this should be indexed for query performance
+		// 3. Be immutable. If the value changes between paged queries, it could appear twice
+		// 4. Match the bson field name the result struct. e.g.:
+		//
+		//    PaginatedField would be "name" when paginating employees by name
+		//
+		//    type Employee struct {
+		//        ID          bson.ObjectID `bson:"_id"`
+		//        Name        string        `bson:"name"`
+		//    }
+		//
+		PaginatedField string
+		// The collation to use for the sort ordering, the cursor query comparisons and for counting total results.
+		// This is ignored when paginating on the _id only. The index on the paginated fields must be created with
+		// the same collation to be used for the sort
+		Collation *options.Collation
+		// The value to start querying the page
+		Next string
+		// The value to start querying previous page
+		Previous string
+		// Whether to include total count of documents matching filter in the cursor
+		// Specifying true makes an additional query
+		CountTotal bool
+		// The index to use for the operation. This should either be the index name as a string or the index specification
+		// as a document. The default value is nil, which means that no hint will be sent.
+		Hint interface{}
+		// A document describing which fields will be included in the documents returned by the operation. The default value
+		// is nil, which means all fields will be included.
+		// Example: bson.D{"_id":0, "name": 1}
+		Projection interface{}
+		// The v2 driver removed the maxTimeMS option in favor of context deadlines, this parameter bounds the context
+		// of the count and find queries instead. Will default to 45 seconds, but should be set to an appropriate duration
+		Timeout time.Duration
+		// The names of multiple fields being paginated and sorted on. Takes precedence over PaginatedField
+		PaginatedFields []string
+		// The sort orders corresponding to PaginatedFields. Each value must be either 1 or -1
+		SortOrders []int
+		// true, if the page starting at Next or Previous should include the anchor document the cursor points at,
+		// e.g. to resume exactly at a bookmarked document
+		IncludeAnchor bool
+	}
+
+	// Cursor holds the pagination data about the find mongo query that was performed.
+	Cursor struct {
+		// The URL safe previous page cursor to pass in a Find call to get the previous page.
+		// This is set to the empty string if there is no previous page.
+		Previous string
+		// The URL safe next page cursor to pass in a Find call to get the next page.
+		// This is set to the empty string if there is no next page.
+		Next string
+		// true if there is a previous page, false otherwise
+		HasPrevious bool
+		// true if there is a next page, false otherwise
+		HasNext bool
+		// Total count of documents matching filter - only computed if CountTotal is True
+		Count int
+	}
+
+	CursorError struct {
+		err error
+	}
+)
+
+func (e *CursorError) Error() string {
+	return e.err.Error()
+}
+
+// BuildQueries builds the queries without executing them
+func BuildQueries(ctx context.Context, p FindParams) (queries []bson.M, sort bson.D, err error) {
+	p = ensureMandatoryParams(p)
+	var numPaginatedFields int
+	if len(p.PaginatedFields) > 0 {
+		numPaginatedFields = len(p.PaginatedFields)
+	} else {
+		numPaginatedFields = 1
+	}
+
+	if p.Collection == nil {
+		return []bson.M{}, nil, errors.New("Collection can't be nil")
+	}
+
+	if p.Limit <= 0 {
+		return []bson.M{}, nil, errors.New("a limit of at least 1 is required")
+	}
+
+	nextCursorValues, err := parseCursor(p.Next, numPaginatedFields)
+	if err != nil {
+		return []bson.M{}, nil, &CursorError{fmt.Errorf("next cursor parse failed: %s", err)}
+	}
+
+	previousCursorValues, err := parseCursor(p.Previous, numPaginatedFields)
+	if err != nil {
+		return []bson.M{}, nil, &CursorError{fmt.Errorf("previous cursor parse failed: %s", err)}
+	}
+
+	comparisonOps := generateComparisonOps(p)
+
+	// Augment the specified find query with cursor data
+	queries = []bson.M{p.Query}
+
+	// Setup the pagination query
+	if p.Next != "" || p.Previous != "" {
+		var cursorValues []interface{}
+		if p.Next != "" {
+			cursorValues = nextCursorValues
+		} else if p.Previous != "" {
+			cursorValues = previousCursorValues
+		}
+		var cursorQuery bson.M
+		if p.IncludeAnchor {
+			cursorQuery, err = mcpbson.GenerateInclusiveCursorQuery(p.PaginatedFields, comparisonOps, cursorValues)
+		} else {
+			cursorQuery, err = mcpbson.GenerateCursorQuery(p.PaginatedFields, comparisonOps, cursorValues)
+		}
+		if err != nil {
+			return []bson.M{}, nil, err
+		}
+		queries = append(queries, cursorQuery)
+	}
+
+	// Setup the sort query
+	for i := range p.PaginatedFields {
+		sort = append(sort, bson.E{Key: p.PaginatedFields[i], Value: p.SortOrders[i]})
+	}
+
+	return queries, sort, nil
+}
+
+// Find executes a find mongo query by using the provided FindParams, fills the passed in result
+// slice pointer and returns a Cursor.
+func Find(ctx context.Context, p FindParams, results interface{}) (Cursor, error) {
+	var err error
+	p = ensureMandatoryParams(p)
+	err = validate(results, p.PaginatedFields)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	// Compute total count of documents matching filter - only computed if CountTotal is True
+	var count int
+	if p.CountTotal {
+		count, err = executeCountQuery(ctx, p.Collection, []bson.M{p.Query}, p.Collation, p.Timeout)
+		if err != nil {
+			return Cursor{}, err
+		}
+	}
+
+	queries, sort, err := BuildQueries(ctx, p)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	// Execute the augmented query, get an additional element to see if there's another page
+	err = executeCursorQuery(ctx, p.Collection, queries, sort, p.Limit, p.Collation, p.Hint, p.Projection, p.Timeout, results)
+	if err != nil {
+		return Cursor{}, err
+	}
+
+	// Get the results slice's pointer and value
+	resultsPtr := reflect.ValueOf(results)
+	resultsVal := resultsPtr.Elem()
+
+	hasMore := resultsVal.Len() > int(p.Limit)
+
+	// Remove the extra element that we added to see if there was another page
+	if hasMore {
+		resultsVal = resultsVal.Slice(0, resultsVal.Len()-1)
+	}
+
+	hasPrevious := p.Next != "" || (p.Previous != "" && hasMore)
+	hasNext := p.Previous != "" || hasMore
+
+	var previousCursor string
+	var nextCursor string
+
+	if resultsVal.Len() > 0 {
+		// If we sorted reverse to get the previous page, correct the sort order
+		if p.Previous != "" {
+			mcpbson.ReverseResults(resultsVal)
+		}
+
+		// Generate the previous cursor
+		if hasPrevious {
+			firstResult := resultsVal.Index(0).Interface()
+			previousCursor, err = generateCursor(firstResult, p.PaginatedFields)
+			if err != nil {
+				return Cursor{}, fmt.Errorf("could not create a previous cursor: %s", err)
+			}
+		}
+
+		// Generate the next cursor
+		if hasNext {
+			lastResult := resultsVal.Index(resultsVal.Len() - 1).Interface()
+			nextCursor, err = generateCursor(lastResult, p.PaginatedFields)
+			if err != nil {
+				return Cursor{}, fmt.Errorf("could not create a next cursor: %s", err)
+			}
+		}
+	}
+
+	// Create the response cursor
+	cursor := Cursor{
+		Previous:    previousCursor,
+		HasPrevious: hasPrevious,
+		Next:        nextCursor,
+		HasNext:     hasNext,
+		Count:       count,
+	}
+
+	// Save the modified result slice in the result pointer
+	resultsPtr.Elem().Set(resultsVal)
+
+	return cursor, nil
+}
+
+// GenerateCursor returns the URL safe cursor pointing at the specified result for the paginated fields of
+// the provided FindParams. Passing it as Next or Previous in a Find call with the same FindParams returns the
+// page after or before that result.
+func GenerateCursor(result interface{}, p FindParams) (string, error) {
+	p = ensureMandatoryParams(p)
+	return generateCursor(result, p.PaginatedFields)
+}
+
+func generateComparisonOps(p FindParams) []string {
+	return mcpbson.ComparisonOps(p.SortOrders, p.Previous != "")
+}
+
+func ensureMandatoryParams(p FindParams) FindParams {
+	if p.PaginatedField == "" {
+		p.PaginatedField = "_id"
+		// The collation only matters when sorting on other fields than the _id
+		if len(p.PaginatedFields) == 0 {
+			p.Collation = nil
+		}
+	}
+	p.PaginatedFields, p.SortOrders = mcpbson.EnsurePaginatedFields(p.PaginatedField, p.PaginatedFields, p.SortOrders, p.SortAscending, "_id")
+	return p
+}
+
+func parseCursor(cursor string, numPaginatedFields int) ([]interface{}, error) {
+	cursorValues := make([]interface{}, 0, numPaginatedFields)
+	if cursor != "" {
+		parsedCursor, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		err = mcpbson.CheckCursorLength(len(parsedCursor), numPaginatedFields)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range parsedCursor {
+			cursorValues = append(cursorValues, obj.Value)
+		}
+	}
+
+	return cursorValues, nil
+}
+
+// decodeCursor decodes cursor data that was previously encoded with createCursor
+func decodeCursor(cursor string) (bson.D, error) {
+	var cursorData bson.D
+	// Validating the document first rejects the truncated or hostile cursors without unmarshaling them
+	data, err := mcpbson.DecodeCursor(cursor, mcpbson.DefaultMaxCursorSize, mcpbson.DefaultMaxCursorDepth)
+	if err != nil {
+		return cursorData, err
+	}
+
+	err = bson.Unmarshal(data, &cursorData)
+	return cursorData, err
+}
+
+// withTimeout bounds the context of a query as the v2 driver doesn't support the maxTimeMS option anymore
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= time.Duration(0) {
+		timeout = defaultCursorTimeout
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func executeCountQuery(ctx context.Context, c Collection, queries []bson.M, collation *options.Collation, timeout time.Duration) (int, error) {
+	ctx, cancel := withTimeout(ctx, timeout)
+	defer cancel()
+
+	options := options.Count()
+	if collation != nil {
+		options.SetCollation(collation)
+	}
+	count, err := c.CountDocuments(ctx, mcpbson.MergeQueries(queries), options)
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+func executeCursorQuery(ctx context.Context, c Collection, query []bson.M, sort bson.D, limit int64, collation *options.Collation, hint interface{}, projection interface{}, timeout time.Duration, results interface{}) error {
+	ctx, cancel := withTimeout(ctx, timeout)
+	defer cancel()
+
+	options := options.Find()
+	options.SetSort(sort)
+	options.SetLimit(limit + 1)
+
+	if collation != nil {
+		options.SetCollation(collation)
+	}
+	if hint != nil {
+		options.SetHint(hint)
+	}
+	if projection != nil {
+		options.SetProjection(projection)
+	}
+	cursor, err := c.Find(ctx, mcpbson.MergeQueries(query), options)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, results)
+}
+
+// generateCursor returns the cursor of the paginated fields of result
+func generateCursor(result interface{}, paginatedFields []string) (string, error) {
+	if result == nil {
+		return "", fmt.Errorf("the specified result must be a non nil value")
+	}
+
+	var recordAsBytes []byte
+	var err error
+
+	switch v := result.(type) {
+	case bson.Raw:
+		recordAsBytes = v
+	default:
+		recordAsBytes, err = bson.Marshal(result)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	cursorData, err := mcpbson.CursorDocument(recordAsBytes, paginatedFields)
+	if err != nil {
+		return "", err
+	}
+	return mcpbson.EncodeCursor(cursorData), nil
+}
+
+// validate verifies that the results array is of a supported type and that its underlying struct has a bson tag that
+// matches each paginated field
+func validate(results interface{}, paginatedFields []string) error {
+	if results == nil {
+		return NewErrInvalidResults("expected results to be non nil")
+	}
+
+	// Check if results is a pointer
+	val := reflect.TypeOf(results)
+	if val.Kind() != reflect.Ptr {
+		return NewErrInvalidResults("expected results to be a slice pointer")
+	}
+
+	// Dereference the pointer to get the slice type
+	elem := val.Elem()
+
+	// Ensure we are dealing with a slice
+	if elem.Kind() != reflect.Slice {
+		return NewErrInvalidResults("expected results to be a slice pointer")
+	}
+
+	// Get the element type of the slice
+	elem = elem.Elem()
+
+	// We can't validate bson.Raw as we don't have the bson tags
+	if elem == reflect.TypeOf(bson.Raw{}) || elem == reflect.TypeOf(&bson.Raw{}) {
+		return nil
+	}
+
+	// If the slice contains pointers to structs, dereference to get the struct type
+	if elem.Kind() == reflect.Ptr {
+		elem = elem.Elem()
+	}
+
+	// Ensure that elem is now a struct
+	if elem.Kind() != reflect.Struct {
+		return NewErrInvalidResults("expected results' element to be a struct or struct pointer")
+	}
+
+	for _, paginatedField := range paginatedFields {
+		if !mcpbson.HasPaginatedField(elem, paginatedField) {
+			return NewErrPaginatedFieldNotFound(paginatedField)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,195 @@
+package mongov2
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type (
+	Item struct {
+		ID        bson.ObjectID `bson:"_id"`
+		Name      string        `bson:"name"`
+		Data      string        `bson:"data,omitempty"`
+		CreatedAt time.Time     `bson:"createdAt"`
+	}
+
+	ItemWithInline struct {
+		ID      bson.ObjectID `bson:"_id"`
+		Example string        `bson:"example,omitempty"`
+		Item    Item          `bson:",inline"`
+	}
+
+	ItemWithDeepInline struct {
+		Inline *ItemWithInline `bson:",omitempty,inline"`
+		Name   int64           `bson:"name"`
+	}
+
+	fakeCollection struct {
+		docs     []interface{}
+		filter   interface{}
+		deadline bool
+	}
+)
+
+func (c *fakeCollection) CountDocuments(context.Context, interface{}, ...options.Lister[options.CountOptions]) (int64, error) {
+	return int64(len(c.docs)), nil
+}
+
+func (c *fakeCollection) Find(ctx context.Context, filter interface{}, _ ...options.Lister[options.FindOptions]) (MongoCursor, error) {
+	c.filter = filter
+	_, c.deadline = ctx.Deadline()
+	return mongo.NewCursorFromDocuments(c.docs, nil, nil)
+}
+
+func TestFind(t *testing.T) {
+	items := []Item{
+		{ID: bson.NewObjectID(), Name: "a"},
+		{ID: bson.NewObjectID(), Name: "b"},
+		{ID: bson.NewObjectID(), Name: "c"},
+	}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1], items[2]}}
+	p := FindParams{Collection: col, Limit: 2, PaginatedField: "name", SortAscending: true, CountTotal: true}
+
+	var results []Item
+	cursor, err := Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[0], items[1]}, results)
+	require.True(t, cursor.HasNext)
+	require.False(t, cursor.HasPrevious)
+	require.Equal(t, 3, cursor.Count)
+	require.True(t, col.deadline)
+
+	expectedNext, err := GenerateCursor(items[1], p)
+	require.NoError(t, err)
+	require.Equal(t, expectedNext, cursor.Next)
+
+	col.docs = col.docs[2:]
+	p.Next = cursor.Next
+	cursor, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[2]}, results)
+	require.False(t, cursor.HasNext)
+	require.True(t, cursor.HasPrevious)
+	require.Equal(t, bson.M{"$or": []map[string]interface{}{
+		{"name": map[string]interface{}{"$gt": "b"}},
+		{"$and": []map[string]interface{}{
+			{"name": map[string]interface{}{"$gte": "b"}},
+			{"_id": map[string]interface{}{"$gt": items[1].ID}},
+		}},
+	}}, col.filter)
+
+	// The page resumes at the document the cursor points at
+	col.docs = []interface{}{items[1], items[2]}
+	p.IncludeAnchor = true
+	_, err = Find(context.Background(), p, &results)
+	require.NoError(t, err)
+	require.Equal(t, []Item{items[1], items[2]}, results)
+	require.Equal(t, bson.M{"$or": []map[string]interface{}{
+		{"name": map[string]interface{}{"$gt": "b"}},
+		{"$and": []map[string]interface{}{
+			{"name": map[string]interface{}{"$gte": "b"}},
+			{"_id": map[string]interface{}{"$gte": items[1].ID}},
+		}},
+	}}, col.filter)
+}
+
+func TestValidate(t *testing.T) {
+	var cases = []struct {
+		name            string
+		results         interface{}
+		paginatedFields []string
+		expectedErr     error
+	}{
+		{
+			name:            "errors when results is nil",
+			results:         nil,
+			paginatedFields: nil,
+			expectedErr:     NewErrInvalidResults("expected results to be non nil"),
+		},
+		{
+			name:            "errors when results is not a slice pointer",
+			results:         &struct{}{},
+			paginatedFields: nil,
+			expectedErr:     NewErrInvalidResults("expected results to be a slice pointer"),
+		},
+		{
+			name:            "passes validation when results' element type is a bson.Raw",
+			results:         &[]bson.Raw{},
+			paginatedFields: nil,
+			expectedErr:     nil,
+		},
+		{
+			name:            "passes validation when results is of a supported type and paginatedFields is found inline",
+			results:         &[]*ItemWithInline{},
+			paginatedFields: []string{"_id", "createdAt"},
+			expectedErr:     nil,
+		},
+		{
+			name:            "passes validation when paginatedFields are found inline through several levels and pointers",
+			results:         &[]ItemWithDeepInline{},
+			paginatedFields: []string{"_id", "example", "createdAt", "name"},
+			expectedErr:     nil,
+		},
+		{
+			name:            "errors when a paginatedFields is only found in an unexported field",
+			results:         &[]struct{ hidden int64 }{},
+			paginatedFields: []string{"hidden"},
+			expectedErr:     NewErrPaginatedFieldNotFound("hidden"),
+		},
+		{
+			name:            "errors when results is of a supported type but a paginatedFields is not found",
+			results:         &[]Item{},
+			paginatedFields: []string{"_id", "data", "invalid"},
+			expectedErr:     NewErrPaginatedFieldNotFound("invalid"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validate(tc.results, tc.paginatedFields)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestGenerateCursorPreservesBSONTypes(t *testing.T) {
+	amount, err := bson.ParseDecimal128("1.0000000000000000000000000001")
+	require.NoError(t, err)
+	uuid := bson.Binary{Subtype: 0x04, Data: []byte("0123456789abcdef")}
+	doc := bson.D{
+		{Key: "_id", Value: bson.NewObjectID()},
+		{Key: "amount", Value: amount},
+		{Key: "ts", Value: bson.Timestamp{T: 1700000000, I: 3}},
+		{Key: "uuid", Value: uuid},
+		{Key: "version", Value: bson.D{{Key: "major", Value: int32(2)}, {Key: "minor", Value: int32(1)}}},
+	}
+
+	cursor, err := generateCursor(doc, []string{"amount", "ts", "uuid", "version", "_id"})
+	require.NoError(t, err)
+	values, err := decodeCursor(cursor)
+	require.NoError(t, err)
+	require.Equal(t, bson.D{
+		{Key: "amount", Value: amount},
+		{Key: "ts", Value: bson.Timestamp{T: 1700000000, I: 3}},
+		{Key: "uuid", Value: uuid},
+		{Key: "version", Value: bson.D{{Key: "major", Value: int32(2)}, {Key: "minor", Value: int32(1)}}},
+		{Key: "_id", Value: doc[0].Value},
+	}, values)
+}
+
+func FuzzDecodeCursor(f *testing.F) {
+	cursor, err := generateCursor(Item{ID: bson.NewObjectID(), Name: "a"}, []string{"name", "_id"})
+	require.NoError(f, err)
+	f.Add(cursor)
+	f.Add(cursor[:len(cursor)/2])
+	f.Add("")
+
+	f.Fuzz(func(t *testing.T, cursor string) {
+		_, _ = decodeCursor(cursor)
+	})
+}
@@ -0,0 +1,15 @@
+package relay
+
+type (
+	ErrInvalidConnectionArgs struct {
+		message string
+	}
+)
+
+func NewErrInvalidConnectionArgs(message string) error {
+	return &ErrInvalidConnectionArgs{message: message}
+}
+
+func (e *ErrInvalidConnectionArgs) Error() string {
+	return e.message
+}
@@ -0,0 +1,169 @@
+// Package relay maps GraphQL Relay connection arguments onto paginated find mongo queries and returns
+// Relay connections, so the package can be used directly from GraphQL resolvers.
+// See https://relay.dev/graphql/connections.htm
+package relay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/qlik-oss/mongocursorpagination/mongo"
+)
+
+type (
+	// ConnectionArgs holds the Relay connection arguments. Forward pagination uses First and After,
+	// backward pagination uses Last and Before, Last without Before paging backwards from the end.
+	ConnectionArgs struct {
+		First  *int
+		After  *string
+		Last   *int
+		Before *string
+	}
+
+	// Edge holds a node and the cursor pointing at it.
+	Edge[T any] struct {
+		Node   T
+		Cursor string
+	}
+
+	// PageInfo holds the pagination information of a connection.
+	PageInfo struct {
+		HasNextPage     bool
+		HasPreviousPage bool
+		// The cursor of the first edge, empty if there are no edges
+		StartCursor string
+		// The cursor of the last edge, empty if there are no edges
+		EndCursor string
+	}
+
+	// Connection holds a page of edges and its pagination information.
+	Connection[T any] struct {
+		Edges    []Edge[T]
+		PageInfo PageInfo
+		// Total count of documents matching filter - only computed if CountTotal is True
+		TotalCount int
+	}
+)
+
+// Find applies the connection arguments onto the specified FindParams, executes the paginated find mongo
+// query decoding the documents into T and returns the resulting connection. The Limit of p is used when
+// neither First nor Last are specified. A First or Last of 0 returns a connection without edges, whose page info
+// tells whether edges follow it.
+func Find[T any](ctx context.Context, p mongo.FindParams, args ConnectionArgs) (Connection[T], error) {
+	var err error
+	p, err = ApplyConnectionArgs(p, args)
+	if err != nil {
+		return Connection[T]{}, err
+	}
+	// The page without edges fetches the first edge following it, if any
+	empty := p.Limit == 0 && (args.First != nil || args.Last != nil)
+	if empty {
+		p.Limit = 1
+	}
+
+	var nodes []T
+	cursor, err := mongo.Find(ctx, p, &nodes)
+	if err != nil {
+		return Connection[T]{}, err
+	}
+	var connection Connection[T]
+	if empty {
+		connection = emptyConnection[T](nodes, cursor, p)
+	} else {
+		connection, err = NewConnection(nodes, cursor, p)
+		if err != nil {
+			return Connection[T]{}, err
+		}
+	}
+	// The last page has no next page
+	if args.Last != nil && args.Before == nil {
+		connection.PageInfo.HasNextPage = false
+	}
+	return connection, nil
+}
+
+// emptyConnection returns the connection without edges of a First or Last of 0, whose page of p holds the edge
+// following it in the direction it was queried, if any
+func emptyConnection[T any](nodes []T, cursor mongo.Cursor, p mongo.FindParams) Connection[T] {
+	pageInfo := PageInfo{HasNextPage: cursor.HasNext, HasPreviousPage: cursor.HasPrevious}
+	if p.Previous != "" {
+		pageInfo.HasPreviousPage = len(nodes) > 0
+	} else {
+		pageInfo.HasNextPage = len(nodes) > 0
+	}
+	return Connection[T]{Edges: []Edge[T]{}, PageInfo: pageInfo, TotalCount: cursor.Count}
+}
+
+// NewConnection returns the connection of a page of nodes fetched with the specified FindParams and Cursor, e.g.
+// by a batch loader, the cursor of each edge pointing at its node
+func NewConnection[T any](nodes []T, cursor mongo.Cursor, p mongo.FindParams) (Connection[T], error) {
+	edgeCursors, err := mongo.GenerateCursors(nodes, p)
+	if err != nil {
+		return Connection[T]{}, fmt.Errorf("could not create an edge cursor: %s", err)
+	}
+	edges := make([]Edge[T], 0, len(nodes))
+	for i, node := range nodes {
+		edges = append(edges, Edge[T]{Node: node, Cursor: edgeCursors[i]})
+	}
+
+	pageInfo := PageInfo{
+		HasNextPage:     cursor.HasNext,
+		HasPreviousPage: cursor.HasPrevious,
+	}
+	if len(edges) > 0 {
+		pageInfo.StartCursor = edges[0].Cursor
+		pageInfo.EndCursor = edges[len(edges)-1].Cursor
+	}
+
+	return Connection[T]{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: cursor.Count,
+	}, nil
+}
+
+// ApplyConnectionArgs returns p with the limit and cursor of the connection arguments, the Limit of p being kept
+// when neither First nor Last are specified. Last without Before sets the EndCursor of p as its Previous cursor.
+func ApplyConnectionArgs(p mongo.FindParams, args ConnectionArgs) (mongo.FindParams, error) {
+	if args.First != nil && args.Last != nil {
+		return p, NewErrInvalidConnectionArgs("first and last can't be specified together")
+	}
+	if args.After != nil && args.Before != nil {
+		return p, NewErrInvalidConnectionArgs("after and before can't be specified together")
+	}
+	if args.First != nil && args.Before != nil {
+		return p, NewErrInvalidConnectionArgs("first can't be combined with before")
+	}
+	if args.Last != nil && args.After != nil {
+		return p, NewErrInvalidConnectionArgs("last can't be combined with after")
+	}
+
+	p.Next = ""
+	p.Previous = ""
+	if args.First != nil {
+		if *args.First < 0 {
+			return p, NewErrInvalidConnectionArgs("first can't be negative")
+		}
+		p.Limit = int64(*args.First)
+	}
+	if args.Last != nil {
+		if *args.Last < 0 {
+			return p, NewErrInvalidConnectionArgs("last can't be negative")
+		}
+		p.Limit = int64(*args.Last)
+	}
+	if args.After != nil {
+		p.Next = *args.After
+	}
+	if args.Before != nil {
+		p.Previous = *args.Before
+	}
+	if args.Last != nil && args.Before == nil {
+		end, err := mongo.EndCursor(p)
+		if err != nil {
+			return p, err
+		}
+		p.Previous = end
+	}
+	return p, nil
+}
@@ -0,0 +1,160 @@
+package relay
+
+import (
+	"context"
+	"testing"
+
+	mcpmongo "github.com/qlik-oss/mongocursorpagination/mongo"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type (
+	item struct {
+		ID   primitive.ObjectID `bson:"_id"`
+		Name string             `bson:"name"`
+	}
+
+	fakeCollection struct {
+		docs []interface{}
+	}
+)
+
+func (c *fakeCollection) CountDocuments(context.Context, interface{}, ...*options.CountOptions) (int64, error) {
+	return int64(len(c.docs)), nil
+}
+
+func (c *fakeCollection) Find(context.Context, interface{}, ...*options.FindOptions) (mcpmongo.MongoCursor, error) {
+	return mongo.NewCursorFromDocuments(c.docs, nil, nil)
+}
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
+
+func TestFind(t *testing.T) {
+	items := []item{
+		{ID: primitive.NewObjectID(), Name: "a"},
+		{ID: primitive.NewObjectID(), Name: "b"},
+		{ID: primitive.NewObjectID(), Name: "c"},
+	}
+	col := &fakeCollection{docs: []interface{}{items[0], items[1], items[2]}}
+	p := mcpmongo.FindParams{Collection: col, PaginatedField: "name", SortAscending: true, CountTotal: true}
+
+	conn, err := Find[item](context.Background(), p, ConnectionArgs{First: intPtr(2)})
+	require.NoError(t, err)
+	require.Len(t, conn.Edges, 2)
+	require.Equal(t, items[0], conn.Edges[0].Node)
+	require.Equal(t, items[1], conn.Edges[1].Node)
+	require.True(t, conn.PageInfo.HasNextPage)
+	require.False(t, conn.PageInfo.HasPreviousPage)
+	require.Equal(t, conn.Edges[0].Cursor, conn.PageInfo.StartCursor)
+	require.Equal(t, conn.Edges[1].Cursor, conn.PageInfo.EndCursor)
+	require.Equal(t, 3, conn.TotalCount)
+
+	expectedCursor, err := mcpmongo.GenerateCursor(items[1], p)
+	require.NoError(t, err)
+	require.Equal(t, expectedCursor, conn.PageInfo.EndCursor)
+
+	col.docs = col.docs[2:]
+	conn, err = Find[item](context.Background(), p, ConnectionArgs{First: intPtr(2), After: stringPtr(conn.PageInfo.EndCursor)})
+	require.NoError(t, err)
+	require.Len(t, conn.Edges, 1)
+	require.Equal(t, items[2], conn.Edges[0].Node)
+	require.False(t, conn.PageInfo.HasNextPage)
+	require.True(t, conn.PageInfo.HasPreviousPage)
+
+	// A first of 0 has no edges but tells whether some follow
+	col.docs = []interface{}{items[0], items[1], items[2]}
+	conn, err = Find[item](context.Background(), p, ConnectionArgs{First: intPtr(0)})
+	require.NoError(t, err)
+	require.Empty(t, conn.Edges)
+	require.NotNil(t, conn.Edges)
+	require.True(t, conn.PageInfo.HasNextPage)
+	require.False(t, conn.PageInfo.HasPreviousPage)
+	require.Equal(t, 3, conn.TotalCount)
+
+	// Last without before pages backwards from the end, the documents being fetched in the reverse order
+	col.docs = []interface{}{items[2], items[1], items[0]}
+	conn, err = Find[item](context.Background(), p, ConnectionArgs{Last: intPtr(2)})
+	require.NoError(t, err)
+	require.Len(t, conn.Edges, 2)
+	require.Equal(t, items[1], conn.Edges[0].Node)
+	require.Equal(t, items[2], conn.Edges[1].Node)
+	require.False(t, conn.PageInfo.HasNextPage)
+	require.True(t, conn.PageInfo.HasPreviousPage)
+
+	// A last of 0 without before is past the last edge
+	conn, err = Find[item](context.Background(), p, ConnectionArgs{Last: intPtr(0)})
+	require.NoError(t, err)
+	require.Empty(t, conn.Edges)
+	require.False(t, conn.PageInfo.HasNextPage)
+	require.True(t, conn.PageInfo.HasPreviousPage)
+}
+
+func TestApplyConnectionArgs(t *testing.T) {
+	endCursor, err := mcpmongo.EndCursor(mcpmongo.FindParams{})
+	require.NoError(t, err)
+	var cases = []struct {
+		name               string
+		args               ConnectionArgs
+		expectedFindParams mcpmongo.FindParams
+		expectedErr        error
+	}{
+		{
+			name:               "uses the find params limit when neither first nor last are specified",
+			args:               ConnectionArgs{},
+			expectedFindParams: mcpmongo.FindParams{Limit: 10},
+			expectedErr:        nil,
+		},
+		{
+			name:               "maps first and after onto limit and next",
+			args:               ConnectionArgs{First: intPtr(5), After: stringPtr("abc")},
+			expectedFindParams: mcpmongo.FindParams{Limit: 5, Next: "abc"},
+			expectedErr:        nil,
+		},
+		{
+			name:               "maps last and before onto limit and previous",
+			args:               ConnectionArgs{Last: intPtr(5), Before: stringPtr("abc")},
+			expectedFindParams: mcpmongo.FindParams{Limit: 5, Previous: "abc"},
+			expectedErr:        nil,
+		},
+		{
+			name:               "errors when first and last are specified",
+			args:               ConnectionArgs{First: intPtr(5), Last: intPtr(5)},
+			expectedFindParams: mcpmongo.FindParams{Limit: 10},
+			expectedErr:        NewErrInvalidConnectionArgs("first and last can't be specified together"),
+		},
+		{
+			name:               "errors when after and before are specified",
+			args:               ConnectionArgs{After: stringPtr("abc"), Before: stringPtr("def")},
+			expectedFindParams: mcpmongo.FindParams{Limit: 10},
+			expectedErr:        NewErrInvalidConnectionArgs("after and before can't be specified together"),
+		},
+		{
+			name:               "maps last without before onto limit and the end cursor",
+			args:               ConnectionArgs{Last: intPtr(5)},
+			expectedFindParams: mcpmongo.FindParams{Limit: 5, Previous: endCursor},
+			expectedErr:        nil,
+		},
+		{
+			name:               "errors when first is negative",
+			args:               ConnectionArgs{First: intPtr(-1)},
+			expectedFindParams: mcpmongo.FindParams{Limit: 10},
+			expectedErr:        NewErrInvalidConnectionArgs("first can't be negative"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p, err := ApplyConnectionArgs(mcpmongo.FindParams{Limit: 10}, tc.args)
+			require.Equal(t, tc.expectedFindParams, p)
+			require.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
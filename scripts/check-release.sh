@@ -0,0 +1,15 @@
+#!/usr/bin/env sh
+
+# Purpose: This script checks that the root module release required by the nested modules is tagged, as their
+# replace directives are ignored by their consumers.
+# Instructions: make check-release
+
+set -eu
+
+for mod in mgo mongov2 test/integration; do
+    VERSION=$(cd $mod && go list -m -f '{{.Version}}' github.com/qlik-oss/mongocursorpagination)
+    if ! git rev-parse -q --verify "refs/tags/$VERSION" > /dev/null; then
+        echo "$mod requires the root module $VERSION, which isn't tagged" >&2
+        exit 1
+    fi
+done
@@ -4,4 +4,5 @@ set -eu
 
 ARGS=$1
 
-go test -coverprofile=integration.cover ./test/integration $ARGS
+cd test/integration
+go test -coverprofile=../../integration.cover . $ARGS
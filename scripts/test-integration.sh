@@ -8,4 +8,6 @@ set -eu
 ARGS=$1
 
 # The idiomatic way to disable test caching explicitly is to use -count=1
-go test -count=1 -race -v ./test/integration/... $ARGS
+# The integration tests are a separate module depending on both the root and the mgo modules
+cd test/integration
+go test -count=1 -race -v ./... $ARGS
@@ -12,6 +12,15 @@ for pkg in $GO_PACKAGES; do
     go test -coverprofile=$(echo $pkg | tr / -).cover.tmp $pkg;
 done
 
+# The mgo package is a separate module so that the mongo-driver packages do not pull globalsign/mgo
+(cd mgo && go test -coverprofile=../mgo.cover.tmp ./...)
+
+# The mongov2 package is a separate module so that the mongo-driver packages do not pull the v2 driver
+(cd mongov2 && go test -coverprofile=../mongov2.cover.tmp ./...)
+
+# The lint analyzer is a separate module so that golang.org/x/tools isn't pulled by the library
+(cd lint && go test -coverprofile=../lint.cover.tmp ./...)
+
 echo "mode: set" > unit.cover
 grep -h -v "^mode:" ./*.cover.tmp >> unit.cover
 rm -f *.cover.tmp
@@ -9,3 +9,12 @@ GO_PACKAGES=$(go list ./... | grep -v "test/")
 
 # The idiomatic way to disable test caching explicitly is to use -count=1
 go test -count=1 -race -cover $GO_PACKAGES
+
+# The mgo package is a separate module so that the mongo-driver packages do not pull globalsign/mgo
+(cd mgo && go test -count=1 -race -cover ./...)
+
+# The mongov2 package is a separate module so that the mongo-driver packages do not pull the v2 driver
+(cd mongov2 && go test -count=1 -race -cover ./...)
+
+# The lint analyzer is a separate module so that golang.org/x/tools isn't pulled by the library
+(cd lint && go test -count=1 -race -cover ./...)
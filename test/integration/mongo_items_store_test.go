@@ -22,7 +22,7 @@ func newMongoStore(t *testing.T) MongoStore {
 	return store
 }
 
-func newMongoCollection(t *testing.T) *mongoCollectionWrapper {
+func newMongoCollection(t testing.TB) *mongo.Collection {
 	t.Helper()
 	mongoAddr := os.Getenv("MONGO_URI")
 	require.NotEmpty(t, mongoAddr, "MONGO_URI is required")
@@ -31,10 +31,7 @@ func newMongoCollection(t *testing.T) *mongoCollectionWrapper {
 	defer cancel()
 	client, err := mongo.Connect(ctx, options.Client().ApplyURI(mongoAddr))
 	require.NoError(t, err, "error connecting to mongo")
-	col := mongoCollectionWrapper{
-		collection: client.Database("test_db").Collection("items"),
-	}
-	return &col
+	return client.Database("test_db").Collection("items")
 }
 
 func createMongoItem(t *testing.T, mongoStore MongoStore, name string, data string) *MongoItem {
@@ -70,7 +67,7 @@ func TestMongoFindManyPagination(t *testing.T) {
 	englishCollation := options.Collation{Locale: "en", Strength: 3}
 
 	// Get empty array when no items created
-	foundItems, cursor, err := store.Find(context.Background(), searchQuery, "", "", 4, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err := store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 4, SortAscending: true, PaginatedField: "name"}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Empty(t, foundItems)
 	require.False(t, cursor.HasNext)
@@ -82,7 +79,7 @@ func TestMongoFindManyPagination(t *testing.T) {
 	item2 := createMongoItem(t, store, "test item 2", "")
 
 	// Get first page of search for items
-	foundItems, cursor, err = store.Find(context.Background(), searchQuery, "", "", 2, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 2, SortAscending: true, PaginatedField: "name"}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(foundItems))
 	require.True(t, cursor.HasNext)
@@ -91,7 +88,7 @@ func TestMongoFindManyPagination(t *testing.T) {
 	require.Equal(t, item2.ID, foundItems[1].ID)
 
 	// Get 2nd page of search for items
-	foundItems, cursor, err = store.Find(context.Background(), searchQuery, cursor.Next, "", 2, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 2, SortAscending: true, PaginatedField: "name", Next: cursor.Next}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(foundItems))
 	require.False(t, cursor.HasNext)
@@ -100,7 +97,7 @@ func TestMongoFindManyPagination(t *testing.T) {
 	require.Equal(t, item4.ID, foundItems[1].ID)
 
 	// Get previous page of search for items
-	foundItems, cursor, err = store.Find(context.Background(), searchQuery, "", cursor.Previous, 2, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 2, SortAscending: true, PaginatedField: "name", Previous: cursor.Previous}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(foundItems))
 	require.True(t, cursor.HasNext)
@@ -119,7 +116,7 @@ func TestMongoFindManyPaginationWithInlineFeature(t *testing.T) {
 	englishCollation := options.Collation{Locale: "en", Strength: 3}
 
 	// Get empty array when no items created
-	foundItems, cursor, err := store.Find(context.Background(), searchQuery, "", "", 4, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err := store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 4, SortAscending: true, PaginatedField: "name"}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Empty(t, foundItems)
 	require.False(t, cursor.HasNext)
@@ -131,7 +128,7 @@ func TestMongoFindManyPaginationWithInlineFeature(t *testing.T) {
 	item2 := createItemWithSampleInline(t, store, "test item 2", "", "test2")
 
 	// Get first page of search for items
-	foundItems, cursor, err = store.Find(context.Background(), searchQuery, "", "", 2, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 2, SortAscending: true, PaginatedField: "name"}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(foundItems))
 	require.True(t, cursor.HasNext)
@@ -142,7 +139,7 @@ func TestMongoFindManyPaginationWithInlineFeature(t *testing.T) {
 	require.Equal(t, item2.Inline.Sample, foundItems[1].Inline.Sample)
 
 	// Get 2nd page of search for items
-	foundItems, cursor, err = store.Find(context.Background(), searchQuery, cursor.Next, "", 2, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 2, SortAscending: true, PaginatedField: "name", Next: cursor.Next}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(foundItems))
 	require.False(t, cursor.HasNext)
@@ -153,7 +150,7 @@ func TestMongoFindManyPaginationWithInlineFeature(t *testing.T) {
 	require.Equal(t, item4.Inline.Sample, foundItems[1].Inline.Sample)
 
 	// Get previous page of search for items
-	foundItems, cursor, err = store.Find(context.Background(), searchQuery, "", cursor.Previous, 2, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 2, SortAscending: true, PaginatedField: "name", Previous: cursor.Previous}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(foundItems))
 	require.True(t, cursor.HasNext)
@@ -177,13 +174,13 @@ func TestPaginationWithoutPaginatedField(t *testing.T) {
 	item2 := createMongoItem(t, store, fmt.Sprintf("%s-2", itemNamePrefix), "")
 
 	// Call Find without paginatedField argument.
-	foundItems, cursor, err := store.Find(context.Background(), searchQuery, "", "", 1, true, "", &options.Collation{}, nil, nil)
+	foundItems, cursor, err := store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 1, SortAscending: true}, &options.Collation{}, nil, nil)
 	require.NoError(t, err)
 	require.Len(t, foundItems, 1)
 	require.Equal(t, item1.Name, foundItems[0].Name)
 	require.True(t, cursor.HasNext)
 	// Validate that cursor.Next works as expected.
-	foundItems, cursor, err = store.Find(context.Background(), searchQuery, cursor.Next, "", 1, true, "", &options.Collation{}, nil, nil)
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 1, SortAscending: true, Next: cursor.Next}, &options.Collation{}, nil, nil)
 	require.NoError(t, err)
 	require.Len(t, foundItems, 1)
 	require.Equal(t, item2.Name, foundItems[0].Name)
@@ -197,7 +194,7 @@ func TestMongoFindCursorError(t *testing.T) {
 	store := newMongoStore(t)
 	searchQuery := bson.M{"name": primitive.Regex{Pattern: "test item.*", Options: "i"}}
 	englishCollation := options.Collation{Locale: "en", Strength: 3}
-	foundItems, cursor, err := store.Find(context.Background(), searchQuery, "bad_cursor_string", "", 4, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err := store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 4, SortAscending: true, PaginatedField: "name", Next: "bad_cursor_string"}, &englishCollation, nil, nil)
 	require.Error(t, err)
 	require.IsType(t, &mongocursorpagination.CursorError{}, err)
 	require.Empty(t, foundItems)
@@ -215,7 +212,7 @@ func TestMongoPaginationBSONRaw(t *testing.T) {
 	createMongoItem(t, store, "test item 3", "")
 	createMongoItem(t, store, "test item 4", "")
 
-	foundItems, cursor, err := store.FindBSONRaw(context.Background(), searchQuery, "", "", 2, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err := store.FindBSONRaw(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 2, SortAscending: true, PaginatedField: "name"}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(foundItems))
 	require.True(t, cursor.HasNext)
@@ -270,15 +267,15 @@ func TestMongoBuildPaginatedQueries(t *testing.T) {
 		{
 			name: "Sort on name, first page",
 			params: mongocursorpagination.FindParams{
-				Collection:     col,
-				Query:          query1,
-				Limit:          int64(42),
-				SortAscending:  true,
-				PaginatedField: "name",
-				Collation:      &englishCollation,
-				Next:           "",
-				Previous:       "",
-				CountTotal:     false,
+				MongoCollection: col,
+				Query:           query1,
+				Limit:           int64(42),
+				SortAscending:   true,
+				PaginatedField:  "name",
+				Collation:       &englishCollation,
+				Next:            "",
+				Previous:        "",
+				CountTotal:      false,
 			},
 			expectQueries: []bson.M{query1},
 			expectSort:    bson.D{primitive.E{Key: "name", Value: 1}, primitive.E{Key: "_id", Value: 1}},
@@ -287,15 +284,15 @@ func TestMongoBuildPaginatedQueries(t *testing.T) {
 		{
 			name: "Sort on name, second page",
 			params: mongocursorpagination.FindParams{
-				Collection:     col,
-				Query:          query1,
-				Limit:          int64(42),
-				SortAscending:  true,
-				PaginatedField: "name",
-				Collation:      &englishCollation,
-				Next:           encodeCursor(t, bson.D{bson.E{Key: "name", Value: "foo"}, bson.E{Key: "_id", Value: oid1}}),
-				Previous:       "",
-				CountTotal:     false,
+				MongoCollection: col,
+				Query:           query1,
+				Limit:           int64(42),
+				SortAscending:   true,
+				PaginatedField:  "name",
+				Collation:       &englishCollation,
+				Next:            encodeCursor(t, bson.D{bson.E{Key: "name", Value: "foo"}, bson.E{Key: "_id", Value: oid1}}),
+				Previous:        "",
+				CountTotal:      false,
 			},
 			expectQueries: []bson.M{query1, query2},
 			expectSort:    bson.D{bson.E{Key: "name", Value: 1}, bson.E{Key: "_id", Value: 1}},
@@ -324,11 +321,22 @@ func TestMongoProjection(t *testing.T) {
 		bson.E{Key: "name", Value: 1},
 	}
 
-	foundItems, _, err := store.FindBSONRaw(context.Background(), searchQuery, "", "", 2, true, "name", nil, nil, projection)
+	// The _id breaks the ties of the names in the cursors
+	_, _, err := store.FindBSONRaw(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 2, SortAscending: true, PaginatedField: "name"}, nil, nil, projection)
+	var projectionErr *mongocursorpagination.ErrProjectionExcludesField
+	require.ErrorAs(t, err, &projectionErr)
+
+	projection = bson.D{bson.E{Key: "name", Value: 1}}
+	foundItems, _, err := store.FindBSONRaw(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 2, SortAscending: true, PaginatedField: "name"}, nil, nil, projection)
 	require.NoError(t, err)
 	require.Equal(t, 2, len(foundItems))
-	require.Equal(t, `{"name": "test item 0"}`, foundItems[0].String())
-	require.Equal(t, `{"name": "test item 1"}`, foundItems[1].String())
+	for i, foundItem := range foundItems {
+		elements, err := foundItem.Elements()
+		require.NoError(t, err)
+		require.Len(t, elements, 2)
+		require.Equal(t, "_id", elements[0].Key())
+		require.Equal(t, fmt.Sprintf("test item %d", i), foundItem.Lookup("name").StringValue())
+	}
 
 	// Cleanup
 	err = store.RemoveAll(context.Background())
@@ -343,16 +351,16 @@ func TestMongoHint(t *testing.T) {
 		_ = createMongoItem(t, store, string(c), "")
 	}
 
-	_, _, err := store.Find(context.Background(), searchQuery, "", "", 10, true, "_id", nil, "indexName_id", nil)
+	_, _, err := store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 10, SortAscending: true, PaginatedField: "_id"}, nil, "indexName_id", nil)
 	require.True(t, errors.As(err, &mongo.CommandError{}), "non existing index by name should result in a command error")
 
-	_, _, err = store.Find(context.Background(), searchQuery, "", "", 10, true, "_id", nil, bson.D{bson.E{Key: "created", Value: 1}}, nil)
+	_, _, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 10, SortAscending: true, PaginatedField: "_id"}, nil, bson.D{bson.E{Key: "created", Value: 1}}, nil)
 	require.True(t, errors.As(err, &mongo.CommandError{}), "non existing index by specification document should result in a command error")
 
-	_, _, err = store.Find(context.Background(), searchQuery, "", "", 10, true, "_id", nil, "_id_", nil)
+	_, _, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 10, SortAscending: true, PaginatedField: "_id"}, nil, "_id_", nil)
 	require.NoError(t, err, "hinting the default _id index by name should succeed")
 
-	_, _, err = store.Find(context.Background(), searchQuery, "", "", 10, true, "_id", nil, bson.D{bson.E{Key: "_id", Value: 1}}, nil)
+	_, _, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 10, SortAscending: true, PaginatedField: "_id"}, nil, bson.D{bson.E{Key: "_id", Value: 1}}, nil)
 	require.NoError(t, err, "hinting the default _id index by specification document should succeed")
 
 	// Cleanup
@@ -371,7 +379,7 @@ func TestMongoFindMultiplePaginatedFields(t *testing.T) {
 	searchQuery := bson.M{"name": primitive.Regex{Pattern: "test item.*", Options: "i"}}
 	englishCollation := options.Collation{Locale: "en", Strength: 3}
 
-	foundItems, cursor, err := store.Find(context.Background(), searchQuery, "", "", 4, true, "name", &englishCollation, nil, nil)
+	foundItems, cursor, err := store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 4, SortAscending: true, PaginatedField: "name"}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Empty(t, foundItems)
 	require.False(t, cursor.HasNext)
@@ -387,7 +395,7 @@ func TestMongoFindMultiplePaginatedFields(t *testing.T) {
 	item8 := createMongoItem(t, store, "test item 8", "2")
 
 	// Get first page of search for items
-	foundItems, cursor, err = store.FindMultiplePaginatedFields(context.Background(), searchQuery, "", "", 4, []int{1, -1}, []string{"data", "name"}, &englishCollation, nil, nil)
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 4, PaginatedFields: []string{"data", "name"}, SortOrders: []int{1, -1}}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 4, len(foundItems))
 	require.True(t, cursor.HasNext)
@@ -398,7 +406,7 @@ func TestMongoFindMultiplePaginatedFields(t *testing.T) {
 	require.Equal(t, item5.ID, foundItems[3].ID)
 
 	// Get 2nd page of search for items
-	foundItems, cursor, err = store.FindMultiplePaginatedFields(context.Background(), searchQuery, cursor.Next, "", 4, []int{1, -1}, []string{"data", "name"}, &englishCollation, nil, nil)
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 4, PaginatedFields: []string{"data", "name"}, SortOrders: []int{1, -1}, Next: cursor.Next}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 4, len(foundItems))
 	require.False(t, cursor.HasNext)
@@ -409,7 +417,7 @@ func TestMongoFindMultiplePaginatedFields(t *testing.T) {
 	require.Equal(t, item1.ID, foundItems[3].ID)
 
 	// Get previous page of search for items
-	foundItems, cursor, err = store.FindMultiplePaginatedFields(context.Background(), searchQuery, "", cursor.Previous, 4, []int{1, -1}, []string{"data", "name"}, &englishCollation, nil, nil)
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, mongocursorpagination.PageRequest{Limit: 4, PaginatedFields: []string{"data", "name"}, SortOrders: []int{1, -1}, Previous: cursor.Previous}, &englishCollation, nil, nil)
 	require.NoError(t, err)
 	require.Equal(t, 4, len(foundItems))
 	require.True(t, cursor.HasNext)
@@ -423,3 +431,214 @@ func TestMongoFindMultiplePaginatedFields(t *testing.T) {
 	err = store.RemoveAll(context.Background())
 	require.NoError(t, err)
 }
+
+func TestMongoFindCaseInsensitivePagination(t *testing.T) {
+	col := newMongoCollection(t)
+	store := NewMongoStore(col)
+	searchQuery := bson.M{}
+	caseInsensitiveCollation := options.Collation{Locale: "en", Strength: 2}
+
+	_, err := col.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("name_ci").SetCollation(&caseInsensitiveCollation),
+	})
+	require.NoError(t, err)
+	defer func() {
+		_, err := col.Indexes().DropOne(context.Background(), "name_ci")
+		require.NoError(t, err)
+	}()
+
+	page := mongocursorpagination.PageRequest{Limit: 2, PaginatedFields: []string{"name"}, SortOrders: []int{1}}
+	err = mongocursorpagination.ValidateCollationIndex(context.Background(), col.Indexes(), page.Apply(mongocursorpagination.FindParams{Collation: &caseInsensitiveCollation}))
+	require.NoError(t, err)
+
+	itemA := createMongoItem(t, store, "item a", "")
+	itemUpperA := createMongoItem(t, store, "item A", "")
+	itemB := createMongoItem(t, store, "item B", "")
+	itemC := createMongoItem(t, store, "item c", "")
+
+	// The case folded values compare equal, so their ties are broken by _id on every page
+	foundItems, cursor, err := store.Find(context.Background(), searchQuery, page, &caseInsensitiveCollation, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, []primitive.ObjectID{itemA.ID, itemUpperA.ID}, mongoItemIDs(foundItems))
+	require.True(t, cursor.HasNext)
+
+	foundItems, cursor, err = store.Find(context.Background(), searchQuery, page.WithToken(cursor.Next, mongocursorpagination.Forward), &caseInsensitiveCollation, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, []primitive.ObjectID{itemB.ID, itemC.ID}, mongoItemIDs(foundItems))
+	require.False(t, cursor.HasNext)
+
+	foundItems, _, err = store.Find(context.Background(), searchQuery, page.WithToken(cursor.Previous, mongocursorpagination.Backward), &caseInsensitiveCollation, nil, nil)
+	require.NoError(t, err)
+	require.Equal(t, []primitive.ObjectID{itemA.ID, itemUpperA.ID}, mongoItemIDs(foundItems))
+
+	// Cleanup
+	err = store.RemoveAll(context.Background())
+	require.NoError(t, err)
+}
+
+func mongoItemIDs(items []*MongoItem) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.ID)
+	}
+	return ids
+}
+
+func BenchmarkMongoPredicateStrategies(b *testing.B) {
+	col := newMongoCollection(b)
+	ctx := context.Background()
+
+	docs := make([]interface{}, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		docs = append(docs, MongoItem{ID: primitive.NewObjectID(), Name: fmt.Sprintf("item %04d", i), Data: fmt.Sprintf("data %d", i%10), CreatedAt: time.Now()})
+	}
+	_, err := col.InsertMany(ctx, docs)
+	require.NoError(b, err)
+	_, err = col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "data", Value: 1}, {Key: "name", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("data_name"),
+	})
+	require.NoError(b, err)
+	defer func() {
+		_, err := col.Indexes().DropOne(ctx, "data_name")
+		require.NoError(b, err)
+		_, err = col.DeleteMany(ctx, bson.M{})
+		require.NoError(b, err)
+	}()
+
+	page := mongocursorpagination.PageRequest{Limit: 20, PaginatedFields: []string{"data", "name"}, SortOrders: []int{1, 1}}
+	var results []*MongoItem
+	cursor, err := mongocursorpagination.Find(ctx, page.Apply(mongocursorpagination.FindParams{MongoCollection: col, Query: bson.M{}}), &results)
+	require.NoError(b, err)
+	page = page.WithToken(cursor.Next, mongocursorpagination.Forward)
+
+	for _, strategy := range []struct {
+		name     string
+		strategy mongocursorpagination.PredicateStrategy
+	}{
+		{name: "NestedOr", strategy: mongocursorpagination.PredicateNestedOr},
+		{name: "RowValueExpr", strategy: mongocursorpagination.PredicateRowValueExpr},
+	} {
+		b.Run(strategy.name, func(b *testing.B) {
+			p := page.Apply(mongocursorpagination.FindParams{MongoCollection: col, Query: bson.M{}, PredicateStrategy: strategy.strategy})
+
+			// Report how many documents the planner examines for the page with the strategy
+			queries, sort, err := mongocursorpagination.BuildQueries(ctx, p)
+			require.NoError(b, err)
+			docsExamined, err := mongocursorpagination.ExplainDocsExamined(ctx, col, mongocursorpagination.MergeQueries(queries), options.Find().SetSort(sort).SetLimit(p.Limit+1))
+			require.NoError(b, err)
+			b.ReportMetric(float64(docsExamined), "docsExamined/op")
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var results []*MongoItem
+				_, err := mongocursorpagination.Find(ctx, p, &results)
+				require.NoError(b, err)
+			}
+		})
+	}
+}
+
+func TestMongoFindMergedQueryUsesIndex(t *testing.T) {
+	ctx := context.Background()
+	col := newMongoCollection(t)
+	store := NewMongoStore(col)
+	_, err := col.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}},
+		Options: options.Index().SetName("name_id"),
+	})
+	require.NoError(t, err)
+	defer func() {
+		_, err := col.Indexes().DropOne(ctx, "name_id")
+		require.NoError(t, err)
+		_, err = col.DeleteMany(ctx, bson.M{})
+		require.NoError(t, err)
+	}()
+	for i := 0; i < 50; i++ {
+		createMongoItem(t, store, fmt.Sprintf("item %02d", i), "")
+	}
+
+	page := mongocursorpagination.PageRequest{Limit: 5, PaginatedField: "name", SortAscending: true}
+	var results []*MongoItem
+	cursor, err := mongocursorpagination.Find(ctx, page.Apply(mongocursorpagination.FindParams{MongoCollection: col}), &results)
+	require.NoError(t, err)
+
+	// Without a base query, the cursor predicate is the whole filter
+	p := page.WithToken(cursor.Next, mongocursorpagination.Forward).Apply(mongocursorpagination.FindParams{MongoCollection: col})
+	queries, sort, err := mongocursorpagination.BuildQueries(ctx, p)
+	require.NoError(t, err)
+	filter := mongocursorpagination.MergeQueries(queries)
+	require.NotContains(t, filter, "$and")
+
+	// The page is read from the index without examining the documents of the previous pages
+	docsExamined, err := mongocursorpagination.ExplainDocsExamined(ctx, col, filter, options.Find().SetSort(sort).SetLimit(p.Limit+1))
+	require.NoError(t, err)
+	require.LessOrEqual(t, docsExamined, p.Limit+1)
+}
+
+func TestMongoFindArrayElements(t *testing.T) {
+	ctx := context.Background()
+	col := newMongoCollection(t)
+	type element struct {
+		ID   int    `bson:"id"`
+		Name string `bson:"name"`
+	}
+	var elements []element
+	for i := 1; i <= 7; i++ {
+		elements = append(elements, element{ID: i, Name: fmt.Sprintf("element %d", i)})
+	}
+	parentID := primitive.NewObjectID()
+	_, err := col.InsertOne(ctx, bson.M{"_id": parentID, "elements": elements})
+	require.NoError(t, err)
+	defer func() {
+		_, err := col.DeleteMany(ctx, bson.M{})
+		require.NoError(t, err)
+	}()
+
+	for _, elementKey := range []string{"", "id"} {
+		t.Run("element key "+elementKey, func(t *testing.T) {
+			p := mongocursorpagination.ArrayParams{
+				MongoCollection: col,
+				Query:           bson.M{"_id": parentID},
+				Field:           "elements",
+				ElementKey:      elementKey,
+				Limit:           3,
+				CountTotal:      true,
+			}
+			var results []element
+			cursor, err := mongocursorpagination.FindArrayElements(ctx, p, &results)
+			require.NoError(t, err)
+			require.Equal(t, elements[0:3], results)
+			require.Equal(t, 7, cursor.Count)
+			require.True(t, cursor.HasNext)
+			require.False(t, cursor.HasPrevious)
+
+			p.Next = cursor.Next
+			cursor, err = mongocursorpagination.FindArrayElements(ctx, p, &results)
+			require.NoError(t, err)
+			require.Equal(t, elements[3:6], results)
+			require.True(t, cursor.HasNext)
+			require.True(t, cursor.HasPrevious)
+
+			p.Next = cursor.Next
+			cursor, err = mongocursorpagination.FindArrayElements(ctx, p, &results)
+			require.NoError(t, err)
+			require.Equal(t, elements[6:], results)
+			require.False(t, cursor.HasNext)
+
+			p.Next = ""
+			p.Previous = cursor.Previous
+			cursor, err = mongocursorpagination.FindArrayElements(ctx, p, &results)
+			require.NoError(t, err)
+			require.Equal(t, elements[3:6], results)
+			require.True(t, cursor.HasPrevious)
+
+			p.Previous = cursor.Previous
+			cursor, err = mongocursorpagination.FindArrayElements(ctx, p, &results)
+			require.NoError(t, err)
+			require.Equal(t, elements[0:3], results)
+			require.False(t, cursor.HasPrevious)
+		})
+	}
+}